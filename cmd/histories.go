@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"go-test/internal/config"
+	"go-test/internal/workflow"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/log"
+	"go.uber.org/zap"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+const (
+	historiesUsage      = "usage: histories export [-out dir] [-query visibility-query] [-limit n] [workflow-id ...] | histories verify [-dir dir]"
+	defaultHistoriesDir = "internal/workflow/replaytest/testdata/histories"
+)
+
+var historyFileNameChar = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func runHistoriesCommand(logger *zap.Logger, cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(historiesUsage)
+	}
+
+	switch args[0] {
+	case "export":
+		return exportHistories(logger, args[1:])
+	case "verify":
+		flags := flag.NewFlagSet("histories verify", flag.ContinueOnError)
+		dir := flags.String("dir", defaultHistoriesDir, "directory with the exported histories")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		replayer := workflow.NewReplayer(cfg.Workflow, logger)
+		return workflow.ReplayHistories(replayer, log.NewStructuredLogger(slog.Default()), *dir)
+	default:
+		return errors.New(historiesUsage)
+	}
+}
+
+func exportHistories(logger *zap.Logger, args []string) error {
+	flags := flag.NewFlagSet("histories export", flag.ContinueOnError)
+	out := flags.String("out", defaultHistoriesDir, "directory to write the histories to")
+	query := flags.String("query", "", "visibility query selecting the workflows to export")
+	limit := flags.Int("limit", 20, "maximum number of workflows selected by -query")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *query == "" && flags.NArg() == 0 {
+		return errors.New(historiesUsage)
+	}
+
+	c, err := createTemporalClient()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	ctx := context.Background()
+
+	workflowIDs := flags.Args()
+	if *query != "" {
+		executions, err := workflow.ListExecutions(ctx, c, *query, *limit)
+		if err != nil {
+			return err
+		}
+		for _, execution := range executions {
+			workflowIDs = append(workflowIDs, execution.WorkflowId)
+		}
+	}
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		return fmt.Errorf("unable to create %s: %w", *out, err)
+	}
+
+	for _, workflowID := range workflowIDs {
+		path := filepath.Join(*out, historyFileNameChar.ReplaceAllString(workflowID, "_")+".json")
+
+		if err := exportHistory(ctx, c, workflowID, path); err != nil {
+			return err
+		}
+
+		logger.Info("Exported workflow history", zap.String("workflowId", workflowID), zap.String("path", path))
+	}
+
+	return nil
+}
+
+func exportHistory(ctx context.Context, c client.Client, workflowID, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("unable to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	return workflow.ExportHistory(ctx, c, workflowID, "", file)
+}
@@ -9,9 +9,14 @@ import (
 	"context"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"go-test/internal/adapters"
+	"go-test/internal/auth"
+	"go-test/internal/calendar"
+	"go-test/internal/config"
 	"go-test/internal/controllers"
 	"go-test/internal/events"
 	"go-test/internal/handlers"
+	"go-test/internal/storage"
 	"go-test/internal/util"
 	"go-test/internal/workflow"
 	"go-test/repository"
@@ -31,23 +36,54 @@ func main() {
 	}
 	defer logger.Sync()
 
-	// TODO add config
-	repo, err := repository.NewRepository(&repository.ConnectionParams{
-		Host:     "localhost",
-		Port:     "5445",
-		User:     "postgres",
-		Password: "password",
-		DbName:   "test",
-		SSLMode:  "disable",
-		Logger:   logger,
-	})
+	cfg, err := config.Load(os.Getenv(config.PathEnv))
+	if err != nil {
+		logger.Fatal("Unable to load configuration", zap.Error(err))
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrateCommand(logger, os.Args[2:]); err != nil {
+			logger.Fatal("Migrate command failed", zap.Error(err))
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "histories" {
+		if err := runHistoriesCommand(logger, cfg, os.Args[2:]); err != nil {
+			logger.Fatal("Histories command failed", zap.Error(err))
+		}
+		return
+	}
+
+	repo, err := newRepository(logger)
 	if err != nil {
 		logger.Fatal("Unable to initialize repository", zap.Error(err))
 	}
 	defer repo.CloseConn()
 
-	if err := repo.Migrate(); err != nil {
-		logger.Fatal("Database migration failed", zap.Error(err))
+	if err := repo.EnsureMigrated(); err != nil {
+		logger.Fatal("Database schema is out of date, run \"migrate up\" first", zap.Error(err))
+	}
+
+	objectStore, err := storage.New(cfg.Storage)
+	if err != nil {
+		logger.Fatal("Unable to initialize object storage", zap.Error(err))
+	}
+
+	links, err := auth.NewConfirmationLinks(cfg.Auth, repo, logger)
+	if err != nil {
+		logger.Fatal("Unable to initialize confirmation links", zap.Error(err))
+	}
+	operators := auth.NewOperators(cfg.Auth.OperatorTokens)
+
+	deliveryCalendar, err := calendar.New(cfg.Calendar)
+	if err != nil {
+		logger.Fatal("Unable to initialize the delivery calendar", zap.Error(err))
+	}
+
+	webhooks, err := adapters.NewWebhooks(cfg.Webhooks, repo)
+	if err != nil {
+		logger.Fatal("Invalid webhook configuration", zap.Error(err))
 	}
 
 	c, err := createTemporalClient()
@@ -56,7 +92,12 @@ func main() {
 	}
 	defer c.Close()
 
+	if err := workflow.RegisterSearchAttributes(context.Background(), c, temporalNamespace); err != nil {
+		logger.Fatal("Unable to register the workflow search attributes", zap.Error(err))
+	}
+
 	producer := events.NewEventProducerConfig(logger).InitEventProducer(handlers.PackageDeliveryQueueName)
+	compensationProducer := events.NewEventProducerConfig(logger).InitEventProducer(handlers.PackageCompensationQueueName)
 	consumer := events.NewEventConsumerConfig(logger, c, workflow.PackageDeliveryTaskQueueName).InitEventConsumer(handlers.PackageDeliveryQueueName)
 
 	maxConcurrentActivityTaskPollers := 2
@@ -74,12 +115,28 @@ func main() {
 		WorkerStopTimeout:                      timeout,
 	}
 
+	if err := workflow.ApplyWorkerVersioning(&workerOptions, cfg.Worker); err != nil {
+		logger.Fatal("Invalid worker versioning configuration", zap.Error(err))
+	}
+
+	if err := workflow.RegisterBuildID(context.Background(), c, workflow.PackageDeliveryTaskQueueName, cfg.Worker); err != nil {
+		logger.Fatal("Unable to register the worker build ID", zap.Error(err))
+	}
+
 	w := worker.New(c, workflow.PackageDeliveryTaskQueueName, workerOptions)
 
-	workflow.SetupWorkflow(w, repo, logger)
+	workflow.SetupWorkflow(w, cfg.Workflow, repo, compensationProducer, links, workflow.NewVisibilityStuckPackageFinder(c), webhooks, logger)
+
+	if err := workflow.EnsureStuckPackageScanSchedule(context.Background(), c, cfg.Workflow.StuckDetection); err != nil {
+		logger.Fatal("Unable to schedule the stuck package scan", zap.Error(err))
+	}
+
+	if err := workflow.EnsureDeliveryReportSchedule(context.Background(), c, cfg.Workflow.DeliveryReport); err != nil {
+		logger.Fatal("Unable to schedule the delivery report", zap.Error(err))
+	}
 
 	ginRouter := gin.Default()
-	controllers.InitializeRoutes(logger, c, ginRouter, producer)
+	controllers.InitializeRoutes(logger, c, ginRouter, producer, repo, objectStore, operators, links, temporalNamespace, deliveryCalendar, webhooks.Breakers(), workflow.NewCustomerEmailHasher(cfg.Workflow.CustomerEmailHashSecret))
 
 	// TODO add config
 	server := &http.Server{
@@ -126,10 +183,25 @@ func main() {
 	<-wait
 }
 
+func newRepository(logger *zap.Logger) (*repository.Repository, error) {
+	// TODO add config
+	return repository.NewRepository(&repository.ConnectionParams{
+		Host:     "localhost",
+		Port:     "5445",
+		User:     "postgres",
+		Password: "password",
+		DbName:   "test",
+		SSLMode:  "disable",
+		Logger:   logger,
+	})
+}
+
+const temporalNamespace = "default"
+
 func createTemporalClient() (client.Client, error) {
 	temporalClient, err := client.NewLazyClient(client.Options{
 		HostPort:  "localhost:7233",
-		Namespace: "default",
+		Namespace: temporalNamespace,
 	})
 	return temporalClient, err
 }
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go-test/repository"
+	"go.uber.org/zap"
+	"os"
+	"text/tabwriter"
+)
+
+const migrateUsage = "usage: migrate up|down|status|create [-dir path] <name>"
+
+func runMigrateCommand(logger *zap.Logger, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	if args[0] == "create" {
+		flags := flag.NewFlagSet("migrate create", flag.ContinueOnError)
+		dir := flags.String("dir", repository.MigrationsDir, "directory to write the migration files to")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if flags.NArg() != 1 {
+			return errors.New(migrateUsage)
+		}
+
+		upPath, downPath, err := repository.CreateMigration(*dir, flags.Arg(0))
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stdout, "created %s\ncreated %s\n", upPath, downPath)
+		return nil
+	}
+
+	repo, err := newRepository(logger)
+	if err != nil {
+		return err
+	}
+	defer repo.CloseConn()
+
+	switch args[0] {
+	case "up":
+		return repo.MigrateUp()
+	case "down":
+		return repo.MigrateDown()
+	case "status":
+		statuses, err := repo.MigrationStatus()
+		if err != nil {
+			return err
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.Applied() {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			fmt.Fprintf(tw, "%04d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+
+		return tw.Flush()
+	default:
+		return errors.New(migrateUsage)
+	}
+}
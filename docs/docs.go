@@ -15,35 +15,105 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/packages": {
-            "post": {
-                "description": "Create a new package and start the delivery workflow",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/admin/audit": {
+            "get": {
+                "description": "List the actions operators took on a subject, oldest first. Notification templates are identified by\nevent/channel/locale, the delivery report schedule by its schedule ID, webhook subscriptions by their\nURL and customer preferences by the customer email. Requires an operator bearer token.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "packages"
+                    "admin"
                 ],
-                "summary": "Create a new delivery package",
+                "summary": "List the audit log of a subject",
                 "parameters": [
                     {
-                        "description": "Package details",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
+                        "enum": [
+                            "package",
+                            "notificationTemplate",
+                            "deliveryReportSchedule",
+                            "webhookSubscription",
+                            "customerPreferences"
+                        ],
+                        "type": "string",
+                        "description": "Subject type",
+                        "name": "subject_type",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subject ID",
+                        "name": "subject_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operator bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/packages.CreatePackageRequest"
+                            "$ref": "#/definitions/admin.ListSubjectAuditEntriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
                         }
                     }
+                }
+            }
+        },
+        "/api/v1/admin/packages/{id}/audit": {
+            "get": {
+                "description": "List the actions operators took on a package, oldest first. Requires an operator bearer token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List the audit log of a package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Package ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operator bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Package ID",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/packages.CreatePackageResponse"
+                            "$ref": "#/definitions/admin.ListAuditEntriesResponse"
                         }
                     },
                     "400": {
@@ -52,6 +122,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.HttpErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal error",
                         "schema": {
@@ -61,9 +137,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/packages/{id}": {
-            "get": {
-                "description": "Get details of a specific package delivery",
+        "/api/v1/admin/packages/{id}/retry": {
+            "post": {
+                "description": "Retry the step a package delivery is stuck on. A parked delivery is resolved with a retry, a\nfailed one is reset to run its last failed activity again. Requires an operator bearer token and\nthe package version the operator looked at as If-Match.",
                 "consumes": [
                     "application/json"
                 ],
@@ -71,9 +147,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "packages"
+                    "admin"
                 ],
-                "summary": "Get package details",
+                "summary": "Retry the failed step of a package delivery",
                 "parameters": [
                     {
                         "type": "string",
@@ -81,13 +157,36 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operator bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expected package version (ETag)",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.RemediationRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Retry accepted",
                         "schema": {
-                            "$ref": "#/definitions/model.DeliveryPackage"
+                            "$ref": "#/definitions/admin.RetryPackageResponse"
                         }
                     },
                     "400": {
@@ -96,18 +195,48 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.HttpErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Package not found",
                         "schema": {
                             "$ref": "#/definitions/model.HttpErrorResponse"
                         }
+                    },
+                    "409": {
+                        "description": "Nothing to retry",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Package has been modified",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Unable to retry",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/api/v1/packages/{id}/confirm": {
+        "/api/v1/admin/packages/{id}/terminate": {
             "post": {
-                "description": "Confirm the delivery of a package",
+                "description": "Terminate the running workflow of a package, leaving the package as it is. Requires an operator\nbearer token.",
                 "consumes": [
                     "application/json"
                 ],
@@ -115,9 +244,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "packages"
+                    "admin"
                 ],
-                "summary": "Confirm package delivery",
+                "summary": "Terminate a package delivery",
                 "parameters": [
                     {
                         "type": "string",
@@ -125,13 +254,29 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operator bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.RemediationRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Confirmation status",
+                        "description": "Workflow terminated",
                         "schema": {
-                            "$ref": "#/definitions/packages.ConfirmPackageResponse"
+                            "$ref": "#/definitions/admin.TerminatePackageResponse"
                         }
                     },
                     "400": {
@@ -140,64 +285,2881 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.HttpErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No running workflow for the package",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
                     "502": {
-                        "description": "Unable to confirm package",
+                        "description": "Unable to terminate",
                         "schema": {
                             "$ref": "#/definitions/model.HttpErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "model.DeliveryPackage": {
-            "type": "object",
-            "properties": {
-                "customer_email": {
-                    "type": "string"
-                },
-                "delivery_address": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                }
-            }
         },
-        "model.HttpErrorResponse": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "integer",
-                    "example": 400
-                },
-                "message": {
-                    "type": "string",
-                    "example": "Invalid input data"
+        "/api/v1/admin/packages/{id}/transition": {
+            "post": {
+                "description": "End the delivery of a package with the given status. A running workflow stores the status and\ncompletes once it next waits, the status of a closed one is stored directly. Packages with an open\ndispute must have it resolved instead. Requires an operator bearer token and the package version\nthe operator looked at as If-Match.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Force the status of a package delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Package ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operator bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expected package version (ETag)",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Status and reason",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.TransitionPackageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Transition accepted",
+                        "schema": {
+                            "$ref": "#/definitions/admin.TransitionPackageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Package not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Package has an open dispute",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Package has been modified",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header is required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Unable to force the status",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "model.PackageDeliveryState": {
-            "type": "string",
-            "enum": [
-                "inProgress",
-                "confirmed",
-                "confirmed",
-                "confirmed",
-                "errored"
-            ],
-            "x-enum-varnames": [
-                "PackageDeliveryInProgress",
-                "PackageDeliveryConfirmed",
-                "PackageDeliverySaved",
-                "PackageDeliveryNotified",
-                "PackageDeliveryErrored"
-            ]
-        },
-        "packages.ConfirmPackageResponse": {
-            "type": "object",
+        "/api/v1/admin/reports/delivery/schedule": {
+            "get": {
+                "description": "Return the time of day the daily delivery report runs at and its recipients. Requires an operator\nbearer token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get the delivery report schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Operator bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Report schedule",
+                        "schema": {
+                            "$ref": "#/definitions/workflow.DeliveryReportSchedule"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Report schedule not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Unable to read the report schedule",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Set the time of day, as HH:MM in the time zone, the daily delivery report runs at and the addresses\nit is sent to. The time zone is UTC when empty. Requires an operator bearer token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Change the delivery report schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Operator bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Report schedule",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.DeliveryReportScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated report schedule",
+                        "schema": {
+                            "$ref": "#/definitions/workflow.DeliveryReportSchedule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid report schedule",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Report schedule not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Unable to update the report schedule",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/templates": {
+            "get": {
+                "description": "Return the templates stored in the database, which override the templates embedded in the service.\nRequires an operator bearer token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List the stored notification templates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Operator bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stored templates",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.NotificationTemplate"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/templates/preview": {
+            "post": {
+                "description": "Render the template of an event, channel and locale with sample data. Without a body, the template the\nnotification would use is rendered, after falling back from the locale to its language and to English.\nWith a body, the draft is rendered instead. Requires an operator bearer token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Preview a notification template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Operator bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Template to preview",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.PreviewTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rendered message",
+                        "schema": {
+                            "$ref": "#/definitions/admin.PreviewTemplateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid template",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/templates/{event}/{channel}/{locale}": {
+            "put": {
+                "description": "Create or replace the template of an event, channel and locale. Templates use Go template syntax with\nthe variables .Package, .ConfirmationLink, .LinkExpiresAt, .DeliveryWindow, .Attempt, .NextAttemptAt\nand .Shipment, and the function local to convert a time to a time zone. Email bodies are HTML. The\ntemplate must render with sample data of its event. Requires an operator bearer token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Store a notification template",
+                "parameters": [
+                    {
+                        "enum": [
+                            "confirmation_request",
+                            "delivered",
+                            "arrival",
+                            "failed_attempt",
+                            "returned_to_sender",
+                            "shipment_delivered"
+                        ],
+                        "type": "string",
+                        "description": "Event",
+                        "name": "event",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "email",
+                            "sms",
+                            "push"
+                        ],
+                        "type": "string",
+                        "description": "Channel",
+                        "name": "channel",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Locale, such as en or de-AT",
+                        "name": "locale",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operator bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Template",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.NotificationTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stored template",
+                        "schema": {
+                            "$ref": "#/definitions/model.NotificationTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid template",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Notifications fall back to the next locale, and finally to the embedded template. Requires an operator\nbearer token.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete a stored notification template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event",
+                        "name": "event",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Channel",
+                        "name": "channel",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Locale",
+                        "name": "locale",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operator bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Template deleted"
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Template not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/webhooks/breakers": {
+            "get": {
+                "description": "Return the circuit breaker state and the calls in flight of every webhook destination called since the\nservice started. While a circuit is open, notifications to its destination are retried once probing\nstarts. Requires an operator bearer token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List the circuit breakers of webhook destinations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Operator bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Destination states",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/circuit.DestinationState"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/webhooks/subscriptions/disabled": {
+            "get": {
+                "description": "Return the webhook URLs disabled after they answered 410 Gone, with their destination and the start of\nthe response that disabled them. Notifications to a disabled URL fail without being sent or retried.\nRequires an operator bearer token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List the disabled webhook subscriptions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Operator bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Disabled subscriptions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.DisabledWebhookSubscription"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/webhooks/subscriptions/enable": {
+            "post": {
+                "description": "Send notifications to the URL again, once the partner restored its endpoint. A destination configured\nwith a new URL needs no enabling. Requires an operator bearer token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Enable a disabled webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Operator bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Disabled subscription",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.EnableWebhookSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Subscription enabled"
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Subscription is not disabled",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/workflows": {
+            "get": {
+                "description": "Run a Temporal visibility query over the package delivery workflows, filtered by the search\nattributes they publish. Requires an operator bearer token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Find package delivery workflows",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Operator bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Package status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Customer email, matched by its keyed hash",
+                        "name": "customer_email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Delivery region",
+                        "name": "region",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or after, RFC 3339",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created before, RFC 3339",
+                        "name": "created_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only running workflows",
+                        "name": "running",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Workflows per page, 50 by default",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token of the next page",
+                        "name": "page_token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Matching workflows",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ListWorkflowsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Unable to list workflows",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/confirm/{token}": {
+            "get": {
+                "description": "Check the token of the link sent to the customer and render the page confirming the delivery. The\ntoken is not used until the page is submitted.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "confirmations"
+                ],
+                "summary": "Open a confirmation link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Confirmation token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Confirmation page",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid confirmation token",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Confirmation token expired or already used",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to render the confirmation page",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Confirm the delivery with the single-use token of the link sent to the customer. Accepts the same\noptional proof of delivery as the package confirm endpoint.",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "confirmations"
+                ],
+                "summary": "Confirm package delivery with a confirmation link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Confirmation token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Proof of delivery",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/packages.ConfirmPackageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Confirmation status",
+                        "schema": {
+                            "$ref": "#/definitions/packages.ConfirmPackageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid confirmation token",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No running delivery workflow",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Package delivery is already confirmed or disputed",
+                        "schema": {
+                            "$ref": "#/definitions/packages.ConfirmPackageResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Confirmation token expired or already used",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Unable to confirm package",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/customers/{email}/preferences": {
+            "get": {
+                "description": "Requires an operator bearer token or an unused confirmation token of the customer.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Get the notification preferences of a customer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Customer email",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operator bearer token",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Confirmation token sent to the customer",
+                        "name": "X-Confirmation-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Preferences",
+                        "schema": {
+                            "$ref": "#/definitions/model.CustomerPreferences"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid email",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Preferences not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Confirmation token expired or already used",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Requires an operator bearer token or an unused confirmation token of the customer.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Replace the notification preferences of a customer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Customer email",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operator bearer token",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Confirmation token sent to the customer",
+                        "name": "X-Confirmation-Token",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Notification preferences",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/customers.CustomerPreferencesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated preferences",
+                        "schema": {
+                            "$ref": "#/definitions/model.CustomerPreferences"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid preferences",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Preferences not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Confirmation token expired or already used",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Set the channels, language, quiet hours and opt-outs of a customer. Notifications that fall within the\nquiet hours are deferred until they end. Opt-outs do not apply to legally required messages, such as the\nreturn of a package to its sender. Requires an operator bearer token or an unused confirmation token of the customer.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Create the notification preferences of a customer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Customer email",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operator bearer token",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Confirmation token sent to the customer",
+                        "name": "X-Confirmation-Token",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Notification preferences",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/customers.CustomerPreferencesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created preferences",
+                        "schema": {
+                            "$ref": "#/definitions/model.CustomerPreferences"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid preferences",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Preferences already exist",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Confirmation token expired or already used",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "The customer is notified with the default preferences afterwards. Requires an operator bearer token or an\nunused confirmation token of the customer.",
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Delete the notification preferences of a customer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Customer email",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operator bearer token",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Confirmation token sent to the customer",
+                        "name": "X-Confirmation-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Preferences deleted"
+                    },
+                    "400": {
+                        "description": "Invalid email",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Preferences not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Confirmation token expired or already used",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/packages": {
+            "post": {
+                "description": "Create a new package and start the delivery workflow. An optional delivery window must fall within the\nbusiness hours of a working day; the confirmation window opens shortly before it. Activity policies\noverride the configured retry and timeout policies of the named activities for this package only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packages"
+                ],
+                "summary": "Create a new delivery package",
+                "parameters": [
+                    {
+                        "description": "Package details",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/packages.CreatePackageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Package ID",
+                        "schema": {
+                            "$ref": "#/definitions/packages.CreatePackageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data, delivery window or activity policies",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/packages/{id}": {
+            "get": {
+                "description": "Get details of a specific package delivery",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packages"
+                ],
+                "summary": "Get package details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Package ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.DeliveryPackage"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current package version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Package not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/packages/{id}/attempts": {
+            "get": {
+                "description": "List the recorded delivery attempts of a package",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packages"
+                ],
+                "summary": "List delivery attempts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Package ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/packages.ListAttemptsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Report the outcome of a delivery attempt. A delivered attempt confirms the delivery, a failed one\nschedules a reattempt or, after too many failures, returns the package to the sender. Requires an\noperator bearer token, whose name is recorded as the driver.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packages"
+                ],
+                "summary": "Report a delivery attempt",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Package ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operator bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Attempt outcome",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/packages.RecordAttemptRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Attempt accepted",
+                        "schema": {
+                            "$ref": "#/definitions/packages.RecordAttemptResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No running delivery workflow",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Package delivery is already confirmed or disputed",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Unable to report attempt",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/packages/{id}/confirm": {
+            "post": {
+                "description": "Confirm the delivery of a package, optionally with a proof of delivery. The proof can also be sent\nas multipart/form-data with the fields recipient_name, latitude and longitude and the files\nsignature and photo. Requires an operator bearer token or the package's confirmation token. With\nIf-Match the package is only confirmed while it is still at that version.",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packages"
+                ],
+                "summary": "Confirm package delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Package ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operator bearer token",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Confirmation token sent to the customer",
+                        "name": "X-Confirmation-Token",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expected package version (ETag)",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Proof of delivery",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/packages.ConfirmPackageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Confirmation status",
+                        "schema": {
+                            "$ref": "#/definitions/packages.ConfirmPackageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No running delivery workflow",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Package delivery is already confirmed or disputed",
+                        "schema": {
+                            "$ref": "#/definitions/packages.ConfirmPackageResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Confirmation token expired or already used",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Package has been modified",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Unable to confirm package",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/packages/{id}/dispute": {
+            "post": {
+                "description": "Report a package as not received or damaged instead of confirming it. Support is notified and the\ndelivery waits for an operator to resolve the dispute. Requires an operator bearer token or the\npackage's confirmation token, which is consumed once the dispute is accepted. The response carries\nthe ID of the dispute, which its resolution refers to.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packages"
+                ],
+                "summary": "Dispute package delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Package ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operator bearer token",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Confirmation token sent to the customer",
+                        "name": "X-Confirmation-Token",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Dispute",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/packages.DisputePackageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Dispute accepted",
+                        "schema": {
+                            "$ref": "#/definitions/packages.DisputePackageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No running delivery workflow",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Package delivery is already confirmed or disputed",
+                        "schema": {
+                            "$ref": "#/definitions/packages.DisputePackageResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Confirmation token expired or already used",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Unable to dispute package",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/packages/{id}/dispute/resolution": {
+            "post": {
+                "description": "Resolve the open dispute of a package. redeliver sends the package again and waits for a new\nconfirmation, refund and close end the delivery. Requires an operator bearer token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packages"
+                ],
+                "summary": "Resolve a package dispute",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Package ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operator bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Resolution",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/packages.ResolveDisputeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Resolution accepted",
+                        "schema": {
+                            "$ref": "#/definitions/packages.ResolveDisputeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No open dispute for the package",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Unable to resolve dispute",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/packages/{id}/proof": {
+            "get": {
+                "description": "Get the proof of delivery sent with the package confirmation. Requires an operator bearer token or\nthe package's confirmation token, as long as it has not been used.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packages"
+                ],
+                "summary": "Get proof of delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Package ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operator bearer token",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Confirmation token sent to the customer",
+                        "name": "X-Confirmation-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/packages.GetProofResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No proof of delivery for the package",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Confirmation token expired or already used",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to read proof of delivery",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/packages/{id}/proof/{item}": {
+            "get": {
+                "description": "Download the signature or photo of the proof of delivery. Requires an operator bearer token or the\npackage's confirmation token, as long as it has not been used.",
+                "produces": [
+                    "image/png",
+                    "image/jpeg",
+                    "image/webp"
+                ],
+                "tags": [
+                    "packages"
+                ],
+                "summary": "Get proof of delivery image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Package ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "signature",
+                            "photo"
+                        ],
+                        "type": "string",
+                        "description": "Proof item",
+                        "name": "item",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operator bearer token",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Confirmation token sent to the customer",
+                        "name": "X-Confirmation-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Proof item not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Confirmation token expired or already used",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to read proof of delivery",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/shipments": {
+            "post": {
+                "description": "Create a shipment of several parcels for one order and start its delivery. Every parcel is delivered\nas a package of its own, the customer gets one confirmation link and one notification for the shipment.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shipments"
+                ],
+                "summary": "Create a new shipment",
+                "parameters": [
+                    {
+                        "description": "Shipment details",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/packages.CreateShipmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Shipment and package IDs",
+                        "schema": {
+                            "$ref": "#/definitions/packages.CreateShipmentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/shipments/{id}": {
+            "get": {
+                "description": "Get the aggregated state of a shipment and the state of each of its parcels",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shipments"
+                ],
+                "summary": "Get shipment details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Shipment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/workflow.ShipmentWorkflowResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Shipment not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/shipments/{id}/confirm": {
+            "post": {
+                "description": "Confirm the delivery of every parcel of a shipment that is not confirmed yet, optionally with a proof\nof delivery that applies to all of them. Parcels with an open dispute stay disputed. Requires an\noperator bearer token or the shipment's confirmation token.",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shipments"
+                ],
+                "summary": "Confirm all parcels of a shipment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Shipment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operator bearer token",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Confirmation token sent to the customer",
+                        "name": "X-Confirmation-Token",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Proof of delivery",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/packages.ConfirmPackageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Confirmation status",
+                        "schema": {
+                            "$ref": "#/definitions/packages.ConfirmPackageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No running delivery workflow",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Shipment is already confirmed",
+                        "schema": {
+                            "$ref": "#/definitions/packages.ConfirmPackageResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Confirmation token expired or already used",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Unable to confirm shipment",
+                        "schema": {
+                            "$ref": "#/definitions/model.HttpErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "admin.DeliveryReportScheduleRequest": {
+            "type": "object",
+            "required": [
+                "time"
+            ],
+            "properties": {
+                "recipients": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "time": {
+                    "type": "string",
+                    "example": "06:00"
+                },
+                "time_zone": {
+                    "type": "string",
+                    "example": "Europe/Berlin"
+                }
+            }
+        },
+        "admin.EnableWebhookSubscriptionRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "url": {
+                    "type": "string",
+                    "example": "https://partner.example.com/hooks/notifications"
+                }
+            }
+        },
+        "admin.ListAuditEntriesResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AuditEntry"
+                    }
+                },
+                "package_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin.ListSubjectAuditEntriesResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AuditEntry"
+                    }
+                },
+                "subject_id": {
+                    "type": "string"
+                },
+                "subject_type": {
+                    "$ref": "#/definitions/model.AuditSubjectType"
+                }
+            }
+        },
+        "admin.ListWorkflowsResponse": {
+            "type": "object",
+            "properties": {
+                "next_page_token": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "workflows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/workflow.ExecutionSummary"
+                    }
+                }
+            }
+        },
+        "admin.NotificationTemplateRequest": {
+            "type": "object",
+            "required": [
+                "body"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "example": "Package {{.Package.ID}} was delivered to {{.Package.DeliveryAddress}}."
+                },
+                "subject": {
+                    "type": "string",
+                    "example": "Package {{.Package.ID}} was delivered"
+                }
+            }
+        },
+        "admin.PreviewTemplateRequest": {
+            "type": "object",
+            "required": [
+                "channel",
+                "event"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "channel": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.NotificationChannel"
+                        }
+                    ],
+                    "example": "sms"
+                },
+                "event": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.NotificationKind"
+                        }
+                    ],
+                    "example": "delivered"
+                },
+                "locale": {
+                    "type": "string",
+                    "example": "de-AT"
+                },
+                "subject": {
+                    "description": "Body previews a draft instead of the template in use.",
+                    "type": "string"
+                }
+            }
+        },
+        "admin.PreviewTemplateResponse": {
+            "type": "object",
+            "properties": {
+                "locale": {
+                    "description": "Locale is the locale of the template used after the fallback rules.",
+                    "type": "string",
+                    "example": "de"
+                },
+                "message": {
+                    "$ref": "#/definitions/model.NotificationMessage"
+                },
+                "source": {
+                    "description": "Source is database, embedded or draft.",
+                    "type": "string",
+                    "example": "embedded"
+                }
+            }
+        },
+        "admin.RemediationRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin.RetryPackageResponse": {
+            "type": "object",
+            "properties": {
+                "package_id": {
+                    "type": "string"
+                },
+                "reset": {
+                    "description": "Reset is set when the failed workflow was reset to a new run, and\nunset when a parked workflow was asked to retry the step.",
+                    "type": "boolean"
+                },
+                "run_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin.TerminatePackageResponse": {
+            "type": "object",
+            "properties": {
+                "package_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "admin.TransitionPackageRequest": {
+            "type": "object",
+            "required": [
+                "reason",
+                "status"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "enum": [
+                        "confirmed",
+                        "returnedToSender",
+                        "refunded",
+                        "disputeClosed",
+                        "errored"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.PackageDeliveryState"
+                        }
+                    ]
+                }
+            }
+        },
+        "admin.TransitionPackageResponse": {
+            "type": "object",
+            "properties": {
+                "package_id": {
+                    "type": "string"
+                },
+                "running": {
+                    "description": "Running is set when the running workflow was asked to end with the\nstatus, and unset when the status of a closed one was stored.",
+                    "type": "boolean"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.PackageDeliveryState"
+                }
+            }
+        },
+        "circuit.DestinationState": {
+            "type": "object",
+            "properties": {
+                "consecutive_failures": {
+                    "type": "integer"
+                },
+                "destination": {
+                    "type": "string",
+                    "example": "webhook.site"
+                },
+                "in_flight": {
+                    "type": "integer"
+                },
+                "max_concurrent": {
+                    "type": "integer"
+                },
+                "opened_at": {
+                    "description": "OpenedAt is when the circuit last opened, unset while it is closed.",
+                    "type": "string"
+                },
+                "probe_at": {
+                    "description": "ProbeAt is when an open circuit lets probe calls through.",
+                    "type": "string"
+                },
+                "state": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/circuit.State"
+                        }
+                    ],
+                    "example": "open"
+                }
+            }
+        },
+        "circuit.State": {
+            "type": "string",
+            "enum": [
+                "closed",
+                "open",
+                "half_open"
+            ],
+            "x-enum-varnames": [
+                "StateClosed",
+                "StateOpen",
+                "StateHalfOpen"
+            ]
+        },
+        "config.ActivityPolicy": {
+            "type": "object",
+            "properties": {
+                "backoff_coefficient": {
+                    "type": "number"
+                },
+                "heartbeat_timeout": {
+                    "type": "string",
+                    "example": "30s"
+                },
+                "initial_interval": {
+                    "type": "string",
+                    "example": "30s"
+                },
+                "maximum_attempts": {
+                    "type": "integer"
+                },
+                "maximum_interval": {
+                    "type": "string",
+                    "example": "30s"
+                },
+                "non_retryable_error_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "schedule_to_close_timeout": {
+                    "type": "string",
+                    "example": "30s"
+                },
+                "start_to_close_timeout": {
+                    "type": "string",
+                    "example": "30s"
+                }
+            }
+        },
+        "customers.CustomerPreferencesRequest": {
+            "type": "object",
+            "properties": {
+                "channels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.NotificationChannel"
+                    },
+                    "example": [
+                        "email",
+                        "sms"
+                    ]
+                },
+                "language": {
+                    "type": "string",
+                    "example": "de-AT"
+                },
+                "opt_out_all": {
+                    "type": "boolean"
+                },
+                "opt_out_arrival": {
+                    "type": "boolean"
+                },
+                "opt_out_delivered": {
+                    "type": "boolean"
+                },
+                "opt_out_failed_attempt": {
+                    "type": "boolean"
+                },
+                "quiet_hours_end": {
+                    "type": "string",
+                    "example": "07:00"
+                },
+                "quiet_hours_start": {
+                    "type": "string",
+                    "example": "22:00"
+                },
+                "time_zone": {
+                    "type": "string",
+                    "example": "Europe/Vienna"
+                }
+            }
+        },
+        "model.AttemptOutcome": {
+            "type": "string",
+            "enum": [
+                "delivered",
+                "nobodyHome",
+                "refused",
+                "addressInvalid"
+            ],
+            "x-enum-varnames": [
+                "AttemptDelivered",
+                "AttemptNobodyHome",
+                "AttemptRefused",
+                "AttemptAddressInvalid"
+            ]
+        },
+        "model.AuditAction": {
+            "type": "string",
+            "enum": [
+                "confirm",
+                "dispute",
+                "resolveDispute",
+                "recordAttempt",
+                "retry",
+                "forceTransition",
+                "terminate",
+                "saveTemplate",
+                "deleteTemplate",
+                "updateSchedule",
+                "enableWebhook",
+                "createPreferences",
+                "updatePreferences",
+                "deletePreferences"
+            ],
+            "x-enum-varnames": [
+                "AuditConfirm",
+                "AuditDispute",
+                "AuditResolveDispute",
+                "AuditRecordAttempt",
+                "AuditRetry",
+                "AuditForceTransition",
+                "AuditTerminate",
+                "AuditSaveTemplate",
+                "AuditDeleteTemplate",
+                "AuditUpdateSchedule",
+                "AuditEnableWebhook",
+                "AuditCreatePreferences",
+                "AuditUpdatePreferences",
+                "AuditDeletePreferences"
+            ]
+        },
+        "model.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/model.AuditAction"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "detail": {
+                    "description": "Detail describes what the action did, such as the status a package\nwas forced into.",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "operator": {
+                    "type": "string"
+                },
+                "package_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "subject_id": {
+                    "type": "string"
+                },
+                "subject_type": {
+                    "$ref": "#/definitions/model.AuditSubjectType"
+                }
+            }
+        },
+        "model.AuditSubjectType": {
+            "type": "string",
+            "enum": [
+                "package",
+                "notificationTemplate",
+                "deliveryReportSchedule",
+                "webhookSubscription",
+                "customerPreferences"
+            ],
+            "x-enum-varnames": [
+                "AuditSubjectPackage",
+                "AuditSubjectNotificationTemplate",
+                "AuditSubjectDeliveryReportSchedule",
+                "AuditSubjectWebhookSubscription",
+                "AuditSubjectCustomerPreferences"
+            ]
+        },
+        "model.ConfirmationChannel": {
+            "type": "string",
+            "enum": [
+                "api",
+                "link",
+                "driver"
+            ],
+            "x-enum-varnames": [
+                "ConfirmationChannelAPI",
+                "ConfirmationChannelLink",
+                "ConfirmationChannelDriver"
+            ]
+        },
+        "model.CustomerPreferences": {
+            "type": "object",
+            "properties": {
+                "channels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.NotificationChannel"
+                    }
+                },
+                "email": {
+                    "type": "string"
+                },
+                "language": {
+                    "description": "Language is a language tag such as \"en\" or \"de-AT\".",
+                    "type": "string"
+                },
+                "opt_out_all": {
+                    "description": "OptOutAll opts out of every message that is not legally required.",
+                    "type": "boolean"
+                },
+                "opt_out_arrival": {
+                    "type": "boolean"
+                },
+                "opt_out_delivered": {
+                    "type": "boolean"
+                },
+                "opt_out_failed_attempt": {
+                    "type": "boolean"
+                },
+                "quiet_hours_end": {
+                    "type": "string"
+                },
+                "quiet_hours_start": {
+                    "description": "QuietHoursStart and QuietHoursEnd are HH:MM in TimeZone, both empty\nwhen the customer has no quiet hours. Quiet hours may span midnight.",
+                    "type": "string"
+                },
+                "time_zone": {
+                    "description": "TimeZone is the IANA time zone the quiet hours are in.",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.DeliveryAttempt": {
+            "type": "object",
+            "properties": {
+                "attempted_at": {
+                    "type": "string"
+                },
+                "driver": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "number": {
+                    "description": "Number is assigned by the workflow, starting at 1.",
+                    "type": "integer"
+                },
+                "outcome": {
+                    "$ref": "#/definitions/model.AttemptOutcome"
+                }
+            }
+        },
+        "model.DeliveryConfirmation": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "$ref": "#/definitions/model.ConfirmationChannel"
+                },
+                "confirmed_at": {
+                    "type": "string"
+                },
+                "confirmed_by": {
+                    "type": "string"
+                },
+                "proof": {
+                    "$ref": "#/definitions/model.ProofOfDelivery"
+                }
+            }
+        },
+        "model.DeliveryPackage": {
+            "type": "object",
+            "properties": {
+                "customer_email": {
+                    "type": "string"
+                },
+                "delivery_address": {
+                    "type": "string"
+                },
+                "delivery_window": {
+                    "description": "DeliveryWindow is carried by the delivery workflow and not stored.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.DeliveryWindow"
+                        }
+                    ]
+                },
+                "id": {
+                    "type": "string"
+                },
+                "proof": {
+                    "$ref": "#/definitions/model.ProofOfDelivery"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.PackageDeliveryState"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.DeliveryWindow": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "time_zone": {
+                    "description": "TimeZone is the IANA time zone the customer chose the window in, used\nto tell them about it in their local time.",
+                    "type": "string"
+                }
+            }
+        },
+        "model.DisabledWebhookSubscription": {
+            "type": "object",
+            "properties": {
+                "destination": {
+                    "description": "Destination is the destination the URL was configured for when it\nanswered.",
+                    "type": "string",
+                    "example": "notifications"
+                },
+                "disabled_at": {
+                    "type": "string"
+                },
+                "response_excerpt": {
+                    "description": "ResponseExcerpt is the start of the body of the response that\ndisabled the subscription.",
+                    "type": "string"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 410
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://partner.example.com/hooks/notifications"
+                }
+            }
+        },
+        "model.Dispute": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "$ref": "#/definitions/model.DisputeCategory"
+                },
+                "channel": {
+                    "$ref": "#/definitions/model.ConfirmationChannel"
+                },
+                "id": {
+                    "description": "ID is assigned by the workflow when the dispute is accepted.",
+                    "type": "string"
+                },
+                "raised_at": {
+                    "type": "string"
+                },
+                "raised_by": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.DisputeAction": {
+            "type": "string",
+            "enum": [
+                "redeliver",
+                "refund",
+                "close"
+            ],
+            "x-enum-varnames": [
+                "DisputeRedeliver",
+                "DisputeRefund",
+                "DisputeClose"
+            ]
+        },
+        "model.DisputeCategory": {
+            "type": "string",
+            "enum": [
+                "notReceived",
+                "damaged",
+                "other"
+            ],
+            "x-enum-varnames": [
+                "DisputeNotReceived",
+                "DisputeDamaged",
+                "DisputeOther"
+            ]
+        },
+        "model.DisputeResolution": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/model.DisputeAction"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "resolved_at": {
+                    "type": "string"
+                },
+                "resolved_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.GeoLocation": {
+            "type": "object",
+            "properties": {
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.HttpErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Invalid input data"
+                }
+            }
+        },
+        "model.NotificationChannel": {
+            "type": "string",
+            "enum": [
+                "email",
+                "sms",
+                "push"
+            ],
+            "x-enum-varnames": [
+                "NotificationChannelEmail",
+                "NotificationChannelSMS",
+                "NotificationChannelPush"
+            ]
+        },
+        "model.NotificationKind": {
+            "type": "string",
+            "enum": [
+                "confirmation_request",
+                "delivered",
+                "arrival",
+                "failed_attempt",
+                "returned_to_sender",
+                "shipment_delivered"
+            ],
+            "x-enum-varnames": [
+                "NotificationConfirmationRequest",
+                "NotificationDelivered",
+                "NotificationArrival",
+                "NotificationFailedAttempt",
+                "NotificationReturnedToSender",
+                "NotificationShipmentDelivered"
+            ]
+        },
+        "model.NotificationMessage": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "channel": {
+                    "$ref": "#/definitions/model.NotificationChannel"
+                },
+                "locale": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.NotificationTemplate": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "channel": {
+                    "$ref": "#/definitions/model.NotificationChannel"
+                },
+                "event": {
+                    "$ref": "#/definitions/model.NotificationKind"
+                },
+                "locale": {
+                    "type": "string"
+                },
+                "subject": {
+                    "description": "Subject is only used by email templates.",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ObjectReference": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.PackageDeliveryAttempt": {
+            "type": "object",
+            "properties": {
+                "attempted_at": {
+                    "type": "string"
+                },
+                "driver": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "outcome": {
+                    "$ref": "#/definitions/model.AttemptOutcome"
+                },
+                "package_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PackageDeliveryState": {
+            "type": "string",
+            "enum": [
+                "inProgress",
+                "scheduled",
+                "confirmed",
+                "confirmed",
+                "confirmed",
+                "errored",
+                "notificationFailed",
+                "rolledBack",
+                "parked",
+                "disputed",
+                "redelivery",
+                "refunded",
+                "disputeClosed",
+                "attemptFailed",
+                "returnedToSender"
+            ],
+            "x-enum-varnames": [
+                "PackageDeliveryInProgress",
+                "PackageDeliveryScheduled",
+                "PackageDeliveryConfirmed",
+                "PackageDeliverySaved",
+                "PackageDeliveryNotified",
+                "PackageDeliveryErrored",
+                "PackageDeliveryNotificationFailed",
+                "PackageDeliveryRolledBack",
+                "PackageDeliveryParked",
+                "PackageDeliveryDisputed",
+                "PackageDeliveryRedelivery",
+                "PackageDeliveryRefunded",
+                "PackageDeliveryDisputeClosed",
+                "PackageDeliveryAttemptFailed",
+                "PackageDeliveryReturnedToSender"
+            ]
+        },
+        "model.ProofOfDelivery": {
+            "type": "object",
+            "properties": {
+                "location": {
+                    "$ref": "#/definitions/model.GeoLocation"
+                },
+                "photo": {
+                    "$ref": "#/definitions/model.ObjectReference"
+                },
+                "recipient_name": {
+                    "type": "string"
+                },
+                "signature": {
+                    "$ref": "#/definitions/model.ObjectReference"
+                }
+            }
+        },
+        "model.ShipmentParcel": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "type": "boolean"
+                },
+                "confirmation": {
+                    "$ref": "#/definitions/model.DeliveryConfirmation"
+                },
+                "package_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.PackageDeliveryState"
+                }
+            }
+        },
+        "model.ShipmentState": {
+            "type": "string",
+            "enum": [
+                "inProgress",
+                "partiallyConfirmed",
+                "confirmed",
+                "completed",
+                "completedWithIssues"
+            ],
+            "x-enum-varnames": [
+                "ShipmentInProgress",
+                "ShipmentPartiallyConfirmed",
+                "ShipmentConfirmed",
+                "ShipmentCompleted",
+                "ShipmentCompletedWithIssues"
+            ]
+        },
+        "packages.ConfirmPackageRequest": {
+            "type": "object",
+            "properties": {
+                "location": {
+                    "$ref": "#/definitions/model.GeoLocation"
+                },
+                "photo": {
+                    "type": "string",
+                    "format": "base64"
+                },
+                "recipient_name": {
+                    "type": "string"
+                },
+                "signature": {
+                    "type": "string",
+                    "format": "base64"
+                }
+            }
+        },
+        "packages.ConfirmPackageResponse": {
+            "type": "object",
             "properties": {
+                "confirmation": {
+                    "$ref": "#/definitions/model.DeliveryConfirmation"
+                },
                 "status": {
                     "$ref": "#/definitions/model.PackageDeliveryState"
                 }
@@ -210,11 +3172,24 @@ const docTemplate = `{
                 "delivery_address"
             ],
             "properties": {
+                "activity_policies": {
+                    "description": "ActivityPolicies overrides the configured retry and timeout policies\nof activities for this package only, keyed by activity name.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/config.ActivityPolicy"
+                    }
+                },
                 "customer_email": {
                     "type": "string"
                 },
                 "delivery_address": {
                     "type": "string"
+                },
+                "delivery_window": {
+                    "$ref": "#/definitions/packages.DeliveryWindowRequest"
+                },
+                "region": {
+                    "type": "string"
                 }
             }
         },
@@ -225,6 +3200,271 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "packages.CreateShipmentRequest": {
+            "type": "object",
+            "required": [
+                "customer_email",
+                "delivery_address",
+                "parcels"
+            ],
+            "properties": {
+                "customer_email": {
+                    "type": "string"
+                },
+                "delivery_address": {
+                    "type": "string"
+                },
+                "parcels": {
+                    "type": "integer",
+                    "maximum": 50,
+                    "minimum": 1
+                },
+                "region": {
+                    "type": "string"
+                }
+            }
+        },
+        "packages.CreateShipmentResponse": {
+            "type": "object",
+            "properties": {
+                "packageIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "shipmentId": {
+                    "type": "string"
+                }
+            }
+        },
+        "packages.DeliveryWindowRequest": {
+            "type": "object",
+            "required": [
+                "end",
+                "start",
+                "time_zone"
+            ],
+            "properties": {
+                "end": {
+                    "type": "string",
+                    "example": "2024-05-14T16:00"
+                },
+                "start": {
+                    "type": "string",
+                    "example": "2024-05-14T14:00"
+                },
+                "time_zone": {
+                    "type": "string",
+                    "example": "Europe/Berlin"
+                }
+            }
+        },
+        "packages.DisputePackageRequest": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "enum": [
+                        "notReceived",
+                        "damaged",
+                        "other"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.DisputeCategory"
+                        }
+                    ]
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "packages.DisputePackageResponse": {
+            "type": "object",
+            "properties": {
+                "dispute": {
+                    "$ref": "#/definitions/model.Dispute"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.PackageDeliveryState"
+                }
+            }
+        },
+        "packages.GetProofResponse": {
+            "type": "object",
+            "properties": {
+                "links": {
+                    "description": "Links points to the stored images, keyed by proof item.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "proof": {
+                    "$ref": "#/definitions/model.ProofOfDelivery"
+                }
+            }
+        },
+        "packages.ListAttemptsResponse": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PackageDeliveryAttempt"
+                    }
+                }
+            }
+        },
+        "packages.RecordAttemptRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                },
+                "outcome": {
+                    "enum": [
+                        "delivered",
+                        "nobodyHome",
+                        "refused",
+                        "addressInvalid"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.AttemptOutcome"
+                        }
+                    ]
+                }
+            }
+        },
+        "packages.RecordAttemptResponse": {
+            "type": "object",
+            "properties": {
+                "attempt": {
+                    "$ref": "#/definitions/model.DeliveryAttempt"
+                }
+            }
+        },
+        "packages.ResolveDisputeRequest": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "enum": [
+                        "redeliver",
+                        "refund",
+                        "close"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.DisputeAction"
+                        }
+                    ]
+                },
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "packages.ResolveDisputeResponse": {
+            "type": "object",
+            "properties": {
+                "dispute_id": {
+                    "type": "string"
+                },
+                "resolution": {
+                    "$ref": "#/definitions/model.DisputeResolution"
+                }
+            }
+        },
+        "workflow.DeliveryReportSchedule": {
+            "type": "object",
+            "properties": {
+                "recipients": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "time": {
+                    "description": "Time is the time of day the report runs at, as HH:MM in TimeZone.",
+                    "type": "string"
+                },
+                "time_zone": {
+                    "description": "TimeZone is an IANA time zone name.",
+                    "type": "string"
+                }
+            }
+        },
+        "workflow.ExecutionSummary": {
+            "type": "object",
+            "properties": {
+                "close_time": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "execution_status": {
+                    "type": "string"
+                },
+                "package_status": {
+                    "$ref": "#/definitions/model.PackageDeliveryState"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "run_id": {
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "status_changed_at": {
+                    "type": "string"
+                },
+                "workflow_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "workflow.ShipmentWorkflowResult": {
+            "type": "object",
+            "properties": {
+                "confirmation": {
+                    "description": "Confirmation is the confirmation of the whole shipment, if any.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.DeliveryConfirmation"
+                        }
+                    ]
+                },
+                "customer_email": {
+                    "type": "string"
+                },
+                "delivery_address": {
+                    "type": "string"
+                },
+                "duplicateConfirmations": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "parcels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ShipmentParcel"
+                    }
+                },
+                "region": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.ShipmentState"
+                }
+            }
         }
     }
 }`
@@ -0,0 +1,107 @@
+package activities
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-test/internal/model"
+	"go-test/repository"
+	"go.temporal.io/sdk/activity"
+	"go.uber.org/zap"
+)
+
+const (
+	MarkNotificationFailedActivityName   = "mark-notification-failed-activity"
+	RollbackDeliveryActivityName         = "rollback-delivery-activity"
+	PublishCompensationEventActivityName = "publish-compensation-event-activity"
+)
+
+type EventSender interface {
+	SendEvent(message string) error
+}
+
+type CompensateDelivery struct {
+	Repo   repository.PackageStore
+	Events EventSender
+	Logger *zap.Logger
+}
+
+type CompensateDeliveryInput struct {
+	PackageID string
+}
+
+type PublishCompensationEventInput struct {
+	Event *model.CompensationEvent
+}
+
+func NewCompensateDelivery(repo repository.PackageStore, events EventSender, logger *zap.Logger) *CompensateDelivery {
+	return &CompensateDelivery{Repo: repo, Events: events, Logger: logger}
+}
+
+// MarkNotificationFailedActivity flags the saved package so it no longer
+// claims a confirmed delivery. Version conflicts are retried by Temporal,
+// re-reading the row on the next attempt.
+func (c *CompensateDelivery) MarkNotificationFailedActivity(ctx context.Context, input *CompensateDeliveryInput) (*model.DeliveryPackage, error) {
+	attempt := int(activity.GetInfo(ctx).Attempt)
+
+	c.Logger.Info("Starting mark notification failed activity", zap.Int("attempt", attempt), zap.String("packageId", input.PackageID))
+
+	stored, err := c.Repo.GetPackageDelivery(ctx, input.PackageID)
+	if err != nil {
+		c.Logger.Error("Failed to read delivery package", zap.Error(err), zap.String("packageId", input.PackageID))
+		return nil, err
+	}
+
+	if stored.Status == model.PackageDeliveryNotificationFailed {
+		return stored, nil
+	}
+
+	changed := *stored
+	changed.Status = model.PackageDeliveryNotificationFailed
+
+	updated, err := c.Repo.UpdatePackageDelivery(ctx, &changed, stored.Version)
+	if err != nil {
+		c.Logger.Error("Failed to mark delivery package as notification failed", zap.Error(err), zap.String("packageId", input.PackageID))
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (c *CompensateDelivery) RollbackDeliveryActivity(ctx context.Context, input *CompensateDeliveryInput) error {
+	attempt := int(activity.GetInfo(ctx).Attempt)
+
+	c.Logger.Info("Starting rollback delivery activity", zap.Int("attempt", attempt), zap.String("packageId", input.PackageID))
+
+	stored, err := c.Repo.GetPackageDelivery(ctx, input.PackageID)
+	if errors.Is(err, repository.ErrPackageNotFound) {
+		return nil
+	}
+	if err != nil {
+		c.Logger.Error("Failed to read delivery package", zap.Error(err), zap.String("packageId", input.PackageID))
+		return err
+	}
+
+	err = c.Repo.DeletePackageDelivery(ctx, input.PackageID, stored.Version)
+	if err != nil && !errors.Is(err, repository.ErrPackageNotFound) {
+		c.Logger.Error("Failed to roll back delivery package", zap.Error(err), zap.String("packageId", input.PackageID))
+		return err
+	}
+
+	return nil
+}
+
+func (c *CompensateDelivery) PublishCompensationEventActivity(ctx context.Context, input *PublishCompensationEventInput) error {
+	event, err := json.Marshal(input.Event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal compensation event: %w", err)
+	}
+
+	if err := c.Events.SendEvent(string(event)); err != nil {
+		c.Logger.Error("Failed to publish compensation event", zap.Error(err), zap.String("packageId", input.Event.PackageID))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package activities
+
+import (
+	"context"
+	"go-test/internal/model"
+	"go-test/repository"
+	"go.temporal.io/sdk/activity"
+	"go.uber.org/zap"
+)
+
+const LoadCustomerPreferencesActivityName = "load-customer-preferences-activity"
+
+type CustomerPreferences struct {
+	Store  repository.CustomerPreferencesStore
+	Logger *zap.Logger
+}
+
+type LoadCustomerPreferencesInput struct {
+	Email string
+}
+
+func NewCustomerPreferences(store repository.CustomerPreferencesStore, logger *zap.Logger) *CustomerPreferences {
+	return &CustomerPreferences{Store: store, Logger: logger}
+}
+
+// LoadCustomerPreferencesActivity returns the notification preferences of
+// the customer, or the defaults when they have not set any.
+func (c *CustomerPreferences) LoadCustomerPreferencesActivity(ctx context.Context, input *LoadCustomerPreferencesInput) (*model.CustomerPreferences, error) {
+	attempt := int(activity.GetInfo(ctx).Attempt)
+
+	c.Logger.Info("Starting load customer preferences activity", zap.Int("attempt", attempt))
+
+	preferences, err := loadCustomerPreferences(ctx, c.Store, input.Email)
+	if err != nil {
+		c.Logger.Error("Failed to load customer preferences", zap.Error(err))
+		return nil, err
+	}
+
+	return preferences, nil
+}
@@ -0,0 +1,117 @@
+package activities
+
+import (
+	"context"
+	"errors"
+	"go-test/internal/adapters"
+	"go-test/internal/model"
+	"go-test/internal/templates"
+	"go-test/repository"
+	"go.temporal.io/sdk/activity"
+	"go.uber.org/zap"
+)
+
+const (
+	RecordAttemptActivityName       = "record-delivery-attempt-activity"
+	NotifyFailedAttemptActivityName = "notify-failed-attempt-activity"
+	ReturnToSenderActivityName      = "return-to-sender-activity"
+)
+
+type DeliveryAttempts struct {
+	Attempts repository.DeliveryAttemptStore
+	Packages repository.PackageStore
+	Renderer NotificationRenderer
+	Webhooks *adapters.Webhooks
+	Logger   *zap.Logger
+}
+
+type RecordAttemptInput struct {
+	PackageID string
+	Attempt   model.DeliveryAttempt
+}
+
+type NotifyFailedAttemptInput struct {
+	Notification model.FailedAttemptNotification
+}
+
+type ReturnToSenderInput struct {
+	DeliveryPackage *model.DeliveryPackage
+}
+
+func NewDeliveryAttempts(attempts repository.DeliveryAttemptStore, packages repository.PackageStore, renderer NotificationRenderer, webhooks *adapters.Webhooks, logger *zap.Logger) *DeliveryAttempts {
+	return &DeliveryAttempts{Attempts: attempts, Packages: packages, Renderer: renderer, Webhooks: webhooks, Logger: logger}
+}
+
+// RecordAttemptActivity persists an attempt. It is idempotent, as attempts
+// are numbered by the workflow.
+func (d *DeliveryAttempts) RecordAttemptActivity(ctx context.Context, input *RecordAttemptInput) error {
+	attempt := int(activity.GetInfo(ctx).Attempt)
+
+	d.Logger.Info("Starting record delivery attempt activity", zap.Int("attempt", attempt), zap.String("packageId", input.PackageID), zap.Int("number", input.Attempt.Number))
+
+	err := d.Attempts.RecordDeliveryAttempt(ctx, &model.PackageDeliveryAttempt{
+		PackageID:   input.PackageID,
+		Number:      input.Attempt.Number,
+		Outcome:     input.Attempt.Outcome,
+		Driver:      input.Attempt.Driver,
+		Note:        input.Attempt.Note,
+		AttemptedAt: input.Attempt.AttemptedAt,
+	})
+	if err != nil && !errors.Is(err, repository.ErrAttemptAlreadyExists) {
+		d.Logger.Error("Failed to record delivery attempt", zap.Error(err), zap.String("packageId", input.PackageID))
+		return err
+	}
+
+	return nil
+}
+
+func (d *DeliveryAttempts) NotifyFailedAttemptActivity(ctx context.Context, input *NotifyFailedAttemptInput) error {
+	attempt := int(activity.GetInfo(ctx).Attempt)
+	packageID := input.Notification.DeliveryPackage.ID
+
+	d.Logger.Info("Starting notify failed attempt activity", zap.Int("attempt", attempt), zap.String("packageId", packageID))
+
+	notification := input.Notification
+
+	event := model.NotificationFailedAttempt
+	if notification.ReturnToSender {
+		event = model.NotificationReturnedToSender
+	}
+
+	messages, err := d.Renderer.Render(ctx, event, notification.NotificationRouting, templates.Data{
+		Package:        *notification.DeliveryPackage,
+		DeliveryWindow: notification.DeliveryPackage.DeliveryWindow,
+		Attempt:        &notification.Attempt,
+		NextAttemptAt:  notification.NextAttemptAt,
+	})
+	if err != nil {
+		d.Logger.Error("Failed to render failed attempt notification", zap.Error(err), zap.String("packageId", packageID))
+		return err
+	}
+	notification.Messages = messages
+
+	notifyDeliveryClient := adapters.NewNotifyDeliveryClient(d.Webhooks, adapters.NotificationsDestination, d.Logger)
+
+	if err := notifyDeliveryClient.NotifyFailedAttempt(ctx, notification); err != nil {
+		d.Logger.Error("Failed to notify failed attempt", zap.Error(err), zap.String("packageId", packageID))
+		return webhookError(err)
+	}
+
+	return nil
+}
+
+// ReturnToSenderActivity stores the package as returned to the sender,
+// creating the record if the package was never saved.
+func (d *DeliveryAttempts) ReturnToSenderActivity(ctx context.Context, input *ReturnToSenderInput) error {
+	attempt := int(activity.GetInfo(ctx).Attempt)
+	packageID := input.DeliveryPackage.ID
+
+	d.Logger.Info("Starting return to sender activity", zap.Int("attempt", attempt), zap.String("packageId", packageID))
+
+	if err := storePackageStatus(ctx, d.Packages, input.DeliveryPackage, model.PackageDeliveryReturnedToSender); err != nil {
+		d.Logger.Error("Failed to mark package as returned to sender", zap.Error(err), zap.String("packageId", packageID))
+		return err
+	}
+
+	return nil
+}
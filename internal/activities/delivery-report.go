@@ -0,0 +1,107 @@
+package activities
+
+import (
+	"context"
+	"go-test/internal/adapters"
+	"go-test/internal/model"
+	"go-test/internal/report"
+	"go-test/repository"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	RecordPackageEventActivityName  = "record-package-event-activity"
+	SummarizeDeliveriesActivityName = "summarize-deliveries-activity"
+	SendDeliveryReportActivityName  = "send-delivery-report-activity"
+)
+
+const ErrTypeInvalidReport = "InvalidReport"
+
+type DeliveryReports struct {
+	Events   repository.PackageEventStore
+	Webhooks *adapters.Webhooks
+	Logger   *zap.Logger
+}
+
+type RecordPackageEventInput struct {
+	Event model.PackageEvent
+}
+
+type SummarizeDeliveriesInput struct {
+	From time.Time
+	To   time.Time
+}
+
+type SendDeliveryReportInput struct {
+	Summary    model.DeliverySummary
+	Recipients []string
+}
+
+func NewDeliveryReports(events repository.PackageEventStore, webhooks *adapters.Webhooks, logger *zap.Logger) *DeliveryReports {
+	return &DeliveryReports{Events: events, Webhooks: webhooks, Logger: logger}
+}
+
+// RecordPackageEventActivity is idempotent, the store keeps the first
+// occurrence of an event.
+func (d *DeliveryReports) RecordPackageEventActivity(ctx context.Context, input *RecordPackageEventInput) error {
+	attempt := int(activity.GetInfo(ctx).Attempt)
+
+	d.Logger.Info("Starting record package event activity", zap.Int("attempt", attempt), zap.String("packageId", input.Event.PackageID), zap.String("event", string(input.Event.Event)))
+
+	if err := d.Events.RecordPackageEvent(ctx, &input.Event); err != nil {
+		d.Logger.Error("Failed to record package event", zap.Error(err), zap.String("packageId", input.Event.PackageID))
+		return err
+	}
+
+	return nil
+}
+
+func (d *DeliveryReports) SummarizeDeliveriesActivity(ctx context.Context, input *SummarizeDeliveriesInput) (*model.DeliverySummary, error) {
+	attempt := int(activity.GetInfo(ctx).Attempt)
+
+	d.Logger.Info("Starting summarize deliveries activity", zap.Int("attempt", attempt), zap.Time("from", input.From), zap.Time("to", input.To))
+
+	summary, err := d.Events.SummarizeDeliveries(ctx, input.From, input.To)
+	if err != nil {
+		d.Logger.Error("Failed to summarize deliveries", zap.Error(err))
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// SendDeliveryReportActivity renders the summary as HTML and CSV and sends
+// both to the recipients.
+func (d *DeliveryReports) SendDeliveryReportActivity(ctx context.Context, input *SendDeliveryReportInput) error {
+	attempt := int(activity.GetInfo(ctx).Attempt)
+
+	d.Logger.Info("Starting send delivery report activity", zap.Int("attempt", attempt), zap.Int("recipients", len(input.Recipients)))
+
+	html, err := report.HTML(input.Summary)
+	if err != nil {
+		return temporal.NewNonRetryableApplicationError(err.Error(), ErrTypeInvalidReport, err)
+	}
+
+	csv, err := report.CSV(input.Summary)
+	if err != nil {
+		return temporal.NewNonRetryableApplicationError(err.Error(), ErrTypeInvalidReport, err)
+	}
+
+	notifyDeliveryClient := adapters.NewNotifyDeliveryClient(d.Webhooks, adapters.OperationsDestination, d.Logger)
+
+	err = notifyDeliveryClient.SendDeliveryReport(ctx, model.DeliveryReport{
+		Summary:    input.Summary,
+		Recipients: input.Recipients,
+		HTML:       html,
+		CSV:        csv,
+	})
+	if err != nil {
+		d.Logger.Error("Failed to send delivery report", zap.Error(err))
+		return webhookError(err)
+	}
+
+	return nil
+}
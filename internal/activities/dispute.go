@@ -0,0 +1,115 @@
+package activities
+
+import (
+	"context"
+	"errors"
+	"go-test/internal/adapters"
+	"go-test/internal/model"
+	"go-test/repository"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+	"go.uber.org/zap"
+)
+
+const (
+	RecordDisputeActivityName  = "record-dispute-activity"
+	NotifySupportActivityName  = "notify-support-activity"
+	ResolveDisputeActivityName = "resolve-dispute-activity"
+)
+
+const ErrTypeDisputeConflict = "DisputeConflict"
+
+type Disputes struct {
+	Repo     repository.DisputeStore
+	Webhooks *adapters.Webhooks
+	Logger   *zap.Logger
+}
+
+type RecordDisputeInput struct {
+	PackageID string
+	Dispute   model.Dispute
+}
+
+type NotifySupportInput struct {
+	DeliveryPackage *model.DeliveryPackage
+	Dispute         model.Dispute
+}
+
+type ResolveDisputeInput struct {
+	DisputeID  string
+	Resolution model.DisputeResolution
+}
+
+func NewDisputes(repo repository.DisputeStore, webhooks *adapters.Webhooks, logger *zap.Logger) *Disputes {
+	return &Disputes{Repo: repo, Webhooks: webhooks, Logger: logger}
+}
+
+// RecordDisputeActivity persists a dispute. It is idempotent, as the dispute
+// ID is assigned by the workflow.
+func (d *Disputes) RecordDisputeActivity(ctx context.Context, input *RecordDisputeInput) error {
+	attempt := int(activity.GetInfo(ctx).Attempt)
+
+	d.Logger.Info("Starting record dispute activity", zap.Int("attempt", attempt), zap.String("disputeId", input.Dispute.ID))
+
+	err := d.Repo.CreateDispute(ctx, &model.PackageDispute{
+		ID:        input.Dispute.ID,
+		PackageID: input.PackageID,
+		Category:  input.Dispute.Category,
+		Reason:    input.Dispute.Reason,
+		RaisedBy:  input.Dispute.RaisedBy,
+		RaisedAt:  input.Dispute.RaisedAt,
+	})
+	if err != nil && !errors.Is(err, repository.ErrDisputeAlreadyExists) {
+		d.Logger.Error("Failed to record dispute", zap.Error(err), zap.String("disputeId", input.Dispute.ID))
+		return err
+	}
+
+	return nil
+}
+
+func (d *Disputes) NotifySupportActivity(ctx context.Context, input *NotifySupportInput) error {
+	attempt := int(activity.GetInfo(ctx).Attempt)
+
+	d.Logger.Info("Starting notify support activity", zap.Int("attempt", attempt), zap.String("disputeId", input.Dispute.ID))
+
+	notifyDeliveryClient := adapters.NewNotifyDeliveryClient(d.Webhooks, adapters.SupportDestination, d.Logger)
+
+	err := notifyDeliveryClient.NotifySupport(ctx, model.SupportNotification{
+		DeliveryPackage: input.DeliveryPackage,
+		Dispute:         input.Dispute,
+	})
+	if err != nil {
+		d.Logger.Error("Failed to notify support", zap.Error(err), zap.String("disputeId", input.Dispute.ID))
+		return webhookError(err)
+	}
+
+	return nil
+}
+
+// ResolveDisputeActivity records the resolution. A retry after a committed
+// attempt finds the same resolution and succeeds; a different resolution
+// already on record is a conflict.
+func (d *Disputes) ResolveDisputeActivity(ctx context.Context, input *ResolveDisputeInput) error {
+	attempt := int(activity.GetInfo(ctx).Attempt)
+
+	d.Logger.Info("Starting resolve dispute activity", zap.Int("attempt", attempt), zap.String("disputeId", input.DisputeID))
+
+	_, err := d.Repo.ResolveDispute(ctx, input.DisputeID, input.Resolution)
+	if !errors.Is(err, repository.ErrDisputeResolved) {
+		if err != nil {
+			d.Logger.Error("Failed to resolve dispute", zap.Error(err), zap.String("disputeId", input.DisputeID))
+		}
+		return err
+	}
+
+	stored, err := d.Repo.GetDispute(ctx, input.DisputeID)
+	if err != nil {
+		return err
+	}
+
+	if stored.Action != input.Resolution.Action || stored.ResolvedBy != input.Resolution.ResolvedBy {
+		return temporal.NewNonRetryableApplicationError("dispute already resolved differently", ErrTypeDisputeConflict, nil)
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package activities
+
+import (
+	"context"
+	"errors"
+	"go-test/internal/model"
+	"go-test/internal/templates"
+	"go-test/repository"
+	"strings"
+)
+
+// NotificationRenderer renders the messages of a notification from the
+// templates of its event, one per channel of the routing.
+type NotificationRenderer interface {
+	Render(ctx context.Context, event model.NotificationKind, routing model.NotificationRouting, data templates.Data) ([]model.NotificationMessage, error)
+}
+
+// loadCustomerPreferences returns the preferences of the customer, or the
+// defaults when they have not set any. Preferences are keyed by the lower
+// case email.
+func loadCustomerPreferences(ctx context.Context, store repository.CustomerPreferencesStore, email string) (*model.CustomerPreferences, error) {
+	email = strings.ToLower(email)
+
+	preferences, err := store.GetCustomerPreferences(ctx, email)
+	if errors.Is(err, repository.ErrCustomerNotFound) {
+		return model.DefaultCustomerPreferences(email), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return preferences, nil
+}
@@ -0,0 +1,56 @@
+package activities
+
+import (
+	"context"
+	"go-test/internal/adapters"
+	"go-test/internal/model"
+	"go-test/internal/templates"
+	"go.temporal.io/sdk/activity"
+	"go.uber.org/zap"
+)
+
+const NotifyArrivalActivityName = "notify-arrival-activity"
+
+type NotifyArrival struct {
+	Renderer NotificationRenderer
+	Webhooks *adapters.Webhooks
+	Logger   *zap.Logger
+}
+
+type NotifyArrivalInput struct {
+	Notification model.ArrivalNotification
+}
+
+func NewNotifyArrival(renderer NotificationRenderer, webhooks *adapters.Webhooks, logger *zap.Logger) *NotifyArrival {
+	return &NotifyArrival{Renderer: renderer, Webhooks: webhooks, Logger: logger}
+}
+
+// NotifyArrivalActivity tells the customer that the package arrives soon,
+// within the delivery window they chose.
+func (n *NotifyArrival) NotifyArrivalActivity(ctx context.Context, input *NotifyArrivalInput) error {
+	attempt := int(activity.GetInfo(ctx).Attempt)
+	packageID := input.Notification.DeliveryPackage.ID
+
+	n.Logger.Info("Starting notify arrival activity", zap.Int("attempt", attempt), zap.String("packageId", packageID))
+
+	notification := input.Notification
+
+	messages, err := n.Renderer.Render(ctx, model.NotificationArrival, notification.NotificationRouting, templates.Data{
+		Package:        *notification.DeliveryPackage,
+		DeliveryWindow: &notification.DeliveryWindow,
+	})
+	if err != nil {
+		n.Logger.Error("Failed to render arrival notification", zap.Error(err), zap.String("packageId", packageID))
+		return err
+	}
+	notification.Messages = messages
+
+	notifyDeliveryClient := adapters.NewNotifyDeliveryClient(n.Webhooks, adapters.NotificationsDestination, n.Logger)
+
+	if err := notifyDeliveryClient.NotifyArrival(ctx, notification); err != nil {
+		n.Logger.Error("Failed to notify arrival", zap.Error(err), zap.String("packageId", packageID))
+		return webhookError(err)
+	}
+
+	return nil
+}
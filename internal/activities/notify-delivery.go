@@ -4,6 +4,7 @@ import (
 	"context"
 	"go-test/internal/adapters"
 	"go-test/internal/model"
+	"go-test/internal/templates"
 	"go.temporal.io/sdk/activity"
 	"go.uber.org/zap"
 )
@@ -11,17 +12,24 @@ import (
 const NotifyDeliveryActivityName = "notify-delivery-activity"
 
 type NotifyDelivery struct {
-	Logger *zap.Logger
+	Renderer NotificationRenderer
+	Webhooks *adapters.Webhooks
+	Logger   *zap.Logger
 }
 
 type NotifyDeliveryInput struct {
 	ID              string
 	DeliveryPackage *model.DeliveryPackage
+	// Preferences decide the channels and language of the notification.
+	// Workflows started before customers had preferences leave it unset.
+	Preferences *model.CustomerPreferences `json:",omitempty"`
 }
 
-func NewNotifyDelivery(logger *zap.Logger) *NotifyDelivery {
+func NewNotifyDelivery(renderer NotificationRenderer, webhooks *adapters.Webhooks, logger *zap.Logger) *NotifyDelivery {
 	return &NotifyDelivery{
-		Logger: logger,
+		Renderer: renderer,
+		Webhooks: webhooks,
+		Logger:   logger,
 	}
 }
 
@@ -30,15 +38,29 @@ func (n *NotifyDelivery) NotifyDeliveryActivity(ctx context.Context, input *Noti
 
 	n.Logger.Info("Starting notify delivery activity", zap.Int("attempt", attempt))
 
-	// TODO add config
-	notifyDeliveryClient := adapters.NewNotifyDeliveryClient("3af31544-ce24-4f48-b563-f5a8ba38656e", n.Logger)
+	notifyDeliveryClient := adapters.NewNotifyDeliveryClient(n.Webhooks, adapters.NotificationsDestination, n.Logger)
 
-	err := notifyDeliveryClient.Notify(ctx, *input.DeliveryPackage)
+	notification := model.DeliveryNotification{DeliveryPackage: *input.DeliveryPackage}
+	if input.Preferences != nil {
+		notification.NotificationRouting = input.Preferences.Routing()
+	}
 
+	messages, err := n.Renderer.Render(ctx, model.NotificationDelivered, notification.NotificationRouting, templates.Data{
+		Package:        *input.DeliveryPackage,
+		DeliveryWindow: input.DeliveryPackage.DeliveryWindow,
+	})
 	if err != nil {
-		n.Logger.Error("Failed to notify delivery activity", zap.Error(err))
+		n.Logger.Error("Failed to render delivery notification", zap.Error(err))
 		return err
 	}
+	notification.Messages = messages
+
+	err = notifyDeliveryClient.Notify(ctx, notification)
+
+	if err != nil {
+		n.Logger.Error("Failed to notify delivery activity", zap.Error(err))
+		return webhookError(err)
+	}
 
 	return nil
 }
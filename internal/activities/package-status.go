@@ -0,0 +1,30 @@
+package activities
+
+import (
+	"context"
+	"errors"
+	"go-test/internal/model"
+	"go-test/repository"
+)
+
+// storePackageStatus sets the status of a package on behalf of its
+// workflow, based on the version it reads. A package that already has the
+// status is left untouched, so that retries are harmless, and a concurrent
+// write fails the attempt, which is retried against the new version.
+func storePackageStatus(ctx context.Context, packages repository.PackageStore, deliveryPackage *model.DeliveryPackage, status model.PackageDeliveryState) error {
+	var version int64
+
+	stored, err := packages.GetPackageDelivery(ctx, deliveryPackage.ID)
+	if err != nil && !errors.Is(err, repository.ErrPackageNotFound) {
+		return err
+	}
+	if err == nil {
+		if stored.Status == status {
+			return nil
+		}
+		deliveryPackage, version = stored, stored.Version
+	}
+
+	_, err = repository.SetPackageStatus(ctx, packages, deliveryPackage, status, version)
+	return err
+}
@@ -0,0 +1,41 @@
+package activities
+
+import (
+	"context"
+	"go-test/internal/model"
+	"go-test/repository"
+	"go.temporal.io/sdk/activity"
+	"go.uber.org/zap"
+)
+
+const ForceTransitionActivityName = "force-transition-activity"
+
+type Remediation struct {
+	Packages repository.PackageStore
+	Logger   *zap.Logger
+}
+
+type ForceTransitionInput struct {
+	DeliveryPackage *model.DeliveryPackage
+	Status          model.PackageDeliveryState
+}
+
+func NewRemediation(packages repository.PackageStore, logger *zap.Logger) *Remediation {
+	return &Remediation{Packages: packages, Logger: logger}
+}
+
+// ForceTransitionActivity stores the status an operator forced the package
+// into.
+func (r *Remediation) ForceTransitionActivity(ctx context.Context, input *ForceTransitionInput) error {
+	attempt := int(activity.GetInfo(ctx).Attempt)
+	packageID := input.DeliveryPackage.ID
+
+	r.Logger.Info("Starting force transition activity", zap.Int("attempt", attempt), zap.String("packageId", packageID), zap.String("status", string(input.Status)))
+
+	if err := storePackageStatus(ctx, r.Packages, input.DeliveryPackage, input.Status); err != nil {
+		r.Logger.Error("Failed to force package status", zap.Error(err), zap.String("packageId", packageID))
+		return err
+	}
+
+	return nil
+}
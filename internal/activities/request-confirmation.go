@@ -0,0 +1,85 @@
+package activities
+
+import (
+	"context"
+	"go-test/internal/adapters"
+	"go-test/internal/model"
+	"go-test/internal/templates"
+	"go-test/repository"
+	"go.temporal.io/sdk/activity"
+	"go.uber.org/zap"
+	"time"
+)
+
+const RequestConfirmationActivityName = "request-confirmation-activity"
+
+// ConfirmationLinkIssuer creates the single-use link a customer confirms the
+// delivery with.
+type ConfirmationLinkIssuer interface {
+	Issue(ctx context.Context, deliveryPackage *model.DeliveryPackage) (string, time.Time, error)
+}
+
+type RequestConfirmation struct {
+	Links       ConfirmationLinkIssuer
+	Preferences repository.CustomerPreferencesStore
+	Renderer    NotificationRenderer
+	Webhooks    *adapters.Webhooks
+	Logger      *zap.Logger
+}
+
+type RequestConfirmationInput struct {
+	DeliveryPackage *model.DeliveryPackage
+}
+
+func NewRequestConfirmation(links ConfirmationLinkIssuer, preferences repository.CustomerPreferencesStore, renderer NotificationRenderer, webhooks *adapters.Webhooks, logger *zap.Logger) *RequestConfirmation {
+	return &RequestConfirmation{Links: links, Preferences: preferences, Renderer: renderer, Webhooks: webhooks, Logger: logger}
+}
+
+// RequestConfirmationActivity sends the customer a confirmation link. Every
+// attempt issues a new link; links of failed attempts are never delivered
+// and simply expire. The request is sent in the channels and language of
+// the customer; the workflow decides beforehand whether their preferences
+// let it through.
+func (r *RequestConfirmation) RequestConfirmationActivity(ctx context.Context, input *RequestConfirmationInput) error {
+	attempt := int(activity.GetInfo(ctx).Attempt)
+
+	r.Logger.Info("Starting request confirmation activity", zap.Int("attempt", attempt), zap.String("packageId", input.DeliveryPackage.ID))
+
+	confirmationURL, expiresAt, err := r.Links.Issue(ctx, input.DeliveryPackage)
+	if err != nil {
+		r.Logger.Error("Failed to issue confirmation link", zap.Error(err), zap.String("packageId", input.DeliveryPackage.ID))
+		return err
+	}
+
+	preferences, err := loadCustomerPreferences(ctx, r.Preferences, input.DeliveryPackage.CustomerEmail)
+	if err != nil {
+		r.Logger.Error("Failed to load customer preferences", zap.Error(err), zap.String("packageId", input.DeliveryPackage.ID))
+		return err
+	}
+
+	messages, err := r.Renderer.Render(ctx, model.NotificationConfirmationRequest, preferences.Routing(), templates.Data{
+		Package:          *input.DeliveryPackage,
+		ConfirmationLink: confirmationURL,
+		LinkExpiresAt:    &expiresAt,
+		DeliveryWindow:   input.DeliveryPackage.DeliveryWindow,
+	})
+	if err != nil {
+		r.Logger.Error("Failed to render confirmation request", zap.Error(err), zap.String("packageId", input.DeliveryPackage.ID))
+		return err
+	}
+
+	notifyDeliveryClient := adapters.NewNotifyDeliveryClient(r.Webhooks, adapters.NotificationsDestination, r.Logger)
+
+	err = notifyDeliveryClient.RequestConfirmation(ctx, model.ConfirmationRequest{
+		DeliveryPackage: input.DeliveryPackage,
+		ConfirmationURL: confirmationURL,
+		ExpiresAt:       expiresAt,
+		Messages:        messages,
+	})
+	if err != nil {
+		r.Logger.Error("Failed to send confirmation request", zap.Error(err), zap.String("packageId", input.DeliveryPackage.ID))
+		return webhookError(err)
+	}
+
+	return nil
+}
@@ -2,16 +2,24 @@ package activities
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"go-test/internal/model"
 	"go-test/repository"
 	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
 	"go.uber.org/zap"
 )
 
 const SaveDeliveryActivityName = "save-delivery-activity"
 
+const (
+	ErrTypeInvalidPackage  = "InvalidPackage"
+	ErrTypePackageConflict = "PackageConflict"
+)
+
 type SaveDelivery struct {
-	Repo   *repository.Repository
+	Repo   repository.PackageStore
 	Logger *zap.Logger
 }
 
@@ -19,16 +27,27 @@ type SaveDeliveryInput struct {
 	DeliveryPackage *model.DeliveryPackage
 }
 
-func NewSaveDelivery(repo *repository.Repository, logger *zap.Logger) *SaveDelivery {
+func NewSaveDelivery(repo repository.PackageStore, logger *zap.Logger) *SaveDelivery {
 	return &SaveDelivery{Repo: repo, Logger: logger}
 }
 
+// SaveDeliveryActivity is idempotent: when a previous attempt already
+// committed the same package, the stored row is returned instead of failing
+// on the duplicate key. A stored row with a different payload is a real
+// conflict and is reported as a non-retryable error.
 func (s *SaveDelivery) SaveDeliveryActivity(ctx context.Context, params *SaveDeliveryInput) (*model.DeliveryPackage, error) {
 	attempt := int(activity.GetInfo(ctx).Attempt)
 
 	s.Logger.Info("Starting save delivery activity", zap.Int("attempt", attempt))
 
-	pack, err := s.Repo.CreatePackageDelivery(params.DeliveryPackage)
+	if params.DeliveryPackage == nil || params.DeliveryPackage.ID == "" {
+		return nil, temporal.NewNonRetryableApplicationError("delivery package id is required", ErrTypeInvalidPackage, nil)
+	}
+
+	pack, err := s.Repo.CreatePackageDelivery(ctx, params.DeliveryPackage)
+	if errors.Is(err, repository.ErrPackageAlreadyExists) {
+		return s.resolveExisting(ctx, params.DeliveryPackage)
+	}
 	if err != nil {
 		s.Logger.Error("Failed to save delivery package", zap.Error(err), zap.String("packageId", params.DeliveryPackage.ID))
 		return nil, err
@@ -38,3 +57,24 @@ func (s *SaveDelivery) SaveDeliveryActivity(ctx context.Context, params *SaveDel
 
 	return pack, nil
 }
+
+func (s *SaveDelivery) resolveExisting(ctx context.Context, payload *model.DeliveryPackage) (*model.DeliveryPackage, error) {
+	stored, err := s.Repo.GetPackageDelivery(ctx, payload.ID)
+	if err != nil {
+		s.Logger.Error("Failed to read existing delivery package", zap.Error(err), zap.String("packageId", payload.ID))
+		return nil, err
+	}
+
+	if !stored.SamePayload(payload) {
+		s.Logger.Error("Stored delivery package differs from payload", zap.String("packageId", payload.ID))
+		return nil, temporal.NewNonRetryableApplicationError(
+			fmt.Sprintf("package %s is already stored with a different payload", payload.ID),
+			ErrTypePackageConflict,
+			repository.ErrPackageAlreadyExists,
+		)
+	}
+
+	s.Logger.Info("Delivery package was already saved", zap.String("packageId", payload.ID))
+
+	return stored, nil
+}
@@ -0,0 +1,123 @@
+package activities
+
+import (
+	"context"
+	"errors"
+	"go-test/internal/model"
+	"go-test/repository"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func newSaveDeliveryEnvironment(repo repository.PackageStore) *testsuite.TestActivityEnvironment {
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestActivityEnvironment()
+	env.RegisterActivityWithOptions(
+		NewSaveDelivery(repo, zap.NewNop()).SaveDeliveryActivity,
+		activity.RegisterOptions{Name: SaveDeliveryActivityName},
+	)
+
+	return env
+}
+
+func deliveredPackage() *model.DeliveryPackage {
+	return &model.DeliveryPackage{
+		ID:              "PKG-1",
+		CustomerEmail:   "customer@example.com",
+		DeliveryAddress: "1 Main Street",
+		Region:          "north",
+		Proof: &model.ProofOfDelivery{
+			RecipientName: "Jane Doe",
+			Signature:     &model.ObjectReference{Key: "proofs/PKG-1/signature.png", ContentType: "image/png", Size: 512},
+			Location:      &model.GeoLocation{Latitude: 52.52, Longitude: 13.40},
+		},
+	}
+}
+
+func saveDelivery(env *testsuite.TestActivityEnvironment, deliveryPackage *model.DeliveryPackage) (*model.DeliveryPackage, error) {
+	value, err := env.ExecuteActivity(SaveDeliveryActivityName, &SaveDeliveryInput{DeliveryPackage: deliveryPackage})
+	if err != nil {
+		return nil, err
+	}
+
+	var saved model.DeliveryPackage
+	if err := value.Get(&saved); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+func TestSaveDeliveryInsertsPackage(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+
+	saved, err := saveDelivery(newSaveDeliveryEnvironment(repo), deliveredPackage())
+	if err != nil {
+		t.Fatalf("SaveDeliveryActivity: %v", err)
+	}
+	if saved.Version != 1 {
+		t.Fatalf("saved version = %d, want 1", saved.Version)
+	}
+
+	stored, err := repo.GetPackageDelivery(context.Background(), "PKG-1")
+	if err != nil {
+		t.Fatalf("GetPackageDelivery: %v", err)
+	}
+	if !stored.SamePayload(deliveredPackage()) {
+		t.Fatalf("stored package = %+v, want the saved payload", stored)
+	}
+}
+
+func TestSaveDeliveryRetryWithSamePayloadIsNoOp(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	env := newSaveDeliveryEnvironment(repo)
+
+	if _, err := saveDelivery(env, deliveredPackage()); err != nil {
+		t.Fatalf("first SaveDeliveryActivity: %v", err)
+	}
+
+	saved, err := saveDelivery(env, deliveredPackage())
+	if err != nil {
+		t.Fatalf("retried SaveDeliveryActivity: %v", err)
+	}
+	if saved.Version != 1 {
+		t.Fatalf("retry returned version %d, want the stored version 1", saved.Version)
+	}
+}
+
+func TestSaveDeliveryConflictIsNonRetryable(t *testing.T) {
+	differentAddress := deliveredPackage()
+	differentAddress.DeliveryAddress = "2 Side Street"
+
+	differentProof := deliveredPackage()
+	differentProof.Proof.Signature = &model.ObjectReference{Key: "proofs/PKG-1/other.png", ContentType: "image/png", Size: 256}
+
+	withoutProof := deliveredPackage()
+	withoutProof.Proof = nil
+
+	for name, payload := range map[string]*model.DeliveryPackage{
+		"address":       differentAddress,
+		"proof":         differentProof,
+		"missing proof": withoutProof,
+	} {
+		t.Run(name, func(t *testing.T) {
+			env := newSaveDeliveryEnvironment(repository.NewMemoryRepository())
+
+			if _, err := saveDelivery(env, deliveredPackage()); err != nil {
+				t.Fatalf("first SaveDeliveryActivity: %v", err)
+			}
+
+			_, err := saveDelivery(env, payload)
+			var appErr *temporal.ApplicationError
+			if !errors.As(err, &appErr) {
+				t.Fatalf("SaveDeliveryActivity error = %v, want an application error", err)
+			}
+			if appErr.Type() != ErrTypePackageConflict || !appErr.NonRetryable() {
+				t.Fatalf("application error type = %s, non-retryable = %t, want a non-retryable %s",
+					appErr.Type(), appErr.NonRetryable(), ErrTypePackageConflict)
+			}
+		})
+	}
+}
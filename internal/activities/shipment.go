@@ -0,0 +1,79 @@
+package activities
+
+import (
+	"context"
+	"go-test/internal/adapters"
+	"go-test/internal/model"
+	"go-test/internal/templates"
+	"go-test/repository"
+	"go.temporal.io/sdk/activity"
+	"go.uber.org/zap"
+)
+
+const (
+	SaveShipmentActivityName   = "save-shipment-activity"
+	NotifyShipmentActivityName = "notify-shipment-activity"
+)
+
+type Shipments struct {
+	Repo        repository.ShipmentStore
+	Preferences repository.CustomerPreferencesStore
+	Renderer    NotificationRenderer
+	Webhooks    *adapters.Webhooks
+	Logger      *zap.Logger
+}
+
+type ShipmentInput struct {
+	Shipment *model.Shipment
+}
+
+func NewShipments(repo repository.ShipmentStore, preferences repository.CustomerPreferencesStore, renderer NotificationRenderer, webhooks *adapters.Webhooks, logger *zap.Logger) *Shipments {
+	return &Shipments{Repo: repo, Preferences: preferences, Renderer: renderer, Webhooks: webhooks, Logger: logger}
+}
+
+// SaveShipmentActivity stores the shipment with the final state of its
+// parcels. Saving overwrites, so retries are safe.
+func (s *Shipments) SaveShipmentActivity(ctx context.Context, input *ShipmentInput) error {
+	attempt := int(activity.GetInfo(ctx).Attempt)
+
+	s.Logger.Info("Starting save shipment activity", zap.Int("attempt", attempt), zap.String("shipmentId", input.Shipment.ID))
+
+	if err := s.Repo.SaveShipment(ctx, input.Shipment); err != nil {
+		s.Logger.Error("Failed to save shipment", zap.Error(err), zap.String("shipmentId", input.Shipment.ID))
+		return err
+	}
+
+	return nil
+}
+
+// NotifyShipmentActivity sends the customer the single notification for all
+// parcels of the shipment, in their channels and language.
+func (s *Shipments) NotifyShipmentActivity(ctx context.Context, input *ShipmentInput) error {
+	attempt := int(activity.GetInfo(ctx).Attempt)
+
+	s.Logger.Info("Starting notify shipment activity", zap.Int("attempt", attempt), zap.String("shipmentId", input.Shipment.ID))
+
+	preferences, err := loadCustomerPreferences(ctx, s.Preferences, input.Shipment.CustomerEmail)
+	if err != nil {
+		s.Logger.Error("Failed to load customer preferences", zap.Error(err), zap.String("shipmentId", input.Shipment.ID))
+		return err
+	}
+
+	messages, err := s.Renderer.Render(ctx, model.NotificationShipmentDelivered, preferences.Routing(), templates.Data{
+		Shipment: input.Shipment,
+	})
+	if err != nil {
+		s.Logger.Error("Failed to render shipment notification", zap.Error(err), zap.String("shipmentId", input.Shipment.ID))
+		return err
+	}
+
+	notifyDeliveryClient := adapters.NewNotifyDeliveryClient(s.Webhooks, adapters.NotificationsDestination, s.Logger)
+
+	notification := model.ShipmentNotification{Shipment: *input.Shipment, Messages: messages}
+	if err := notifyDeliveryClient.NotifyShipment(ctx, notification); err != nil {
+		s.Logger.Error("Failed to notify shipment", zap.Error(err), zap.String("shipmentId", input.Shipment.ID))
+		return webhookError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package activities
+
+import (
+	"context"
+	"errors"
+	"go-test/internal/adapters"
+	"go-test/internal/model"
+	"go-test/repository"
+	"go.temporal.io/sdk/activity"
+	"go.uber.org/zap"
+	"sort"
+	"time"
+)
+
+const (
+	FindStuckPackagesActivityName  = "find-stuck-packages-activity"
+	AlertStuckPackagesActivityName = "alert-stuck-packages-activity"
+)
+
+// maxStuckPackagesPerStatus bounds the size of a single alert.
+const maxStuckPackagesPerStatus = 100
+
+// StuckPackageFinder looks up the packages that have kept a status since
+// before a given time.
+type StuckPackageFinder interface {
+	FindStuckPackages(ctx context.Context, status model.PackageDeliveryState, changedBefore time.Time, limit int) ([]model.StuckPackage, error)
+}
+
+type StuckPackages struct {
+	Finder   StuckPackageFinder
+	Packages repository.PackageStore
+	Webhooks *adapters.Webhooks
+	Logger   *zap.Logger
+}
+
+type FindStuckPackagesInput struct {
+	ScannedAt  time.Time
+	Thresholds map[model.PackageDeliveryState]time.Duration
+}
+
+type AlertStuckPackagesInput struct {
+	Alert model.StuckPackagesAlert
+}
+
+func NewStuckPackages(finder StuckPackageFinder, packages repository.PackageStore, webhooks *adapters.Webhooks, logger *zap.Logger) *StuckPackages {
+	return &StuckPackages{Finder: finder, Packages: packages, Webhooks: webhooks, Logger: logger}
+}
+
+// FindStuckPackagesActivity returns the packages whose status is older than
+// its threshold. Packages whose workflow has failed are skipped once an
+// operator has stored another status for them.
+func (s *StuckPackages) FindStuckPackagesActivity(ctx context.Context, input *FindStuckPackagesInput) ([]model.StuckPackage, error) {
+	attempt := int(activity.GetInfo(ctx).Attempt)
+
+	s.Logger.Info("Starting find stuck packages activity", zap.Int("attempt", attempt), zap.Time("scannedAt", input.ScannedAt))
+
+	statuses := make([]model.PackageDeliveryState, 0, len(input.Thresholds))
+	for status := range input.Thresholds {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i] < statuses[j] })
+
+	stuck := []model.StuckPackage{}
+	for _, status := range statuses {
+		found, err := s.Finder.FindStuckPackages(ctx, status, input.ScannedAt.Add(-input.Thresholds[status]), maxStuckPackagesPerStatus)
+		if err != nil {
+			s.Logger.Error("Failed to find stuck packages", zap.Error(err), zap.String("status", string(status)))
+			return nil, err
+		}
+
+		for _, stuckPackage := range found {
+			settled, err := s.settled(ctx, stuckPackage)
+			if err != nil {
+				return nil, err
+			}
+			if !settled {
+				stuck = append(stuck, stuckPackage)
+			}
+		}
+	}
+
+	return stuck, nil
+}
+
+// settled reports whether an operator has moved the package of a failed
+// workflow to another status.
+func (s *StuckPackages) settled(ctx context.Context, stuckPackage model.StuckPackage) (bool, error) {
+	if stuckPackage.Running {
+		return false, nil
+	}
+
+	stored, err := s.Packages.GetPackageDelivery(ctx, stuckPackage.PackageID)
+	if errors.Is(err, repository.ErrPackageNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		s.Logger.Error("Failed to read delivery package", zap.Error(err), zap.String("packageId", stuckPackage.PackageID))
+		return false, err
+	}
+
+	return stored.Status != stuckPackage.Status, nil
+}
+
+func (s *StuckPackages) AlertStuckPackagesActivity(ctx context.Context, input *AlertStuckPackagesInput) error {
+	attempt := int(activity.GetInfo(ctx).Attempt)
+
+	s.Logger.Info("Starting alert stuck packages activity", zap.Int("attempt", attempt), zap.Int("packages", len(input.Alert.Packages)))
+
+	notifyDeliveryClient := adapters.NewNotifyDeliveryClient(s.Webhooks, adapters.OperationsDestination, s.Logger)
+
+	if err := notifyDeliveryClient.AlertStuckPackages(ctx, input.Alert); err != nil {
+		s.Logger.Error("Failed to alert stuck packages", zap.Error(err))
+		return webhookError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package activities
+
+import (
+	"errors"
+	"go-test/internal/adapters"
+	"go-test/internal/circuit"
+	"go.temporal.io/sdk/temporal"
+)
+
+const (
+	// ErrTypeDestinationUnavailable is the error type of activities whose
+	// webhook call was not made, as the circuit of its destination is open or
+	// the destination is busy. It is retried after the delay the circuit
+	// hints at instead of the backoff of the retry policy.
+	ErrTypeDestinationUnavailable = "DestinationUnavailable"
+	// ErrTypeWebhookThrottled is the error type of activities whose webhook
+	// destination asked to be called again later with Retry-After. It is
+	// retried after that delay.
+	ErrTypeWebhookThrottled = "WebhookThrottled"
+	// ErrTypeWebhookRejected is the error type of activities whose webhook
+	// call was refused with a client error. It is not retried, as the same
+	// request would be refused again.
+	ErrTypeWebhookRejected = "WebhookRejected"
+	// ErrTypeWebhookDisabled is the error type of activities whose webhook
+	// URL was disabled after a 410 Gone. It is not retried.
+	ErrTypeWebhookDisabled = "WebhookDisabled"
+)
+
+// webhookError turns the failure of a webhook call into the application
+// error telling Temporal whether and when to retry it, and returns other
+// errors as is.
+func webhookError(err error) error {
+	if errors.Is(err, adapters.ErrWebhookDisabled) {
+		return temporal.NewNonRetryableApplicationError(err.Error(), ErrTypeWebhookDisabled, err)
+	}
+
+	var unavailable *circuit.UnavailableError
+	if errors.As(err, &unavailable) {
+		return temporal.NewApplicationErrorWithOptions(err.Error(), ErrTypeDestinationUnavailable, temporal.ApplicationErrorOptions{
+			Cause:          err,
+			NextRetryDelay: unavailable.RetryAfter,
+		})
+	}
+
+	var rejected *adapters.WebhookError
+	if !errors.As(err, &rejected) {
+		return err
+	}
+
+	switch {
+	case rejected.Permanent():
+		return temporal.NewNonRetryableApplicationError(err.Error(), ErrTypeWebhookRejected, err)
+	case rejected.RetryAfter > 0:
+		return temporal.NewApplicationErrorWithOptions(err.Error(), ErrTypeWebhookThrottled, temporal.ApplicationErrorOptions{
+			Cause:          err,
+			NextRetryDelay: rejected.RetryAfter,
+		})
+	default:
+		return err
+	}
+}
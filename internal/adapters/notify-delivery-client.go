@@ -4,47 +4,151 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"go-test/internal/circuit"
 	"go-test/internal/model"
+	"go-test/repository"
 	"go.uber.org/zap"
-	"net"
 	"net/http"
-	"sync"
 	"time"
 )
 
-var (
-	clientOnce sync.Once
-	client     *http.Client
-)
-
 type NotifyDeliveryClient struct {
-	basePath  string
-	webhookId string
-	client    *http.Client
-	Logger    *zap.Logger
+	webhooks      *Webhooks
+	destination   *webhookDestination
+	breakers      *circuit.Destinations
+	subscriptions repository.WebhookSubscriptionStore
+	Logger        *zap.Logger
 }
 
-func NewNotifyDeliveryClient(webhookId string, logger *zap.Logger) *NotifyDeliveryClient {
-	clientOnce.Do(func() {
-		client = initClient()
-	})
-
+// NewNotifyDeliveryClient returns a client sending to the named destination
+// of webhooks. Its calls go through the circuit breaker and bulkhead of the
+// destination host.
+//
+// Calls answered with a status code other than 2xx fail with a
+// *WebhookError. A 410 Gone disables the subscription of the URL that
+// answered it and alerts operations; calls to that URL then fail with
+// ErrWebhookDisabled until it is enabled again.
+func NewNotifyDeliveryClient(webhooks *Webhooks, destination string, logger *zap.Logger) *NotifyDeliveryClient {
 	return &NotifyDeliveryClient{
-		basePath:  "https://webhook.site",
-		client:    client,
-		webhookId: webhookId,
-		Logger:    logger,
+		webhooks:      webhooks,
+		destination:   webhooks.destination(destination),
+		breakers:      webhooks.Breakers(),
+		subscriptions: webhooks.subscriptions,
+		Logger:        logger,
+	}
+}
+
+func (nc *NotifyDeliveryClient) Notify(ctx context.Context, notification model.DeliveryNotification) error {
+	if err := nc.post(ctx, notification); err != nil {
+		return err
+	}
+
+	nc.Logger.Info("Successfully sent delivery notification")
+	return nil
+}
+
+// RequestConfirmation sends the customer the link to confirm the delivery.
+func (nc *NotifyDeliveryClient) RequestConfirmation(ctx context.Context, request model.ConfirmationRequest) error {
+	if err := nc.post(ctx, request); err != nil {
+		return err
+	}
+
+	nc.Logger.Info("Successfully sent confirmation request")
+	return nil
+}
+
+// NotifySupport asks support to look into a dispute raised by the customer.
+func (nc *NotifyDeliveryClient) NotifySupport(ctx context.Context, notification model.SupportNotification) error {
+	if err := nc.post(ctx, notification); err != nil {
+		return err
+	}
+
+	nc.Logger.Info("Successfully sent support notification")
+	return nil
+}
+
+// NotifyShipment sends the single notification for all parcels of a
+// shipment.
+func (nc *NotifyDeliveryClient) NotifyShipment(ctx context.Context, notification model.ShipmentNotification) error {
+	if err := nc.post(ctx, notification); err != nil {
+		return err
+	}
+
+	nc.Logger.Info("Successfully sent shipment notification")
+	return nil
+}
+
+// NotifyFailedAttempt tells the customer that a delivery attempt failed.
+func (nc *NotifyDeliveryClient) NotifyFailedAttempt(ctx context.Context, notification model.FailedAttemptNotification) error {
+	if err := nc.post(ctx, notification); err != nil {
+		return err
+	}
+
+	nc.Logger.Info("Successfully sent failed attempt notification")
+	return nil
+}
+
+// NotifyArrival tells the customer that the package arrives within the
+// delivery window they chose.
+func (nc *NotifyDeliveryClient) NotifyArrival(ctx context.Context, notification model.ArrivalNotification) error {
+	if err := nc.post(ctx, notification); err != nil {
+		return err
+	}
+
+	nc.Logger.Info("Successfully sent arrival notification")
+	return nil
+}
+
+// AlertStuckPackages asks operators to look into packages that stopped
+// making progress.
+func (nc *NotifyDeliveryClient) AlertStuckPackages(ctx context.Context, alert model.StuckPackagesAlert) error {
+	if err := nc.post(ctx, alert); err != nil {
+		return err
 	}
+
+	nc.Logger.Info("Successfully sent stuck packages alert")
+	return nil
 }
 
-func (nc *NotifyDeliveryClient) Notify(ctx context.Context, deliveryPackage model.DeliveryPackage) error {
-	webhookURL := fmt.Sprintf("%s/%s", nc.basePath, nc.webhookId)
+// AlertWebhookDisabled asks operators to look into a webhook subscription
+// disabled after a 410 Gone.
+func (nc *NotifyDeliveryClient) AlertWebhookDisabled(ctx context.Context, alert model.WebhookDisabledAlert) error {
+	if err := nc.post(ctx, alert); err != nil {
+		return err
+	}
+
+	nc.Logger.Info("Successfully sent webhook disabled alert")
+	return nil
+}
+
+// SendDeliveryReport sends the delivery report to its recipients.
+func (nc *NotifyDeliveryClient) SendDeliveryReport(ctx context.Context, report model.DeliveryReport) error {
+	if err := nc.post(ctx, report); err != nil {
+		return err
+	}
+
+	nc.Logger.Info("Successfully sent delivery report")
+	return nil
+}
+
+func (nc *NotifyDeliveryClient) post(ctx context.Context, body interface{}) error {
+	webhookURL := nc.destination.url
+
+	_, err := nc.subscriptions.GetDisabledWebhookSubscription(ctx, webhookURL)
+	if err == nil {
+		nc.Logger.Warn("Webhook subscription is disabled", zap.String("destination", nc.destination.name), zap.String("webhookURL", webhookURL))
+		return fmt.Errorf("%s: %w", nc.destination.name, ErrWebhookDisabled)
+	}
+	if !errors.Is(err, repository.ErrSubscriptionNotDisabled) {
+		return fmt.Errorf("failed to check webhook subscription: %w", err)
+	}
 
-	payload, err := json.Marshal(deliveryPackage)
+	payload, err := json.Marshal(body)
 	if err != nil {
-		nc.Logger.Error("Failed to marshal delivery package", zap.Error(err))
-		return fmt.Errorf("failed to marshal delivery package: %w", err)
+		nc.Logger.Error("Failed to marshal webhook payload", zap.Error(err))
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
 	}
 
 	nc.Logger.Info("Sending request to webhook", zap.String("webhookURL", webhookURL))
@@ -55,49 +159,73 @@ func (nc *NotifyDeliveryClient) Notify(ctx context.Context, deliveryPackage mode
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	if nc.destination.auth.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+nc.destination.auth.BearerToken)
+	} else if nc.destination.auth.Username != "" {
+		req.SetBasicAuth(nc.destination.auth.Username, nc.destination.auth.Password)
+	}
+
+	destination := req.URL.Host
 
-	resp, err := nc.client.Do(req)
+	release, err := nc.breakers.Acquire(ctx, destination)
 	if err != nil {
+		nc.Logger.Warn("Webhook destination is unavailable", zap.String("destination", destination), zap.Error(err))
+		return err
+	}
+
+	resp, err := nc.destination.client.Do(req)
+	if err != nil {
+		release(true)
 		nc.Logger.Error("Failed to send request", zap.Error(err))
 		return fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
+	// Server errors and throttling tell the destination is unhealthy; other
+	// client errors are about the request and say nothing about it.
+	release(resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests)
+
 	nc.Logger.Info("Received response from webhook", zap.Int("statusCode", resp.StatusCode))
 
 	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
-		nc.Logger.Error("Webhook responded with an error", zap.Int("statusCode", resp.StatusCode))
-		return fmt.Errorf("webhook responded with status code: %d", resp.StatusCode)
+		webhookErr := newWebhookError(nc.destination.name, resp, time.Now())
+		nc.Logger.Error("Webhook responded with an error",
+			zap.Int("statusCode", resp.StatusCode),
+			zap.Duration("retryAfter", webhookErr.RetryAfter),
+			zap.String("responseBody", webhookErr.Body))
+
+		if resp.StatusCode == http.StatusGone {
+			nc.disable(ctx, webhookErr)
+		}
+
+		return webhookErr
 	}
 
-	nc.Logger.Info("Successfully sent delivery notification")
 	return nil
 }
 
-func initClient() *http.Client {
-	dc := func(ctx context.Context, network, addr string) (net.Conn, error) {
-		dialer := net.Dialer{
-			Timeout: time.Minute,
-		}
-		return dialer.DialContext(ctx, network, addr)
-	}
-
-	c := http.Client{
-		Transport: &http.Transport{
-			DialContext:           dc,
-			MaxIdleConnsPerHost:   3,
-			MaxIdleConns:          12,
-			MaxConnsPerHost:       3,
-			IdleConnTimeout:       time.Minute,
-			ResponseHeaderTimeout: time.Second,
-			WriteBufferSize:       8 << 10,
-			ReadBufferSize:        8 << 10,
-		},
-		CheckRedirect: func(next *http.Request, history []*http.Request) error {
-			return http.ErrUseLastResponse
-		},
-		Timeout: time.Second * 30,
-	}
-
-	return &c
+// disable stops the calls to the URL that answered 410 Gone, leaving the
+// other URLs alone, and alerts operations. Failing to record it only costs
+// another call, so the error is logged and dropped, as is a failed alert.
+func (nc *NotifyDeliveryClient) disable(ctx context.Context, webhookErr *WebhookError) {
+	subscription := model.DisabledWebhookSubscription{
+		URL:             nc.destination.url,
+		Destination:     nc.destination.name,
+		StatusCode:      webhookErr.StatusCode,
+		ResponseExcerpt: webhookErr.Body,
+		DisabledAt:      time.Now().UTC(),
+	}
+
+	if err := nc.subscriptions.DisableWebhookSubscription(ctx, &subscription); err != nil {
+		nc.Logger.Error("Failed to disable webhook subscription", zap.String("destination", nc.destination.name), zap.String("webhookURL", subscription.URL), zap.Error(err))
+		return
+	}
+
+	nc.Logger.Warn("Disabled webhook subscription of a gone URL", zap.String("destination", nc.destination.name), zap.String("webhookURL", subscription.URL))
+
+	// The alert fails as disabled when operations share the gone URL.
+	operations := NewNotifyDeliveryClient(nc.webhooks, OperationsDestination, nc.Logger)
+	if err := operations.AlertWebhookDisabled(ctx, model.WebhookDisabledAlert{Subscription: subscription}); err != nil {
+		nc.Logger.Error("Failed to alert operations of a disabled webhook subscription", zap.String("webhookURL", subscription.URL), zap.Error(err))
+	}
 }
@@ -0,0 +1,95 @@
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxResponseExcerpt is the number of bytes of an error response kept
+	// for the logs.
+	maxResponseExcerpt = 512
+	// maxRetryAfter caps the Retry-After of a destination, so a wrong header
+	// cannot hold a notification back for days.
+	maxRetryAfter = time.Hour
+)
+
+// ErrWebhookDisabled is returned for calls to a webhook URL disabled after it
+// answered 410 Gone.
+var ErrWebhookDisabled = errors.New("webhook subscription is disabled")
+
+// WebhookError is returned when a destination answers with a status code
+// other than 2xx.
+type WebhookError struct {
+	Destination string
+	StatusCode  int
+	// RetryAfter is the delay the destination asked for on a 429 or 503, zero
+	// when it gave none.
+	RetryAfter time.Duration
+	// Body is the start of the response body.
+	Body string
+}
+
+func (e *WebhookError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("webhook responded with status code: %d", e.StatusCode)
+	}
+	return fmt.Sprintf("webhook responded with status code: %d: %s", e.StatusCode, e.Body)
+}
+
+// Permanent tells whether sending the same request again is bound to fail.
+// That is the case for client errors, except timeouts and throttling.
+func (e *WebhookError) Permanent() bool {
+	return e.StatusCode >= http.StatusBadRequest && e.StatusCode < http.StatusInternalServerError &&
+		e.StatusCode != http.StatusRequestTimeout && e.StatusCode != http.StatusTooManyRequests
+}
+
+func newWebhookError(destination string, resp *http.Response, now time.Time) *WebhookError {
+	webhookErr := &WebhookError{
+		Destination: destination,
+		StatusCode:  resp.StatusCode,
+		Body:        responseExcerpt(resp.Body),
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		webhookErr.RetryAfter = retryAfter(resp.Header.Get("Retry-After"), now)
+	}
+
+	return webhookErr
+}
+
+// responseExcerpt reads the first bytes of body, dropping the partial
+// character the cut may leave.
+func responseExcerpt(body io.Reader) string {
+	excerpt, _ := io.ReadAll(io.LimitReader(body, maxResponseExcerpt))
+
+	return strings.TrimSpace(strings.ToValidUTF8(string(excerpt), ""))
+}
+
+// retryAfter parses a Retry-After header, given in seconds or as an HTTP
+// date. It returns zero when the header is missing or invalid.
+func retryAfter(header string, now time.Time) time.Duration {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0
+	}
+
+	var delay time.Duration
+	if seconds, err := strconv.Atoi(header); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(header); err == nil {
+		delay = date.Sub(now)
+	}
+
+	if delay <= 0 {
+		return 0
+	}
+	if delay > maxRetryAfter {
+		return maxRetryAfter
+	}
+	return delay
+}
@@ -0,0 +1,174 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-test/internal/config"
+	"go-test/internal/model"
+	"go-test/repository"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func respondingServer(t *testing.T, status int, header http.Header, body string) (*httptest.Server, *int) {
+	t.Helper()
+
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		for name, values := range header {
+			w.Header()[name] = values
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &calls
+}
+
+func TestWebhookResponsesAreClassified(t *testing.T) {
+	for name, tc := range map[string]struct {
+		status     int
+		retryAfter string
+		body       string
+		permanent  bool
+		wantDelay  time.Duration
+	}{
+		"bad request":             {status: http.StatusBadRequest, body: `{"error":"invalid payload"}`, permanent: true},
+		"request timeout":         {status: http.StatusRequestTimeout},
+		"throttled":               {status: http.StatusTooManyRequests, retryAfter: "120", wantDelay: 2 * time.Minute},
+		"unavailable":             {status: http.StatusServiceUnavailable, retryAfter: "30", wantDelay: 30 * time.Second},
+		"unavailable for days":    {status: http.StatusServiceUnavailable, retryAfter: "604800", wantDelay: maxRetryAfter},
+		"bad gateway retry after": {status: http.StatusBadGateway, retryAfter: "30"},
+		"invalid retry after":     {status: http.StatusTooManyRequests, retryAfter: "soon"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			header := http.Header{}
+			if tc.retryAfter != "" {
+				header.Set("Retry-After", tc.retryAfter)
+			}
+			server, _ := respondingServer(t, tc.status, header, tc.body)
+
+			webhooks, err := NewWebhooks(config.WebhooksConfig{
+				Destinations: map[string]config.WebhookDestinationConfig{
+					DefaultDestination: {URL: server.URL},
+				},
+			}, repository.NewMemoryRepository())
+			if err != nil {
+				t.Fatalf("NewWebhooks: %v", err)
+			}
+
+			var webhookErr *WebhookError
+			if err := notify(webhooks, NotificationsDestination); !errors.As(err, &webhookErr) {
+				t.Fatalf("notify = %v, want a *WebhookError", err)
+			}
+			if webhookErr.StatusCode != tc.status || webhookErr.Permanent() != tc.permanent || webhookErr.RetryAfter != tc.wantDelay {
+				t.Errorf("error = %+v with Permanent() = %t, want status %d, permanent %t and retry after %s",
+					webhookErr, webhookErr.Permanent(), tc.status, tc.permanent, tc.wantDelay)
+			}
+			if webhookErr.Body != tc.body {
+				t.Errorf("Body = %q, want %q", webhookErr.Body, tc.body)
+			}
+		})
+	}
+}
+
+func TestRetryAfterTakesHTTPDates(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	if delay := retryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now); delay != 90*time.Second {
+		t.Errorf("retryAfter of a date = %s, want 1m30s", delay)
+	}
+	if delay := retryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now); delay != 0 {
+		t.Errorf("retryAfter of a past date = %s, want 0", delay)
+	}
+}
+
+func TestResponseExcerptIsCut(t *testing.T) {
+	body := strings.Repeat("x", maxResponseExcerpt-1) + "é and more"
+
+	if excerpt := responseExcerpt(strings.NewReader(body)); excerpt != strings.Repeat("x", maxResponseExcerpt-1) {
+		t.Errorf("excerpt has %d bytes, want the %d bytes before the cut character", len(excerpt), maxResponseExcerpt-1)
+	}
+}
+
+func TestGoneDisablesWebhookSubscription(t *testing.T) {
+	gone, goneCalls := respondingServer(t, http.StatusGone, nil, "subscription removed")
+	other, otherCalls := respondingServer(t, http.StatusOK, nil, "")
+
+	var alerts []model.WebhookDisabledAlert
+	operations := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert model.WebhookDisabledAlert
+		if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+			t.Errorf("decode alert: %v", err)
+		}
+		alerts = append(alerts, alert)
+	}))
+	t.Cleanup(operations.Close)
+
+	store := repository.NewMemoryRepository()
+	webhooks, err := NewWebhooks(config.WebhooksConfig{
+		Destinations: map[string]config.WebhookDestinationConfig{
+			DefaultDestination:       {URL: other.URL},
+			NotificationsDestination: {URL: gone.URL},
+			OperationsDestination:    {URL: operations.URL},
+		},
+	}, store)
+	if err != nil {
+		t.Fatalf("NewWebhooks: %v", err)
+	}
+
+	var webhookErr *WebhookError
+	if err := notify(webhooks, NotificationsDestination); !errors.As(err, &webhookErr) || !webhookErr.Permanent() {
+		t.Fatalf("notify a gone destination = %v, want a permanent *WebhookError", err)
+	}
+
+	subscription, err := store.GetDisabledWebhookSubscription(context.Background(), gone.URL)
+	if err != nil {
+		t.Fatalf("GetDisabledWebhookSubscription: %v", err)
+	}
+	if subscription.Destination != NotificationsDestination || subscription.StatusCode != http.StatusGone || subscription.ResponseExcerpt != "subscription removed" {
+		t.Errorf("disabled subscription = %+v", subscription)
+	}
+	if len(alerts) != 1 || alerts[0].Subscription.URL != gone.URL || alerts[0].Subscription.Destination != NotificationsDestination {
+		t.Errorf("operations alerts = %+v, want one for the gone URL", alerts)
+	}
+
+	if err := notify(webhooks, NotificationsDestination); !errors.Is(err, ErrWebhookDisabled) {
+		t.Errorf("notify a disabled destination = %v, want ErrWebhookDisabled", err)
+	}
+	if *goneCalls != 1 {
+		t.Errorf("gone destination got %d calls, want 1", *goneCalls)
+	}
+
+	// Destinations falling back to the default one are not affected.
+	if err := notify(webhooks, SupportDestination); err != nil || *otherCalls != 1 {
+		t.Errorf("notify support = %v after %d calls, want it sent", err, *otherCalls)
+	}
+
+	// The destination configured with a new URL is called again.
+	moved, err := NewWebhooks(config.WebhooksConfig{
+		Destinations: map[string]config.WebhookDestinationConfig{
+			NotificationsDestination: {URL: other.URL + "/moved"},
+		},
+	}, store)
+	if err != nil {
+		t.Fatalf("NewWebhooks: %v", err)
+	}
+	if err := notify(moved, NotificationsDestination); err != nil || *otherCalls != 2 {
+		t.Errorf("notify the new URL = %v after %d calls, want it sent", err, *otherCalls)
+	}
+
+	if err := store.EnableWebhookSubscription(context.Background(), gone.URL); err != nil {
+		t.Fatalf("EnableWebhookSubscription: %v", err)
+	}
+	_ = notify(webhooks, NotificationsDestination)
+	if *goneCalls != 2 {
+		t.Errorf("gone destination got %d calls after enabling it, want 2", *goneCalls)
+	}
+}
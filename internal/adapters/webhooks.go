@@ -0,0 +1,198 @@
+package adapters
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"go-test/internal/circuit"
+	"go-test/internal/config"
+	"go-test/repository"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// Destination names, see config.WebhooksConfig.
+const (
+	DefaultDestination       = "default"
+	NotificationsDestination = "notifications"
+	SupportDestination       = "support"
+	OperationsDestination    = "operations"
+)
+
+// DefaultWebhookDestination holds the settings no destination sets.
+func DefaultWebhookDestination() config.WebhookDestinationConfig {
+	return config.WebhookDestinationConfig{
+		URL:                   "https://webhook.site/3af31544-ce24-4f48-b563-f5a8ba38656e",
+		Timeout:               config.Duration(30 * time.Second),
+		DialTimeout:           config.Duration(time.Minute),
+		ResponseHeaderTimeout: config.Duration(time.Second),
+		MaxConnsPerHost:       3,
+	}
+}
+
+type webhookDestination struct {
+	name   string
+	url    string
+	client *http.Client
+	auth   config.WebhookAuthConfig
+}
+
+// Webhooks holds the HTTP client of every destination, the circuit
+// breakers guarding their hosts and the subscriptions disabled after a 410
+// Gone. The clients are built once, up front, so it is safe for concurrent
+// use.
+type Webhooks struct {
+	destinations  map[string]*webhookDestination
+	breakers      *circuit.Destinations
+	subscriptions repository.WebhookSubscriptionStore
+}
+
+func NewWebhooks(cfg config.WebhooksConfig, subscriptions repository.WebhookSubscriptionStore) (*Webhooks, error) {
+	w := &Webhooks{
+		destinations:  make(map[string]*webhookDestination),
+		breakers:      circuit.NewDestinations(cfg),
+		subscriptions: subscriptions,
+	}
+
+	defaults := DefaultWebhookDestination().Merge(cfg.Destinations[DefaultDestination])
+
+	names := []string{DefaultDestination}
+	for name := range cfg.Destinations {
+		switch name {
+		case DefaultDestination:
+		case NotificationsDestination, SupportDestination, OperationsDestination:
+			names = append(names, name)
+		default:
+			return nil, fmt.Errorf("unknown webhook destination %q", name)
+		}
+	}
+
+	for _, name := range names {
+		destination, err := newWebhookDestination(name, defaults.Merge(cfg.Destinations[name]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook destination %q: %w", name, err)
+		}
+		w.destinations[name] = destination
+	}
+
+	return w, nil
+}
+
+// Breakers returns the circuit breakers of the destination hosts.
+func (w *Webhooks) Breakers() *circuit.Destinations {
+	return w.breakers
+}
+
+// destination returns the destination of name, or the default one when it
+// is not configured.
+func (w *Webhooks) destination(name string) *webhookDestination {
+	if destination, ok := w.destinations[name]; ok {
+		return destination
+	}
+
+	return w.destinations[DefaultDestination]
+}
+
+func newWebhookDestination(name string, cfg config.WebhookDestinationConfig) (*webhookDestination, error) {
+	webhookURL, err := url.Parse(cfg.URL)
+	if err != nil || (webhookURL.Scheme != "http" && webhookURL.Scheme != "https") || webhookURL.Host == "" {
+		return nil, fmt.Errorf("url %q is not an http or https URL", cfg.URL)
+	}
+
+	if cfg.Auth.BearerToken != "" && cfg.Auth.Username != "" {
+		return nil, errors.New("auth takes either a username or a bearer token")
+	}
+	if cfg.Auth.Password != "" && cfg.Auth.Username == "" {
+		return nil, errors.New("auth password requires a username")
+	}
+
+	tlsConfig, err := newTLSConfig(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{Timeout: cfg.DialTimeout.Duration()}
+
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		TLSClientConfig:       tlsConfig,
+		MaxIdleConnsPerHost:   cfg.MaxConnsPerHost,
+		MaxIdleConns:          4 * cfg.MaxConnsPerHost,
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		IdleConnTimeout:       time.Minute,
+		ResponseHeaderTimeout: cfg.ResponseHeaderTimeout.Duration(),
+		WriteBufferSize:       8 << 10,
+		ReadBufferSize:        8 << 10,
+	}
+
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("proxy url %q is invalid", cfg.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	maxRedirects := cfg.MaxRedirects
+
+	return &webhookDestination{
+		name: name,
+		url:  webhookURL.String(),
+		client: &http.Client{
+			Transport: transport,
+			// The last response of a refused redirect is returned, and
+			// treated as a failure by its 3xx status code.
+			CheckRedirect: func(next *http.Request, history []*http.Request) error {
+				if len(history) > maxRedirects {
+					return http.ErrUseLastResponse
+				}
+				return nil
+			},
+			Timeout: cfg.Timeout.Duration(),
+		},
+		auth: cfg.Auth,
+	}, nil
+}
+
+// newTLSConfig returns nil when cfg sets nothing, so the transport keeps
+// its defaults.
+func newTLSConfig(cfg config.WebhookTLSConfig) (*tls.Config, error) {
+	if cfg == (config.WebhookTLSConfig{}) {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: cfg.ServerName,
+	}
+
+	if cfg.CAFile != "" {
+		bundle, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read CA bundle: %w", err)
+		}
+
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("CA bundle %s holds no PEM certificates", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = roots
+	}
+
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return nil, errors.New("a client certificate needs both a cert file and a key file")
+	}
+	if cfg.CertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
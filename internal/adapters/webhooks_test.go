@@ -0,0 +1,145 @@
+package adapters
+
+import (
+	"context"
+	"encoding/pem"
+	"go-test/internal/config"
+	"go-test/internal/model"
+	"go-test/repository"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// recordingServer answers every call with status and records the
+// Authorization header of the last one.
+func recordingServer(t *testing.T, status int) (*httptest.Server, *string) {
+	t.Helper()
+
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &authorization
+}
+
+func notify(webhooks *Webhooks, destination string) error {
+	client := NewNotifyDeliveryClient(webhooks, destination, zap.NewNop())
+	return client.Notify(context.Background(), model.DeliveryNotification{DeliveryPackage: model.DeliveryPackage{ID: "PKG-1"}})
+}
+
+func TestWebhookDestinationsFallBackToDefault(t *testing.T) {
+	defaultServer, defaultAuth := recordingServer(t, http.StatusOK)
+	notificationsServer, notificationsAuth := recordingServer(t, http.StatusOK)
+
+	webhooks, err := NewWebhooks(config.WebhooksConfig{
+		Destinations: map[string]config.WebhookDestinationConfig{
+			DefaultDestination: {
+				URL:  defaultServer.URL + "/hook",
+				Auth: config.WebhookAuthConfig{Username: "partner", Password: "secret"},
+			},
+			NotificationsDestination: {
+				URL:  notificationsServer.URL + "/hook",
+				Auth: config.WebhookAuthConfig{BearerToken: "token"},
+			},
+		},
+	}, repository.NewMemoryRepository())
+	if err != nil {
+		t.Fatalf("NewWebhooks: %v", err)
+	}
+
+	if err := notify(webhooks, NotificationsDestination); err != nil {
+		t.Fatalf("notify notifications: %v", err)
+	}
+	if *notificationsAuth != "Bearer token" {
+		t.Errorf("notifications Authorization = %q, want the bearer token", *notificationsAuth)
+	}
+
+	if err := notify(webhooks, SupportDestination); err != nil {
+		t.Fatalf("notify support: %v", err)
+	}
+	if !strings.HasPrefix(*defaultAuth, "Basic ") {
+		t.Errorf("support Authorization = %q, want the basic auth of the default destination", *defaultAuth)
+	}
+}
+
+func TestWebhookRedirects(t *testing.T) {
+	target, _ := recordingServer(t, http.StatusOK)
+	redirect := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+	t.Cleanup(redirect.Close)
+
+	refusing, err := NewWebhooks(config.WebhooksConfig{
+		Destinations: map[string]config.WebhookDestinationConfig{
+			DefaultDestination: {URL: redirect.URL},
+		},
+	}, repository.NewMemoryRepository())
+	if err != nil {
+		t.Fatalf("NewWebhooks: %v", err)
+	}
+	if err := notify(refusing, NotificationsDestination); err == nil || !strings.Contains(err.Error(), "307") {
+		t.Errorf("notify through a refused redirect = %v, want the 307 status", err)
+	}
+
+	following, err := NewWebhooks(config.WebhooksConfig{
+		Destinations: map[string]config.WebhookDestinationConfig{
+			DefaultDestination: {URL: redirect.URL, MaxRedirects: 1},
+		},
+	}, repository.NewMemoryRepository())
+	if err != nil {
+		t.Fatalf("NewWebhooks: %v", err)
+	}
+	if err := notify(following, NotificationsDestination); err != nil {
+		t.Errorf("notify through a followed redirect: %v", err)
+	}
+}
+
+func TestWebhookCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(server.Close)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, bundle, 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	webhooks, err := NewWebhooks(config.WebhooksConfig{
+		Destinations: map[string]config.WebhookDestinationConfig{
+			DefaultDestination:       {URL: server.URL},
+			NotificationsDestination: {TLS: config.WebhookTLSConfig{CAFile: caFile}},
+		},
+	}, repository.NewMemoryRepository())
+	if err != nil {
+		t.Fatalf("NewWebhooks: %v", err)
+	}
+
+	if err := notify(webhooks, NotificationsDestination); err != nil {
+		t.Errorf("notify with the CA bundle: %v", err)
+	}
+	if err := notify(webhooks, SupportDestination); err == nil {
+		t.Error("notify without the CA bundle succeeded, want an unknown authority error")
+	}
+}
+
+func TestNewWebhooksRejectsInvalidDestinations(t *testing.T) {
+	for name, destinations := range map[string]map[string]config.WebhookDestinationConfig{
+		"unknown name":       {"notification": {}},
+		"relative url":       {NotificationsDestination: {URL: "/hook"}},
+		"two credentials":    {DefaultDestination: {Auth: config.WebhookAuthConfig{Username: "u", BearerToken: "t"}}},
+		"password only":      {SupportDestination: {Auth: config.WebhookAuthConfig{Password: "p"}}},
+		"cert without key":   {OperationsDestination: {TLS: config.WebhookTLSConfig{CertFile: "client.pem"}}},
+		"missing CA bundle":  {DefaultDestination: {TLS: config.WebhookTLSConfig{CAFile: "missing.pem"}}},
+		"proxy without host": {DefaultDestination: {ProxyURL: "proxy"}},
+	} {
+		if _, err := NewWebhooks(config.WebhooksConfig{Destinations: destinations}, repository.NewMemoryRepository()); err == nil {
+			t.Errorf("NewWebhooks with %s succeeded, want an error", name)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Package audit keeps the trail of the actions operators take on packages
+// and on the configuration of the service.
+package audit
+
+import (
+	"context"
+	"go-test/internal/model"
+	"go-test/repository"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Log writes audit entries to the audit log store.
+type Log struct {
+	store  repository.AuditLogStore
+	logger *zap.Logger
+}
+
+func NewLog(store repository.AuditLogStore, logger *zap.Logger) *Log {
+	return &Log{store: store, logger: logger}
+}
+
+// Record appends an entry for an action that has been carried out. The
+// action cannot be undone at that point, so a failure is logged for the
+// trail to be completed by hand rather than returned. Entries without a
+// subject are about their package.
+func (l *Log) Record(ctx context.Context, entry model.AuditEntry) {
+	entry.CreatedAt = time.Now().UTC()
+	if entry.SubjectType == "" {
+		entry.SubjectType = model.AuditSubjectPackage
+		entry.SubjectID = entry.PackageID
+	}
+
+	if err := l.store.RecordAuditEntry(ctx, &entry); err != nil {
+		l.logger.Error("Failed to write audit entry",
+			zap.String("subjectType", string(entry.SubjectType)),
+			zap.String("subjectId", entry.SubjectID),
+			zap.String("operator", entry.Operator),
+			zap.String("action", string(entry.Action)),
+			zap.String("reason", entry.Reason),
+			zap.String("detail", entry.Detail),
+			zap.Error(err),
+		)
+	}
+}
+
+// Entries returns the trail of a subject.
+func (l *Log) Entries(ctx context.Context, subjectType model.AuditSubjectType, subjectID string) ([]model.AuditEntry, error) {
+	return l.store.ListAuditEntries(ctx, subjectType, subjectID)
+}
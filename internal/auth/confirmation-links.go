@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"go-test/internal/config"
+	"go-test/internal/model"
+	"go-test/repository"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+const (
+	ConfirmPath = "/api/v1/confirm/"
+
+	defaultConfirmationTTL     = 72 * time.Hour
+	defaultConfirmationBaseURL = "http://localhost:3010"
+
+	// MinConfirmationSecretLength is the shortest secret links are signed
+	// with, the size of the HMAC-SHA256 key.
+	MinConfirmationSecretLength = 32
+)
+
+var ErrConfirmationSecret = errors.New("confirmation secret must be configured")
+
+// ConfirmationLinks issues and redeems the single-use confirmation links
+// sent to customers. The signature makes links unforgeable, the token store
+// makes them single-use.
+type ConfirmationLinks struct {
+	Signer  *TokenSigner
+	Store   repository.ConfirmationTokenStore
+	TTL     time.Duration
+	BaseURL string
+	Logger  *zap.Logger
+}
+
+func NewConfirmationLinks(cfg config.AuthConfig, store repository.ConfirmationTokenStore, logger *zap.Logger) (*ConfirmationLinks, error) {
+	// Links must validate on every replica and across restarts, so the
+	// secret is never generated.
+	secret := []byte(cfg.ConfirmationSecret)
+	if len(secret) < MinConfirmationSecretLength {
+		return nil, fmt.Errorf("%w with at least %d bytes, got %d", ErrConfirmationSecret, MinConfirmationSecretLength, len(secret))
+	}
+
+	links := &ConfirmationLinks{
+		Signer:  NewTokenSigner(secret),
+		Store:   store,
+		TTL:     cfg.ConfirmationTTL.Duration(),
+		BaseURL: strings.TrimSuffix(cfg.ConfirmationBaseURL, "/"),
+		Logger:  logger,
+	}
+	if links.TTL <= 0 {
+		links.TTL = defaultConfirmationTTL
+	}
+	if links.BaseURL == "" {
+		links.BaseURL = defaultConfirmationBaseURL
+	}
+
+	return links, nil
+}
+
+// Issue records a new token for the package and returns the link carrying it.
+func (l *ConfirmationLinks) Issue(ctx context.Context, deliveryPackage *model.DeliveryPackage) (string, time.Time, error) {
+	now := time.Now().UTC()
+	token := &model.ConfirmationToken{
+		ID:            uuid.NewString(),
+		PackageID:     deliveryPackage.ID,
+		CustomerEmail: deliveryPackage.CustomerEmail,
+		ExpiresAt:     now.Add(l.TTL).Truncate(time.Second),
+		CreatedAt:     now,
+	}
+
+	signed, err := l.Signer.Sign(ConfirmationClaims{
+		TokenID:       token.ID,
+		PackageID:     token.PackageID,
+		CustomerEmail: token.CustomerEmail,
+		ExpiresAt:     token.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("unable to sign confirmation token: %w", err)
+	}
+
+	if err := l.Store.CreateConfirmationToken(ctx, token); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return l.BaseURL + ConfirmPath + signed, token.ExpiresAt, nil
+}
+
+// Verify checks a token without consuming it.
+func (l *ConfirmationLinks) Verify(token string) (*ConfirmationClaims, error) {
+	return l.Signer.Verify(token, time.Now())
+}
+
+// CheckUnredeemed reports whether the token of verified claims can still be
+// used, without consuming it. It fails with repository.ErrTokenConsumed when
+// the token has been used before.
+func (l *ConfirmationLinks) CheckUnredeemed(ctx context.Context, claims *ConfirmationClaims) error {
+	token, err := l.Store.GetConfirmationToken(ctx, claims.TokenID)
+	if err != nil {
+		return err
+	}
+	if token.ConsumedAt != nil {
+		return repository.ErrTokenConsumed
+	}
+
+	return nil
+}
+
+// Redeem consumes the token of verified claims with a single conditional
+// write and returns the time it was consumed at. It fails with
+// repository.ErrTokenConsumed when the token has been used before, so that
+// concurrent uses of one token succeed at most once.
+func (l *ConfirmationLinks) Redeem(ctx context.Context, claims *ConfirmationClaims) (time.Time, error) {
+	// The store keeps microseconds, Release matches the time exactly.
+	redeemedAt := time.Now().UTC().Truncate(time.Microsecond)
+
+	if _, err := l.Store.ConsumeConfirmationToken(ctx, claims.TokenID, redeemedAt); err != nil {
+		return time.Time{}, err
+	}
+
+	return redeemedAt, nil
+}
+
+// Release makes the token of claims usable again after the action it was
+// redeemed for at redeemedAt has been refused.
+func (l *ConfirmationLinks) Release(ctx context.Context, claims *ConfirmationClaims, redeemedAt time.Time) error {
+	return l.Store.ReleaseConfirmationToken(ctx, claims.TokenID, redeemedAt)
+}
@@ -0,0 +1,82 @@
+// Package auth authenticates confirmations: signed one-time links for
+// customers and bearer tokens for operators.
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid confirmation token")
+	ErrTokenExpired = errors.New("confirmation token expired")
+)
+
+// ConfirmationClaims is the signed content of a confirmation token.
+type ConfirmationClaims struct {
+	TokenID       string `json:"jti"`
+	PackageID     string `json:"pkg"`
+	CustomerEmail string `json:"email"`
+	ExpiresAt     int64  `json:"exp"`
+}
+
+// TokenSigner signs and verifies confirmation tokens with HMAC-SHA256. A
+// token is the base64url encoded claims and signature, joined by a dot.
+type TokenSigner struct {
+	secret []byte
+}
+
+func NewTokenSigner(secret []byte) *TokenSigner {
+	return &TokenSigner{secret: secret}
+}
+
+func (s *TokenSigner) Sign(claims ConfirmationClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.mac(encoded)), nil
+}
+
+// Verify checks the signature and expiry of token and returns its claims.
+func (s *TokenSigner) Verify(token string, now time.Time) (*ConfirmationClaims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, s.mac(encoded)) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var claims ConfirmationClaims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.TokenID == "" || claims.PackageID == "" {
+		return nil, ErrInvalidToken
+	}
+
+	if !now.Before(time.Unix(claims.ExpiresAt, 0)) {
+		return nil, ErrTokenExpired
+	}
+
+	return &claims, nil
+}
+
+func (s *TokenSigner) mac(encoded string) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(encoded))
+	return h.Sum(nil)
+}
@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"go-test/internal/config"
+	"go-test/internal/model"
+	"go-test/repository"
+	"go.uber.org/zap"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenSigner(t *testing.T) {
+	signer := NewTokenSigner([]byte("secret"))
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	claims := ConfirmationClaims{TokenID: "token-1", PackageID: "pkg-1", CustomerEmail: "customer@example.com", ExpiresAt: now.Add(time.Hour).Unix()}
+
+	token, err := signer.Sign(claims)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	got, err := signer.Verify(token, now)
+	if err != nil || *got != claims {
+		t.Fatalf("Verify = %+v, %v, want %+v", got, err, claims)
+	}
+
+	if _, err := signer.Verify(token, now.Add(time.Hour)); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("Verify expired token error = %v, want ErrTokenExpired", err)
+	}
+
+	if _, err := NewTokenSigner([]byte("other")).Verify(token, now); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Verify with another secret error = %v, want ErrInvalidToken", err)
+	}
+
+	forged, err := NewTokenSigner([]byte("other")).Sign(ConfirmationClaims{TokenID: "token-1", PackageID: "pkg-2", ExpiresAt: claims.ExpiresAt})
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	payload, _, _ := strings.Cut(forged, ".")
+	_, signature, _ := strings.Cut(token, ".")
+	for _, invalid := range []string{"", "garbage", payload + "." + signature, token + "x"} {
+		if _, err := signer.Verify(invalid, now); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("Verify(%q) error = %v, want ErrInvalidToken", invalid, err)
+		}
+	}
+}
+
+const testConfirmationSecret = "test-confirmation-secret-of-32-bytes"
+
+func TestConfirmationLinksRequireSecret(t *testing.T) {
+	for _, secret := range []string{"", "too-short-secret"} {
+		_, err := NewConfirmationLinks(config.AuthConfig{ConfirmationSecret: secret}, repository.NewMemoryRepository(), zap.NewNop())
+		if !errors.Is(err, ErrConfirmationSecret) {
+			t.Errorf("NewConfirmationLinks with a %d byte secret error = %v, want ErrConfirmationSecret", len(secret), err)
+		}
+	}
+}
+
+func TestConfirmationLinks(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryRepository()
+	links, err := NewConfirmationLinks(config.AuthConfig{ConfirmationSecret: testConfirmationSecret, ConfirmationBaseURL: "https://example.com/"}, store, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewConfirmationLinks: %v", err)
+	}
+
+	url, expiresAt, err := links.Issue(ctx, &model.DeliveryPackage{ID: "pkg-1", CustomerEmail: "customer@example.com"})
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if !strings.HasPrefix(url, "https://example.com"+ConfirmPath) || expiresAt.Before(time.Now().Add(71*time.Hour)) {
+		t.Fatalf("Issue returned %s expiring at %s", url, expiresAt)
+	}
+
+	claims, err := links.Verify(strings.TrimPrefix(url, "https://example.com"+ConfirmPath))
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if claims.PackageID != "pkg-1" || claims.CustomerEmail != "customer@example.com" {
+		t.Fatalf("Verify returned %+v", claims)
+	}
+
+	if err := links.CheckUnredeemed(ctx, claims); err != nil {
+		t.Fatalf("CheckUnredeemed: %v", err)
+	}
+	redeemedAt, err := links.Redeem(ctx, claims)
+	if err != nil {
+		t.Fatalf("Redeem: %v", err)
+	}
+	if err := links.CheckUnredeemed(ctx, claims); !errors.Is(err, repository.ErrTokenConsumed) {
+		t.Fatalf("CheckUnredeemed after Redeem error = %v, want ErrTokenConsumed", err)
+	}
+	if _, err := links.Redeem(ctx, claims); !errors.Is(err, repository.ErrTokenConsumed) {
+		t.Fatalf("second Redeem error = %v, want ErrTokenConsumed", err)
+	}
+
+	if err := links.Release(ctx, claims, redeemedAt); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if _, err := links.Redeem(ctx, claims); err != nil {
+		t.Fatalf("Redeem after Release: %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// Operators authenticates operators by the bearer token they send in the
+// Authorization header.
+type Operators struct {
+	tokens map[string]string
+}
+
+// NewOperators takes the configured tokens, keyed by operator name.
+func NewOperators(tokens map[string]string) *Operators {
+	return &Operators{tokens: tokens}
+}
+
+// Authenticate returns the name of the operator the request is sent by.
+func (o *Operators) Authenticate(r *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return "", false
+	}
+
+	// Every configured token is compared, so the response time does not
+	// reveal which operator a token is close to.
+	operator := ""
+	for name, expected := range o.tokens {
+		if expected != "" && subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1 {
+			operator = name
+		}
+	}
+
+	return operator, operator != ""
+}
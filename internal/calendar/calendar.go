@@ -0,0 +1,169 @@
+// Package calendar checks delivery windows against the business hours and
+// holidays of the delivery service.
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"go-test/internal/config"
+	"go-test/internal/model"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultMinNotice  = time.Hour
+	DefaultMaxAdvance = 30 * 24 * time.Hour
+)
+
+const dateLayout = "2006-01-02"
+
+var ErrWindowUnavailable = errors.New("delivery window is not available")
+
+// DefaultBusinessHours are used when no business hours are configured.
+func DefaultBusinessHours() map[string]config.BusinessHours {
+	hours := make(map[string]config.BusinessHours)
+	for day := time.Monday; day <= time.Saturday; day++ {
+		hours[strings.ToLower(day.String())] = config.BusinessHours{Open: "08:00", Close: "20:00"}
+	}
+
+	return hours
+}
+
+// openingHours are the opening and closing times of a day, as the time
+// since midnight.
+type openingHours struct {
+	open  time.Duration
+	close time.Duration
+}
+
+type Calendar struct {
+	location   *time.Location
+	hours      map[time.Weekday]openingHours
+	holidays   map[string]bool
+	minNotice  time.Duration
+	maxAdvance time.Duration
+}
+
+func New(cfg config.CalendarConfig) (*Calendar, error) {
+	location, err := time.LoadLocation(cfg.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid calendar time zone %q: %w", cfg.TimeZone, err)
+	}
+
+	c := &Calendar{
+		location:   location,
+		hours:      make(map[time.Weekday]openingHours),
+		holidays:   make(map[string]bool),
+		minNotice:  cfg.MinNotice.Duration(),
+		maxAdvance: cfg.MaxAdvance.Duration(),
+	}
+	if c.minNotice <= 0 {
+		c.minNotice = DefaultMinNotice
+	}
+	if c.maxAdvance <= 0 {
+		c.maxAdvance = DefaultMaxAdvance
+	}
+
+	businessHours := cfg.BusinessHours
+	if len(businessHours) == 0 {
+		businessHours = DefaultBusinessHours()
+	}
+
+	for name, hours := range businessHours {
+		day, ok := parseWeekday(name)
+		if !ok {
+			return nil, fmt.Errorf("invalid business day %q", name)
+		}
+
+		open, err := parseTimeOfDay(hours.Open)
+		if err != nil {
+			return nil, fmt.Errorf("invalid opening time of %s: %w", name, err)
+		}
+		closing, err := parseTimeOfDay(hours.Close)
+		if err != nil {
+			return nil, fmt.Errorf("invalid closing time of %s: %w", name, err)
+		}
+		if closing <= open {
+			return nil, fmt.Errorf("business hours of %s close before they open", name)
+		}
+
+		c.hours[day] = openingHours{open: open, close: closing}
+	}
+
+	for _, holiday := range cfg.Holidays {
+		if _, err := time.Parse(dateLayout, holiday); err != nil {
+			return nil, fmt.Errorf("invalid holiday %q, expected YYYY-MM-DD", holiday)
+		}
+		c.holidays[holiday] = true
+	}
+
+	return c, nil
+}
+
+// CheckWindow reports why a window chosen at now cannot be served: it must
+// start between the minimum notice and the maximum advance from now, and
+// fall within the business hours of a single day that is not a holiday.
+func (c *Calendar) CheckWindow(window model.DeliveryWindow, now time.Time) error {
+	if err := window.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrWindowUnavailable, err)
+	}
+
+	if window.Start.Before(now.Add(c.minNotice)) {
+		return fmt.Errorf("%w: it must start at least %s from now", ErrWindowUnavailable, c.minNotice)
+	}
+	if window.Start.After(now.Add(c.maxAdvance)) {
+		return fmt.Errorf("%w: it must start within %s from now", ErrWindowUnavailable, c.maxAdvance)
+	}
+
+	start := window.Start.In(c.location)
+	end := window.End.In(c.location)
+
+	date := start.Format(dateLayout)
+	if end.Format(dateLayout) != date {
+		return fmt.Errorf("%w: it must start and end on the same day", ErrWindowUnavailable)
+	}
+	if c.holidays[date] {
+		return fmt.Errorf("%w: %s is a holiday", ErrWindowUnavailable, date)
+	}
+
+	hours, ok := c.hours[start.Weekday()]
+	if !ok {
+		return fmt.Errorf("%w: there are no deliveries on %s", ErrWindowUnavailable, start.Weekday())
+	}
+
+	if timeOfDay(start) < hours.open || timeOfDay(end) > hours.close {
+		return fmt.Errorf("%w: deliveries on %s are made between %s and %s", ErrWindowUnavailable,
+			start.Weekday(), formatTimeOfDay(hours.open), formatTimeOfDay(hours.close))
+	}
+
+	return nil
+}
+
+func parseWeekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(name, day.String()) {
+			return day, true
+		}
+	}
+
+	return 0, false
+}
+
+func parseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not HH:MM", value)
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// timeOfDay is the wall clock time of t, as the time since midnight.
+func timeOfDay(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+}
+
+func formatTimeOfDay(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+}
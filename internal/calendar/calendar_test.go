@@ -0,0 +1,95 @@
+package calendar
+
+import (
+	"errors"
+	"go-test/internal/config"
+	"go-test/internal/model"
+	"testing"
+	"time"
+)
+
+func newTestCalendar(t *testing.T) *Calendar {
+	t.Helper()
+
+	c, err := New(config.CalendarConfig{
+		TimeZone: "Europe/Berlin",
+		BusinessHours: map[string]config.BusinessHours{
+			"monday":   {Open: "08:00", Close: "20:00"},
+			"Saturday": {Open: "09:00", Close: "14:00"},
+		},
+		Holidays:   []string{"2026-12-28"},
+		MinNotice:  config.Duration(2 * time.Hour),
+		MaxAdvance: config.Duration(14 * 24 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	return c
+}
+
+func TestCheckWindow(t *testing.T) {
+	c := newTestCalendar(t)
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+
+	// Monday 2026-10-19, 12:00 in Berlin.
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, berlin)
+	window := func(day, startHour, endHour int) model.DeliveryWindow {
+		return model.DeliveryWindow{
+			Start:    time.Date(2026, 10, day, startHour, 0, 0, 0, berlin),
+			End:      time.Date(2026, 10, day, endHour, 0, 0, 0, berlin),
+			TimeZone: "America/New_York",
+		}
+	}
+
+	if err := c.CheckWindow(window(19, 14, 16), now); err != nil {
+		t.Fatalf("CheckWindow Monday afternoon: %v", err)
+	}
+	if err := c.CheckWindow(window(24, 9, 14), now); err != nil {
+		t.Fatalf("CheckWindow Saturday morning: %v", err)
+	}
+
+	holiday := model.DeliveryWindow{
+		Start:    time.Date(2026, 12, 28, 10, 0, 0, 0, berlin),
+		End:      time.Date(2026, 12, 28, 12, 0, 0, 0, berlin),
+		TimeZone: "Europe/Berlin",
+	}
+
+	for name, unavailable := range map[string]model.DeliveryWindow{
+		"closed day":            window(20, 14, 16),
+		"before opening":        window(19, 7, 9),
+		"after closing":         window(24, 13, 15),
+		"too soon":              window(19, 13, 15),
+		"too far ahead":         window(26+7, 14, 16),
+		"ends before it starts": window(19, 16, 14),
+		"holiday":               holiday,
+		"spans two days": {
+			Start:    time.Date(2026, 10, 19, 19, 0, 0, 0, berlin),
+			End:      time.Date(2026, 10, 20, 9, 0, 0, 0, berlin),
+			TimeZone: "Europe/Berlin",
+		},
+		"unknown time zone": {
+			Start:    time.Date(2026, 10, 19, 14, 0, 0, 0, berlin),
+			End:      time.Date(2026, 10, 19, 16, 0, 0, 0, berlin),
+			TimeZone: "Mars/Olympus",
+		},
+	} {
+		if err := c.CheckWindow(unavailable, now); !errors.Is(err, ErrWindowUnavailable) {
+			t.Errorf("CheckWindow %s = %v, want ErrWindowUnavailable", name, err)
+		}
+	}
+}
+
+func TestNewRejectsInvalidCalendar(t *testing.T) {
+	for name, cfg := range map[string]config.CalendarConfig{
+		"time zone":      {TimeZone: "Mars/Olympus"},
+		"business day":   {BusinessHours: map[string]config.BusinessHours{"someday": {Open: "08:00", Close: "20:00"}}},
+		"opening time":   {BusinessHours: map[string]config.BusinessHours{"monday": {Open: "8am", Close: "20:00"}}},
+		"closing before": {BusinessHours: map[string]config.BusinessHours{"monday": {Open: "20:00", Close: "08:00"}}},
+		"holiday":        {Holidays: []string{"25.12.2026"}},
+	} {
+		if _, err := New(cfg); err == nil {
+			t.Errorf("New accepted an invalid %s", name)
+		}
+	}
+}
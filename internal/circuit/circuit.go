@@ -0,0 +1,254 @@
+// Package circuit guards the calls made to external destinations, such as
+// partner webhooks. Every destination has a circuit breaker, which stops
+// calls to it after consecutive failures and lets probe calls through once
+// it had time to recover, and a bulkhead, which limits the calls made to it
+// at a time.
+package circuit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-test/internal/config"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultFailureThreshold = 5
+	DefaultOpenTimeout      = 30 * time.Second
+	DefaultHalfOpenProbes   = 1
+	DefaultMaxConcurrent    = 3
+	DefaultMaxWait          = 5 * time.Second
+)
+
+// probeRetryDelay is the retry hint of calls rejected while probes are in
+// flight, which settle the state of the circuit within a request timeout.
+const probeRetryDelay = 5 * time.Second
+
+type State string
+
+const (
+	StateClosed   State = "closed"
+	StateOpen     State = "open"
+	StateHalfOpen State = "half_open"
+)
+
+var (
+	ErrCircuitOpen  = errors.New("circuit is open")
+	ErrBulkheadFull = errors.New("too many concurrent calls")
+)
+
+// UnavailableError is returned for a call that was not made, because the
+// circuit of its destination is open or the destination is already busy
+// with as many calls as it may get.
+type UnavailableError struct {
+	Destination string
+	// Reason is ErrCircuitOpen or ErrBulkheadFull.
+	Reason error
+	// RetryAfter is when the call stands a chance to be let through.
+	RetryAfter time.Duration
+}
+
+func (e *UnavailableError) Error() string {
+	return fmt.Sprintf("%s unavailable: %s, retry in %s", e.Destination, e.Reason, e.RetryAfter)
+}
+
+func (e *UnavailableError) Unwrap() error {
+	return e.Reason
+}
+
+// DestinationState is the state of the breaker and bulkhead of a
+// destination.
+type DestinationState struct {
+	Destination         string `json:"destination" example:"webhook.site"`
+	State               State  `json:"state" example:"open"`
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	// OpenedAt is when the circuit last opened, unset while it is closed.
+	OpenedAt *time.Time `json:"opened_at,omitempty"`
+	// ProbeAt is when an open circuit lets probe calls through.
+	ProbeAt       *time.Time `json:"probe_at,omitempty"`
+	InFlight      int        `json:"in_flight"`
+	MaxConcurrent int        `json:"max_concurrent"`
+}
+
+type destination struct {
+	state               State
+	consecutiveFailures int
+	openedAt            time.Time
+	probes              int
+	slots               chan struct{}
+}
+
+// Destinations holds the breaker and bulkhead of every destination called
+// so far. It is safe for concurrent use.
+type Destinations struct {
+	failureThreshold int
+	openTimeout      time.Duration
+	halfOpenProbes   int
+	maxConcurrent    int
+	maxWait          time.Duration
+
+	mu           sync.Mutex
+	destinations map[string]*destination
+	now          func() time.Time
+}
+
+func NewDestinations(cfg config.WebhooksConfig) *Destinations {
+	d := &Destinations{
+		failureThreshold: cfg.Breaker.FailureThreshold,
+		openTimeout:      cfg.Breaker.OpenTimeout.Duration(),
+		halfOpenProbes:   cfg.Breaker.HalfOpenProbes,
+		maxConcurrent:    cfg.MaxConcurrent,
+		maxWait:          cfg.MaxWait.Duration(),
+		destinations:     make(map[string]*destination),
+		now:              time.Now,
+	}
+	if d.failureThreshold <= 0 {
+		d.failureThreshold = DefaultFailureThreshold
+	}
+	if d.openTimeout <= 0 {
+		d.openTimeout = DefaultOpenTimeout
+	}
+	if d.halfOpenProbes <= 0 {
+		d.halfOpenProbes = DefaultHalfOpenProbes
+	}
+	if d.maxConcurrent <= 0 {
+		d.maxConcurrent = DefaultMaxConcurrent
+	}
+	if d.maxWait <= 0 {
+		d.maxWait = DefaultMaxWait
+	}
+
+	return d
+}
+
+// Acquire lets a call to the destination through, waiting for a slot of
+// its bulkhead if needed, or returns an *UnavailableError. The caller must
+// report the outcome of the call through release; failed is true for
+// outcomes that tell the destination is unhealthy.
+func (d *Destinations) Acquire(ctx context.Context, name string) (release func(failed bool), err error) {
+	dest, probe, err := d.allow(name)
+	if err != nil {
+		return nil, err
+	}
+
+	timer := time.NewTimer(d.maxWait)
+	defer timer.Stop()
+
+	select {
+	case dest.slots <- struct{}{}:
+	case <-timer.C:
+		d.abandon(dest, probe)
+		return nil, &UnavailableError{Destination: name, Reason: ErrBulkheadFull, RetryAfter: d.maxWait}
+	case <-ctx.Done():
+		d.abandon(dest, probe)
+		return nil, ctx.Err()
+	}
+
+	var once sync.Once
+	return func(failed bool) {
+		once.Do(func() {
+			<-dest.slots
+			d.record(dest, probe, failed)
+		})
+	}, nil
+}
+
+// States returns the state of every destination, ordered by name.
+func (d *Destinations) States() []DestinationState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	states := make([]DestinationState, 0, len(d.destinations))
+	for name, dest := range d.destinations {
+		state := DestinationState{
+			Destination:         name,
+			State:               dest.state,
+			ConsecutiveFailures: dest.consecutiveFailures,
+			InFlight:            len(dest.slots),
+			MaxConcurrent:       cap(dest.slots),
+		}
+		if dest.state != StateClosed {
+			openedAt := dest.openedAt
+			probeAt := dest.openedAt.Add(d.openTimeout)
+			state.OpenedAt, state.ProbeAt = &openedAt, &probeAt
+		}
+		states = append(states, state)
+	}
+
+	sort.Slice(states, func(i, j int) bool { return states[i].Destination < states[j].Destination })
+
+	return states
+}
+
+// allow checks the breaker of the destination. An open circuit turns
+// half-open once its timeout elapsed, letting a limited number of probe
+// calls through.
+func (d *Destinations) allow(name string) (*destination, bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	dest, ok := d.destinations[name]
+	if !ok {
+		dest = &destination{state: StateClosed, slots: make(chan struct{}, d.maxConcurrent)}
+		d.destinations[name] = dest
+	}
+
+	if dest.state == StateOpen {
+		probeAt := dest.openedAt.Add(d.openTimeout)
+		if now := d.now(); now.Before(probeAt) {
+			return nil, false, &UnavailableError{Destination: name, Reason: ErrCircuitOpen, RetryAfter: probeAt.Sub(now)}
+		}
+		dest.state = StateHalfOpen
+		dest.probes = 0
+	}
+
+	if dest.state == StateHalfOpen {
+		if dest.probes >= d.halfOpenProbes {
+			return nil, false, &UnavailableError{Destination: name, Reason: ErrCircuitOpen, RetryAfter: probeRetryDelay}
+		}
+		dest.probes++
+		return dest, true, nil
+	}
+
+	return dest, false, nil
+}
+
+// abandon gives back the probe of a call that was never made.
+func (d *Destinations) abandon(dest *destination, probe bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if probe && dest.state == StateHalfOpen {
+		dest.probes--
+	}
+}
+
+// record updates the breaker with the outcome of a call. A failed probe
+// opens the circuit again, a successful one closes it.
+func (d *Destinations) record(dest *destination, probe bool, failed bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !failed {
+		dest.consecutiveFailures = 0
+		if dest.state == StateHalfOpen {
+			dest.state = StateClosed
+			dest.openedAt = time.Time{}
+		}
+		return
+	}
+
+	dest.consecutiveFailures++
+
+	switch {
+	case dest.state == StateHalfOpen && probe:
+		dest.state = StateOpen
+		dest.openedAt = d.now()
+	case dest.state == StateClosed && dest.consecutiveFailures >= d.failureThreshold:
+		dest.state = StateOpen
+		dest.openedAt = d.now()
+	}
+}
@@ -0,0 +1,174 @@
+package circuit
+
+import (
+	"context"
+	"errors"
+	"go-test/internal/config"
+	"testing"
+	"time"
+)
+
+func newTestDestinations(now *time.Time) *Destinations {
+	d := NewDestinations(config.WebhooksConfig{
+		Breaker: config.BreakerConfig{
+			FailureThreshold: 3,
+			OpenTimeout:      config.Duration(time.Minute),
+		},
+		MaxConcurrent: 2,
+		MaxWait:       config.Duration(10 * time.Millisecond),
+	})
+	d.now = func() time.Time { return *now }
+
+	return d
+}
+
+func call(t *testing.T, d *Destinations, failed bool) {
+	t.Helper()
+
+	release, err := d.Acquire(context.Background(), "partner.example.com")
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	release(failed)
+}
+
+func TestBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	d := newTestDestinations(&now)
+
+	call(t, d, true)
+	call(t, d, true)
+	call(t, d, false)
+	call(t, d, true)
+	call(t, d, true)
+	if state := d.States()[0]; state.State != StateClosed || state.ConsecutiveFailures != 2 {
+		t.Fatalf("state after a success in between = %+v, want closed with 2 failures", state)
+	}
+
+	call(t, d, true)
+
+	now = now.Add(20 * time.Second)
+	_, err := d.Acquire(context.Background(), "partner.example.com")
+
+	var unavailable *UnavailableError
+	if !errors.As(err, &unavailable) || !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Acquire on an open circuit = %v, want ErrCircuitOpen", err)
+	}
+	if unavailable.RetryAfter != 40*time.Second {
+		t.Errorf("RetryAfter = %s, want 40s", unavailable.RetryAfter)
+	}
+
+	state := d.States()[0]
+	if state.State != StateOpen || state.ProbeAt == nil || !state.ProbeAt.Equal(now.Add(40*time.Second)) {
+		t.Errorf("state = %+v, want open until the probe", state)
+	}
+}
+
+func TestBreakerProbesWhenHalfOpen(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	d := newTestDestinations(&now)
+
+	for i := 0; i < 3; i++ {
+		call(t, d, true)
+	}
+	now = now.Add(time.Minute)
+
+	release, err := d.Acquire(context.Background(), "partner.example.com")
+	if err != nil {
+		t.Fatalf("Acquire probe: %v", err)
+	}
+	if state := d.States()[0].State; state != StateHalfOpen {
+		t.Fatalf("state while probing = %s, want half_open", state)
+	}
+	if _, err := d.Acquire(context.Background(), "partner.example.com"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Acquire during the probe = %v, want ErrCircuitOpen", err)
+	}
+
+	// A failed probe opens the circuit for another timeout.
+	release(true)
+	if _, err := d.Acquire(context.Background(), "partner.example.com"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Acquire after a failed probe = %v, want ErrCircuitOpen", err)
+	}
+
+	now = now.Add(time.Minute)
+	call(t, d, false)
+
+	if state := d.States()[0]; state.State != StateClosed || state.ConsecutiveFailures != 0 || state.OpenedAt != nil {
+		t.Errorf("state after a successful probe = %+v, want closed", state)
+	}
+}
+
+func TestBulkheadLimitsConcurrentCalls(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	d := newTestDestinations(&now)
+
+	first, err := d.Acquire(context.Background(), "partner.example.com")
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	second, err := d.Acquire(context.Background(), "partner.example.com")
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+
+	if state := d.States()[0]; state.InFlight != 2 || state.MaxConcurrent != 2 {
+		t.Errorf("state = %+v, want 2 of 2 calls in flight", state)
+	}
+
+	_, err = d.Acquire(context.Background(), "partner.example.com")
+	var unavailable *UnavailableError
+	if !errors.As(err, &unavailable) || !errors.Is(err, ErrBulkheadFull) {
+		t.Fatalf("Acquire over the limit = %v, want ErrBulkheadFull", err)
+	}
+
+	// Other destinations have their own bulkhead.
+	other, err := d.Acquire(context.Background(), "other.example.com")
+	if err != nil {
+		t.Fatalf("Acquire other destination: %v", err)
+	}
+	other(false)
+
+	first(false)
+	// Releasing twice must not free a slot of another call.
+	first(false)
+
+	third, err := d.Acquire(context.Background(), "partner.example.com")
+	if err != nil {
+		t.Fatalf("Acquire after a release: %v", err)
+	}
+	second(false)
+	third(false)
+
+	if state := d.States()[1]; state.Destination != "partner.example.com" || state.InFlight != 0 {
+		t.Errorf("state = %+v, want no calls in flight", state)
+	}
+}
+
+func TestBulkheadGivesBackProbeOfRejectedCall(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	d := newTestDestinations(&now)
+
+	for i := 0; i < 3; i++ {
+		call(t, d, true)
+	}
+	now = now.Add(time.Minute)
+
+	// Calls made before the circuit opened still fill the bulkhead.
+	slots := d.destinations["partner.example.com"].slots
+	slots <- struct{}{}
+	slots <- struct{}{}
+
+	if _, err := d.Acquire(context.Background(), "partner.example.com"); !errors.Is(err, ErrBulkheadFull) {
+		t.Fatalf("Acquire with a full bulkhead = %v, want ErrBulkheadFull", err)
+	}
+
+	<-slots
+	<-slots
+
+	// The rejected call must not use up the probe of the next one.
+	call(t, d, false)
+
+	if state := d.States()[0].State; state != StateClosed {
+		t.Errorf("state = %s, want closed", state)
+	}
+}
@@ -0,0 +1,70 @@
+package config
+
+import "fmt"
+
+// ActivityPolicy describes how an activity is retried and timed out. Zero
+// values mean "not set" so policies can be layered with Merge.
+type ActivityPolicy struct {
+	InitialInterval        Duration `json:"initial_interval,omitempty" swaggertype:"string" example:"30s"`
+	BackoffCoefficient     float64  `json:"backoff_coefficient,omitempty"`
+	MaximumInterval        Duration `json:"maximum_interval,omitempty" swaggertype:"string" example:"30s"`
+	MaximumAttempts        int32    `json:"maximum_attempts,omitempty"`
+	StartToCloseTimeout    Duration `json:"start_to_close_timeout,omitempty" swaggertype:"string" example:"30s"`
+	ScheduleToCloseTimeout Duration `json:"schedule_to_close_timeout,omitempty" swaggertype:"string" example:"30s"`
+	HeartbeatTimeout       Duration `json:"heartbeat_timeout,omitempty" swaggertype:"string" example:"30s"`
+	NonRetryableErrorTypes []string `json:"non_retryable_error_types,omitempty"`
+}
+
+// Merge returns p with every field that is set in override replaced.
+func (p ActivityPolicy) Merge(override ActivityPolicy) ActivityPolicy {
+	if override.InitialInterval != 0 {
+		p.InitialInterval = override.InitialInterval
+	}
+	if override.BackoffCoefficient != 0 {
+		p.BackoffCoefficient = override.BackoffCoefficient
+	}
+	if override.MaximumInterval != 0 {
+		p.MaximumInterval = override.MaximumInterval
+	}
+	if override.MaximumAttempts != 0 {
+		p.MaximumAttempts = override.MaximumAttempts
+	}
+	if override.StartToCloseTimeout != 0 {
+		p.StartToCloseTimeout = override.StartToCloseTimeout
+	}
+	if override.ScheduleToCloseTimeout != 0 {
+		p.ScheduleToCloseTimeout = override.ScheduleToCloseTimeout
+	}
+	if override.HeartbeatTimeout != 0 {
+		p.HeartbeatTimeout = override.HeartbeatTimeout
+	}
+	if override.NonRetryableErrorTypes != nil {
+		p.NonRetryableErrorTypes = override.NonRetryableErrorTypes
+	}
+
+	return p
+}
+
+// Validate checks that no field of the policy is negative and that a set
+// backoff coefficient does not shrink the retry interval.
+func (p ActivityPolicy) Validate() error {
+	for name, value := range map[string]Duration{
+		"initial_interval":          p.InitialInterval,
+		"maximum_interval":          p.MaximumInterval,
+		"start_to_close_timeout":    p.StartToCloseTimeout,
+		"schedule_to_close_timeout": p.ScheduleToCloseTimeout,
+		"heartbeat_timeout":         p.HeartbeatTimeout,
+	} {
+		if value < 0 {
+			return fmt.Errorf("%s must not be negative", name)
+		}
+	}
+	if p.MaximumAttempts < 0 {
+		return fmt.Errorf("maximum_attempts must not be negative")
+	}
+	if p.BackoffCoefficient != 0 && p.BackoffCoefficient < 1 {
+		return fmt.Errorf("backoff_coefficient must be at least 1")
+	}
+
+	return nil
+}
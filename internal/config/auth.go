@@ -0,0 +1,14 @@
+package config
+
+type AuthConfig struct {
+	// ConfirmationSecret signs the confirmation links sent to customers. It
+	// must be at least 32 bytes and the same on every replica.
+	ConfirmationSecret string `json:"confirmation_secret"`
+	// ConfirmationTTL is how long a confirmation link stays valid.
+	ConfirmationTTL Duration `json:"confirmation_ttl"`
+	// ConfirmationBaseURL is the public address of the API the links point to.
+	ConfirmationBaseURL string `json:"confirmation_base_url"`
+	// OperatorTokens maps operator names to the bearer tokens they
+	// authenticate with.
+	OperatorTokens map[string]string `json:"operator_tokens"`
+}
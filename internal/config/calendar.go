@@ -0,0 +1,24 @@
+package config
+
+// CalendarConfig describes when packages can be delivered, which the
+// delivery windows chosen by customers are checked against.
+type CalendarConfig struct {
+	// TimeZone is the IANA time zone of the business hours and holidays,
+	// UTC when empty.
+	TimeZone string `json:"time_zone"`
+	// BusinessHours maps lowercase weekday names to the hours deliveries are
+	// made on that day. Days without an entry are closed.
+	BusinessHours map[string]BusinessHours `json:"business_hours"`
+	// Holidays are closed dates, as YYYY-MM-DD.
+	Holidays []string `json:"holidays"`
+	// MinNotice is the time a window must start after it is chosen.
+	MinNotice Duration `json:"min_notice"`
+	// MaxAdvance is how far ahead a window may start.
+	MaxAdvance Duration `json:"max_advance"`
+}
+
+// BusinessHours are the opening and closing times of a day, as HH:MM.
+type BusinessHours struct {
+	Open  string `json:"open"`
+	Close string `json:"close"`
+}
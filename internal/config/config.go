@@ -0,0 +1,73 @@
+// Package config loads the service configuration from a JSON file. Every
+// section is optional; missing values fall back to the built-in defaults of
+// the component that consumes them.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+const PathEnv = "CONFIG_PATH"
+
+type Config struct {
+	Worker   WorkerConfig   `json:"worker"`
+	Workflow WorkflowConfig `json:"workflow"`
+	Storage  StorageConfig  `json:"storage"`
+	Auth     AuthConfig     `json:"auth"`
+	Calendar CalendarConfig `json:"calendar"`
+	Webhooks WebhooksConfig `json:"webhooks"`
+}
+
+type WorkerConfig struct {
+	// BuildID identifies the workflow code deployed with this worker.
+	BuildID string `json:"build_id"`
+	// UseBuildIDVersioning pins workflows to the build ID they started on,
+	// so old and new workflow code can run side by side.
+	UseBuildIDVersioning bool `json:"use_build_id_versioning"`
+	// CompatibleWithBuildID marks BuildID as a compatible successor of an
+	// existing build ID, letting running workflows move over to it. When
+	// empty, BuildID becomes the new default for new workflows only.
+	CompatibleWithBuildID string `json:"compatible_with_build_id"`
+}
+
+type WorkflowConfig struct {
+	// ActivityPolicies overrides the retry and timeout policy of activities,
+	// keyed by activity name.
+	ActivityPolicies map[string]ActivityPolicy `json:"activity_policies"`
+	// CompensationPolicies overrides what happens once an activity has
+	// exhausted its retries, keyed by activity name. The actions are
+	// markFailed, rollback and park.
+	CompensationPolicies map[string]string      `json:"compensation_policies"`
+	DeliveryAttempts     DeliveryAttemptsConfig `json:"delivery_attempts"`
+	HistoryLimits        HistoryLimitsConfig    `json:"history_limits"`
+	StuckDetection       StuckDetectionConfig   `json:"stuck_detection"`
+	DeliveryReport       DeliveryReportConfig   `json:"delivery_report"`
+	DeliveryWindows      DeliveryWindowsConfig  `json:"delivery_windows"`
+	// CustomerEmailHashSecret keys the CustomerEmailHash search attribute.
+	// Without it the attribute is not published and workflows cannot be
+	// searched by customer. Changing it hides the running workflows from
+	// searches by customer.
+	CustomerEmailHashSecret string `json:"customer_email_hash_secret"`
+}
+
+// Load reads the configuration file at path. An empty path yields the zero
+// configuration, so every component runs with its defaults.
+func Load(path string) (*Config, error) {
+	cfg := &Config{}
+	if path == "" {
+		return cfg, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read config file %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(content, cfg); err != nil {
+		return nil, fmt.Errorf("unable to parse config file %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
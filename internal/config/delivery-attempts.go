@@ -0,0 +1,11 @@
+package config
+
+// DeliveryAttemptsConfig controls what happens after a driver fails to
+// deliver a package.
+type DeliveryAttemptsConfig struct {
+	// ReattemptDelay is the time between a failed attempt and the next one.
+	ReattemptDelay Duration `json:"reattempt_delay"`
+	// MaxFailedAttempts is the number of failed attempts after which the
+	// package is returned to the sender.
+	MaxFailedAttempts int `json:"max_failed_attempts"`
+}
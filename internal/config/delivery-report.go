@@ -0,0 +1,12 @@
+package config
+
+// DeliveryReportConfig is the initial schedule of the daily delivery report.
+// It only applies when the schedule is created, operators change it through
+// the admin API afterwards.
+type DeliveryReportConfig struct {
+	// Time is the time of day the report runs at, as HH:MM in TimeZone.
+	Time string `json:"time"`
+	// TimeZone is an IANA time zone name, UTC when empty.
+	TimeZone   string   `json:"time_zone"`
+	Recipients []string `json:"recipients"`
+}
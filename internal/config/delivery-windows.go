@@ -0,0 +1,9 @@
+package config
+
+// DeliveryWindowsConfig controls how the delivery workflow waits for the
+// delivery window a customer chose.
+type DeliveryWindowsConfig struct {
+	// NoticeLead is the time before the window the customer is told that
+	// the package arrives soon, and the confirmation window opens.
+	NoticeLead Duration `json:"notice_lead"`
+}
@@ -0,0 +1,13 @@
+package config
+
+// HistoryLimitsConfig bounds the history of long-lived workflows. Once a
+// limit is reached, the workflow continues as new and carries its state
+// over to the new run.
+type HistoryLimitsConfig struct {
+	// MaxEvents is the number of history events after which the workflow
+	// continues as new.
+	MaxEvents int `json:"max_events"`
+	// MaxSizeBytes is the history size after which the workflow continues
+	// as new.
+	MaxSizeBytes int `json:"max_size_bytes"`
+}
@@ -0,0 +1,29 @@
+package config
+
+const (
+	StorageBackendLocal = "local"
+	StorageBackendS3    = "s3"
+)
+
+type StorageConfig struct {
+	// Backend selects the object store, "local" (the default) or "s3".
+	Backend string       `json:"backend"`
+	Local   LocalStorage `json:"local"`
+	S3      S3Storage    `json:"s3"`
+}
+
+type LocalStorage struct {
+	// Dir is the directory objects are written to.
+	Dir string `json:"dir"`
+}
+
+type S3Storage struct {
+	Bucket string `json:"bucket"`
+	Region string `json:"region"`
+	// Endpoint points the client at an S3-compatible service such as MinIO
+	// or LocalStack. Empty means AWS.
+	Endpoint string `json:"endpoint"`
+	// ForcePathStyle addresses buckets as endpoint/bucket, which most
+	// S3-compatible services require.
+	ForcePathStyle bool `json:"force_path_style"`
+}
@@ -0,0 +1,12 @@
+package config
+
+// StuckDetectionConfig controls the scheduled scan for packages that stay in
+// one status for too long.
+type StuckDetectionConfig struct {
+	// ScanInterval is the time between two scans.
+	ScanInterval Duration `json:"scan_interval"`
+	// Thresholds maps package statuses to the time a package may keep them
+	// before it is reported as stuck. Statuses without a threshold are
+	// never reported.
+	Thresholds map[string]Duration `json:"thresholds"`
+}
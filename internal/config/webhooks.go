@@ -0,0 +1,116 @@
+package config
+
+// WebhooksConfig describes the webhook destinations notifications are sent
+// to, and protects them from being overwhelmed while they are down or slow.
+type WebhooksConfig struct {
+	// Destinations are keyed by name: "notifications" receives the messages
+	// to customers, "support" the disputes and "operations" the alerts and
+	// reports. Settings a destination leaves unset are taken from the
+	// "default" destination, and then from the built-in defaults.
+	Destinations map[string]WebhookDestinationConfig `json:"destinations"`
+	Breaker      BreakerConfig                       `json:"breaker"`
+	// MaxConcurrent is the number of calls made to one destination at a
+	// time.
+	MaxConcurrent int `json:"max_concurrent"`
+	// MaxWait is how long a call waits for one of those slots before it is
+	// rejected and retried later.
+	MaxWait Duration `json:"max_wait"`
+}
+
+// BreakerConfig controls the circuit breaker of each destination.
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that open the
+	// circuit.
+	FailureThreshold int `json:"failure_threshold"`
+	// OpenTimeout is how long the circuit stays open before probe calls
+	// are let through.
+	OpenTimeout Duration `json:"open_timeout"`
+	// HalfOpenProbes is the number of probe calls made at a time while the
+	// circuit is half-open.
+	HalfOpenProbes int `json:"half_open_probes"`
+}
+
+// WebhookDestinationConfig describes where the webhooks of a destination
+// are sent and how. Zero values mean "not set" so destinations can be
+// layered with Merge.
+type WebhookDestinationConfig struct {
+	URL string `json:"url,omitempty"`
+	// Timeout bounds a whole call, including reading the response.
+	Timeout               Duration `json:"timeout,omitempty"`
+	DialTimeout           Duration `json:"dial_timeout,omitempty"`
+	ResponseHeaderTimeout Duration `json:"response_header_timeout,omitempty"`
+	MaxConnsPerHost       int      `json:"max_conns_per_host,omitempty"`
+	// MaxRedirects is the number of redirects followed. Redirects are
+	// refused when unset.
+	MaxRedirects int `json:"max_redirects,omitempty"`
+	// ProxyURL is the HTTP proxy calls go through. Calls are made directly
+	// when unset.
+	ProxyURL string            `json:"proxy_url,omitempty"`
+	TLS      WebhookTLSConfig  `json:"tls"`
+	Auth     WebhookAuthConfig `json:"auth"`
+}
+
+// WebhookTLSConfig holds PEM files. CAFile replaces the system roots the
+// destination is verified against; CertFile and KeyFile are the client
+// certificate presented for mutual TLS.
+type WebhookTLSConfig struct {
+	CAFile     string `json:"ca_file,omitempty"`
+	CertFile   string `json:"cert_file,omitempty"`
+	KeyFile    string `json:"key_file,omitempty"`
+	ServerName string `json:"server_name,omitempty"`
+}
+
+// WebhookAuthConfig holds either basic auth credentials or a bearer token.
+type WebhookAuthConfig struct {
+	Username    string `json:"username,omitempty"`
+	Password    string `json:"password,omitempty"`
+	BearerToken string `json:"bearer_token,omitempty"`
+}
+
+func (a WebhookAuthConfig) set() bool {
+	return a.Username != "" || a.Password != "" || a.BearerToken != ""
+}
+
+// Merge returns d with every field that is set in override replaced. The
+// credentials are replaced as a whole, so a destination never mixes the
+// credentials of two others.
+func (d WebhookDestinationConfig) Merge(override WebhookDestinationConfig) WebhookDestinationConfig {
+	if override.URL != "" {
+		d.URL = override.URL
+	}
+	if override.Timeout != 0 {
+		d.Timeout = override.Timeout
+	}
+	if override.DialTimeout != 0 {
+		d.DialTimeout = override.DialTimeout
+	}
+	if override.ResponseHeaderTimeout != 0 {
+		d.ResponseHeaderTimeout = override.ResponseHeaderTimeout
+	}
+	if override.MaxConnsPerHost != 0 {
+		d.MaxConnsPerHost = override.MaxConnsPerHost
+	}
+	if override.MaxRedirects != 0 {
+		d.MaxRedirects = override.MaxRedirects
+	}
+	if override.ProxyURL != "" {
+		d.ProxyURL = override.ProxyURL
+	}
+	if override.TLS.CAFile != "" {
+		d.TLS.CAFile = override.TLS.CAFile
+	}
+	if override.TLS.CertFile != "" {
+		d.TLS.CertFile = override.TLS.CertFile
+	}
+	if override.TLS.KeyFile != "" {
+		d.TLS.KeyFile = override.TLS.KeyFile
+	}
+	if override.TLS.ServerName != "" {
+		d.TLS.ServerName = override.TLS.ServerName
+	}
+	if override.Auth.set() {
+		d.Auth = override.Auth
+	}
+
+	return d
+}
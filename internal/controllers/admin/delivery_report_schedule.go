@@ -0,0 +1,140 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-test/internal/audit"
+	"go-test/internal/auth"
+	"go-test/internal/model"
+	"go-test/internal/workflow"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/client"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+)
+
+type DeliveryReportScheduleRequest struct {
+	Time       string   `json:"time" binding:"required" example:"06:00"`
+	TimeZone   string   `json:"time_zone" example:"Europe/Berlin"`
+	Recipients []string `json:"recipients"`
+}
+
+type DeliveryReportScheduleController struct {
+	Logger         *zap.Logger
+	TemporalClient client.Client
+	Operators      *auth.Operators
+	Audit          *audit.Log
+}
+
+func RegisterDeliveryReportScheduleController(logger *zap.Logger, temporalClient client.Client, operators *auth.Operators, auditLog *audit.Log) *DeliveryReportScheduleController {
+	return &DeliveryReportScheduleController{
+		Logger:         logger,
+		TemporalClient: temporalClient,
+		Operators:      operators,
+		Audit:          auditLog,
+	}
+}
+
+// GetDeliveryReportSchedule godoc
+// @Summary      Get the delivery report schedule
+// @Description  Return the time of day the daily delivery report runs at and its recipients. Requires an operator
+// @Description  bearer token.
+// @Tags         admin
+// @Produce      json
+// @Param        Authorization header string true "Operator bearer token"
+// @Success      200 {object} workflow.DeliveryReportSchedule "Report schedule"
+// @Failure      401 {object} model.HttpErrorResponse "Authentication required"
+// @Failure      404 {object} model.HttpErrorResponse "Report schedule not found"
+// @Failure      502 {object} model.HttpErrorResponse "Unable to read the report schedule"
+// @Router       /api/v1/admin/reports/delivery/schedule [get]
+func (c *DeliveryReportScheduleController) GetDeliveryReportSchedule(ctx *gin.Context) {
+	if _, ok := c.Operators.Authenticate(ctx.Request); !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Operator authentication is required"})
+		return
+	}
+
+	schedule, err := workflow.GetDeliveryReportSchedule(context.Background(), c.TemporalClient)
+	if !c.handleError(ctx, "Unable to read the report schedule", err) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, schedule)
+}
+
+// UpdateDeliveryReportSchedule godoc
+// @Summary      Change the delivery report schedule
+// @Description  Set the time of day, as HH:MM in the time zone, the daily delivery report runs at and the addresses
+// @Description  it is sent to. The time zone is UTC when empty. Requires an operator bearer token.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Operator bearer token"
+// @Param        body body DeliveryReportScheduleRequest true "Report schedule"
+// @Success      200 {object} workflow.DeliveryReportSchedule "Updated report schedule"
+// @Failure      400 {object} model.HttpErrorResponse "Invalid report schedule"
+// @Failure      401 {object} model.HttpErrorResponse "Authentication required"
+// @Failure      404 {object} model.HttpErrorResponse "Report schedule not found"
+// @Failure      502 {object} model.HttpErrorResponse "Unable to update the report schedule"
+// @Router       /api/v1/admin/reports/delivery/schedule [put]
+func (c *DeliveryReportScheduleController) UpdateDeliveryReportSchedule(ctx *gin.Context) {
+	operator, ok := c.Operators.Authenticate(ctx.Request)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Operator authentication is required"})
+		return
+	}
+
+	var req DeliveryReportScheduleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data"})
+		return
+	}
+
+	schedule, err := workflow.UpdateDeliveryReportSchedule(context.Background(), c.TemporalClient, workflow.DeliveryReportSchedule{
+		Time:       req.Time,
+		TimeZone:   req.TimeZone,
+		Recipients: req.Recipients,
+	})
+	if errors.Is(err, workflow.ErrInvalidReportSchedule) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !c.handleError(ctx, "Unable to update the report schedule", err) {
+		return
+	}
+
+	c.Logger.Info("Updated delivery report schedule",
+		zap.String("operator", operator),
+		zap.String("time", schedule.Time),
+		zap.String("timeZone", schedule.TimeZone),
+		zap.Strings("recipients", schedule.Recipients))
+
+	c.Audit.Record(ctx.Request.Context(), model.AuditEntry{
+		SubjectType: model.AuditSubjectDeliveryReportSchedule,
+		SubjectID:   workflow.DeliveryReportScheduleID,
+		Operator:    operator,
+		Action:      model.AuditUpdateSchedule,
+		Detail:      schedule.Time + " " + schedule.TimeZone + " to " + strings.Join(schedule.Recipients, ", "),
+	})
+
+	ctx.JSON(http.StatusOK, schedule)
+}
+
+// handleError writes the response for an error of the schedule service and
+// reports whether the request can go on.
+func (c *DeliveryReportScheduleController) handleError(ctx *gin.Context, message string, err error) bool {
+	if err == nil {
+		return true
+	}
+
+	var notFound *serviceerror.NotFound
+	if errors.As(err, &notFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Report schedule not found"})
+		return false
+	}
+
+	c.Logger.Error(message, zap.Error(err))
+	ctx.JSON(http.StatusBadGateway, gin.H{"error": message})
+	return false
+}
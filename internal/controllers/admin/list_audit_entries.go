@@ -0,0 +1,112 @@
+package admin
+
+import (
+	"github.com/gin-gonic/gin"
+	"go-test/internal/audit"
+	"go-test/internal/auth"
+	"go-test/internal/model"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type ListAuditEntriesResponse struct {
+	PackageID string             `json:"package_id"`
+	Entries   []model.AuditEntry `json:"entries"`
+}
+
+type ListSubjectAuditEntriesResponse struct {
+	SubjectType model.AuditSubjectType `json:"subject_type"`
+	SubjectID   string                 `json:"subject_id"`
+	Entries     []model.AuditEntry     `json:"entries"`
+}
+
+type ListAuditEntriesController struct {
+	Logger    *zap.Logger
+	Operators *auth.Operators
+	Audit     *audit.Log
+}
+
+func RegisterListAuditEntriesController(logger *zap.Logger, operators *auth.Operators, auditLog *audit.Log) *ListAuditEntriesController {
+	return &ListAuditEntriesController{
+		Logger:    logger,
+		Operators: operators,
+		Audit:     auditLog,
+	}
+}
+
+// ListAuditEntries godoc
+// @Summary      List the audit log of a package
+// @Description  List the actions operators took on a package, oldest first. Requires an operator bearer token.
+// @Tags         admin
+// @Produce      json
+// @Param        id path string true "Package ID"
+// @Param        Authorization header string true "Operator bearer token"
+// @Success      200 {object} ListAuditEntriesResponse
+// @Failure      400 {object} model.HttpErrorResponse "Invalid input data"
+// @Failure      401 {object} model.HttpErrorResponse "Authentication required"
+// @Failure      500 {object} model.HttpErrorResponse "Internal error"
+// @Router       /api/v1/admin/packages/{id}/audit [get]
+func (c *ListAuditEntriesController) ListAuditEntries(ctx *gin.Context) {
+	packageId := ctx.Param("id")
+
+	if packageId == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Package ID is required"})
+		return
+	}
+
+	if _, ok := c.Operators.Authenticate(ctx.Request); !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Operator authentication is required"})
+		return
+	}
+
+	entries, err := c.Audit.Entries(ctx.Request.Context(), model.AuditSubjectPackage, packageId)
+	if err != nil {
+		c.Logger.Error("Unable to list audit entries", zap.String("packageId", packageId), zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list audit entries"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &ListAuditEntriesResponse{PackageID: packageId, Entries: entries})
+}
+
+// ListSubjectAuditEntries godoc
+// @Summary      List the audit log of a subject
+// @Description  List the actions operators took on a subject, oldest first. Notification templates are identified by
+// @Description  event/channel/locale, the delivery report schedule by its schedule ID, webhook subscriptions by their
+// @Description  URL and customer preferences by the customer email. Requires an operator bearer token.
+// @Tags         admin
+// @Produce      json
+// @Param        subject_type query string true "Subject type" Enums(package, notificationTemplate, deliveryReportSchedule, webhookSubscription, customerPreferences)
+// @Param        subject_id query string true "Subject ID"
+// @Param        Authorization header string true "Operator bearer token"
+// @Success      200 {object} ListSubjectAuditEntriesResponse
+// @Failure      400 {object} model.HttpErrorResponse "Invalid input data"
+// @Failure      401 {object} model.HttpErrorResponse "Authentication required"
+// @Failure      500 {object} model.HttpErrorResponse "Internal error"
+// @Router       /api/v1/admin/audit [get]
+func (c *ListAuditEntriesController) ListSubjectAuditEntries(ctx *gin.Context) {
+	subjectType := model.AuditSubjectType(ctx.Query("subject_type"))
+	subjectId := ctx.Query("subject_id")
+
+	if !subjectType.Valid() || subjectId == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "A known subject_type and a subject_id are required"})
+		return
+	}
+
+	if _, ok := c.Operators.Authenticate(ctx.Request); !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Operator authentication is required"})
+		return
+	}
+
+	entries, err := c.Audit.Entries(ctx.Request.Context(), subjectType, subjectId)
+	if err != nil {
+		c.Logger.Error("Unable to list audit entries",
+			zap.String("subjectType", string(subjectType)),
+			zap.String("subjectId", subjectId),
+			zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list audit entries"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &ListSubjectAuditEntriesResponse{SubjectType: subjectType, SubjectID: subjectId, Entries: entries})
+}
@@ -0,0 +1,157 @@
+package admin
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-test/internal/auth"
+	"go-test/internal/model"
+	"go-test/internal/workflow"
+	"go.temporal.io/sdk/client"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const defaultWorkflowsPageSize = 50
+
+type ListWorkflowsResponse struct {
+	Query         string                      `json:"query"`
+	Workflows     []workflow.ExecutionSummary `json:"workflows"`
+	NextPageToken string                      `json:"next_page_token,omitempty"`
+}
+
+type ListWorkflowsController struct {
+	Logger         *zap.Logger
+	TemporalClient client.Client
+	Operators      *auth.Operators
+	EmailHasher    *workflow.CustomerEmailHasher
+}
+
+func RegisterListWorkflowsController(logger *zap.Logger, temporalClient client.Client, operators *auth.Operators, emailHasher *workflow.CustomerEmailHasher) *ListWorkflowsController {
+	return &ListWorkflowsController{
+		Logger:         logger,
+		TemporalClient: temporalClient,
+		Operators:      operators,
+		EmailHasher:    emailHasher,
+	}
+}
+
+// ListWorkflows godoc
+// @Summary      Find package delivery workflows
+// @Description  Run a Temporal visibility query over the package delivery workflows, filtered by the search
+// @Description  attributes they publish. Requires an operator bearer token.
+// @Tags         admin
+// @Produce      json
+// @Param        Authorization header string true "Operator bearer token"
+// @Param        status query string false "Package status"
+// @Param        customer_email query string false "Customer email, matched by its keyed hash"
+// @Param        region query string false "Delivery region"
+// @Param        created_after query string false "Created at or after, RFC 3339"
+// @Param        created_before query string false "Created before, RFC 3339"
+// @Param        running query bool false "Only running workflows"
+// @Param        page_size query int false "Workflows per page, 50 by default"
+// @Param        page_token query string false "Token of the next page"
+// @Success      200 {object} ListWorkflowsResponse "Matching workflows"
+// @Failure      400 {object} model.HttpErrorResponse "Invalid filter"
+// @Failure      401 {object} model.HttpErrorResponse "Authentication required"
+// @Failure      502 {object} model.HttpErrorResponse "Unable to list workflows"
+// @Router       /api/v1/admin/workflows [get]
+func (c *ListWorkflowsController) ListWorkflows(ctx *gin.Context) {
+	operator, ok := c.Operators.Authenticate(ctx.Request)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Operator authentication is required"})
+		return
+	}
+
+	filter, err := c.parseExecutionFilter(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query, err := filter.VisibilityQuery()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pageSize := defaultWorkflowsPageSize
+	if value := ctx.Query("page_size"); value != "" {
+		if pageSize, err = strconv.Atoi(value); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page_size"})
+			return
+		}
+	}
+
+	pageToken, err := base64.RawURLEncoding.DecodeString(ctx.Query("page_token"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page_token"})
+		return
+	}
+
+	workflows, nextPageToken, err := workflow.ListExecutionPage(context.Background(), c.TemporalClient, filter, pageSize, pageToken)
+	if errors.Is(err, workflow.ErrInvalidPageSize) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.Logger.Error("Unable to list workflows", zap.String("operator", operator), zap.String("query", query), zap.Error(err))
+		ctx.JSON(http.StatusBadGateway, gin.H{"error": "Unable to list workflows"})
+		return
+	}
+
+	c.Logger.Info("Listed workflows", zap.String("operator", operator), zap.String("query", query), zap.Int("count", len(workflows)))
+
+	ctx.JSON(http.StatusOK, &ListWorkflowsResponse{
+		Query:         query,
+		Workflows:     workflows,
+		NextPageToken: base64.RawURLEncoding.EncodeToString(nextPageToken),
+	})
+}
+
+func (c *ListWorkflowsController) parseExecutionFilter(ctx *gin.Context) (workflow.ExecutionFilter, error) {
+	filter := workflow.ExecutionFilter{
+		Status: model.PackageDeliveryState(ctx.Query("status")),
+		Region: ctx.Query("region"),
+	}
+
+	if email := ctx.Query("customer_email"); email != "" {
+		if !c.EmailHasher.Enabled() {
+			return filter, errors.New("searching by customer_email requires a customer email hash secret")
+		}
+		filter.CustomerEmailHashes = c.EmailHasher.SearchHashes(email)
+	}
+
+	var err error
+	if filter.CreatedAfter, err = parseTimeParam(ctx, "created_after"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedBefore, err = parseTimeParam(ctx, "created_before"); err != nil {
+		return filter, err
+	}
+
+	if value := ctx.Query("running"); value != "" {
+		if filter.Running, err = strconv.ParseBool(value); err != nil {
+			return filter, errors.New("invalid running")
+		}
+	}
+
+	return filter, nil
+}
+
+func parseTimeParam(ctx *gin.Context, name string) (time.Time, error) {
+	value := ctx.Query(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.New("invalid " + name + ", expected RFC 3339")
+	}
+
+	return t, nil
+}
@@ -0,0 +1,283 @@
+package admin
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-test/internal/audit"
+	"go-test/internal/auth"
+	"go-test/internal/model"
+	"go-test/internal/templates"
+	"go-test/repository"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type NotificationTemplateRequest struct {
+	Subject string `json:"subject" example:"Package {{.Package.ID}} was delivered"`
+	Body    string `json:"body" binding:"required" example:"Package {{.Package.ID}} was delivered to {{.Package.DeliveryAddress}}."`
+}
+
+type PreviewTemplateRequest struct {
+	Event   model.NotificationKind    `json:"event" binding:"required" example:"delivered"`
+	Channel model.NotificationChannel `json:"channel" binding:"required" example:"sms"`
+	Locale  string                    `json:"locale" example:"de-AT"`
+	// Body previews a draft instead of the template in use.
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+type PreviewTemplateResponse struct {
+	// Locale is the locale of the template used after the fallback rules.
+	Locale string `json:"locale" example:"de"`
+	// Source is database, embedded or draft.
+	Source  string                    `json:"source" example:"embedded"`
+	Message model.NotificationMessage `json:"message"`
+}
+
+type NotificationTemplatesController struct {
+	Logger    *zap.Logger
+	Store     repository.NotificationTemplateStore
+	Engine    *templates.Engine
+	Operators *auth.Operators
+	Audit     *audit.Log
+}
+
+func RegisterNotificationTemplatesController(
+	logger *zap.Logger,
+	store repository.NotificationTemplateStore,
+	operators *auth.Operators,
+	auditLog *audit.Log,
+) *NotificationTemplatesController {
+	return &NotificationTemplatesController{
+		Logger:    logger,
+		Store:     store,
+		Engine:    templates.NewEngine(store, logger),
+		Operators: operators,
+		Audit:     auditLog,
+	}
+}
+
+// templateSubjectID identifies a template in the audit log.
+func templateSubjectID(event model.NotificationKind, channel model.NotificationChannel, locale string) string {
+	return string(event) + "/" + string(channel) + "/" + locale
+}
+
+// ListNotificationTemplates godoc
+// @Summary      List the stored notification templates
+// @Description  Return the templates stored in the database, which override the templates embedded in the service.
+// @Description  Requires an operator bearer token.
+// @Tags         admin
+// @Produce      json
+// @Param        Authorization header string true "Operator bearer token"
+// @Success      200 {array} model.NotificationTemplate "Stored templates"
+// @Failure      401 {object} model.HttpErrorResponse "Authentication required"
+// @Failure      500 {object} model.HttpErrorResponse "Internal error"
+// @Router       /api/v1/admin/templates [get]
+func (c *NotificationTemplatesController) ListNotificationTemplates(ctx *gin.Context) {
+	if _, ok := c.Operators.Authenticate(ctx.Request); !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Operator authentication is required"})
+		return
+	}
+
+	stored, err := c.Store.ListNotificationTemplates(ctx.Request.Context())
+	if err != nil {
+		c.Logger.Error("Unable to list notification templates", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list notification templates"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, stored)
+}
+
+// SaveNotificationTemplate godoc
+// @Summary      Store a notification template
+// @Description  Create or replace the template of an event, channel and locale. Templates use Go template syntax with
+// @Description  the variables .Package, .ConfirmationLink, .LinkExpiresAt, .DeliveryWindow, .Attempt, .NextAttemptAt
+// @Description  and .Shipment, and the function local to convert a time to a time zone. Email bodies are HTML. The
+// @Description  template must render with sample data of its event. Requires an operator bearer token.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        event path string true "Event" Enums(confirmation_request, delivered, arrival, failed_attempt, returned_to_sender, shipment_delivered)
+// @Param        channel path string true "Channel" Enums(email, sms, push)
+// @Param        locale path string true "Locale, such as en or de-AT"
+// @Param        Authorization header string true "Operator bearer token"
+// @Param        body body NotificationTemplateRequest true "Template"
+// @Success      200 {object} model.NotificationTemplate "Stored template"
+// @Failure      400 {object} model.HttpErrorResponse "Invalid template"
+// @Failure      401 {object} model.HttpErrorResponse "Authentication required"
+// @Failure      500 {object} model.HttpErrorResponse "Internal error"
+// @Router       /api/v1/admin/templates/{event}/{channel}/{locale} [put]
+func (c *NotificationTemplatesController) SaveNotificationTemplate(ctx *gin.Context) {
+	operator, ok := c.Operators.Authenticate(ctx.Request)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Operator authentication is required"})
+		return
+	}
+
+	var req NotificationTemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data"})
+		return
+	}
+
+	template := &model.NotificationTemplate{
+		Event:     model.NotificationKind(ctx.Param("event")),
+		Channel:   model.NotificationChannel(ctx.Param("channel")),
+		Locale:    ctx.Param("locale"),
+		Subject:   req.Subject,
+		Body:      req.Body,
+		UpdatedAt: time.Now().UTC(),
+	}
+	if err := templates.Validate(*template); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.Store.SaveNotificationTemplate(ctx.Request.Context(), template); err != nil {
+		c.Logger.Error("Unable to save notification template", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save notification template"})
+		return
+	}
+
+	c.Logger.Info("Saved notification template",
+		zap.String("operator", operator),
+		zap.String("event", string(template.Event)),
+		zap.String("channel", string(template.Channel)),
+		zap.String("locale", template.Locale))
+
+	c.Audit.Record(ctx.Request.Context(), model.AuditEntry{
+		SubjectType: model.AuditSubjectNotificationTemplate,
+		SubjectID:   templateSubjectID(template.Event, template.Channel, template.Locale),
+		Operator:    operator,
+		Action:      model.AuditSaveTemplate,
+		Detail:      template.Body,
+	})
+
+	ctx.JSON(http.StatusOK, template)
+}
+
+// DeleteNotificationTemplate godoc
+// @Summary      Delete a stored notification template
+// @Description  Notifications fall back to the next locale, and finally to the embedded template. Requires an operator
+// @Description  bearer token.
+// @Tags         admin
+// @Param        event path string true "Event"
+// @Param        channel path string true "Channel"
+// @Param        locale path string true "Locale"
+// @Param        Authorization header string true "Operator bearer token"
+// @Success      204 "Template deleted"
+// @Failure      401 {object} model.HttpErrorResponse "Authentication required"
+// @Failure      404 {object} model.HttpErrorResponse "Template not found"
+// @Failure      500 {object} model.HttpErrorResponse "Internal error"
+// @Router       /api/v1/admin/templates/{event}/{channel}/{locale} [delete]
+func (c *NotificationTemplatesController) DeleteNotificationTemplate(ctx *gin.Context) {
+	operator, ok := c.Operators.Authenticate(ctx.Request)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Operator authentication is required"})
+		return
+	}
+
+	event := model.NotificationKind(ctx.Param("event"))
+	channel := model.NotificationChannel(ctx.Param("channel"))
+	locale := ctx.Param("locale")
+
+	err := c.Store.DeleteNotificationTemplate(ctx.Request.Context(), event, channel, locale)
+	if errors.Is(err, repository.ErrTemplateNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Notification template not found"})
+		return
+	}
+	if err != nil {
+		c.Logger.Error("Unable to delete notification template", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete notification template"})
+		return
+	}
+
+	c.Logger.Info("Deleted notification template",
+		zap.String("operator", operator),
+		zap.String("event", string(event)),
+		zap.String("channel", string(channel)),
+		zap.String("locale", locale))
+
+	c.Audit.Record(ctx.Request.Context(), model.AuditEntry{
+		SubjectType: model.AuditSubjectNotificationTemplate,
+		SubjectID:   templateSubjectID(event, channel, locale),
+		Operator:    operator,
+		Action:      model.AuditDeleteTemplate,
+	})
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// PreviewNotificationTemplate godoc
+// @Summary      Preview a notification template
+// @Description  Render the template of an event, channel and locale with sample data. Without a body, the template the
+// @Description  notification would use is rendered, after falling back from the locale to its language and to English.
+// @Description  With a body, the draft is rendered instead. Requires an operator bearer token.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Operator bearer token"
+// @Param        body body PreviewTemplateRequest true "Template to preview"
+// @Success      200 {object} PreviewTemplateResponse "Rendered message"
+// @Failure      400 {object} model.HttpErrorResponse "Invalid template"
+// @Failure      401 {object} model.HttpErrorResponse "Authentication required"
+// @Failure      500 {object} model.HttpErrorResponse "Internal error"
+// @Router       /api/v1/admin/templates/preview [post]
+func (c *NotificationTemplatesController) PreviewNotificationTemplate(ctx *gin.Context) {
+	if _, ok := c.Operators.Authenticate(ctx.Request); !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Operator authentication is required"})
+		return
+	}
+
+	var req PreviewTemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data"})
+		return
+	}
+	if req.Locale == "" {
+		req.Locale = templates.DefaultLocale
+	}
+
+	template := &model.NotificationTemplate{
+		Event:   req.Event,
+		Channel: req.Channel,
+		Locale:  req.Locale,
+		Subject: req.Subject,
+		Body:    req.Body,
+	}
+	source := "draft"
+
+	if req.Body == "" {
+		if !req.Event.Valid() || !req.Channel.Valid() {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Unknown event or channel"})
+			return
+		}
+
+		resolved, resolvedSource, err := c.Engine.Resolve(ctx.Request.Context(), req.Event, req.Channel, req.Locale)
+		if err != nil {
+			c.Logger.Error("Unable to resolve notification template", zap.Error(err))
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve notification template"})
+			return
+		}
+		template, source = resolved, string(resolvedSource)
+	} else if err := templates.Validate(*template); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	message, err := templates.RenderTemplate(*template, templates.SampleData(template.Event))
+	if err != nil {
+		// Only a stored template can fail here, as drafts are validated and
+		// embedded templates are tested.
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, PreviewTemplateResponse{
+		Locale:  template.Locale,
+		Source:  source,
+		Message: message,
+	})
+}
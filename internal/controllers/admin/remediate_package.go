@@ -0,0 +1,301 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-test/internal/audit"
+	"go-test/internal/auth"
+	"go-test/internal/controllers/etag"
+	"go-test/internal/model"
+	"go-test/internal/workflow"
+	"go-test/repository"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/client"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// RemediationRequest gives the reason for an operator action, which is
+// written to the audit log.
+type RemediationRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type RetryPackageResponse struct {
+	PackageID string `json:"package_id"`
+	workflow.RetriedStep
+}
+
+type TransitionPackageRequest struct {
+	Status model.PackageDeliveryState `json:"status" binding:"required" enums:"confirmed,returnedToSender,refunded,disputeClosed,errored"`
+	Reason string                     `json:"reason" binding:"required"`
+}
+
+type TransitionPackageResponse struct {
+	PackageID string                     `json:"package_id"`
+	Status    model.PackageDeliveryState `json:"status"`
+	// Running is set when the running workflow was asked to end with the
+	// status, and unset when the status of a closed one was stored.
+	Running bool `json:"running"`
+}
+
+type TerminatePackageResponse struct {
+	PackageID string `json:"package_id"`
+}
+
+type RemediatePackageController struct {
+	Logger         *zap.Logger
+	TemporalClient client.Client
+	Namespace      string
+	Packages       repository.PackageStore
+	Operators      *auth.Operators
+	Audit          *audit.Log
+}
+
+func RegisterRemediatePackageController(
+	logger *zap.Logger,
+	temporalClient client.Client,
+	namespace string,
+	packages repository.PackageStore,
+	operators *auth.Operators,
+	auditLog *audit.Log,
+) *RemediatePackageController {
+	return &RemediatePackageController{
+		Logger:         logger,
+		TemporalClient: temporalClient,
+		Namespace:      namespace,
+		Packages:       packages,
+		Operators:      operators,
+		Audit:          auditLog,
+	}
+}
+
+// RetryPackage godoc
+// @Summary      Retry the failed step of a package delivery
+// @Description  Retry the step a package delivery is stuck on. A parked delivery is resolved with a retry, a
+// @Description  failed one is reset to run its last failed activity again. Requires an operator bearer token and
+// @Description  the package version the operator looked at as If-Match.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Package ID"
+// @Param        Authorization header string true "Operator bearer token"
+// @Param        If-Match header string true "Expected package version (ETag)"
+// @Param        body body RemediationRequest true "Reason"
+// @Success      202 {object} RetryPackageResponse "Retry accepted"
+// @Failure      400 {object} model.HttpErrorResponse "Invalid input data"
+// @Failure      401 {object} model.HttpErrorResponse "Authentication required"
+// @Failure      404 {object} model.HttpErrorResponse "Package not found"
+// @Failure      409 {object} model.HttpErrorResponse "Nothing to retry"
+// @Failure      412 {object} model.HttpErrorResponse "Package has been modified"
+// @Failure      428 {object} model.HttpErrorResponse "If-Match header is required"
+// @Failure      502 {object} model.HttpErrorResponse "Unable to retry"
+// @Router       /api/v1/admin/packages/{id}/retry [post]
+func (c *RemediatePackageController) RetryPackage(ctx *gin.Context) {
+	packageId := ctx.Param("id")
+
+	operator, ok := c.authorize(ctx)
+	if !ok {
+		return
+	}
+
+	expectedVersion, ok := etag.RequireVersion(ctx)
+	if !ok {
+		return
+	}
+
+	var req RemediationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data"})
+		return
+	}
+
+	retried, err := workflow.RetryFailedStep(context.Background(), c.TemporalClient, c.Packages, c.Namespace, packageId, expectedVersion, operator, req.Reason)
+	if errors.Is(err, workflow.ErrNothingToRetry) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if !c.handleError(ctx, packageId, "Unable to retry", err) {
+		return
+	}
+
+	detail := "resolved parked step"
+	if retried.Reset {
+		detail = "reset to run " + retried.RunID
+	}
+	c.Audit.Record(ctx.Request.Context(), model.AuditEntry{
+		PackageID: packageId,
+		Operator:  operator,
+		Action:    model.AuditRetry,
+		Reason:    req.Reason,
+		Detail:    detail,
+	})
+
+	c.Logger.Info("Retried package delivery", zap.String("packageId", packageId), zap.String("operator", operator), zap.Bool("reset", retried.Reset))
+
+	ctx.JSON(http.StatusAccepted, &RetryPackageResponse{PackageID: packageId, RetriedStep: *retried})
+}
+
+// TransitionPackage godoc
+// @Summary      Force the status of a package delivery
+// @Description  End the delivery of a package with the given status. A running workflow stores the status and
+// @Description  completes once it next waits, the status of a closed one is stored directly. Packages with an open
+// @Description  dispute must have it resolved instead. Requires an operator bearer token and the package version
+// @Description  the operator looked at as If-Match.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Package ID"
+// @Param        Authorization header string true "Operator bearer token"
+// @Param        If-Match header string true "Expected package version (ETag)"
+// @Param        body body TransitionPackageRequest true "Status and reason"
+// @Success      202 {object} TransitionPackageResponse "Transition accepted"
+// @Failure      400 {object} model.HttpErrorResponse "Invalid input data"
+// @Failure      401 {object} model.HttpErrorResponse "Authentication required"
+// @Failure      404 {object} model.HttpErrorResponse "Package not found"
+// @Failure      409 {object} model.HttpErrorResponse "Package has an open dispute"
+// @Failure      412 {object} model.HttpErrorResponse "Package has been modified"
+// @Failure      428 {object} model.HttpErrorResponse "If-Match header is required"
+// @Failure      502 {object} model.HttpErrorResponse "Unable to force the status"
+// @Router       /api/v1/admin/packages/{id}/transition [post]
+func (c *RemediatePackageController) TransitionPackage(ctx *gin.Context) {
+	packageId := ctx.Param("id")
+
+	operator, ok := c.authorize(ctx)
+	if !ok {
+		return
+	}
+
+	expectedVersion, ok := etag.RequireVersion(ctx)
+	if !ok {
+		return
+	}
+
+	var req TransitionPackageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data"})
+		return
+	}
+
+	transition := &workflow.ForcedTransition{Status: req.Status, Operator: operator, Reason: req.Reason}
+
+	running, err := workflow.ForcePackageTransition(context.Background(), c.TemporalClient, c.Packages, packageId, transition, expectedVersion)
+	if errors.Is(err, workflow.ErrInvalidTransition) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, workflow.ErrDisputeOpen) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Package has an open dispute, resolve it instead"})
+		return
+	}
+	if !c.handleError(ctx, packageId, "Unable to force the status", err) {
+		return
+	}
+
+	c.Audit.Record(ctx.Request.Context(), model.AuditEntry{
+		PackageID: packageId,
+		Operator:  operator,
+		Action:    model.AuditForceTransition,
+		Reason:    req.Reason,
+		Detail:    string(req.Status),
+	})
+
+	c.Logger.Info("Forced package delivery status", zap.String("packageId", packageId), zap.String("operator", operator), zap.String("status", string(req.Status)))
+
+	ctx.JSON(http.StatusAccepted, &TransitionPackageResponse{PackageID: packageId, Status: req.Status, Running: running})
+}
+
+// TerminatePackage godoc
+// @Summary      Terminate a package delivery
+// @Description  Terminate the running workflow of a package, leaving the package as it is. Requires an operator
+// @Description  bearer token.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Package ID"
+// @Param        Authorization header string true "Operator bearer token"
+// @Param        body body RemediationRequest true "Reason"
+// @Success      200 {object} TerminatePackageResponse "Workflow terminated"
+// @Failure      400 {object} model.HttpErrorResponse "Invalid input data"
+// @Failure      401 {object} model.HttpErrorResponse "Authentication required"
+// @Failure      404 {object} model.HttpErrorResponse "No running workflow for the package"
+// @Failure      502 {object} model.HttpErrorResponse "Unable to terminate"
+// @Router       /api/v1/admin/packages/{id}/terminate [post]
+func (c *RemediatePackageController) TerminatePackage(ctx *gin.Context) {
+	packageId := ctx.Param("id")
+
+	operator, ok := c.authorize(ctx)
+	if !ok {
+		return
+	}
+
+	var req RemediationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data"})
+		return
+	}
+
+	err := c.TemporalClient.TerminateWorkflow(context.Background(), packageId, "", req.Reason, "operator", operator)
+	var notFound *serviceerror.NotFound
+	if errors.As(err, &notFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "No running workflow for the package"})
+		return
+	}
+	if !c.handleError(ctx, packageId, "Unable to terminate", err) {
+		return
+	}
+
+	c.Audit.Record(ctx.Request.Context(), model.AuditEntry{
+		PackageID: packageId,
+		Operator:  operator,
+		Action:    model.AuditTerminate,
+		Reason:    req.Reason,
+	})
+
+	c.Logger.Info("Terminated package delivery", zap.String("packageId", packageId), zap.String("operator", operator))
+
+	ctx.JSON(http.StatusOK, &TerminatePackageResponse{PackageID: packageId})
+}
+
+// authorize returns the operator sending the request, or writes the error
+// response.
+func (c *RemediatePackageController) authorize(ctx *gin.Context) (string, bool) {
+	if ctx.Param("id") == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Package ID is required"})
+		return "", false
+	}
+
+	operator, ok := c.Operators.Authenticate(ctx.Request)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Operator authentication is required"})
+		return "", false
+	}
+
+	return operator, true
+}
+
+// handleError writes the response for an error of the workflow service and
+// reports whether the request can go on.
+func (c *RemediatePackageController) handleError(ctx *gin.Context, packageId, message string, err error) bool {
+	if err == nil {
+		return true
+	}
+
+	var notFound *serviceerror.NotFound
+	if errors.As(err, &notFound) || errors.Is(err, repository.ErrPackageNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Package not found"})
+		return false
+	}
+
+	var conflict *repository.VersionConflictError
+	if errors.As(err, &conflict) {
+		etag.Set(ctx, conflict.ActualVersion)
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{"error": "Package has been modified"})
+		return false
+	}
+
+	c.Logger.Error(message, zap.String("packageId", packageId), zap.Error(err))
+	ctx.JSON(http.StatusBadGateway, gin.H{"error": message})
+	return false
+}
@@ -0,0 +1,141 @@
+package admin
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"go-test/internal/audit"
+	"go-test/internal/auth"
+	"go-test/internal/model"
+	"go-test/repository"
+	"go.temporal.io/api/common/v1"
+	"go.temporal.io/api/enums/v1"
+	workflowpb "go.temporal.io/api/workflow/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/mocks"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testOperatorToken = "operator-token"
+
+// transitionRouter serves the transition endpoint for a package whose
+// workflow has completed, so that the status is stored directly.
+func transitionRouter(t *testing.T, store *repository.MemoryRepository) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	temporalClient := &mocks.Client{}
+	temporalClient.On("DescribeWorkflowExecution", mock.Anything, "PKG-1", "").Return(&workflowservice.DescribeWorkflowExecutionResponse{
+		WorkflowExecutionInfo: &workflowpb.WorkflowExecutionInfo{
+			Execution: &common.WorkflowExecution{WorkflowId: "PKG-1", RunId: "run-1"},
+			Status:    enums.WORKFLOW_EXECUTION_STATUS_COMPLETED,
+		},
+	}, nil)
+
+	logger := zap.NewNop()
+	controller := RegisterRemediatePackageController(
+		logger,
+		temporalClient,
+		"default",
+		store,
+		auth.NewOperators(map[string]string{"alice": testOperatorToken}),
+		audit.NewLog(store, logger),
+	)
+
+	router := gin.New()
+	router.POST("/packages/:id/transition", controller.TransitionPackage)
+	return router
+}
+
+func savedPackage(t *testing.T) *repository.MemoryRepository {
+	t.Helper()
+
+	store := repository.NewMemoryRepository()
+	_, err := store.CreatePackageDelivery(context.Background(), &model.DeliveryPackage{
+		ID:              "PKG-1",
+		CustomerEmail:   "customer@example.com",
+		DeliveryAddress: "1 Main Street",
+	})
+	if err != nil {
+		t.Fatalf("CreatePackageDelivery: %v", err)
+	}
+
+	return store
+}
+
+func transition(router *gin.Engine, ifMatch string) *httptest.ResponseRecorder {
+	body := `{"status":"refunded","reason":"lost in transit"}`
+	req := httptest.NewRequest(http.MethodPost, "/packages/PKG-1/transition", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+testOperatorToken)
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func storedPackage(t *testing.T, store *repository.MemoryRepository) *model.DeliveryPackage {
+	t.Helper()
+
+	deliveryPackage, err := store.GetPackageDelivery(context.Background(), "PKG-1")
+	if err != nil {
+		t.Fatalf("GetPackageDelivery: %v", err)
+	}
+	return deliveryPackage
+}
+
+func TestTransitionPackageWithMatchingVersion(t *testing.T) {
+	store := savedPackage(t)
+
+	rec := transition(transitionRouter(t, store), `"1"`)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusAccepted, rec.Body)
+	}
+
+	stored := storedPackage(t, store)
+	if stored.Status != model.PackageDeliveryRefunded || stored.Version != 2 {
+		t.Fatalf("stored package = %s at version %d, want refunded at version 2", stored.Status, stored.Version)
+	}
+}
+
+func TestTransitionPackageWithStaleVersion(t *testing.T) {
+	store := savedPackage(t)
+
+	rec := transition(transitionRouter(t, store), `"3"`)
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusPreconditionFailed, rec.Body)
+	}
+	if etag := rec.Header().Get("ETag"); etag != `"1"` {
+		t.Fatalf("ETag = %s, want the current version", etag)
+	}
+
+	stored := storedPackage(t, store)
+	if stored.Status != model.PackageDeliverySaved || stored.Version != 1 {
+		t.Fatalf("stored package = %s at version %d, want it untouched", stored.Status, stored.Version)
+	}
+}
+
+func TestTransitionPackageRequiresIfMatch(t *testing.T) {
+	store := savedPackage(t)
+	router := transitionRouter(t, store)
+
+	if rec := transition(router, ""); rec.Code != http.StatusPreconditionRequired {
+		t.Fatalf("status without If-Match = %d, want %d", rec.Code, http.StatusPreconditionRequired)
+	}
+	for _, header := range []string{"1", `W/"1"`, "*", `"1", "2"`, `"-1"`} {
+		if rec := transition(router, header); rec.Code != http.StatusBadRequest {
+			t.Errorf("status with If-Match %s = %d, want %d", header, rec.Code, http.StatusBadRequest)
+		}
+	}
+
+	if stored := storedPackage(t, store); stored.Version != 1 {
+		t.Fatalf("stored version = %d, want it untouched", stored.Version)
+	}
+}
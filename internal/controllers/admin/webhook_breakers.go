@@ -0,0 +1,43 @@
+package admin
+
+import (
+	"github.com/gin-gonic/gin"
+	"go-test/internal/auth"
+	"go-test/internal/circuit"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type WebhookBreakersController struct {
+	Logger       *zap.Logger
+	Destinations *circuit.Destinations
+	Operators    *auth.Operators
+}
+
+func RegisterWebhookBreakersController(logger *zap.Logger, destinations *circuit.Destinations, operators *auth.Operators) *WebhookBreakersController {
+	return &WebhookBreakersController{
+		Logger:       logger,
+		Destinations: destinations,
+		Operators:    operators,
+	}
+}
+
+// ListWebhookBreakers godoc
+// @Summary      List the circuit breakers of webhook destinations
+// @Description  Return the circuit breaker state and the calls in flight of every webhook destination called since the
+// @Description  service started. While a circuit is open, notifications to its destination are retried once probing
+// @Description  starts. Requires an operator bearer token.
+// @Tags         admin
+// @Produce      json
+// @Param        Authorization header string true "Operator bearer token"
+// @Success      200 {array} circuit.DestinationState "Destination states"
+// @Failure      401 {object} model.HttpErrorResponse "Authentication required"
+// @Router       /api/v1/admin/webhooks/breakers [get]
+func (c *WebhookBreakersController) ListWebhookBreakers(ctx *gin.Context) {
+	if _, ok := c.Operators.Authenticate(ctx.Request); !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Operator authentication is required"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, c.Destinations.States())
+}
@@ -0,0 +1,117 @@
+package admin
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-test/internal/audit"
+	"go-test/internal/auth"
+	"go-test/internal/model"
+	"go-test/repository"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type EnableWebhookSubscriptionRequest struct {
+	URL string `json:"url" binding:"required" example:"https://partner.example.com/hooks/notifications"`
+}
+
+type WebhookSubscriptionsController struct {
+	Logger    *zap.Logger
+	Store     repository.WebhookSubscriptionStore
+	Operators *auth.Operators
+	Audit     *audit.Log
+}
+
+func RegisterWebhookSubscriptionsController(
+	logger *zap.Logger,
+	store repository.WebhookSubscriptionStore,
+	operators *auth.Operators,
+	auditLog *audit.Log,
+) *WebhookSubscriptionsController {
+	return &WebhookSubscriptionsController{
+		Logger:    logger,
+		Store:     store,
+		Operators: operators,
+		Audit:     auditLog,
+	}
+}
+
+// ListDisabledWebhookSubscriptions godoc
+// @Summary      List the disabled webhook subscriptions
+// @Description  Return the webhook URLs disabled after they answered 410 Gone, with their destination and the start of
+// @Description  the response that disabled them. Notifications to a disabled URL fail without being sent or retried.
+// @Description  Requires an operator bearer token.
+// @Tags         admin
+// @Produce      json
+// @Param        Authorization header string true "Operator bearer token"
+// @Success      200 {array} model.DisabledWebhookSubscription "Disabled subscriptions"
+// @Failure      401 {object} model.HttpErrorResponse "Authentication required"
+// @Failure      500 {object} model.HttpErrorResponse "Internal error"
+// @Router       /api/v1/admin/webhooks/subscriptions/disabled [get]
+func (c *WebhookSubscriptionsController) ListDisabledWebhookSubscriptions(ctx *gin.Context) {
+	if _, ok := c.Operators.Authenticate(ctx.Request); !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Operator authentication is required"})
+		return
+	}
+
+	subscriptions, err := c.Store.ListDisabledWebhookSubscriptions(ctx.Request.Context())
+	if err != nil {
+		c.Logger.Error("Unable to list disabled webhook subscriptions", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list disabled webhook subscriptions"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subscriptions)
+}
+
+// EnableWebhookSubscription godoc
+// @Summary      Enable a disabled webhook subscription
+// @Description  Send notifications to the URL again, once the partner restored its endpoint. A destination configured
+// @Description  with a new URL needs no enabling. Requires an operator bearer token.
+// @Tags         admin
+// @Accept       json
+// @Param        Authorization header string true "Operator bearer token"
+// @Param        body body EnableWebhookSubscriptionRequest true "Disabled subscription"
+// @Success      204 "Subscription enabled"
+// @Failure      400 {object} model.HttpErrorResponse "Invalid input data"
+// @Failure      401 {object} model.HttpErrorResponse "Authentication required"
+// @Failure      404 {object} model.HttpErrorResponse "Subscription is not disabled"
+// @Failure      500 {object} model.HttpErrorResponse "Internal error"
+// @Router       /api/v1/admin/webhooks/subscriptions/enable [post]
+func (c *WebhookSubscriptionsController) EnableWebhookSubscription(ctx *gin.Context) {
+	operator, ok := c.Operators.Authenticate(ctx.Request)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Operator authentication is required"})
+		return
+	}
+
+	var req EnableWebhookSubscriptionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data"})
+		return
+	}
+
+	err := c.Store.EnableWebhookSubscription(ctx.Request.Context(), req.URL)
+	if errors.Is(err, repository.ErrSubscriptionNotDisabled) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Webhook subscription is not disabled"})
+		return
+	}
+	if err != nil {
+		c.Logger.Error("Unable to enable webhook subscription", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enable webhook subscription"})
+		return
+	}
+
+	c.Logger.Info("Enabled webhook subscription",
+		zap.String("operator", operator),
+		zap.String("webhookURL", req.URL))
+
+	c.Audit.Record(ctx.Request.Context(), model.AuditEntry{
+		SubjectType: model.AuditSubjectWebhookSubscription,
+		SubjectID:   req.URL,
+		Operator:    operator,
+		Action:      model.AuditEnableWebhook,
+	})
+
+	ctx.Status(http.StatusNoContent)
+}
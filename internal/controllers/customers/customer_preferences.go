@@ -0,0 +1,318 @@
+package customers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-test/internal/audit"
+	"go-test/internal/auth"
+	"go-test/internal/controllers/packages"
+	"go-test/internal/model"
+	"go-test/repository"
+	"go.uber.org/zap"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+type CustomerPreferencesRequest struct {
+	Channels            []model.NotificationChannel `json:"channels" example:"email,sms"`
+	Language            string                      `json:"language" example:"de-AT"`
+	TimeZone            string                      `json:"time_zone" example:"Europe/Vienna"`
+	QuietHoursStart     string                      `json:"quiet_hours_start" example:"22:00"`
+	QuietHoursEnd       string                      `json:"quiet_hours_end" example:"07:00"`
+	OptOutAll           bool                        `json:"opt_out_all"`
+	OptOutDelivered     bool                        `json:"opt_out_delivered"`
+	OptOutArrival       bool                        `json:"opt_out_arrival"`
+	OptOutFailedAttempt bool                        `json:"opt_out_failed_attempt"`
+}
+
+type CustomerPreferencesController struct {
+	Logger    *zap.Logger
+	Store     repository.CustomerPreferencesStore
+	Operators *auth.Operators
+	Links     *auth.ConfirmationLinks
+	Audit     *audit.Log
+}
+
+func RegisterCustomerPreferencesController(
+	logger *zap.Logger,
+	store repository.CustomerPreferencesStore,
+	operators *auth.Operators,
+	links *auth.ConfirmationLinks,
+	auditLog *audit.Log,
+) *CustomerPreferencesController {
+	return &CustomerPreferencesController{
+		Logger:    logger,
+		Store:     store,
+		Operators: operators,
+		Links:     links,
+		Audit:     auditLog,
+	}
+}
+
+// CreateCustomerPreferences godoc
+// @Summary      Create the notification preferences of a customer
+// @Description  Set the channels, language, quiet hours and opt-outs of a customer. Notifications that fall within the
+// @Description  quiet hours are deferred until they end. Opt-outs do not apply to legally required messages, such as the
+// @Description  return of a package to its sender. Requires an operator bearer token or an unused confirmation token of the customer.
+// @Tags         customers
+// @Accept       json
+// @Produce      json
+// @Param        email path string true "Customer email"
+// @Param        Authorization header string false "Operator bearer token"
+// @Param        X-Confirmation-Token header string false "Confirmation token sent to the customer"
+// @Param        body body CustomerPreferencesRequest true "Notification preferences"
+// @Success      201 {object} model.CustomerPreferences "Created preferences"
+// @Failure      400 {object} model.HttpErrorResponse "Invalid preferences"
+// @Failure      401 {object} model.HttpErrorResponse "Authentication required"
+// @Failure      410 {object} model.HttpErrorResponse "Confirmation token expired or already used"
+// @Failure      409 {object} model.HttpErrorResponse "Preferences already exist"
+// @Failure      500 {object} model.HttpErrorResponse "Internal error"
+// @Router       /api/v1/customers/{email}/preferences [post]
+func (c *CustomerPreferencesController) CreateCustomerPreferences(ctx *gin.Context) {
+	email, operator, ok := c.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	preferences, ok := c.bindPreferences(ctx, email)
+	if !ok {
+		return
+	}
+
+	err := c.Store.CreateCustomerPreferences(ctx.Request.Context(), preferences)
+	if errors.Is(err, repository.ErrCustomerAlreadyExists) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Customer preferences already exist"})
+		return
+	}
+	if err != nil {
+		c.Logger.Error("Unable to create customer preferences", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create customer preferences"})
+		return
+	}
+	c.recordOperatorEdit(ctx.Request.Context(), operator, model.AuditCreatePreferences, preferences)
+
+	ctx.JSON(http.StatusCreated, preferences)
+}
+
+// GetCustomerPreferences godoc
+// @Summary      Get the notification preferences of a customer
+// @Description  Requires an operator bearer token or an unused confirmation token of the customer.
+// @Tags         customers
+// @Produce      json
+// @Param        email path string true "Customer email"
+// @Param        Authorization header string false "Operator bearer token"
+// @Param        X-Confirmation-Token header string false "Confirmation token sent to the customer"
+// @Success      200 {object} model.CustomerPreferences "Preferences"
+// @Failure      400 {object} model.HttpErrorResponse "Invalid email"
+// @Failure      401 {object} model.HttpErrorResponse "Authentication required"
+// @Failure      410 {object} model.HttpErrorResponse "Confirmation token expired or already used"
+// @Failure      404 {object} model.HttpErrorResponse "Preferences not found"
+// @Failure      500 {object} model.HttpErrorResponse "Internal error"
+// @Router       /api/v1/customers/{email}/preferences [get]
+func (c *CustomerPreferencesController) GetCustomerPreferences(ctx *gin.Context) {
+	email, _, ok := c.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	preferences, err := c.Store.GetCustomerPreferences(ctx.Request.Context(), email)
+	if errors.Is(err, repository.ErrCustomerNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Customer preferences not found"})
+		return
+	}
+	if err != nil {
+		c.Logger.Error("Unable to get customer preferences", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get customer preferences"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, preferences)
+}
+
+// UpdateCustomerPreferences godoc
+// @Summary      Replace the notification preferences of a customer
+// @Description  Requires an operator bearer token or an unused confirmation token of the customer.
+// @Tags         customers
+// @Accept       json
+// @Produce      json
+// @Param        email path string true "Customer email"
+// @Param        Authorization header string false "Operator bearer token"
+// @Param        X-Confirmation-Token header string false "Confirmation token sent to the customer"
+// @Param        body body CustomerPreferencesRequest true "Notification preferences"
+// @Success      200 {object} model.CustomerPreferences "Updated preferences"
+// @Failure      400 {object} model.HttpErrorResponse "Invalid preferences"
+// @Failure      401 {object} model.HttpErrorResponse "Authentication required"
+// @Failure      410 {object} model.HttpErrorResponse "Confirmation token expired or already used"
+// @Failure      404 {object} model.HttpErrorResponse "Preferences not found"
+// @Failure      500 {object} model.HttpErrorResponse "Internal error"
+// @Router       /api/v1/customers/{email}/preferences [put]
+func (c *CustomerPreferencesController) UpdateCustomerPreferences(ctx *gin.Context) {
+	email, operator, ok := c.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	preferences, ok := c.bindPreferences(ctx, email)
+	if !ok {
+		return
+	}
+
+	err := c.Store.UpdateCustomerPreferences(ctx.Request.Context(), preferences)
+	if errors.Is(err, repository.ErrCustomerNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Customer preferences not found"})
+		return
+	}
+	if err != nil {
+		c.Logger.Error("Unable to update customer preferences", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update customer preferences"})
+		return
+	}
+	c.recordOperatorEdit(ctx.Request.Context(), operator, model.AuditUpdatePreferences, preferences)
+
+	ctx.JSON(http.StatusOK, preferences)
+}
+
+// DeleteCustomerPreferences godoc
+// @Summary      Delete the notification preferences of a customer
+// @Description  The customer is notified with the default preferences afterwards. Requires an operator bearer token or an
+// @Description  unused confirmation token of the customer.
+// @Tags         customers
+// @Param        email path string true "Customer email"
+// @Param        Authorization header string false "Operator bearer token"
+// @Param        X-Confirmation-Token header string false "Confirmation token sent to the customer"
+// @Success      204 "Preferences deleted"
+// @Failure      400 {object} model.HttpErrorResponse "Invalid email"
+// @Failure      401 {object} model.HttpErrorResponse "Authentication required"
+// @Failure      410 {object} model.HttpErrorResponse "Confirmation token expired or already used"
+// @Failure      404 {object} model.HttpErrorResponse "Preferences not found"
+// @Failure      500 {object} model.HttpErrorResponse "Internal error"
+// @Router       /api/v1/customers/{email}/preferences [delete]
+func (c *CustomerPreferencesController) DeleteCustomerPreferences(ctx *gin.Context) {
+	email, operator, ok := c.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	err := c.Store.DeleteCustomerPreferences(ctx.Request.Context(), email)
+	if errors.Is(err, repository.ErrCustomerNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Customer preferences not found"})
+		return
+	}
+	if err != nil {
+		c.Logger.Error("Unable to delete customer preferences", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete customer preferences"})
+		return
+	}
+	c.recordOperatorEdit(ctx.Request.Context(), operator, model.AuditDeletePreferences, &model.CustomerPreferences{Email: email})
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// authenticate returns the normalized email of the path once the caller is
+// an operator or holds an unexpired, unused confirmation token of that
+// customer, or writes the error response. The operator is empty for
+// customers.
+func (c *CustomerPreferencesController) authenticate(ctx *gin.Context) (string, string, bool) {
+	address, err := mail.ParseAddress(ctx.Param("email"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid customer email"})
+		return "", "", false
+	}
+	email := strings.ToLower(address.Address)
+
+	if operator, ok := c.Operators.Authenticate(ctx.Request); ok {
+		return email, operator, true
+	}
+
+	token := ctx.GetHeader(packages.ConfirmationTokenHeader)
+	if token == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Operator authentication or a confirmation token is required"})
+		return "", "", false
+	}
+
+	claims, err := c.Links.Verify(token)
+	if errors.Is(err, auth.ErrTokenExpired) {
+		ctx.JSON(http.StatusGone, gin.H{"error": "Confirmation token expired"})
+		return "", "", false
+	}
+	if err != nil || !strings.EqualFold(claims.CustomerEmail, email) {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid confirmation token"})
+		return "", "", false
+	}
+
+	// A token stops granting access once it has been used for what it was
+	// sent for.
+	err = c.Links.CheckUnredeemed(ctx.Request.Context(), claims)
+	if errors.Is(err, repository.ErrTokenConsumed) {
+		ctx.JSON(http.StatusGone, gin.H{"error": "Confirmation token already used"})
+		return "", "", false
+	}
+	if errors.Is(err, repository.ErrTokenNotFound) {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid confirmation token"})
+		return "", "", false
+	}
+	if err != nil {
+		c.Logger.Error("Unable to check confirmation token", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check confirmation token"})
+		return "", "", false
+	}
+
+	return email, "", true
+}
+
+// recordOperatorEdit adds the change an operator made to the preferences of
+// a customer to the audit log. Customers editing their own preferences are
+// not audited.
+func (c *CustomerPreferencesController) recordOperatorEdit(ctx context.Context, operator string, action model.AuditAction, preferences *model.CustomerPreferences) {
+	if operator == "" {
+		return
+	}
+
+	entry := model.AuditEntry{
+		SubjectType: model.AuditSubjectCustomerPreferences,
+		SubjectID:   preferences.Email,
+		Operator:    operator,
+		Action:      action,
+	}
+	if action != model.AuditDeletePreferences {
+		if detail, err := json.Marshal(preferences); err == nil {
+			entry.Detail = string(detail)
+		}
+	}
+
+	c.Audit.Record(ctx, entry)
+}
+
+// bindPreferences reads and validates the preferences of the request, or
+// writes the error response.
+func (c *CustomerPreferencesController) bindPreferences(ctx *gin.Context, email string) (*model.CustomerPreferences, bool) {
+	var req CustomerPreferencesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data"})
+		return nil, false
+	}
+
+	preferences := &model.CustomerPreferences{
+		Email:               email,
+		Channels:            req.Channels,
+		Language:            req.Language,
+		TimeZone:            req.TimeZone,
+		QuietHoursStart:     req.QuietHoursStart,
+		QuietHoursEnd:       req.QuietHoursEnd,
+		OptOutAll:           req.OptOutAll,
+		OptOutDelivered:     req.OptOutDelivered,
+		OptOutArrival:       req.OptOutArrival,
+		OptOutFailedAttempt: req.OptOutFailedAttempt,
+		UpdatedAt:           time.Now().UTC(),
+	}
+	if err := preferences.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	return preferences, true
+}
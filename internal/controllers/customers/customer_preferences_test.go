@@ -0,0 +1,116 @@
+package customers
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"go-test/internal/audit"
+	"go-test/internal/auth"
+	"go-test/internal/config"
+	"go-test/internal/controllers/packages"
+	"go-test/internal/model"
+	"go-test/repository"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testEmail = "customer@example.com"
+
+func newPreferencesRouter(t *testing.T) (*gin.Engine, *auth.ConfirmationLinks) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	store := repository.NewMemoryRepository()
+	links, err := auth.NewConfirmationLinks(config.AuthConfig{ConfirmationSecret: "test-confirmation-secret-of-32-bytes"}, store, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewConfirmationLinks: %v", err)
+	}
+
+	logger := zap.NewNop()
+	controller := RegisterCustomerPreferencesController(logger, store, auth.NewOperators(nil), links, audit.NewLog(store, logger))
+
+	router := gin.New()
+	router.GET("/customers/:email/preferences", controller.GetCustomerPreferences)
+	return router, links
+}
+
+// issueToken returns the token of a link issued to the test customer.
+func issueToken(t *testing.T, links *auth.ConfirmationLinks) (string, *auth.ConfirmationClaims) {
+	t.Helper()
+
+	url, _, err := links.Issue(context.Background(), &model.DeliveryPackage{ID: "PKG-1", CustomerEmail: testEmail})
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	token := url[strings.LastIndex(url, "/")+1:]
+
+	claims, err := links.Verify(token)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	return token, claims
+}
+
+func getPreferences(router *gin.Engine, token string) int {
+	req := httptest.NewRequest(http.MethodGet, "/customers/"+testEmail+"/preferences", nil)
+	req.Header.Set(packages.ConfirmationTokenHeader, token)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestPreferencesAcceptUnusedToken(t *testing.T) {
+	router, links := newPreferencesRouter(t)
+	token, _ := issueToken(t, links)
+
+	// No preferences are stored, so an authenticated customer gets 404.
+	if status := getPreferences(router, token); status != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", status, http.StatusNotFound)
+	}
+}
+
+func TestPreferencesRejectRedeemedToken(t *testing.T) {
+	router, links := newPreferencesRouter(t)
+	token, claims := issueToken(t, links)
+
+	if _, err := links.Redeem(context.Background(), claims); err != nil {
+		t.Fatalf("Redeem: %v", err)
+	}
+
+	if status := getPreferences(router, token); status != http.StatusGone {
+		t.Fatalf("status = %d, want %d", status, http.StatusGone)
+	}
+}
+
+func TestPreferencesRejectExpiredToken(t *testing.T) {
+	router, links := newPreferencesRouter(t)
+	_, claims := issueToken(t, links)
+
+	expired := *claims
+	expired.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+	token, err := links.Signer.Sign(expired)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	if status := getPreferences(router, token); status != http.StatusGone {
+		t.Fatalf("status = %d, want %d", status, http.StatusGone)
+	}
+}
+
+func TestPreferencesRejectTokenOfAnotherCustomer(t *testing.T) {
+	router, links := newPreferencesRouter(t)
+
+	url, _, err := links.Issue(context.Background(), &model.DeliveryPackage{ID: "PKG-2", CustomerEmail: "other@example.com"})
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	if status := getPreferences(router, url[strings.LastIndex(url, "/")+1:]); status != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", status, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,79 @@
+// Package etag maps package versions to HTTP entity tags and reads the
+// version a client expects from its If-Match precondition.
+package etag
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrMissing = errors.New("If-Match header is required")
+	ErrInvalid = errors.New("If-Match header must be a single package version")
+)
+
+func Format(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+func Set(ctx *gin.Context, version int64) {
+	ctx.Header("ETag", Format(version))
+}
+
+// ExpectedVersion parses the If-Match header into the package version the
+// client based its request on. Only a single strong entity tag names a
+// version, so weak tags, lists and "*" are rejected with ErrInvalid.
+func ExpectedVersion(ctx *gin.Context) (int64, error) {
+	header := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if header == "" {
+		return 0, ErrMissing
+	}
+
+	unquoted, err := strconv.Unquote(header)
+	if err != nil || !strings.HasPrefix(header, `"`) {
+		return 0, ErrInvalid
+	}
+
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version < 0 {
+		return 0, ErrInvalid
+	}
+
+	return version, nil
+}
+
+// RequireVersion returns the expected version of a request that must carry
+// If-Match, or writes 428 when the header is missing and 400 when it cannot
+// be parsed.
+func RequireVersion(ctx *gin.Context) (int64, bool) {
+	version, err := ExpectedVersion(ctx)
+	if errors.Is(err, ErrMissing) {
+		ctx.JSON(http.StatusPreconditionRequired, gin.H{"error": err.Error()})
+		return 0, false
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return 0, false
+	}
+
+	return version, true
+}
+
+// OptionalVersion returns the expected version of a request on which
+// If-Match is optional and reports whether it was sent. An unparsable header
+// is answered with 400 and ok set to false.
+func OptionalVersion(ctx *gin.Context) (version int64, sent bool, ok bool) {
+	version, err := ExpectedVersion(ctx)
+	if errors.Is(err, ErrMissing) {
+		return 0, false, true
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return 0, false, false
+	}
+
+	return version, true, true
+}
@@ -0,0 +1,118 @@
+package packages
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"go-test/internal/auth"
+	"go-test/internal/model"
+	"go-test/internal/storage"
+	"go-test/internal/workflow"
+	"go.temporal.io/sdk/client"
+	"go.uber.org/zap"
+	"html/template"
+	"net/http"
+	"time"
+)
+
+// confirmPageTemplate is the page the link opens. Mail clients and link
+// scanners fetch links on their own, so only its form confirms the delivery.
+var confirmPageTemplate = template.Must(template.New("confirm-page").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Confirm delivery</title></head>
+<body>
+<h1>Confirm delivery</h1>
+<p>Please confirm that you received package {{.PackageID}}.</p>
+<form method="post" action="{{.Action}}" enctype="multipart/form-data">
+<label>Received by <input type="text" name="recipient_name"></label>
+<button type="submit">Confirm delivery</button>
+</form>
+</body>
+</html>
+`))
+
+type ConfirmLinkController struct {
+	packageConfirmer
+}
+
+func RegisterConfirmLinkController(logger *zap.Logger, temporalClient client.Client, objectStore storage.ObjectStore, links *auth.ConfirmationLinks) *ConfirmLinkController {
+	return &ConfirmLinkController{
+		packageConfirmer: packageConfirmer{
+			Logger:         logger,
+			TemporalClient: temporalClient,
+			ObjectStore:    objectStore,
+			Links:          links,
+		},
+	}
+}
+
+// ConfirmLinkPage godoc
+// @Summary      Open a confirmation link
+// @Description  Check the token of the link sent to the customer and render the page confirming the delivery. The
+// @Description  token is not used until the page is submitted.
+// @Tags         confirmations
+// @Produce      html
+// @Param        token path string true "Confirmation token"
+// @Success      200 {string} string "Confirmation page"
+// @Failure      401 {object} model.HttpErrorResponse "Invalid confirmation token"
+// @Failure      410 {object} model.HttpErrorResponse "Confirmation token expired or already used"
+// @Failure      500 {object} model.HttpErrorResponse "Failed to render the confirmation page"
+// @Router       /api/v1/confirm/{token} [get]
+func (c *ConfirmLinkController) ConfirmLinkPage(ctx *gin.Context) {
+	claims := verifyToken(ctx, c.Links, ctx.Param("token"))
+	if claims == nil {
+		return
+	}
+
+	if !checkUnredeemed(ctx, c.Logger, c.Links, claims.PackageID, claims) {
+		return
+	}
+
+	var page bytes.Buffer
+	err := confirmPageTemplate.Execute(&page, map[string]string{
+		"PackageID": claims.PackageID,
+		"Action":    ctx.Request.URL.Path,
+	})
+	if err != nil {
+		c.Logger.Error("Unable to render confirmation page", zap.String("packageId", claims.PackageID), zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render the confirmation page"})
+		return
+	}
+
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", page.Bytes())
+}
+
+// ConfirmLink godoc
+// @Summary      Confirm package delivery with a confirmation link
+// @Description  Confirm the delivery with the single-use token of the link sent to the customer. Accepts the same
+// @Description  optional proof of delivery as the package confirm endpoint.
+// @Tags         confirmations
+// @Accept       json,mpfd
+// @Produce      json
+// @Param        token path string true "Confirmation token"
+// @Param        body body ConfirmPackageRequest false "Proof of delivery"
+// @Success      200 {object} ConfirmPackageResponse "Confirmation status"
+// @Failure      400 {object} model.HttpErrorResponse "Invalid input data"
+// @Failure      401 {object} model.HttpErrorResponse "Invalid confirmation token"
+// @Failure      404 {object} model.HttpErrorResponse "No running delivery workflow"
+// @Failure      409 {object} ConfirmPackageResponse "Package delivery is already confirmed or disputed"
+// @Failure      410 {object} model.HttpErrorResponse "Confirmation token expired or already used"
+// @Failure      502 {object} model.HttpErrorResponse "Unable to confirm package"
+// @Router       /api/v1/confirm/{token} [post]
+func (c *ConfirmLinkController) ConfirmLink(ctx *gin.Context) {
+	claims := verifyToken(ctx, c.Links, ctx.Param("token"))
+	if claims == nil {
+		return
+	}
+
+	// Links are issued for packages and for shipments.
+	workflowName := requireWorkflow(ctx, c.Logger, c.TemporalClient, claims.PackageID, workflow.PackageDeliveryWorkflowName, workflow.ShipmentWorkflowName)
+	if workflowName == "" {
+		return
+	}
+
+	c.confirm(ctx, claims.PackageID, confirmUpdates[workflowName], &model.DeliveryConfirmation{
+		ConfirmedBy: claims.CustomerEmail,
+		ConfirmedAt: time.Now().UTC(),
+		Channel:     model.ConfirmationChannelLink,
+	}, claims)
+}
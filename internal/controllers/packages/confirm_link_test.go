@@ -0,0 +1,137 @@
+package packages
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"go-test/internal/auth"
+	"go-test/internal/config"
+	"go-test/internal/model"
+	"go-test/internal/workflow"
+	"go-test/repository"
+	"go.temporal.io/api/common/v1"
+	workflowpb "go.temporal.io/api/workflow/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/mocks"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// confirmLinkFixture serves the confirmation link endpoint with the token
+// of a link issued for a running package delivery.
+type confirmLinkFixture struct {
+	router         *gin.Engine
+	temporalClient *mocks.Client
+	links          *auth.ConfirmationLinks
+	token          string
+	claims         *auth.ConfirmationClaims
+}
+
+// newConfirmLinkFixture answers every confirm update with outcome.
+func newConfirmLinkFixture(t *testing.T, outcome workflow.UpdateOutcome) *confirmLinkFixture {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	store := repository.NewMemoryRepository()
+	links, err := auth.NewConfirmationLinks(config.AuthConfig{ConfirmationSecret: "test-confirmation-secret-of-32-bytes"}, store, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewConfirmationLinks: %v", err)
+	}
+	url, _, err := links.Issue(context.Background(), &model.DeliveryPackage{ID: "PKG-1", CustomerEmail: "customer@example.com"})
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	token := url[strings.LastIndex(url, "/")+1:]
+	claims, err := links.Verify(token)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	handle := &mocks.WorkflowUpdateHandle{}
+	handle.On("Get", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			*args.Get(1).(*workflow.PackageUpdateResult) = workflow.PackageUpdateResult{Outcome: outcome}
+		}).
+		Return(nil)
+
+	temporalClient := &mocks.Client{}
+	temporalClient.On("DescribeWorkflowExecution", mock.Anything, "PKG-1", "").Return(&workflowservice.DescribeWorkflowExecutionResponse{
+		WorkflowExecutionInfo: &workflowpb.WorkflowExecutionInfo{
+			Type: &common.WorkflowType{Name: workflow.PackageDeliveryWorkflowName},
+		},
+	}, nil)
+	temporalClient.On("UpdateWorkflow", mock.Anything, mock.Anything).Return(handle, nil)
+
+	controller := RegisterConfirmLinkController(zap.NewNop(), temporalClient, nil, links)
+	router := gin.New()
+	router.POST("/confirm/:token", controller.ConfirmLink)
+
+	return &confirmLinkFixture{router: router, temporalClient: temporalClient, links: links, token: token, claims: claims}
+}
+
+func (f *confirmLinkFixture) confirm() int {
+	rec := httptest.NewRecorder()
+	f.router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/confirm/"+f.token, nil))
+	return rec.Code
+}
+
+func TestConfirmLinkRedeemsTokenOnce(t *testing.T) {
+	f := newConfirmLinkFixture(t, workflow.UpdateAccepted)
+
+	if status := f.confirm(); status != http.StatusOK {
+		t.Fatalf("first confirmation status = %d, want %d", status, http.StatusOK)
+	}
+	if status := f.confirm(); status != http.StatusGone {
+		t.Fatalf("second confirmation status = %d, want %d", status, http.StatusGone)
+	}
+
+	f.temporalClient.AssertNumberOfCalls(t, "UpdateWorkflow", 1)
+}
+
+func TestConcurrentConfirmLinksSucceedOnce(t *testing.T) {
+	f := newConfirmLinkFixture(t, workflow.UpdateAccepted)
+
+	const requests = 10
+	var wg sync.WaitGroup
+	statuses := make(chan int, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses <- f.confirm()
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+
+	confirmed := 0
+	for status := range statuses {
+		switch status {
+		case http.StatusOK:
+			confirmed++
+		case http.StatusGone:
+		default:
+			t.Errorf("confirmation status = %d", status)
+		}
+	}
+	if confirmed != 1 {
+		t.Fatalf("%d concurrent confirmations succeeded, want 1", confirmed)
+	}
+	f.temporalClient.AssertNumberOfCalls(t, "UpdateWorkflow", 1)
+}
+
+func TestConfirmLinkReleasesRefusedToken(t *testing.T) {
+	f := newConfirmLinkFixture(t, workflow.UpdateConflict)
+
+	if status := f.confirm(); status != http.StatusConflict {
+		t.Fatalf("confirmation status = %d, want %d", status, http.StatusConflict)
+	}
+
+	if err := f.links.CheckUnredeemed(context.Background(), f.claims); err != nil {
+		t.Fatalf("token of a refused confirmation is not usable: %v", err)
+	}
+}
@@ -1,43 +1,87 @@
 package packages
 
 import (
-	"context"
+	"errors"
 	"github.com/gin-gonic/gin"
+	"go-test/internal/audit"
+	"go-test/internal/auth"
+	"go-test/internal/controllers/etag"
 	"go-test/internal/model"
 	_ "go-test/internal/model"
+	"go-test/internal/storage"
 	"go-test/internal/workflow"
+	"go-test/repository"
 	"go.temporal.io/sdk/client"
 	"go.uber.org/zap"
 	"net/http"
+	"time"
 )
 
+const ConfirmationTokenHeader = "X-Confirmation-Token"
+
+type ConfirmPackageRequest struct {
+	RecipientName string             `json:"recipient_name"`
+	Signature     []byte             `json:"signature,omitempty" swaggertype:"string" format:"base64"`
+	Photo         []byte             `json:"photo,omitempty" swaggertype:"string" format:"base64"`
+	Location      *model.GeoLocation `json:"location,omitempty"`
+}
+
 type ConfirmPackageResponse struct {
-	Status model.PackageDeliveryState `json:"status"`
+	Status       model.PackageDeliveryState  `json:"status"`
+	Confirmation *model.DeliveryConfirmation `json:"confirmation,omitempty"`
 }
 
 type ConfirmPackageController struct {
-	Logger                       *zap.Logger
-	TemporalClient               client.Client
+	packageConfirmer
 	PackageDeliveryTaskQueueName string
+	PackageStore                 repository.PackageStore
+	Operators                    *auth.Operators
 }
 
-func RegisterConfirmPackageController(logger *zap.Logger, temporalClient client.Client) *ConfirmPackageController {
+func RegisterConfirmPackageController(
+	logger *zap.Logger,
+	temporalClient client.Client,
+	objectStore storage.ObjectStore,
+	packageStore repository.PackageStore,
+	operators *auth.Operators,
+	links *auth.ConfirmationLinks,
+	auditLog *audit.Log,
+) *ConfirmPackageController {
 	return &ConfirmPackageController{
-		Logger:                       logger,
-		TemporalClient:               temporalClient,
+		packageConfirmer: packageConfirmer{
+			Logger:         logger,
+			TemporalClient: temporalClient,
+			ObjectStore:    objectStore,
+			Links:          links,
+			Audit:          auditLog,
+		},
 		PackageDeliveryTaskQueueName: workflow.PackageDeliveryTaskQueueName,
+		PackageStore:                 packageStore,
+		Operators:                    operators,
 	}
 }
 
 // ConfirmPackage godoc
 // @Summary      Confirm package delivery
-// @Description  Confirm the delivery of a package
+// @Description  Confirm the delivery of a package, optionally with a proof of delivery. The proof can also be sent
+// @Description  as multipart/form-data with the fields recipient_name, latitude and longitude and the files
+// @Description  signature and photo. Requires an operator bearer token or the package's confirmation token. With
+// @Description  If-Match the package is only confirmed while it is still at that version.
 // @Tags         packages
-// @Accept       json
+// @Accept       json,mpfd
 // @Produce      json
 // @Param        id path string true "Package ID"
+// @Param        Authorization header string false "Operator bearer token"
+// @Param        X-Confirmation-Token header string false "Confirmation token sent to the customer"
+// @Param        If-Match header string false "Expected package version (ETag)"
+// @Param        body body ConfirmPackageRequest false "Proof of delivery"
 // @Success      200 {object} ConfirmPackageResponse "Confirmation status"
 // @Failure      400 {object} model.HttpErrorResponse "Invalid input data"
+// @Failure      401 {object} model.HttpErrorResponse "Authentication required"
+// @Failure      404 {object} model.HttpErrorResponse "No running delivery workflow"
+// @Failure      409 {object} ConfirmPackageResponse "Package delivery is already confirmed or disputed"
+// @Failure      410 {object} model.HttpErrorResponse "Confirmation token expired or already used"
+// @Failure      412 {object} model.HttpErrorResponse "Package has been modified"
 // @Failure      502 {object} model.HttpErrorResponse "Unable to confirm package"
 // @Router       /api/v1/packages/{id}/confirm [post]
 func (c *ConfirmPackageController) ConfirmPackage(ctx *gin.Context) {
@@ -48,19 +92,55 @@ func (c *ConfirmPackageController) ConfirmPackage(ctx *gin.Context) {
 		return
 	}
 
-	err := c.TemporalClient.SignalWorkflow(
-		context.Background(),
-		packageId,
-		"",
-		workflow.PackageDeliverySignalConfirm,
-		model.DeliveryPackageWorkflowStatus{RequestReceived: true},
-	)
-	if err != nil {
-		c.Logger.Error("Unable to signal workflow", zap.Error(err))
+	actor := authenticate(ctx, c.Operators, c.Links, packageId)
+	if actor == nil {
+		return
+	}
+
+	confirmation := &model.DeliveryConfirmation{
+		ConfirmedBy: actor.Name,
+		ConfirmedAt: time.Now().UTC(),
+		Channel:     actor.Channel,
+	}
+
+	expectedVersion, conditional, ok := etag.OptionalVersion(ctx)
+	if !ok {
+		return
+	}
 
-		ctx.JSON(http.StatusBadGateway, gin.H{"error": "Unable to confirm package, package is already delivered"})
+	if !requirePackageWorkflow(ctx, c.Logger, c.TemporalClient, packageId) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, &ConfirmPackageResponse{Status: model.PackageDeliveryConfirmed})
+	if conditional && !c.claimVersion(ctx, packageId, expectedVersion) {
+		return
+	}
+
+	c.confirm(ctx, packageId, workflow.PackageDeliveryUpdateConfirm, confirmation, actor.Claims)
+}
+
+// claimVersion claims the package at the version the client expects, or
+// writes the error response and returns false.
+func (c *ConfirmPackageController) claimVersion(ctx *gin.Context, packageId string, expectedVersion int64) bool {
+	claimed, err := repository.ClaimPackageVersion(ctx.Request.Context(), c.PackageStore, packageId, expectedVersion)
+	var conflict *repository.VersionConflictError
+	if errors.As(err, &conflict) {
+		etag.Set(ctx, conflict.ActualVersion)
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{"error": "Package has been modified"})
+		return false
+	}
+	if errors.Is(err, repository.ErrPackageNotFound) {
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{"error": "Package has not been saved"})
+		return false
+	}
+	if err != nil {
+		c.Logger.Error("Unable to claim package version", zap.String("packageId", packageId), zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check package version"})
+		return false
+	}
+
+	if claimed != nil {
+		etag.Set(ctx, claimed.Version)
+	}
+	return true
 }
@@ -0,0 +1,80 @@
+package packages
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"go-test/internal/audit"
+	"go-test/internal/auth"
+	"go-test/internal/model"
+	"go-test/internal/workflow"
+	"go-test/repository"
+	"go.temporal.io/api/common/v1"
+	workflowpb "go.temporal.io/api/workflow/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/mocks"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// confirmRouter serves the confirm endpoint for a running package delivery.
+// The workflow client has no update, so a confirmation that gets past the
+// precondition fails the test.
+func confirmRouter(t *testing.T, store repository.PackageStore) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	temporalClient := &mocks.Client{}
+	temporalClient.On("DescribeWorkflowExecution", mock.Anything, "PKG-1", "").Return(&workflowservice.DescribeWorkflowExecutionResponse{
+		WorkflowExecutionInfo: &workflowpb.WorkflowExecutionInfo{
+			Type: &common.WorkflowType{Name: workflow.PackageDeliveryWorkflowName},
+		},
+	}, nil)
+
+	logger := zap.NewNop()
+	controller := RegisterConfirmPackageController(
+		logger,
+		temporalClient,
+		nil,
+		store,
+		auth.NewOperators(map[string]string{"alice": "operator-token"}),
+		nil,
+		audit.NewLog(repository.NewMemoryRepository(), logger),
+	)
+
+	router := gin.New()
+	router.POST("/packages/:id/confirm", controller.ConfirmPackage)
+	return router
+}
+
+func confirm(router *gin.Engine, ifMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/packages/PKG-1/confirm", nil)
+	req.Header.Set("Authorization", "Bearer operator-token")
+	req.Header.Set("If-Match", ifMatch)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestConfirmPackageRejectsStaleVersion(t *testing.T) {
+	store := repository.NewMemoryRepository()
+	if _, err := store.CreatePackageDelivery(context.Background(), &model.DeliveryPackage{ID: "PKG-1"}); err != nil {
+		t.Fatalf("CreatePackageDelivery: %v", err)
+	}
+	router := confirmRouter(t, store)
+
+	rec := confirm(router, `"2"`)
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusPreconditionFailed, rec.Body)
+	}
+	if etag := rec.Header().Get("ETag"); etag != `"1"` {
+		t.Fatalf("ETag = %s, want the current version", etag)
+	}
+
+	if rec := confirm(router, "2"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("status with unquoted If-Match = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,84 @@
+package packages
+
+import (
+	"github.com/gin-gonic/gin"
+	"go-test/internal/audit"
+	"go-test/internal/auth"
+	"go-test/internal/model"
+	"go-test/internal/storage"
+	"go-test/internal/workflow"
+	"go.temporal.io/sdk/client"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type ConfirmShipmentController struct {
+	packageConfirmer
+	Operators *auth.Operators
+}
+
+func RegisterConfirmShipmentController(
+	logger *zap.Logger,
+	temporalClient client.Client,
+	objectStore storage.ObjectStore,
+	operators *auth.Operators,
+	links *auth.ConfirmationLinks,
+	auditLog *audit.Log,
+) *ConfirmShipmentController {
+	return &ConfirmShipmentController{
+		packageConfirmer: packageConfirmer{
+			Logger:         logger,
+			TemporalClient: temporalClient,
+			ObjectStore:    objectStore,
+			Links:          links,
+			Audit:          auditLog,
+		},
+		Operators: operators,
+	}
+}
+
+// ConfirmShipment godoc
+// @Summary      Confirm all parcels of a shipment
+// @Description  Confirm the delivery of every parcel of a shipment that is not confirmed yet, optionally with a proof
+// @Description  of delivery that applies to all of them. Parcels with an open dispute stay disputed. Requires an
+// @Description  operator bearer token or the shipment's confirmation token.
+// @Tags         shipments
+// @Accept       json,mpfd
+// @Produce      json
+// @Param        id path string true "Shipment ID"
+// @Param        Authorization header string false "Operator bearer token"
+// @Param        X-Confirmation-Token header string false "Confirmation token sent to the customer"
+// @Param        body body ConfirmPackageRequest false "Proof of delivery"
+// @Success      200 {object} ConfirmPackageResponse "Confirmation status"
+// @Failure      400 {object} model.HttpErrorResponse "Invalid input data"
+// @Failure      401 {object} model.HttpErrorResponse "Authentication required"
+// @Failure      404 {object} model.HttpErrorResponse "No running delivery workflow"
+// @Failure      409 {object} ConfirmPackageResponse "Shipment is already confirmed"
+// @Failure      410 {object} model.HttpErrorResponse "Confirmation token expired or already used"
+// @Failure      502 {object} model.HttpErrorResponse "Unable to confirm shipment"
+// @Router       /api/v1/shipments/{id}/confirm [post]
+func (c *ConfirmShipmentController) ConfirmShipment(ctx *gin.Context) {
+	shipmentId := ctx.Param("id")
+
+	if shipmentId == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Shipment ID is required"})
+		return
+	}
+
+	actor := authenticate(ctx, c.Operators, c.Links, shipmentId)
+	if actor == nil {
+		return
+	}
+
+	if requireWorkflow(ctx, c.Logger, c.TemporalClient, shipmentId, workflow.ShipmentWorkflowName) == "" {
+		return
+	}
+
+	// The shipment workflow passes the confirmation on to its parcels.
+	c.confirm(ctx, shipmentId, workflow.ShipmentUpdateConfirm, &model.DeliveryConfirmation{
+		ConfirmedBy: actor.Name,
+		ConfirmedAt: time.Now().UTC(),
+		Channel:     actor.Channel,
+	}, actor.Claims)
+}
@@ -2,8 +2,12 @@ package packages
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"go-test/internal/calendar"
+	"go-test/internal/config"
 	"go-test/internal/events"
 	"go-test/internal/model"
 	_ "go-test/internal/model"
@@ -11,8 +15,13 @@ import (
 	"go.temporal.io/sdk/client"
 	"go.uber.org/zap"
 	"net/http"
+	"time"
 )
 
+// deliveryWindowLayout is the layout of the start and end of a delivery
+// window, a wall clock time in the customer's time zone.
+const deliveryWindowLayout = "2006-01-02T15:04"
+
 type CreatePackageResponse struct {
 	PackageId string `json:"packageId"`
 }
@@ -22,35 +31,72 @@ type CreatePackageController struct {
 	TemporalClient               client.Client
 	PackageDeliveryTaskQueueName string
 	EventProducer                *events.EventProducer
+	Calendar                     *calendar.Calendar
 }
 
 func RegisterCreatePackageController(
 	logger *zap.Logger,
 	temporalClient client.Client,
 	eventProducer *events.EventProducer,
+	deliveryCalendar *calendar.Calendar,
 ) *CreatePackageController {
 	return &CreatePackageController{
 		Logger:                       logger,
 		TemporalClient:               temporalClient,
 		PackageDeliveryTaskQueueName: workflow.PackageDeliveryTaskQueueName,
 		EventProducer:                eventProducer,
+		Calendar:                     deliveryCalendar,
 	}
 }
 
 type CreatePackageRequest struct {
-	CustomerEmail   string `json:"customer_email" binding:"required,email"`
-	DeliveryAddress string `json:"delivery_address" binding:"required"`
+	CustomerEmail   string                 `json:"customer_email" binding:"required,email"`
+	DeliveryAddress string                 `json:"delivery_address" binding:"required"`
+	Region          string                 `json:"region"`
+	DeliveryWindow  *DeliveryWindowRequest `json:"delivery_window"`
+	// ActivityPolicies overrides the configured retry and timeout policies
+	// of activities for this package only, keyed by activity name.
+	ActivityPolicies map[string]config.ActivityPolicy `json:"activity_policies"`
+}
+
+// DeliveryWindowRequest is the time span the customer wants the package
+// delivered in, as wall clock times in their time zone.
+type DeliveryWindowRequest struct {
+	Start    string `json:"start" binding:"required" example:"2024-05-14T14:00"`
+	End      string `json:"end" binding:"required" example:"2024-05-14T16:00"`
+	TimeZone string `json:"time_zone" binding:"required" example:"Europe/Berlin"`
+}
+
+// deliveryWindow parses the window in the customer's time zone.
+func (r *DeliveryWindowRequest) deliveryWindow() (*model.DeliveryWindow, error) {
+	location, err := time.LoadLocation(r.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", r.TimeZone)
+	}
+
+	start, err := time.ParseInLocation(deliveryWindowLayout, r.Start, location)
+	if err != nil {
+		return nil, fmt.Errorf("start %q is not YYYY-MM-DDTHH:MM", r.Start)
+	}
+	end, err := time.ParseInLocation(deliveryWindowLayout, r.End, location)
+	if err != nil {
+		return nil, fmt.Errorf("end %q is not YYYY-MM-DDTHH:MM", r.End)
+	}
+
+	return &model.DeliveryWindow{Start: start, End: end, TimeZone: r.TimeZone}, nil
 }
 
 // CreatePackage godoc
 // @Summary      Create a new delivery package
-// @Description  Create a new package and start the delivery workflow
+// @Description  Create a new package and start the delivery workflow. An optional delivery window must fall within the
+// @Description  business hours of a working day; the confirmation window opens shortly before it. Activity policies
+// @Description  override the configured retry and timeout policies of the named activities for this package only.
 // @Tags         packages
 // @Accept       json
 // @Produce      json
 // @Param        body body CreatePackageRequest true "Package details"
 // @Success      200 {object} CreatePackageResponse "Package ID"
-// @Failure      400 {object} model.HttpErrorResponse "Invalid input data"
+// @Failure      400 {object} model.HttpErrorResponse "Invalid input data, delivery window or activity policies"
 // @Failure      500 {object} model.HttpErrorResponse "Internal error"
 // @Router       /api/v1/packages [post]
 func (c *CreatePackageController) CreatePackage(ctx *gin.Context) {
@@ -65,15 +111,47 @@ func (c *CreatePackageController) CreatePackage(ctx *gin.Context) {
 		return
 	}
 
+	var deliveryWindow *model.DeliveryWindow
+	if req.DeliveryWindow != nil {
+		window, err := req.DeliveryWindow.deliveryWindow()
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		err = c.Calendar.CheckWindow(*window, time.Now())
+		if errors.Is(err, calendar.ErrWindowUnavailable) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.Logger.Error("failed to check delivery window", zap.Error(err))
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create delivery package, try again"})
+			return
+		}
+
+		deliveryWindow = window
+	}
+
+	if err := workflow.ValidatePackageActivityPolicies(req.ActivityPolicies); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	deliveryTrackingId := uuid.New().String()
 
 	deliveryPackage := &model.DeliveryPackage{
 		ID:              deliveryTrackingId,
 		CustomerEmail:   req.CustomerEmail,
 		DeliveryAddress: req.DeliveryAddress,
+		Region:          req.Region,
+		DeliveryWindow:  deliveryWindow,
 	}
 
-	event, err := json.Marshal(deliveryPackage)
+	event, err := json.Marshal(&workflow.PackageDeliveryRequest{
+		DeliveryPackage:  deliveryPackage,
+		ActivityPolicies: req.ActivityPolicies,
+	})
 	if err != nil {
 		c.Logger.Error("failed to marshal delivery package", zap.Error(err))
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create delivery package, try again"})
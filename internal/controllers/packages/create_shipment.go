@@ -0,0 +1,91 @@
+package packages
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go-test/internal/model"
+	"go-test/internal/workflow"
+	"go.temporal.io/sdk/client"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+const maxShipmentParcels = 50
+
+type CreateShipmentRequest struct {
+	CustomerEmail   string `json:"customer_email" binding:"required,email"`
+	DeliveryAddress string `json:"delivery_address" binding:"required"`
+	Region          string `json:"region"`
+	Parcels         int    `json:"parcels" binding:"required,min=1,max=50"`
+}
+
+type CreateShipmentResponse struct {
+	ShipmentId string   `json:"shipmentId"`
+	PackageIds []string `json:"packageIds"`
+}
+
+type CreateShipmentController struct {
+	Logger                       *zap.Logger
+	TemporalClient               client.Client
+	PackageDeliveryTaskQueueName string
+}
+
+func RegisterCreateShipmentController(logger *zap.Logger, temporalClient client.Client) *CreateShipmentController {
+	return &CreateShipmentController{
+		Logger:                       logger,
+		TemporalClient:               temporalClient,
+		PackageDeliveryTaskQueueName: workflow.PackageDeliveryTaskQueueName,
+	}
+}
+
+// CreateShipment godoc
+// @Summary      Create a new shipment
+// @Description  Create a shipment of several parcels for one order and start its delivery. Every parcel is delivered
+// @Description  as a package of its own, the customer gets one confirmation link and one notification for the shipment.
+// @Tags         shipments
+// @Accept       json
+// @Produce      json
+// @Param        body body CreateShipmentRequest true "Shipment details"
+// @Success      200 {object} CreateShipmentResponse "Shipment and package IDs"
+// @Failure      400 {object} model.HttpErrorResponse "Invalid input data"
+// @Failure      500 {object} model.HttpErrorResponse "Internal error"
+// @Router       /api/v1/shipments [post]
+func (c *CreateShipmentController) CreateShipment(ctx *gin.Context) {
+	var req CreateShipmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data"})
+		return
+	}
+
+	if req.Parcels < 1 || req.Parcels > maxShipmentParcels {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "A shipment has between 1 and 50 parcels"})
+		return
+	}
+
+	shipment := &model.Shipment{
+		ID:              workflow.ShipmentIDPrefix + uuid.New().String(),
+		CustomerEmail:   req.CustomerEmail,
+		DeliveryAddress: req.DeliveryAddress,
+		Region:          req.Region,
+		Status:          model.ShipmentInProgress,
+	}
+
+	packageIds := make([]string, req.Parcels)
+	for i := range packageIds {
+		packageIds[i] = uuid.New().String()
+		shipment.Parcels = append(shipment.Parcels, model.ShipmentParcel{PackageID: packageIds[i]})
+	}
+
+	_, err := c.TemporalClient.ExecuteWorkflow(context.Background(), client.StartWorkflowOptions{
+		ID:        shipment.ID,
+		TaskQueue: c.PackageDeliveryTaskQueueName,
+	}, workflow.ShipmentWorkflowName, &workflow.ShipmentWorkflowParams{Shipment: shipment})
+	if err != nil {
+		c.Logger.Error("failed to start shipment workflow", zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create shipment, try again"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &CreateShipmentResponse{ShipmentId: shipment.ID, PackageIds: packageIds})
+}
@@ -0,0 +1,155 @@
+package packages
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"go-test/internal/audit"
+	"go-test/internal/auth"
+	"go-test/internal/model"
+	"go-test/internal/workflow"
+	"go-test/repository"
+	"go.temporal.io/sdk/client"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type RecordAttemptRequest struct {
+	Outcome model.AttemptOutcome `json:"outcome" enums:"delivered,nobodyHome,refused,addressInvalid"`
+	Note    string               `json:"note"`
+}
+
+type RecordAttemptResponse struct {
+	Attempt *model.DeliveryAttempt `json:"attempt"`
+}
+
+type ListAttemptsResponse struct {
+	Attempts []model.PackageDeliveryAttempt `json:"attempts"`
+}
+
+type DeliveryAttemptsController struct {
+	Logger         *zap.Logger
+	TemporalClient client.Client
+	AttemptStore   repository.DeliveryAttemptStore
+	Operators      *auth.Operators
+	Audit          *audit.Log
+}
+
+func RegisterDeliveryAttemptsController(
+	logger *zap.Logger,
+	temporalClient client.Client,
+	attemptStore repository.DeliveryAttemptStore,
+	operators *auth.Operators,
+	auditLog *audit.Log,
+) *DeliveryAttemptsController {
+	return &DeliveryAttemptsController{
+		Logger:         logger,
+		TemporalClient: temporalClient,
+		AttemptStore:   attemptStore,
+		Operators:      operators,
+		Audit:          auditLog,
+	}
+}
+
+// RecordAttempt godoc
+// @Summary      Report a delivery attempt
+// @Description  Report the outcome of a delivery attempt. A delivered attempt confirms the delivery, a failed one
+// @Description  schedules a reattempt or, after too many failures, returns the package to the sender. Requires an
+// @Description  operator bearer token, whose name is recorded as the driver.
+// @Tags         packages
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Package ID"
+// @Param        Authorization header string true "Operator bearer token"
+// @Param        body body RecordAttemptRequest true "Attempt outcome"
+// @Success      202 {object} RecordAttemptResponse "Attempt accepted"
+// @Failure      400 {object} model.HttpErrorResponse "Invalid input data"
+// @Failure      401 {object} model.HttpErrorResponse "Authentication required"
+// @Failure      404 {object} model.HttpErrorResponse "No running delivery workflow"
+// @Failure      409 {object} model.HttpErrorResponse "Package delivery is already confirmed or disputed"
+// @Failure      502 {object} model.HttpErrorResponse "Unable to report attempt"
+// @Router       /api/v1/packages/{id}/attempts [post]
+func (c *DeliveryAttemptsController) RecordAttempt(ctx *gin.Context) {
+	packageId := ctx.Param("id")
+
+	if packageId == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Package ID is required"})
+		return
+	}
+
+	driver, ok := c.Operators.Authenticate(ctx.Request)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Operator authentication is required"})
+		return
+	}
+
+	var req RecordAttemptRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data"})
+		return
+	}
+
+	attempt := &model.DeliveryAttempt{
+		Outcome:     req.Outcome,
+		Driver:      driver,
+		Note:        req.Note,
+		AttemptedAt: time.Now().UTC(),
+	}
+	if err := attempt.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !requirePackageWorkflow(ctx, c.Logger, c.TemporalClient, packageId) {
+		return
+	}
+
+	result, err := updateWorkflow(context.Background(), c.TemporalClient, packageId, workflow.PackageDeliveryUpdateAttempt, attempt)
+	if err != nil {
+		respondUpdateError(ctx, c.Logger, packageId, "Unable to report attempt", err)
+		return
+	}
+
+	if result.Outcome != workflow.UpdateAccepted {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Package delivery is already confirmed or disputed"})
+		return
+	}
+
+	c.Audit.Record(ctx.Request.Context(), model.AuditEntry{
+		PackageID: packageId,
+		Operator:  driver,
+		Action:    model.AuditRecordAttempt,
+		Reason:    req.Note,
+		Detail:    string(req.Outcome),
+	})
+
+	ctx.JSON(http.StatusAccepted, &RecordAttemptResponse{Attempt: attempt})
+}
+
+// ListAttempts godoc
+// @Summary      List delivery attempts
+// @Description  List the recorded delivery attempts of a package
+// @Tags         packages
+// @Produce      json
+// @Param        id path string true "Package ID"
+// @Success      200 {object} ListAttemptsResponse
+// @Failure      400 {object} model.HttpErrorResponse "Invalid input data"
+// @Failure      500 {object} model.HttpErrorResponse "Internal error"
+// @Router       /api/v1/packages/{id}/attempts [get]
+func (c *DeliveryAttemptsController) ListAttempts(ctx *gin.Context) {
+	packageId := ctx.Param("id")
+
+	if packageId == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Package ID is required"})
+		return
+	}
+
+	attempts, err := c.AttemptStore.ListDeliveryAttempts(ctx.Request.Context(), packageId)
+	if err != nil {
+		c.Logger.Error("Unable to list delivery attempts", zap.String("packageId", packageId), zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list delivery attempts"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &ListAttemptsResponse{Attempts: attempts})
+}
@@ -0,0 +1,151 @@
+package packages
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"go-test/internal/audit"
+	"go-test/internal/auth"
+	"go-test/internal/model"
+	"go-test/internal/storage"
+	"go-test/internal/workflow"
+	"go.temporal.io/sdk/client"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type DisputePackageRequest struct {
+	Category model.DisputeCategory `json:"category" enums:"notReceived,damaged,other"`
+	Reason   string                `json:"reason"`
+}
+
+type DisputePackageResponse struct {
+	Status  model.PackageDeliveryState `json:"status"`
+	Dispute *model.Dispute             `json:"dispute,omitempty"`
+}
+
+type DisputePackageController struct {
+	packageConfirmer
+	Operators *auth.Operators
+}
+
+func RegisterDisputePackageController(
+	logger *zap.Logger,
+	temporalClient client.Client,
+	objectStore storage.ObjectStore,
+	operators *auth.Operators,
+	links *auth.ConfirmationLinks,
+	auditLog *audit.Log,
+) *DisputePackageController {
+	return &DisputePackageController{
+		packageConfirmer: packageConfirmer{
+			Logger:         logger,
+			TemporalClient: temporalClient,
+			ObjectStore:    objectStore,
+			Links:          links,
+			Audit:          auditLog,
+		},
+		Operators: operators,
+	}
+}
+
+// DisputePackage godoc
+// @Summary      Dispute package delivery
+// @Description  Report a package as not received or damaged instead of confirming it. Support is notified and the
+// @Description  delivery waits for an operator to resolve the dispute. Requires an operator bearer token or the
+// @Description  package's confirmation token, which is consumed once the dispute is accepted. The response carries
+// @Description  the ID of the dispute, which its resolution refers to.
+// @Tags         packages
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Package ID"
+// @Param        Authorization header string false "Operator bearer token"
+// @Param        X-Confirmation-Token header string false "Confirmation token sent to the customer"
+// @Param        body body DisputePackageRequest true "Dispute"
+// @Success      202 {object} DisputePackageResponse "Dispute accepted"
+// @Failure      400 {object} model.HttpErrorResponse "Invalid input data"
+// @Failure      401 {object} model.HttpErrorResponse "Authentication required"
+// @Failure      404 {object} model.HttpErrorResponse "No running delivery workflow"
+// @Failure      409 {object} DisputePackageResponse "Package delivery is already confirmed or disputed"
+// @Failure      410 {object} model.HttpErrorResponse "Confirmation token expired or already used"
+// @Failure      502 {object} model.HttpErrorResponse "Unable to dispute package"
+// @Router       /api/v1/packages/{id}/dispute [post]
+func (c *DisputePackageController) DisputePackage(ctx *gin.Context) {
+	packageId := ctx.Param("id")
+
+	if packageId == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Package ID is required"})
+		return
+	}
+
+	actor := authenticate(ctx, c.Operators, c.Links, packageId)
+	if actor == nil {
+		return
+	}
+
+	var req DisputePackageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data"})
+		return
+	}
+
+	dispute := &model.Dispute{
+		Category: req.Category,
+		Reason:   req.Reason,
+		RaisedBy: actor.Name,
+		RaisedAt: time.Now().UTC(),
+		Channel:  actor.Channel,
+	}
+	if err := dispute.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Shipments have confirmation tokens too, but are disputed parcel by
+	// parcel.
+	if !requirePackageWorkflow(ctx, c.Logger, c.TemporalClient, packageId) {
+		return
+	}
+
+	var redeemedAt time.Time
+	if actor.Claims != nil {
+		var redeemed bool
+		if redeemedAt, redeemed = redeemToken(ctx, c.Logger, c.Links, packageId, actor.Claims); !redeemed {
+			return
+		}
+	}
+
+	// refuse releases the token of a dispute that did not go through.
+	refuse := func() {
+		if actor.Claims != nil {
+			releaseToken(ctx.Request.Context(), c.Logger, c.Links, packageId, actor.Claims, redeemedAt)
+		}
+	}
+
+	result, err := updateWorkflow(context.Background(), c.TemporalClient, packageId, workflow.PackageDeliveryUpdateDispute, dispute)
+	if err != nil {
+		refuse()
+		respondUpdateError(ctx, c.Logger, packageId, "Unable to dispute package", err)
+		return
+	}
+
+	// Confirmed packages and packages with an open dispute refuse disputes;
+	// the token stays usable.
+	if result.Outcome != workflow.UpdateAccepted {
+		refuse()
+		ctx.JSON(http.StatusConflict, &DisputePackageResponse{Status: result.Status, Dispute: result.Dispute})
+		return
+	}
+
+	if actor.Claims == nil {
+		c.Audit.Record(ctx.Request.Context(), model.AuditEntry{
+			PackageID: packageId,
+			Operator:  actor.Name,
+			Action:    model.AuditDispute,
+			Reason:    dispute.Reason,
+			Detail:    string(dispute.Category),
+		})
+	}
+
+	ctx.JSON(http.StatusAccepted, &DisputePackageResponse{Status: model.PackageDeliveryDisputed, Dispute: result.Dispute})
+}
@@ -3,10 +3,15 @@ package packages
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"github.com/gin-gonic/gin"
-	_ "go-test/internal/model"
+	"go-test/internal/controllers/etag"
+	"go-test/internal/model"
 	"go-test/internal/workflow"
+	"go-test/repository"
+	"go.temporal.io/api/serviceerror"
 	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
 	"go.uber.org/zap"
 	"net/http"
 )
@@ -14,12 +19,14 @@ import (
 type GetPackageController struct {
 	Logger         *zap.Logger
 	TemporalClient client.Client
+	PackageStore   repository.PackageStore
 }
 
-func RegisterGetPackageController(logger *zap.Logger, temporalClient client.Client) *GetPackageController {
+func RegisterGetPackageController(logger *zap.Logger, temporalClient client.Client, packageStore repository.PackageStore) *GetPackageController {
 	return &GetPackageController{
 		Logger:         logger,
 		TemporalClient: temporalClient,
+		PackageStore:   packageStore,
 	}
 }
 
@@ -31,6 +38,7 @@ func RegisterGetPackageController(logger *zap.Logger, temporalClient client.Clie
 // @Produce      json
 // @Param        id path string true "Package ID"
 // @Success      200 {object} model.DeliveryPackage
+// @Header       200 {string} ETag "Current package version"
 // @Failure      400 {object} model.HttpErrorResponse "Invalid input data"
 // @Failure      404 {object} model.HttpErrorResponse "Package not found"
 // @Router       /api/v1/packages/{id} [get]
@@ -41,7 +49,21 @@ func (c *GetPackageController) GetPackage(ctx *gin.Context) {
 		return
 	}
 
-	wf, err := c.TemporalClient.QueryWorkflow(context.Background(), packageId, "", workflow.PackageDeliveryStateQuery)
+	err := checkPackageWorkflow(ctx.Request.Context(), c.TemporalClient, packageId)
+	if errors.Is(err, errNotPackageWorkflow) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Package not found"})
+		return
+	}
+
+	var wf converter.EncodedValue
+	if err == nil {
+		wf, err = c.TemporalClient.QueryWorkflow(context.Background(), packageId, "", workflow.PackageDeliveryStateQuery)
+	}
+	var notFound *serviceerror.NotFound
+	if errors.As(err, &notFound) {
+		c.getStoredPackage(ctx, packageId)
+		return
+	}
 	if err != nil {
 		c.Logger.Error("Error querying Temporal client", zap.String("packageId", packageId), zap.Error(err))
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to query workflow"})
@@ -49,7 +71,6 @@ func (c *GetPackageController) GetPackage(ctx *gin.Context) {
 	}
 
 	var queryResult interface{}
-	err = wf.Get(&queryResult)
 	if err := wf.Get(&queryResult); err != nil {
 		c.Logger.Error("Error getting query result", zap.String("packageId", packageId), zap.Error(err))
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve query result"})
@@ -78,5 +99,38 @@ func (c *GetPackageController) GetPackage(ctx *gin.Context) {
 		resultData = gin.H{"status": workflowResult.Status}
 	}
 
+	version, err := packageVersion(ctx.Request.Context(), c.PackageStore, packageId)
+	if err != nil {
+		c.Logger.Warn("Unable to read package version", zap.String("packageId", packageId), zap.Error(err))
+	} else {
+		etag.Set(ctx, version)
+	}
+
 	ctx.JSON(http.StatusOK, resultData)
 }
+
+// getStoredPackage answers for packages whose workflow is no longer known to
+// Temporal, e.g. after the retention period, from the persisted record.
+func (c *GetPackageController) getStoredPackage(ctx *gin.Context, packageId string) {
+	deliveryPackage, err := c.PackageStore.GetPackageDelivery(ctx.Request.Context(), packageId)
+	if errors.Is(err, repository.ErrPackageNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Package not found"})
+		return
+	}
+	if err != nil {
+		c.Logger.Error("Error reading stored package", zap.String("packageId", packageId), zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read package"})
+		return
+	}
+
+	// The store saves packages without a status as saved, remediation and
+	// returns set another one. Saved is only assumed for a record that
+	// somehow lacks it.
+	status := deliveryPackage.Status
+	if status == "" {
+		status = model.PackageDeliverySaved
+	}
+
+	etag.Set(ctx, deliveryPackage.Version)
+	ctx.JSON(http.StatusOK, gin.H{"status": status, "package": deliveryPackage})
+}
@@ -0,0 +1,194 @@
+package packages
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-test/internal/auth"
+	"go-test/internal/model"
+	"go-test/internal/storage"
+	"go-test/repository"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/client"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+)
+
+type GetProofResponse struct {
+	Proof *model.ProofOfDelivery `json:"proof"`
+	// Links points to the stored images, keyed by proof item.
+	Links map[string]string `json:"links,omitempty"`
+}
+
+type GetProofController struct {
+	Logger         *zap.Logger
+	TemporalClient client.Client
+	PackageStore   repository.PackageStore
+	ObjectStore    storage.ObjectStore
+	Operators      *auth.Operators
+	Links          *auth.ConfirmationLinks
+}
+
+func RegisterGetProofController(
+	logger *zap.Logger,
+	temporalClient client.Client,
+	packageStore repository.PackageStore,
+	objectStore storage.ObjectStore,
+	operators *auth.Operators,
+	links *auth.ConfirmationLinks,
+) *GetProofController {
+	return &GetProofController{
+		Logger:         logger,
+		TemporalClient: temporalClient,
+		PackageStore:   packageStore,
+		ObjectStore:    objectStore,
+		Operators:      operators,
+		Links:          links,
+	}
+}
+
+// authorize accepts an operator bearer token or the confirmation token of
+// the package as long as it has not been used, or writes the error response
+// and returns false.
+func (c *GetProofController) authorize(ctx *gin.Context, packageId string) bool {
+	actor := authenticate(ctx, c.Operators, c.Links, packageId)
+	if actor == nil {
+		return false
+	}
+	if actor.Claims == nil {
+		return true
+	}
+
+	return checkUnredeemed(ctx, c.Logger, c.Links, packageId, actor.Claims)
+}
+
+// GetProof godoc
+// @Summary      Get proof of delivery
+// @Description  Get the proof of delivery sent with the package confirmation. Requires an operator bearer token or
+// @Description  the package's confirmation token, as long as it has not been used.
+// @Tags         packages
+// @Produce      json
+// @Param        id path string true "Package ID"
+// @Param        Authorization header string false "Operator bearer token"
+// @Param        X-Confirmation-Token header string false "Confirmation token sent to the customer"
+// @Success      200 {object} GetProofResponse
+// @Failure      401 {object} model.HttpErrorResponse "Authentication required"
+// @Failure      404 {object} model.HttpErrorResponse "No proof of delivery for the package"
+// @Failure      410 {object} model.HttpErrorResponse "Confirmation token expired or already used"
+// @Failure      500 {object} model.HttpErrorResponse "Failed to read proof of delivery"
+// @Router       /api/v1/packages/{id}/proof [get]
+func (c *GetProofController) GetProof(ctx *gin.Context) {
+	packageId := ctx.Param("id")
+
+	if !c.authorize(ctx, packageId) {
+		return
+	}
+
+	proof, err := c.findProof(ctx.Request.Context(), packageId)
+	if err != nil {
+		c.Logger.Error("Unable to read proof of delivery", zap.String("packageId", packageId), zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read proof of delivery"})
+		return
+	}
+	if proof == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "No proof of delivery for the package"})
+		return
+	}
+
+	links := make(map[string]string)
+	if proof.Signature != nil {
+		links[proofItemSignature] = ctx.Request.URL.Path + "/" + proofItemSignature
+	}
+	if proof.Photo != nil {
+		links[proofItemPhoto] = ctx.Request.URL.Path + "/" + proofItemPhoto
+	}
+
+	ctx.JSON(http.StatusOK, &GetProofResponse{Proof: proof, Links: links})
+}
+
+// GetProofImage godoc
+// @Summary      Get proof of delivery image
+// @Description  Download the signature or photo of the proof of delivery. Requires an operator bearer token or the
+// @Description  package's confirmation token, as long as it has not been used.
+// @Tags         packages
+// @Produce      image/png,image/jpeg,image/webp
+// @Param        id path string true "Package ID"
+// @Param        item path string true "Proof item" Enums(signature, photo)
+// @Param        Authorization header string false "Operator bearer token"
+// @Param        X-Confirmation-Token header string false "Confirmation token sent to the customer"
+// @Success      200 {file} binary
+// @Failure      401 {object} model.HttpErrorResponse "Authentication required"
+// @Failure      404 {object} model.HttpErrorResponse "Proof item not found"
+// @Failure      410 {object} model.HttpErrorResponse "Confirmation token expired or already used"
+// @Failure      500 {object} model.HttpErrorResponse "Failed to read proof of delivery"
+// @Router       /api/v1/packages/{id}/proof/{item} [get]
+func (c *GetProofController) GetProofImage(ctx *gin.Context) {
+	packageId := ctx.Param("id")
+
+	if !c.authorize(ctx, packageId) {
+		return
+	}
+
+	proof, err := c.findProof(ctx.Request.Context(), packageId)
+	if err != nil {
+		c.Logger.Error("Unable to read proof of delivery", zap.String("packageId", packageId), zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read proof of delivery"})
+		return
+	}
+
+	var reference *model.ObjectReference
+	if proof != nil {
+		switch ctx.Param("item") {
+		case proofItemSignature:
+			reference = proof.Signature
+		case proofItemPhoto:
+			reference = proof.Photo
+		}
+	}
+	if reference == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Proof item not found"})
+		return
+	}
+
+	body, err := c.ObjectStore.Get(ctx.Request.Context(), reference.Key)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Proof item not found"})
+		return
+	}
+	if err != nil {
+		c.Logger.Error("Unable to read proof of delivery object", zap.String("packageId", packageId), zap.String("key", reference.Key), zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read proof of delivery"})
+		return
+	}
+	defer body.Close()
+
+	ctx.DataFromReader(http.StatusOK, reference.Size, reference.ContentType, io.LimitReader(body, reference.Size), nil)
+}
+
+// findProof returns the proof from the persisted package, or from the
+// workflow while the package has not been saved yet.
+func (c *GetProofController) findProof(ctx context.Context, packageId string) (*model.ProofOfDelivery, error) {
+	deliveryPackage, err := c.PackageStore.GetPackageDelivery(ctx, packageId)
+	if err == nil && deliveryPackage.Proof != nil {
+		return deliveryPackage.Proof, nil
+	}
+	if err != nil && !errors.Is(err, repository.ErrPackageNotFound) {
+		return nil, err
+	}
+
+	state, err := queryWorkflowState(ctx, c.TemporalClient, packageId)
+	var notFound *serviceerror.NotFound
+	if errors.As(err, &notFound) || errors.Is(err, errNotPackageWorkflow) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if state.Confirmation == nil {
+		return nil, nil
+	}
+
+	return state.Confirmation.Proof, nil
+}
@@ -0,0 +1,94 @@
+package packages
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-test/internal/workflow"
+	"go-test/repository"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type GetShipmentController struct {
+	Logger         *zap.Logger
+	TemporalClient client.Client
+	ShipmentStore  repository.ShipmentStore
+}
+
+func RegisterGetShipmentController(logger *zap.Logger, temporalClient client.Client, shipmentStore repository.ShipmentStore) *GetShipmentController {
+	return &GetShipmentController{
+		Logger:         logger,
+		TemporalClient: temporalClient,
+		ShipmentStore:  shipmentStore,
+	}
+}
+
+// GetShipment godoc
+// @Summary      Get shipment details
+// @Description  Get the aggregated state of a shipment and the state of each of its parcels
+// @Tags         shipments
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Shipment ID"
+// @Success      200 {object} workflow.ShipmentWorkflowResult
+// @Failure      400 {object} model.HttpErrorResponse "Invalid input data"
+// @Failure      404 {object} model.HttpErrorResponse "Shipment not found"
+// @Router       /api/v1/shipments/{id} [get]
+func (c *GetShipmentController) GetShipment(ctx *gin.Context) {
+	shipmentId := ctx.Param("id")
+	if shipmentId == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Shipment ID is required"})
+		return
+	}
+
+	name, err := workflowType(ctx.Request.Context(), c.TemporalClient, shipmentId)
+	if err == nil && name != workflow.ShipmentWorkflowName {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Shipment not found"})
+		return
+	}
+
+	var value converter.EncodedValue
+	if err == nil {
+		value, err = c.TemporalClient.QueryWorkflow(context.Background(), shipmentId, "", workflow.ShipmentStateQuery)
+	}
+	var notFound *serviceerror.NotFound
+	if errors.As(err, &notFound) {
+		c.getStoredShipment(ctx, shipmentId)
+		return
+	}
+	if err != nil {
+		c.Logger.Error("Error querying Temporal client", zap.String("shipmentId", shipmentId), zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to query workflow"})
+		return
+	}
+
+	var result workflow.ShipmentWorkflowResult
+	if err := value.Get(&result); err != nil {
+		c.Logger.Error("Error getting query result", zap.String("shipmentId", shipmentId), zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve query result"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &result)
+}
+
+// getStoredShipment answers for shipments whose workflow is no longer known
+// to Temporal from the persisted record.
+func (c *GetShipmentController) getStoredShipment(ctx *gin.Context, shipmentId string) {
+	shipment, err := c.ShipmentStore.GetShipment(ctx.Request.Context(), shipmentId)
+	if errors.Is(err, repository.ErrShipmentNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Shipment not found"})
+		return
+	}
+	if err != nil {
+		c.Logger.Error("Error reading stored shipment", zap.String("shipmentId", shipmentId), zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read shipment"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &workflow.ShipmentWorkflowResult{Shipment: *shipment})
+}
@@ -0,0 +1,226 @@
+package packages
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-test/internal/audit"
+	"go-test/internal/auth"
+	"go-test/internal/model"
+	"go-test/internal/storage"
+	"go-test/internal/workflow"
+	"go-test/repository"
+	"go.temporal.io/sdk/client"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+// packageConfirmer signals authenticated confirmations to the package
+// delivery workflow. It is shared by the operator and the customer link
+// endpoints.
+type packageConfirmer struct {
+	Logger         *zap.Logger
+	TemporalClient client.Client
+	ObjectStore    storage.ObjectStore
+	Links          *auth.ConfirmationLinks
+	// Audit records the actions of operators. The customer link endpoint
+	// has none.
+	Audit *audit.Log
+}
+
+// verifyToken returns the claims of a confirmation token, or writes the
+// error response and returns nil.
+func verifyToken(ctx *gin.Context, links *auth.ConfirmationLinks, token string) *auth.ConfirmationClaims {
+	claims, err := links.Verify(token)
+	if errors.Is(err, auth.ErrTokenExpired) {
+		ctx.JSON(http.StatusGone, gin.H{"error": "Confirmation token expired"})
+		return nil
+	}
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid confirmation token"})
+		return nil
+	}
+
+	return claims
+}
+
+// confirmUpdates maps the workflows that confirmation links are issued for
+// to their confirm update.
+var confirmUpdates = map[string]string{
+	workflow.PackageDeliveryWorkflowName: workflow.PackageDeliveryUpdateConfirm,
+	workflow.ShipmentWorkflowName:        workflow.ShipmentUpdateConfirm,
+}
+
+// packageActor is the authenticated operator or customer acting on a
+// package.
+type packageActor struct {
+	Name    string
+	Channel model.ConfirmationChannel
+	// Claims is set for customers, whose token must be redeemed.
+	Claims *auth.ConfirmationClaims
+}
+
+// authenticate accepts an operator bearer token or the confirmation token of
+// the package, or writes the error response and returns nil.
+func authenticate(ctx *gin.Context, operators *auth.Operators, links *auth.ConfirmationLinks, packageId string) *packageActor {
+	if operator, ok := operators.Authenticate(ctx.Request); ok {
+		return &packageActor{Name: operator, Channel: model.ConfirmationChannelAPI}
+	}
+
+	token := ctx.GetHeader(ConfirmationTokenHeader)
+	if token == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Operator authentication or a confirmation token is required"})
+		return nil
+	}
+
+	claims := verifyToken(ctx, links, token)
+	if claims == nil {
+		return nil
+	}
+	if claims.PackageID != packageId {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid confirmation token"})
+		return nil
+	}
+
+	return &packageActor{Name: claims.CustomerEmail, Channel: model.ConfirmationChannelLink, Claims: claims}
+}
+
+// checkUnredeemed verifies that the token of claims has not been used, or
+// writes the error response and returns false.
+func checkUnredeemed(ctx *gin.Context, logger *zap.Logger, links *auth.ConfirmationLinks, packageId string, claims *auth.ConfirmationClaims) bool {
+	err := links.CheckUnredeemed(ctx.Request.Context(), claims)
+	if errors.Is(err, repository.ErrTokenConsumed) {
+		ctx.JSON(http.StatusGone, gin.H{"error": "Confirmation token already used"})
+		return false
+	}
+	if errors.Is(err, repository.ErrTokenNotFound) {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid confirmation token"})
+		return false
+	}
+	if err != nil {
+		logger.Error("Unable to check confirmation token", zap.String("packageId", packageId), zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check confirmation token"})
+		return false
+	}
+
+	return true
+}
+
+// redeemToken consumes the token of claims before it is used, so that
+// concurrent requests with one token go ahead at most once. It returns the
+// time of the redemption, or writes the error response and returns false.
+func redeemToken(ctx *gin.Context, logger *zap.Logger, links *auth.ConfirmationLinks, packageId string, claims *auth.ConfirmationClaims) (time.Time, bool) {
+	redeemedAt, err := links.Redeem(ctx.Request.Context(), claims)
+	if errors.Is(err, repository.ErrTokenConsumed) {
+		ctx.JSON(http.StatusGone, gin.H{"error": "Confirmation token already used"})
+		return time.Time{}, false
+	}
+	if errors.Is(err, repository.ErrTokenNotFound) {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid confirmation token"})
+		return time.Time{}, false
+	}
+	if err != nil {
+		logger.Error("Unable to redeem confirmation token", zap.String("packageId", packageId), zap.Error(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to redeem confirmation token"})
+		return time.Time{}, false
+	}
+
+	return redeemedAt, true
+}
+
+// releaseToken makes the token of claims usable again once the workflow has
+// refused what it was redeemed for. The request has failed already, so a
+// token that cannot be released is only logged.
+func releaseToken(ctx context.Context, logger *zap.Logger, links *auth.ConfirmationLinks, packageId string, claims *auth.ConfirmationClaims, redeemedAt time.Time) {
+	if err := links.Release(ctx, claims, redeemedAt); err != nil {
+		logger.Error("Unable to release confirmation token", zap.String("packageId", packageId), zap.Error(err))
+	}
+}
+
+// confirm reads the optional proof of delivery from the request and sends
+// the confirmation to the workflow, which tells whether it is the one that
+// counts. The token of claims, if any, is redeemed before the confirmation
+// is sent and released again when it does not go through.
+func (c *packageConfirmer) confirm(ctx *gin.Context, packageId string, updateName string, confirmation *model.DeliveryConfirmation, claims *auth.ConfirmationClaims) {
+	req, err := bindConfirmPackageRequest(ctx)
+	if errors.Is(err, errInvalidProof) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.Logger.Warn("Invalid confirmation request", zap.String("packageId", packageId), zap.Error(err))
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data"})
+		return
+	}
+
+	var redeemedAt time.Time
+	if claims != nil {
+		var redeemed bool
+		if redeemedAt, redeemed = redeemToken(ctx, c.Logger, c.Links, packageId, claims); !redeemed {
+			return
+		}
+	}
+
+	// refuse undoes the redemption and the stored proof of a confirmation
+	// that did not go through.
+	refuse := func() {
+		c.discardProof(ctx.Request.Context(), packageId, confirmation.Proof)
+		if claims != nil {
+			releaseToken(ctx.Request.Context(), c.Logger, c.Links, packageId, claims, redeemedAt)
+		}
+	}
+
+	if req.hasProof() {
+		confirmation.Proof, err = storeProof(ctx.Request.Context(), c.ObjectStore, packageId, req)
+		if errors.Is(err, errInvalidProof) {
+			refuse()
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			refuse()
+			c.Logger.Error("Unable to store proof of delivery", zap.String("packageId", packageId), zap.Error(err))
+			ctx.JSON(http.StatusBadGateway, gin.H{"error": "Unable to store proof of delivery"})
+			return
+		}
+	}
+
+	result, err := updateWorkflow(context.Background(), c.TemporalClient, packageId, updateName, confirmation)
+	if err != nil {
+		refuse()
+		respondUpdateError(ctx, c.Logger, packageId, "Unable to confirm package", err)
+		return
+	}
+
+	// Duplicates get the confirmation that counted. Disputed packages and
+	// packages waiting for their delivery window do not accept
+	// confirmations, the token stays usable.
+	if result.Outcome != workflow.UpdateAccepted {
+		refuse()
+		ctx.JSON(http.StatusConflict, &ConfirmPackageResponse{Status: result.Status, Confirmation: result.Confirmation})
+		return
+	}
+
+	if claims == nil {
+		c.Audit.Record(ctx.Request.Context(), model.AuditEntry{
+			PackageID: packageId,
+			Operator:  confirmation.ConfirmedBy,
+			Action:    model.AuditConfirm,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, &ConfirmPackageResponse{Status: model.PackageDeliveryConfirmed, Confirmation: confirmation})
+}
+
+// discardProof deletes the images of a proof the workflow did not take. A
+// failure leaves orphaned objects behind, which is only logged.
+func (c *packageConfirmer) discardProof(ctx context.Context, packageId string, proof *model.ProofOfDelivery) {
+	if proof == nil {
+		return
+	}
+
+	if err := discardProof(ctx, c.ObjectStore, proof); err != nil {
+		c.Logger.Warn("Unable to delete proof of delivery", zap.String("packageId", packageId), zap.Error(err))
+	}
+}
@@ -0,0 +1,21 @@
+package packages
+
+import (
+	"context"
+	"errors"
+	"go-test/repository"
+)
+
+// packageVersion returns the version of the persisted package, or 0 while
+// its workflow has not saved it yet.
+func packageVersion(ctx context.Context, store repository.PackageStore, packageId string) (int64, error) {
+	deliveryPackage, err := store.GetPackageDelivery(ctx, packageId)
+	if errors.Is(err, repository.ErrPackageNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return deliveryPackage.Version, nil
+}
@@ -0,0 +1,178 @@
+package packages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go-test/internal/model"
+	"go-test/internal/storage"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+)
+
+const (
+	maxConfirmBodySize = 16 << 20
+	maxProofImageSize  = 5 << 20
+
+	proofItemSignature = "signature"
+	proofItemPhoto     = "photo"
+)
+
+var (
+	errInvalidProof = errors.New("invalid proof of delivery")
+
+	proofImageTypes = map[string]bool{
+		"image/png":  true,
+		"image/jpeg": true,
+		"image/webp": true,
+	}
+)
+
+// bindConfirmPackageRequest reads the optional confirmation body, sent
+// either as JSON with base64 encoded images or as a multipart form with the
+// images as files.
+func bindConfirmPackageRequest(ctx *gin.Context) (*ConfirmPackageRequest, error) {
+	req := &ConfirmPackageRequest{}
+	if ctx.Request.ContentLength == 0 {
+		return req, nil
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxConfirmBodySize)
+
+	if ctx.ContentType() == gin.MIMEMultipartPOSTForm {
+		return req, bindConfirmPackageForm(ctx, req)
+	}
+
+	if err := ctx.ShouldBindJSON(req); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func bindConfirmPackageForm(ctx *gin.Context, req *ConfirmPackageRequest) error {
+	if err := ctx.Request.ParseMultipartForm(maxConfirmBodySize); err != nil {
+		return err
+	}
+
+	req.RecipientName = ctx.PostForm("recipient_name")
+
+	latitude, hasLatitude := ctx.GetPostForm("latitude")
+	longitude, hasLongitude := ctx.GetPostForm("longitude")
+	if hasLatitude || hasLongitude {
+		lat, err := strconv.ParseFloat(latitude, 64)
+		if err != nil {
+			return fmt.Errorf("%w: invalid latitude", errInvalidProof)
+		}
+		lng, err := strconv.ParseFloat(longitude, 64)
+		if err != nil {
+			return fmt.Errorf("%w: invalid longitude", errInvalidProof)
+		}
+		req.Location = &model.GeoLocation{Latitude: lat, Longitude: lng}
+	}
+
+	var err error
+	if req.Signature, err = readFormFile(ctx, proofItemSignature); err != nil {
+		return err
+	}
+	if req.Photo, err = readFormFile(ctx, proofItemPhoto); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func readFormFile(ctx *gin.Context, name string) ([]byte, error) {
+	header, err := ctx.FormFile(name)
+	if errors.Is(err, http.ErrMissingFile) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return readMultipartFile(header)
+}
+
+func readMultipartFile(header *multipart.FileHeader) ([]byte, error) {
+	if header.Size > maxProofImageSize {
+		return nil, fmt.Errorf("%w: %s exceeds %d bytes", errInvalidProof, header.Filename, maxProofImageSize)
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(file)
+}
+
+func (r *ConfirmPackageRequest) hasProof() bool {
+	return r.RecipientName != "" || len(r.Signature) > 0 || len(r.Photo) > 0 || r.Location != nil
+}
+
+// storeProof validates the proof sent with a confirmation and uploads its
+// images, returning the proof referencing the stored objects.
+func storeProof(ctx context.Context, objects storage.ObjectStore, packageId string, req *ConfirmPackageRequest) (*model.ProofOfDelivery, error) {
+	proof := &model.ProofOfDelivery{RecipientName: req.RecipientName, Location: req.Location}
+	if err := proof.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidProof, err)
+	}
+
+	// Every image is checked before any is uploaded, so an invalid photo
+	// does not leave the signature behind.
+	images := map[string][]byte{proofItemSignature: req.Signature, proofItemPhoto: req.Photo}
+	contentTypes := make(map[string]string)
+	for item, data := range images {
+		if len(data) == 0 {
+			continue
+		}
+		if len(data) > maxProofImageSize {
+			return nil, fmt.Errorf("%w: %s exceeds %d bytes", errInvalidProof, item, maxProofImageSize)
+		}
+
+		// The content type is sniffed rather than trusted from the client.
+		contentType := http.DetectContentType(data)
+		if !proofImageTypes[contentType] {
+			return nil, fmt.Errorf("%w: %s has unsupported content type %s", errInvalidProof, item, contentType)
+		}
+		contentTypes[item] = contentType
+	}
+
+	for item, contentType := range contentTypes {
+		data := images[item]
+		key := fmt.Sprintf("packages/%s/proof/%s/%s", packageId, uuid.NewString(), item)
+		if err := objects.Put(ctx, key, contentType, data); err != nil {
+			return nil, errors.Join(err, discardProof(ctx, objects, proof))
+		}
+
+		reference := &model.ObjectReference{Key: key, ContentType: contentType, Size: int64(len(data))}
+		if item == proofItemSignature {
+			proof.Signature = reference
+		} else {
+			proof.Photo = reference
+		}
+	}
+
+	return proof, nil
+}
+
+// discardProof deletes the stored images of a proof that was not accepted.
+func discardProof(ctx context.Context, objects storage.ObjectStore, proof *model.ProofOfDelivery) error {
+	var errs []error
+	for _, reference := range []*model.ObjectReference{proof.Signature, proof.Photo} {
+		if reference == nil {
+			continue
+		}
+		if err := objects.Delete(ctx, reference.Key); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,131 @@
+package packages
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-test/internal/audit"
+	"go-test/internal/auth"
+	"go-test/internal/model"
+	"go-test/internal/workflow"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/client"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type ResolveDisputeRequest struct {
+	Action model.DisputeAction `json:"action" enums:"redeliver,refund,close"`
+	Note   string              `json:"note"`
+}
+
+type ResolveDisputeResponse struct {
+	DisputeID  string                  `json:"dispute_id"`
+	Resolution model.DisputeResolution `json:"resolution"`
+}
+
+type ResolveDisputeController struct {
+	Logger         *zap.Logger
+	TemporalClient client.Client
+	Operators      *auth.Operators
+	Audit          *audit.Log
+}
+
+func RegisterResolveDisputeController(logger *zap.Logger, temporalClient client.Client, operators *auth.Operators, auditLog *audit.Log) *ResolveDisputeController {
+	return &ResolveDisputeController{
+		Logger:         logger,
+		TemporalClient: temporalClient,
+		Operators:      operators,
+		Audit:          auditLog,
+	}
+}
+
+// ResolveDispute godoc
+// @Summary      Resolve a package dispute
+// @Description  Resolve the open dispute of a package. redeliver sends the package again and waits for a new
+// @Description  confirmation, refund and close end the delivery. Requires an operator bearer token.
+// @Tags         packages
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Package ID"
+// @Param        Authorization header string true "Operator bearer token"
+// @Param        body body ResolveDisputeRequest true "Resolution"
+// @Success      202 {object} ResolveDisputeResponse "Resolution accepted"
+// @Failure      400 {object} model.HttpErrorResponse "Invalid input data"
+// @Failure      401 {object} model.HttpErrorResponse "Authentication required"
+// @Failure      404 {object} model.HttpErrorResponse "No open dispute for the package"
+// @Failure      502 {object} model.HttpErrorResponse "Unable to resolve dispute"
+// @Router       /api/v1/packages/{id}/dispute/resolution [post]
+func (c *ResolveDisputeController) ResolveDispute(ctx *gin.Context) {
+	packageId := ctx.Param("id")
+
+	if packageId == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Package ID is required"})
+		return
+	}
+
+	operator, ok := c.Operators.Authenticate(ctx.Request)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Operator authentication is required"})
+		return
+	}
+
+	var req ResolveDisputeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data"})
+		return
+	}
+
+	resolution := model.DisputeResolution{
+		Action:     req.Action,
+		ResolvedBy: operator,
+		Note:       req.Note,
+		ResolvedAt: time.Now().UTC(),
+	}
+	if !resolution.Valid() {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Unknown dispute action: " + string(req.Action)})
+		return
+	}
+
+	state, err := queryWorkflowState(context.Background(), c.TemporalClient, packageId)
+	var notFound *serviceerror.NotFound
+	if errors.As(err, &notFound) || errors.Is(err, errNotPackageWorkflow) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Package not found"})
+		return
+	}
+	if err != nil {
+		c.Logger.Error("Unable to query workflow state", zap.String("packageId", packageId), zap.Error(err))
+		ctx.JSON(http.StatusBadGateway, gin.H{"error": "Failed to query workflow"})
+		return
+	}
+
+	if state.Dispute == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "No open dispute for the package"})
+		return
+	}
+
+	err = c.TemporalClient.SignalWorkflow(
+		context.Background(),
+		state.Dispute.ID,
+		"",
+		workflow.DisputeResolutionSignalResolve,
+		&resolution,
+	)
+	if err != nil {
+		c.Logger.Error("Unable to signal dispute resolution workflow", zap.String("disputeId", state.Dispute.ID), zap.Error(err))
+
+		ctx.JSON(http.StatusBadGateway, gin.H{"error": "Unable to resolve dispute"})
+		return
+	}
+
+	c.Audit.Record(ctx.Request.Context(), model.AuditEntry{
+		PackageID: packageId,
+		Operator:  operator,
+		Action:    model.AuditResolveDispute,
+		Reason:    req.Note,
+		Detail:    string(req.Action),
+	})
+
+	ctx.JSON(http.StatusAccepted, &ResolveDisputeResponse{DisputeID: state.Dispute.ID, Resolution: resolution})
+}
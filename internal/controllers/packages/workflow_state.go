@@ -0,0 +1,133 @@
+package packages
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-test/internal/workflow"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// queryWorkflowState returns the state reported by the package delivery
+// workflow through its state query. It fails with errNotPackageWorkflow for
+// the IDs of other workflows.
+func queryWorkflowState(ctx context.Context, temporalClient client.Client, packageId string) (*workflow.PackageDeliveryWorkflowResult, error) {
+	if err := checkPackageWorkflow(ctx, temporalClient, packageId); err != nil {
+		return nil, err
+	}
+
+	value, err := temporalClient.QueryWorkflow(ctx, packageId, "", workflow.PackageDeliveryStateQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	var result workflow.PackageDeliveryWorkflowResult
+	if err := value.Get(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+var errNotPackageWorkflow = errors.New("not a package delivery workflow")
+
+// workflowType returns the type of the workflow of workflowId, which tells
+// packages and shipments apart.
+func workflowType(ctx context.Context, temporalClient client.Client, workflowId string) (string, error) {
+	description, err := temporalClient.DescribeWorkflowExecution(ctx, workflowId, "")
+	if err != nil {
+		return "", err
+	}
+
+	return description.GetWorkflowExecutionInfo().GetType().GetName(), nil
+}
+
+// checkPackageWorkflow fails with errNotPackageWorkflow when the workflow of
+// packageId is not a package delivery, e.g. a shipment.
+func checkPackageWorkflow(ctx context.Context, temporalClient client.Client, packageId string) error {
+	name, err := workflowType(ctx, temporalClient, packageId)
+	if err != nil {
+		return err
+	}
+
+	if name != workflow.PackageDeliveryWorkflowName {
+		return errNotPackageWorkflow
+	}
+
+	return nil
+}
+
+// requireWorkflow returns the type of the workflow of workflowId if it is
+// one of workflowTypes, or writes the error response and returns "".
+func requireWorkflow(ctx *gin.Context, logger *zap.Logger, temporalClient client.Client, workflowId string, workflowTypes ...string) string {
+	name, err := workflowType(ctx.Request.Context(), temporalClient, workflowId)
+	var notFound *serviceerror.NotFound
+	if errors.As(err, &notFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "No running delivery workflow"})
+		return ""
+	}
+	if err != nil {
+		logger.Error("Unable to describe workflow", zap.String("workflowId", workflowId), zap.Error(err))
+		ctx.JSON(http.StatusBadGateway, gin.H{"error": "Unable to reach the delivery workflow"})
+		return ""
+	}
+
+	for _, workflowType := range workflowTypes {
+		if name == workflowType {
+			return name
+		}
+	}
+
+	ctx.JSON(http.StatusNotFound, gin.H{"error": "No running delivery workflow"})
+	return ""
+}
+
+// requirePackageWorkflow checks that packageId names a package delivery
+// workflow, or writes the error response and returns false.
+func requirePackageWorkflow(ctx *gin.Context, logger *zap.Logger, temporalClient client.Client, packageId string) bool {
+	return requireWorkflow(ctx, logger, temporalClient, packageId, workflow.PackageDeliveryWorkflowName) != ""
+}
+
+// updateWorkflow sends an update to the running workflow of workflowId and
+// waits for the workflow to tell whether it accepted it.
+func updateWorkflow(ctx context.Context, temporalClient client.Client, workflowId string, updateName string, arg interface{}) (*workflow.PackageUpdateResult, error) {
+	handle, err := temporalClient.UpdateWorkflow(ctx, client.UpdateWorkflowOptions{
+		WorkflowID:   workflowId,
+		UpdateName:   updateName,
+		Args:         []interface{}{arg},
+		WaitForStage: client.WorkflowUpdateStageCompleted,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result workflow.PackageUpdateResult
+	if err := handle.Get(ctx, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// respondUpdateError writes the response of an update that failed: the
+// workflow rejected the payload, is not running, or could not be reached.
+func respondUpdateError(ctx *gin.Context, logger *zap.Logger, workflowId string, message string, err error) {
+	var rejected *temporal.ApplicationError
+	if errors.As(err, &rejected) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": rejected.Message()})
+		return
+	}
+
+	var notFound *serviceerror.NotFound
+	if errors.As(err, &notFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "No running delivery workflow"})
+		return
+	}
+
+	logger.Error("Unable to update workflow", zap.String("workflowId", workflowId), zap.Error(err))
+	ctx.JSON(http.StatusBadGateway, gin.H{"error": message})
+}
@@ -5,19 +5,63 @@ import (
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	_ "go-test/docs"
+	"go-test/internal/audit"
+	"go-test/internal/auth"
+	"go-test/internal/calendar"
+	"go-test/internal/circuit"
+	"go-test/internal/controllers/admin"
+	"go-test/internal/controllers/customers"
 	"go-test/internal/controllers/packages"
 	"go-test/internal/events"
+	"go-test/internal/storage"
+	"go-test/internal/workflow"
+	"go-test/repository"
 	"go.temporal.io/sdk/client"
 	"go.uber.org/zap"
 )
 
 const ApiV1Path = "/api/v1"
 const PackagesPath = "/packages"
+const ConfirmPath = "/confirm"
+const ShipmentsPath = "/shipments"
+const AdminPath = "/admin"
+const CustomersPath = "/customers"
 
-func InitializeRoutes(logger *zap.Logger, temporalClient client.Client, r *gin.Engine, ep *events.EventProducer) *gin.Engine {
-	createPackageController := packages.RegisterCreatePackageController(logger, temporalClient, ep)
-	getPackageController := packages.RegisterGetPackageController(logger, temporalClient)
-	confirmPackageController := packages.RegisterConfirmPackageController(logger, temporalClient)
+func InitializeRoutes(
+	logger *zap.Logger,
+	temporalClient client.Client,
+	r *gin.Engine,
+	ep *events.EventProducer,
+	store repository.Store,
+	objectStore storage.ObjectStore,
+	operators *auth.Operators,
+	links *auth.ConfirmationLinks,
+	namespace string,
+	deliveryCalendar *calendar.Calendar,
+	webhooks *circuit.Destinations,
+	emailHasher *workflow.CustomerEmailHasher,
+) *gin.Engine {
+	auditLog := audit.NewLog(store, logger)
+
+	createPackageController := packages.RegisterCreatePackageController(logger, temporalClient, ep, deliveryCalendar)
+	getPackageController := packages.RegisterGetPackageController(logger, temporalClient, store)
+	confirmPackageController := packages.RegisterConfirmPackageController(logger, temporalClient, objectStore, store, operators, links, auditLog)
+	confirmLinkController := packages.RegisterConfirmLinkController(logger, temporalClient, objectStore, links)
+	getProofController := packages.RegisterGetProofController(logger, temporalClient, store, objectStore, operators, links)
+	disputePackageController := packages.RegisterDisputePackageController(logger, temporalClient, objectStore, operators, links, auditLog)
+	resolveDisputeController := packages.RegisterResolveDisputeController(logger, temporalClient, operators, auditLog)
+	deliveryAttemptsController := packages.RegisterDeliveryAttemptsController(logger, temporalClient, store, operators, auditLog)
+	createShipmentController := packages.RegisterCreateShipmentController(logger, temporalClient)
+	getShipmentController := packages.RegisterGetShipmentController(logger, temporalClient, store)
+	confirmShipmentController := packages.RegisterConfirmShipmentController(logger, temporalClient, objectStore, operators, links, auditLog)
+	listWorkflowsController := admin.RegisterListWorkflowsController(logger, temporalClient, operators, emailHasher)
+	remediatePackageController := admin.RegisterRemediatePackageController(logger, temporalClient, namespace, store, operators, auditLog)
+	listAuditEntriesController := admin.RegisterListAuditEntriesController(logger, operators, auditLog)
+	deliveryReportScheduleController := admin.RegisterDeliveryReportScheduleController(logger, temporalClient, operators, auditLog)
+	notificationTemplatesController := admin.RegisterNotificationTemplatesController(logger, store, operators, auditLog)
+	webhookBreakersController := admin.RegisterWebhookBreakersController(logger, webhooks, operators)
+	webhookSubscriptionsController := admin.RegisterWebhookSubscriptionsController(logger, store, operators, auditLog)
+	customerPreferencesController := customers.RegisterCustomerPreferencesController(logger, store, operators, links, auditLog)
 
 	apiV1Group := r.Group(ApiV1Path)
 
@@ -25,6 +69,44 @@ func InitializeRoutes(logger *zap.Logger, temporalClient client.Client, r *gin.E
 	packagesGroup.POST("/", createPackageController.CreatePackage)
 	packagesGroup.GET("/:id", getPackageController.GetPackage)
 	packagesGroup.POST("/:id/confirm", confirmPackageController.ConfirmPackage)
+	packagesGroup.GET("/:id/proof", getProofController.GetProof)
+	packagesGroup.GET("/:id/proof/:item", getProofController.GetProofImage)
+	packagesGroup.POST("/:id/dispute", disputePackageController.DisputePackage)
+	packagesGroup.POST("/:id/dispute/resolution", resolveDisputeController.ResolveDispute)
+	packagesGroup.POST("/:id/attempts", deliveryAttemptsController.RecordAttempt)
+	packagesGroup.GET("/:id/attempts", deliveryAttemptsController.ListAttempts)
+
+	shipmentsGroup := apiV1Group.Group(ShipmentsPath)
+	shipmentsGroup.POST("/", createShipmentController.CreateShipment)
+	shipmentsGroup.GET("/:id", getShipmentController.GetShipment)
+	shipmentsGroup.POST("/:id/confirm", confirmShipmentController.ConfirmShipment)
+
+	customersGroup := apiV1Group.Group(CustomersPath)
+	customersGroup.POST("/:email/preferences", customerPreferencesController.CreateCustomerPreferences)
+	customersGroup.GET("/:email/preferences", customerPreferencesController.GetCustomerPreferences)
+	customersGroup.PUT("/:email/preferences", customerPreferencesController.UpdateCustomerPreferences)
+	customersGroup.DELETE("/:email/preferences", customerPreferencesController.DeleteCustomerPreferences)
+
+	adminGroup := apiV1Group.Group(AdminPath)
+	adminGroup.GET("/workflows", listWorkflowsController.ListWorkflows)
+	adminGroup.POST("/packages/:id/retry", remediatePackageController.RetryPackage)
+	adminGroup.POST("/packages/:id/transition", remediatePackageController.TransitionPackage)
+	adminGroup.POST("/packages/:id/terminate", remediatePackageController.TerminatePackage)
+	adminGroup.GET("/packages/:id/audit", listAuditEntriesController.ListAuditEntries)
+	adminGroup.GET("/audit", listAuditEntriesController.ListSubjectAuditEntries)
+	adminGroup.GET("/reports/delivery/schedule", deliveryReportScheduleController.GetDeliveryReportSchedule)
+	adminGroup.PUT("/reports/delivery/schedule", deliveryReportScheduleController.UpdateDeliveryReportSchedule)
+	adminGroup.GET("/templates", notificationTemplatesController.ListNotificationTemplates)
+	adminGroup.POST("/templates/preview", notificationTemplatesController.PreviewNotificationTemplate)
+	adminGroup.PUT("/templates/:event/:channel/:locale", notificationTemplatesController.SaveNotificationTemplate)
+	adminGroup.DELETE("/templates/:event/:channel/:locale", notificationTemplatesController.DeleteNotificationTemplate)
+	adminGroup.GET("/webhooks/breakers", webhookBreakersController.ListWebhookBreakers)
+	adminGroup.GET("/webhooks/subscriptions/disabled", webhookSubscriptionsController.ListDisabledWebhookSubscriptions)
+	adminGroup.POST("/webhooks/subscriptions/enable", webhookSubscriptionsController.EnableWebhookSubscription)
+
+	confirmGroup := apiV1Group.Group(ConfirmPath)
+	confirmGroup.GET("/:token", confirmLinkController.ConfirmLinkPage)
+	confirmGroup.POST("/:token", confirmLinkController.ConfirmLink)
 
 	apiV1Group.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
@@ -8,7 +8,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"go-test/internal/handlers"
-	"go-test/internal/model"
+	"go-test/internal/workflow"
 	"go.temporal.io/sdk/client"
 	"go.uber.org/zap"
 	"log"
@@ -90,7 +90,7 @@ func (ec *EventConsumer) StartConsuming() {
 			for _, message := range result.Messages {
 				ec.logger.Info("Received message", zap.String("message", *message.Body))
 
-				var event model.DeliveryPackage
+				var event workflow.PackageDeliveryRequest
 				err := json.Unmarshal([]byte(*message.Body), &event)
 				if err != nil {
 					ec.logger.Error("Failed to unmarshal message", zap.Error(err))
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 	"go-test/internal/model"
 	"go-test/internal/workflow"
 	"go.temporal.io/sdk/client"
@@ -9,7 +10,8 @@ import (
 )
 
 const (
-	PackageDeliveryQueueName = "package-delivery-queue"
+	PackageDeliveryQueueName     = "package-delivery-queue"
+	PackageCompensationQueueName = "package-compensation-queue"
 )
 
 type DeliveryEventConsumer struct {
@@ -27,7 +29,15 @@ func NewDeliveryEventConsumer(logger *zap.Logger, temporalClient client.Client,
 	}
 }
 
-func (d *DeliveryEventConsumer) Handle(ctx context.Context, deliveryPackage *model.DeliveryPackage) error {
+func (d *DeliveryEventConsumer) Handle(ctx context.Context, request *workflow.PackageDeliveryRequest) error {
+	deliveryPackage := request.DeliveryPackage
+	if deliveryPackage == nil {
+		return fmt.Errorf("delivery event without a package")
+	}
+	if err := workflow.ValidatePackageActivityPolicies(request.ActivityPolicies); err != nil {
+		return fmt.Errorf("delivery event for package %s: %w", deliveryPackage.ID, err)
+	}
+
 	wo := client.StartWorkflowOptions{
 		ID:        deliveryPackage.ID,
 		TaskQueue: d.PackageDeliveryTaskQueueName,
@@ -35,9 +45,13 @@ func (d *DeliveryEventConsumer) Handle(ctx context.Context, deliveryPackage *mod
 
 	workflowInput := workflow.PackageDeliveryWorkflowParams{
 		DeliveryPackage: &model.DeliveryPackage{
+			ID:              deliveryPackage.ID,
 			CustomerEmail:   deliveryPackage.CustomerEmail,
 			DeliveryAddress: deliveryPackage.DeliveryAddress,
+			Region:          deliveryPackage.Region,
+			DeliveryWindow:  deliveryPackage.DeliveryWindow,
 		},
+		ActivityPolicies: request.ActivityPolicies,
 	}
 
 	_, err := d.TemporalClient.ExecuteWorkflow(context.Background(), wo, workflow.PackageDeliveryWorkflowName, workflowInput)
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/mock"
+	"go-test/internal/activities"
+	"go-test/internal/config"
+	"go-test/internal/workflow"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/mocks"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func decodeDeliveryEvent(t *testing.T, message string) *workflow.PackageDeliveryRequest {
+	t.Helper()
+
+	var request workflow.PackageDeliveryRequest
+	if err := json.Unmarshal([]byte(message), &request); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	return &request
+}
+
+func TestDeliveryEventPassesActivityPoliciesToWorkflow(t *testing.T) {
+	request := decodeDeliveryEvent(t, `{
+		"id": "PKG-1",
+		"customer_email": "customer@example.com",
+		"delivery_address": "1 Main Street",
+		"activity_policies": {"notify-delivery-activity": {"maximum_attempts": 5, "start_to_close_timeout": "30s"}}
+	}`)
+
+	var params workflow.PackageDeliveryWorkflowParams
+	temporalClient := &mocks.Client{}
+	temporalClient.On("ExecuteWorkflow", mock.Anything, mock.Anything, workflow.PackageDeliveryWorkflowName, mock.Anything).
+		Run(func(args mock.Arguments) {
+			if options := args.Get(1).(client.StartWorkflowOptions); options.ID != "PKG-1" {
+				t.Errorf("workflow ID = %s, want PKG-1", options.ID)
+			}
+			params = args.Get(3).(workflow.PackageDeliveryWorkflowParams)
+		}).
+		Return(&mocks.WorkflowRun{}, nil)
+
+	consumer := NewDeliveryEventConsumer(zap.NewNop(), temporalClient, workflow.PackageDeliveryTaskQueueName)
+	if err := consumer.Handle(context.Background(), request); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+
+	if params.DeliveryPackage == nil || params.DeliveryPackage.ID != "PKG-1" {
+		t.Fatalf("workflow package = %+v, want PKG-1", params.DeliveryPackage)
+	}
+	want := config.ActivityPolicy{MaximumAttempts: 5, StartToCloseTimeout: config.Duration(30 * time.Second)}
+	if got := params.ActivityPolicies[activities.NotifyDeliveryActivityName]; got.MaximumAttempts != want.MaximumAttempts || got.StartToCloseTimeout != want.StartToCloseTimeout {
+		t.Fatalf("workflow activity policy = %+v, want %+v", got, want)
+	}
+}
+
+func TestDeliveryEventRejectsInvalidActivityPolicies(t *testing.T) {
+	for name, message := range map[string]string{
+		"unknown activity":  `{"id": "PKG-1", "activity_policies": {"send-spam-activity": {"maximum_attempts": 1}}}`,
+		"negative attempts": `{"id": "PKG-1", "activity_policies": {"save-delivery-activity": {"maximum_attempts": -1}}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			temporalClient := &mocks.Client{}
+			consumer := NewDeliveryEventConsumer(zap.NewNop(), temporalClient, workflow.PackageDeliveryTaskQueueName)
+
+			if err := consumer.Handle(context.Background(), decodeDeliveryEvent(t, message)); err == nil {
+				t.Fatal("Handle accepted invalid activity policies")
+			}
+			temporalClient.AssertNotCalled(t, "ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
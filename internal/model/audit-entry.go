@@ -0,0 +1,66 @@
+package model
+
+import "time"
+
+type AuditAction string
+
+const (
+	AuditConfirm           AuditAction = "confirm"
+	AuditDispute           AuditAction = "dispute"
+	AuditResolveDispute    AuditAction = "resolveDispute"
+	AuditRecordAttempt     AuditAction = "recordAttempt"
+	AuditRetry             AuditAction = "retry"
+	AuditForceTransition   AuditAction = "forceTransition"
+	AuditTerminate         AuditAction = "terminate"
+	AuditSaveTemplate      AuditAction = "saveTemplate"
+	AuditDeleteTemplate    AuditAction = "deleteTemplate"
+	AuditUpdateSchedule    AuditAction = "updateSchedule"
+	AuditEnableWebhook     AuditAction = "enableWebhook"
+	AuditCreatePreferences AuditAction = "createPreferences"
+	AuditUpdatePreferences AuditAction = "updatePreferences"
+	AuditDeletePreferences AuditAction = "deletePreferences"
+)
+
+// AuditSubjectType is the kind of thing an audit entry is about.
+type AuditSubjectType string
+
+const (
+	AuditSubjectPackage                AuditSubjectType = "package"
+	AuditSubjectNotificationTemplate   AuditSubjectType = "notificationTemplate"
+	AuditSubjectDeliveryReportSchedule AuditSubjectType = "deliveryReportSchedule"
+	AuditSubjectWebhookSubscription    AuditSubjectType = "webhookSubscription"
+	AuditSubjectCustomerPreferences    AuditSubjectType = "customerPreferences"
+)
+
+func (t AuditSubjectType) Valid() bool {
+	switch t {
+	case AuditSubjectPackage,
+		AuditSubjectNotificationTemplate,
+		AuditSubjectDeliveryReportSchedule,
+		AuditSubjectWebhookSubscription,
+		AuditSubjectCustomerPreferences:
+		return true
+	}
+	return false
+}
+
+// AuditEntry records an action an operator took on a subject, such as a
+// package or a notification template. PackageID is set for entries about
+// packages only.
+type AuditEntry struct {
+	ID          int64            `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	SubjectType AuditSubjectType `gorm:"column:subject_type" json:"subject_type"`
+	SubjectID   string           `gorm:"column:subject_id" json:"subject_id"`
+	PackageID   string           `gorm:"column:package_id" json:"package_id,omitempty"`
+	Operator    string           `gorm:"column:operator" json:"operator"`
+	Action      AuditAction      `gorm:"column:action" json:"action"`
+	Reason      string           `gorm:"column:reason" json:"reason,omitempty"`
+	// Detail describes what the action did, such as the status a package
+	// was forced into.
+	Detail    string    `gorm:"column:detail" json:"detail,omitempty"`
+	CreatedAt time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+func (AuditEntry) TableName() string {
+	return "audit_log"
+}
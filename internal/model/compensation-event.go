@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+type CompensationEvent struct {
+	PackageID  string               `json:"package_id"`
+	FailedStep string               `json:"failed_step"`
+	Action     string               `json:"action"`
+	Status     PackageDeliveryState `json:"status"`
+	Reason     string               `json:"reason"`
+	OccurredAt time.Time            `json:"occurred_at"`
+}
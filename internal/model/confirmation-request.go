@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// ConfirmationRequest is sent to the customer before delivery, with the link
+// that confirms it.
+type ConfirmationRequest struct {
+	DeliveryPackage *DeliveryPackage      `json:"package"`
+	ConfirmationURL string                `json:"confirmation_url"`
+	ExpiresAt       time.Time             `json:"expires_at"`
+	Messages        []NotificationMessage `json:"messages,omitempty"`
+}
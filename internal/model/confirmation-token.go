@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// ConfirmationToken records a confirmation link sent to a customer, so that
+// every link can be used only once.
+type ConfirmationToken struct {
+	ID            string     `gorm:"column:id;primaryKey" json:"id"`
+	PackageID     string     `gorm:"column:package_id" json:"package_id"`
+	CustomerEmail string     `gorm:"column:customer_email" json:"customer_email"`
+	ExpiresAt     time.Time  `gorm:"column:expires_at" json:"expires_at"`
+	ConsumedAt    *time.Time `gorm:"column:consumed_at" json:"consumed_at,omitempty"`
+	CreatedAt     time.Time  `gorm:"column:created_at" json:"created_at"`
+}
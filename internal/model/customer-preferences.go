@@ -0,0 +1,259 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+type NotificationChannel string
+
+const (
+	NotificationChannelEmail NotificationChannel = "email"
+	NotificationChannelSMS   NotificationChannel = "sms"
+	NotificationChannelPush  NotificationChannel = "push"
+)
+
+func (c NotificationChannel) Valid() bool {
+	switch c {
+	case NotificationChannelEmail, NotificationChannelSMS, NotificationChannelPush:
+		return true
+	default:
+		return false
+	}
+}
+
+// NotificationChannels is stored as a jsonb column.
+type NotificationChannels []NotificationChannel
+
+func (c NotificationChannels) Value() (driver.Value, error) {
+	if c == nil {
+		return "[]", nil
+	}
+
+	return json.Marshal(c)
+}
+
+func (c *NotificationChannels) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case []byte:
+		return json.Unmarshal(value, c)
+	case string:
+		return json.Unmarshal([]byte(value), c)
+	default:
+		return fmt.Errorf("unable to scan %T into NotificationChannels", src)
+	}
+}
+
+// NotificationKind is a message sent to the customer about their package.
+type NotificationKind string
+
+const (
+	NotificationConfirmationRequest NotificationKind = "confirmation_request"
+	NotificationDelivered           NotificationKind = "delivered"
+	NotificationArrival             NotificationKind = "arrival"
+	NotificationFailedAttempt       NotificationKind = "failed_attempt"
+	NotificationReturnedToSender    NotificationKind = "returned_to_sender"
+	NotificationShipmentDelivered   NotificationKind = "shipment_delivered"
+)
+
+func (k NotificationKind) Valid() bool {
+	switch k {
+	case NotificationConfirmationRequest, NotificationDelivered, NotificationArrival,
+		NotificationFailedAttempt, NotificationReturnedToSender, NotificationShipmentDelivered:
+		return true
+	default:
+		return false
+	}
+}
+
+// LegallyRequired reports whether the customer must be sent the message
+// regardless of their opt-outs. The return of a package to its sender ends
+// the delivery contract, which the customer has to be told about.
+func (k NotificationKind) LegallyRequired() bool {
+	return k == NotificationReturnedToSender
+}
+
+const (
+	DefaultNotificationLanguage = "en"
+	DefaultNotificationTimeZone = "UTC"
+)
+
+var languagePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z]{2})?$`)
+
+// ValidLanguage reports whether language is a language tag such as "en" or
+// "de-AT".
+func ValidLanguage(language string) bool {
+	return languagePattern.MatchString(language)
+}
+
+// CustomerPreferences are the notification preferences of a customer, keyed
+// by email. Customers without preferences are notified by email in English
+// at any time of the day.
+type CustomerPreferences struct {
+	Email    string               `gorm:"column:email;primaryKey" json:"email"`
+	Channels NotificationChannels `gorm:"column:channels" json:"channels"`
+	// Language is a language tag such as "en" or "de-AT".
+	Language string `gorm:"column:language" json:"language"`
+	// TimeZone is the IANA time zone the quiet hours are in.
+	TimeZone string `gorm:"column:time_zone" json:"time_zone"`
+	// QuietHoursStart and QuietHoursEnd are HH:MM in TimeZone, both empty
+	// when the customer has no quiet hours. Quiet hours may span midnight.
+	QuietHoursStart string `gorm:"column:quiet_hours_start" json:"quiet_hours_start,omitempty"`
+	QuietHoursEnd   string `gorm:"column:quiet_hours_end" json:"quiet_hours_end,omitempty"`
+	// OptOutAll opts out of every message that is not legally required.
+	OptOutAll           bool      `gorm:"column:opt_out_all" json:"opt_out_all"`
+	OptOutDelivered     bool      `gorm:"column:opt_out_delivered" json:"opt_out_delivered"`
+	OptOutArrival       bool      `gorm:"column:opt_out_arrival" json:"opt_out_arrival"`
+	OptOutFailedAttempt bool      `gorm:"column:opt_out_failed_attempt" json:"opt_out_failed_attempt"`
+	UpdatedAt           time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (CustomerPreferences) TableName() string {
+	return "customers"
+}
+
+// DefaultCustomerPreferences are used for customers who have not set any.
+func DefaultCustomerPreferences(email string) *CustomerPreferences {
+	return &CustomerPreferences{
+		Email:    email,
+		Channels: NotificationChannels{NotificationChannelEmail},
+		Language: DefaultNotificationLanguage,
+		TimeZone: DefaultNotificationTimeZone,
+	}
+}
+
+// Validate checks the preferences and fills in the defaults of the
+// channels, language and time zone.
+func (p *CustomerPreferences) Validate() error {
+	if len(p.Channels) == 0 {
+		p.Channels = NotificationChannels{NotificationChannelEmail}
+	}
+	for _, channel := range p.Channels {
+		if !channel.Valid() {
+			return fmt.Errorf("unknown notification channel %q", channel)
+		}
+	}
+
+	if p.Language == "" {
+		p.Language = DefaultNotificationLanguage
+	}
+	if !ValidLanguage(p.Language) {
+		return fmt.Errorf("language %q is not a language tag such as en or de-AT", p.Language)
+	}
+
+	if p.TimeZone == "" {
+		p.TimeZone = DefaultNotificationTimeZone
+	}
+	if _, err := time.LoadLocation(p.TimeZone); err != nil {
+		return fmt.Errorf("unknown time zone %q", p.TimeZone)
+	}
+
+	if (p.QuietHoursStart == "") != (p.QuietHoursEnd == "") {
+		return errors.New("quiet hours need both a start and an end")
+	}
+	if p.QuietHoursStart != "" {
+		start, err := time.Parse("15:04", p.QuietHoursStart)
+		if err != nil {
+			return fmt.Errorf("quiet hours start %q is not HH:MM", p.QuietHoursStart)
+		}
+		end, err := time.Parse("15:04", p.QuietHoursEnd)
+		if err != nil {
+			return fmt.Errorf("quiet hours end %q is not HH:MM", p.QuietHoursEnd)
+		}
+		if start.Equal(end) {
+			return errors.New("quiet hours must not start and end at the same time")
+		}
+	}
+
+	return nil
+}
+
+// Allows reports whether the customer wants to receive messages of kind.
+func (p *CustomerPreferences) Allows(kind NotificationKind) bool {
+	if kind.LegallyRequired() {
+		return true
+	}
+	if p.OptOutAll {
+		return false
+	}
+
+	switch kind {
+	case NotificationDelivered:
+		return !p.OptOutDelivered
+	case NotificationArrival:
+		return !p.OptOutArrival
+	case NotificationFailedAttempt:
+		return !p.OptOutFailedAttempt
+	default:
+		return true
+	}
+}
+
+// QuietUntil returns the end of the quiet hours now falls in, and false when
+// now is outside of them.
+func (p *CustomerPreferences) QuietUntil(now time.Time) (time.Time, bool) {
+	if p.QuietHoursStart == "" || p.QuietHoursEnd == "" {
+		return time.Time{}, false
+	}
+
+	location, err := time.LoadLocation(p.TimeZone)
+	if err != nil {
+		return time.Time{}, false
+	}
+	start, err := time.Parse("15:04", p.QuietHoursStart)
+	if err != nil {
+		return time.Time{}, false
+	}
+	end, err := time.Parse("15:04", p.QuietHoursEnd)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	local := now.In(location)
+	at := func(clock time.Time, days int) time.Time {
+		return time.Date(local.Year(), local.Month(), local.Day()+days, clock.Hour(), clock.Minute(), 0, 0, location)
+	}
+
+	startsAt, endsAt := at(start, 0), at(end, 0)
+	if endsAt.After(startsAt) {
+		if !local.Before(startsAt) && local.Before(endsAt) {
+			return endsAt, true
+		}
+		return time.Time{}, false
+	}
+
+	// The quiet hours span midnight: they end today when now is early in
+	// the morning, and tomorrow when now is late in the evening.
+	if local.Before(endsAt) {
+		return endsAt, true
+	}
+	if !local.Before(startsAt) {
+		return at(end, 1), true
+	}
+
+	return time.Time{}, false
+}
+
+// NotificationRouting tells the notification service how to reach the
+// customer.
+type NotificationRouting struct {
+	Channels NotificationChannels `json:"channels,omitempty"`
+	Language string               `json:"language,omitempty"`
+}
+
+// Routing returns the channels and language of the preferences.
+func (p *CustomerPreferences) Routing() NotificationRouting {
+	return NotificationRouting{Channels: p.Channels, Language: p.Language}
+}
+
+// DeliveryNotification tells the customer that their package was
+// delivered. The package fields stay at the top level of the message.
+type DeliveryNotification struct {
+	DeliveryPackage
+	NotificationRouting
+	Messages []NotificationMessage `json:"messages,omitempty"`
+}
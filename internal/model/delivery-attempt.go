@@ -0,0 +1,80 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+type AttemptOutcome string
+
+const (
+	AttemptDelivered      AttemptOutcome = "delivered"
+	AttemptNobodyHome     AttemptOutcome = "nobodyHome"
+	AttemptRefused        AttemptOutcome = "refused"
+	AttemptAddressInvalid AttemptOutcome = "addressInvalid"
+)
+
+const maxAttemptNoteLength = 2000
+
+// DeliveryAttempt is the payload of the delivery attempt signal, sent by the
+// driver after each attempt.
+type DeliveryAttempt struct {
+	// Number is assigned by the workflow, starting at 1.
+	Number      int            `json:"number,omitempty"`
+	Outcome     AttemptOutcome `json:"outcome"`
+	Driver      string         `json:"driver"`
+	Note        string         `json:"note,omitempty"`
+	AttemptedAt time.Time      `json:"attempted_at"`
+}
+
+func (a *DeliveryAttempt) Validate() error {
+	switch a.Outcome {
+	case AttemptDelivered, AttemptNobodyHome, AttemptRefused, AttemptAddressInvalid:
+	default:
+		return errors.New("unknown attempt outcome: " + string(a.Outcome))
+	}
+
+	if a.Driver == "" {
+		return errors.New("driver is required")
+	}
+
+	if a.AttemptedAt.IsZero() {
+		return errors.New("attempted_at is required")
+	}
+
+	if len(a.Note) > maxAttemptNoteLength {
+		return errors.New("note is too long")
+	}
+
+	return nil
+}
+
+func (a *DeliveryAttempt) Failed() bool {
+	return a.Outcome != AttemptDelivered
+}
+
+// PackageDeliveryAttempt is the persisted record of a delivery attempt.
+type PackageDeliveryAttempt struct {
+	PackageID   string         `gorm:"column:package_id;primaryKey" json:"package_id"`
+	Number      int            `gorm:"column:number;primaryKey" json:"number"`
+	Outcome     AttemptOutcome `gorm:"column:outcome" json:"outcome"`
+	Driver      string         `gorm:"column:driver" json:"driver"`
+	Note        string         `gorm:"column:note" json:"note,omitempty"`
+	AttemptedAt time.Time      `gorm:"column:attempted_at" json:"attempted_at"`
+}
+
+func (PackageDeliveryAttempt) TableName() string {
+	return "delivery_attempts"
+}
+
+// FailedAttemptNotification tells the customer about a failed attempt and
+// what happens next.
+type FailedAttemptNotification struct {
+	DeliveryPackage *DeliveryPackage `json:"package"`
+	Attempt         DeliveryAttempt  `json:"attempt"`
+	// NextAttemptAt is unset when the package goes back to the sender.
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	ReturnToSender bool       `json:"return_to_sender"`
+	NotificationRouting
+	Messages []NotificationMessage `json:"messages,omitempty"`
+}
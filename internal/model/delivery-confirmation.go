@@ -0,0 +1,52 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+type ConfirmationChannel string
+
+const (
+	// ConfirmationChannelAPI is an operator confirming through the API.
+	ConfirmationChannelAPI ConfirmationChannel = "api"
+	// ConfirmationChannelLink is the customer following a confirmation link.
+	ConfirmationChannelLink ConfirmationChannel = "link"
+	// ConfirmationChannelDriver is a driver reporting a delivered attempt.
+	ConfirmationChannelDriver ConfirmationChannel = "driver"
+)
+
+var knownConfirmationChannels = map[ConfirmationChannel]bool{
+	ConfirmationChannelAPI:    true,
+	ConfirmationChannelLink:   true,
+	ConfirmationChannelDriver: true,
+}
+
+// DeliveryConfirmation is the payload of the confirm signal: who confirmed
+// the delivery, when and through which channel.
+type DeliveryConfirmation struct {
+	ConfirmedBy string              `json:"confirmed_by"`
+	ConfirmedAt time.Time           `json:"confirmed_at"`
+	Channel     ConfirmationChannel `json:"channel"`
+	Proof       *ProofOfDelivery    `json:"proof,omitempty"`
+}
+
+func (c *DeliveryConfirmation) Validate() error {
+	if c.ConfirmedBy == "" {
+		return errors.New("confirmed_by is required")
+	}
+
+	if c.ConfirmedAt.IsZero() {
+		return errors.New("confirmed_at is required")
+	}
+
+	if !knownConfirmationChannels[c.Channel] {
+		return errors.New("unknown confirmation channel: " + string(c.Channel))
+	}
+
+	if c.Proof != nil {
+		return c.Proof.Validate()
+	}
+
+	return nil
+}
@@ -1,7 +1,24 @@
 package model
 
 type DeliveryPackage struct {
-	ID              string `gorm:"primary_key" json:"id"`
-	CustomerEmail   string `gorm:"column:delivery_address" json:"customer_email"`
-	DeliveryAddress string `gorm:"column:customer_email" json:"delivery_address"`
+	ID              string               `gorm:"primary_key" json:"id"`
+	CustomerEmail   string               `gorm:"column:customer_email" json:"customer_email"`
+	DeliveryAddress string               `gorm:"column:delivery_address" json:"delivery_address"`
+	Region          string               `gorm:"column:region" json:"region,omitempty"`
+	Status          PackageDeliveryState `gorm:"column:status" json:"status,omitempty"`
+	Version         int64                `gorm:"column:version;not null;default:1" json:"version"`
+	Proof           *ProofOfDelivery     `gorm:"column:proof" json:"proof,omitempty"`
+	// DeliveryWindow is carried by the delivery workflow and not stored.
+	DeliveryWindow *DeliveryWindow `gorm:"-" json:"delivery_window,omitempty"`
+}
+
+// SamePayload reports whether both packages carry the same delivery details
+// and proof of delivery, ignoring bookkeeping fields such as the status and
+// version.
+func (p *DeliveryPackage) SamePayload(other *DeliveryPackage) bool {
+	return p.ID == other.ID &&
+		p.CustomerEmail == other.CustomerEmail &&
+		p.DeliveryAddress == other.DeliveryAddress &&
+		p.Region == other.Region &&
+		p.Proof.Equal(other.Proof)
 }
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// DeliverySummary aggregates the packages of a period, from included to To
+// excluded.
+type DeliverySummary struct {
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	Created   int64     `json:"created"`
+	Confirmed int64     `json:"confirmed"`
+	// Expired counts the packages whose confirmation link expired unused
+	// before the package was confirmed.
+	Expired int64 `json:"expired"`
+	Errored int64 `json:"errored"`
+	// MedianTimeToConfirm is measured from creation over the packages
+	// confirmed in the period, and unset when none were.
+	MedianTimeToConfirm *time.Duration `json:"median_time_to_confirm,omitempty"`
+}
+
+// DeliveryReport is the rendered summary sent to its recipients.
+type DeliveryReport struct {
+	Summary    DeliverySummary `json:"summary"`
+	Recipients []string        `json:"recipients"`
+	HTML       string          `json:"html"`
+	CSV        string          `json:"csv"`
+}
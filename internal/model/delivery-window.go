@@ -0,0 +1,38 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// DeliveryWindow is the time range a customer chose to receive a package in.
+type DeliveryWindow struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// TimeZone is the IANA time zone the customer chose the window in, used
+	// to tell them about it in their local time.
+	TimeZone string `json:"time_zone"`
+}
+
+func (w *DeliveryWindow) Validate() error {
+	if w.Start.IsZero() || w.End.IsZero() {
+		return errors.New("delivery window start and end are required")
+	}
+	if !w.End.After(w.Start) {
+		return errors.New("delivery window must end after it starts")
+	}
+	if _, err := time.LoadLocation(w.TimeZone); err != nil {
+		return errors.New("delivery window time zone is unknown")
+	}
+
+	return nil
+}
+
+// ArrivalNotification tells the customer that the package arrives within
+// the delivery window they chose.
+type ArrivalNotification struct {
+	DeliveryPackage *DeliveryPackage `json:"package"`
+	DeliveryWindow  DeliveryWindow   `json:"delivery_window"`
+	NotificationRouting
+	Messages []NotificationMessage `json:"messages,omitempty"`
+}
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+type DisputeCategory string
+
+const (
+	DisputeNotReceived DisputeCategory = "notReceived"
+	DisputeDamaged     DisputeCategory = "damaged"
+	DisputeOther       DisputeCategory = "other"
+)
+
+type DisputeAction string
+
+const (
+	// DisputeRedeliver sends the package again and waits for a new confirmation.
+	DisputeRedeliver DisputeAction = "redeliver"
+	DisputeRefund    DisputeAction = "refund"
+	// DisputeClose rejects the dispute without further action.
+	DisputeClose DisputeAction = "close"
+)
+
+const maxDisputeReasonLength = 2000
+
+// Dispute is raised instead of a confirmation when the customer did not
+// receive the package as expected.
+type Dispute struct {
+	// ID is assigned by the workflow when the dispute is accepted.
+	ID       string              `json:"id,omitempty"`
+	Category DisputeCategory     `json:"category"`
+	Reason   string              `json:"reason"`
+	RaisedBy string              `json:"raised_by"`
+	RaisedAt time.Time           `json:"raised_at"`
+	Channel  ConfirmationChannel `json:"channel"`
+}
+
+func (d *Dispute) Validate() error {
+	switch d.Category {
+	case DisputeNotReceived, DisputeDamaged, DisputeOther:
+	default:
+		return errors.New("unknown dispute category: " + string(d.Category))
+	}
+
+	if d.Category == DisputeOther && d.Reason == "" {
+		return errors.New("reason is required for disputes of category other")
+	}
+
+	if len(d.Reason) > maxDisputeReasonLength {
+		return errors.New("reason is too long")
+	}
+
+	if d.RaisedBy == "" {
+		return errors.New("raised_by is required")
+	}
+
+	if d.RaisedAt.IsZero() {
+		return errors.New("raised_at is required")
+	}
+
+	return nil
+}
+
+// DisputeResolution is sent by an operator to settle a dispute.
+type DisputeResolution struct {
+	Action     DisputeAction `json:"action"`
+	ResolvedBy string        `json:"resolved_by"`
+	Note       string        `json:"note"`
+	ResolvedAt time.Time     `json:"resolved_at"`
+}
+
+func (r *DisputeResolution) Valid() bool {
+	switch r.Action {
+	case DisputeRedeliver, DisputeRefund, DisputeClose:
+		return r.ResolvedBy != ""
+	default:
+		return false
+	}
+}
+
+type DisputeOutcome struct {
+	Dispute    Dispute           `json:"dispute"`
+	Resolution DisputeResolution `json:"resolution"`
+}
+
+// PackageDispute is the persisted record of a dispute and its resolution.
+type PackageDispute struct {
+	ID         string          `gorm:"column:id;primaryKey" json:"id"`
+	PackageID  string          `gorm:"column:package_id" json:"package_id"`
+	Category   DisputeCategory `gorm:"column:category" json:"category"`
+	Reason     string          `gorm:"column:reason" json:"reason"`
+	RaisedBy   string          `gorm:"column:raised_by" json:"raised_by"`
+	RaisedAt   time.Time       `gorm:"column:raised_at" json:"raised_at"`
+	Action     DisputeAction   `gorm:"column:action" json:"action,omitempty"`
+	ResolvedBy string          `gorm:"column:resolved_by" json:"resolved_by,omitempty"`
+	Note       string          `gorm:"column:note" json:"note,omitempty"`
+	ResolvedAt *time.Time      `gorm:"column:resolved_at" json:"resolved_at,omitempty"`
+}
+
+// SupportNotification asks support to look into a dispute.
+type SupportNotification struct {
+	DeliveryPackage *DeliveryPackage `json:"package"`
+	Dispute         Dispute          `json:"dispute"`
+}
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// NotificationTemplate is the content of a notification of one event, on
+// one channel and in one locale, before it is rendered. Templates stored in
+// the database take precedence over the ones embedded in the service.
+type NotificationTemplate struct {
+	Event   NotificationKind    `gorm:"column:event;primaryKey" json:"event"`
+	Channel NotificationChannel `gorm:"column:channel;primaryKey" json:"channel"`
+	Locale  string              `gorm:"column:locale;primaryKey" json:"locale"`
+	// Subject is only used by email templates.
+	Subject   string    `gorm:"column:subject" json:"subject,omitempty"`
+	Body      string    `gorm:"column:body" json:"body"`
+	UpdatedAt time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (NotificationTemplate) TableName() string {
+	return "notification_templates"
+}
+
+// NotificationMessage is a notification rendered for one channel.
+type NotificationMessage struct {
+	Channel NotificationChannel `json:"channel"`
+	Locale  string              `json:"locale"`
+	Subject string              `json:"subject,omitempty"`
+	Body    string              `json:"body"`
+}
+
+// ShipmentNotification tells the customer that their shipment was
+// delivered. The shipment fields stay at the top level of the message.
+type ShipmentNotification struct {
+	Shipment
+	Messages []NotificationMessage `json:"messages,omitempty"`
+}
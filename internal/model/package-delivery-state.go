@@ -3,9 +3,19 @@ package model
 type PackageDeliveryState string
 
 const (
-	PackageDeliveryInProgress PackageDeliveryState = "inProgress"
-	PackageDeliveryConfirmed  PackageDeliveryState = "confirmed"
-	PackageDeliverySaved      PackageDeliveryState = "confirmed"
-	PackageDeliveryNotified   PackageDeliveryState = "confirmed"
-	PackageDeliveryErrored    PackageDeliveryState = "errored"
+	PackageDeliveryInProgress         PackageDeliveryState = "inProgress"
+	PackageDeliveryScheduled          PackageDeliveryState = "scheduled"
+	PackageDeliveryConfirmed          PackageDeliveryState = "confirmed"
+	PackageDeliverySaved              PackageDeliveryState = "confirmed"
+	PackageDeliveryNotified           PackageDeliveryState = "confirmed"
+	PackageDeliveryErrored            PackageDeliveryState = "errored"
+	PackageDeliveryNotificationFailed PackageDeliveryState = "notificationFailed"
+	PackageDeliveryRolledBack         PackageDeliveryState = "rolledBack"
+	PackageDeliveryParked             PackageDeliveryState = "parked"
+	PackageDeliveryDisputed           PackageDeliveryState = "disputed"
+	PackageDeliveryRedelivery         PackageDeliveryState = "redelivery"
+	PackageDeliveryRefunded           PackageDeliveryState = "refunded"
+	PackageDeliveryDisputeClosed      PackageDeliveryState = "disputeClosed"
+	PackageDeliveryAttemptFailed      PackageDeliveryState = "attemptFailed"
+	PackageDeliveryReturnedToSender   PackageDeliveryState = "returnedToSender"
 )
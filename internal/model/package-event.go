@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+type PackageEventType string
+
+const (
+	PackageEventCreated   PackageEventType = "created"
+	PackageEventConfirmed PackageEventType = "confirmed"
+	PackageEventErrored   PackageEventType = "errored"
+)
+
+// PackageEvent records when a package first reached a milestone of its
+// delivery, as aggregated by the delivery report.
+type PackageEvent struct {
+	PackageID  string           `gorm:"column:package_id;primaryKey" json:"package_id"`
+	Event      PackageEventType `gorm:"column:event;primaryKey" json:"event"`
+	OccurredAt time.Time        `gorm:"column:occurred_at" json:"occurred_at"`
+}
@@ -0,0 +1,118 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ProofOfDelivery is the evidence collected when a delivery is confirmed.
+// Images are kept in object storage and referenced by key.
+type ProofOfDelivery struct {
+	RecipientName string           `json:"recipient_name"`
+	Signature     *ObjectReference `json:"signature,omitempty"`
+	Photo         *ObjectReference `json:"photo,omitempty"`
+	Location      *GeoLocation     `json:"location,omitempty"`
+}
+
+type ObjectReference struct {
+	Key         string `json:"key"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+type GeoLocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Equal reports whether both proofs name the same recipient, images and
+// location. Two missing proofs are equal.
+func (p *ProofOfDelivery) Equal(other *ProofOfDelivery) bool {
+	if p == nil || other == nil {
+		return p == other
+	}
+
+	return p.RecipientName == other.RecipientName &&
+		samePointee(p.Signature, other.Signature) &&
+		samePointee(p.Photo, other.Photo) &&
+		samePointee(p.Location, other.Location)
+}
+
+func samePointee[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+func (p *ProofOfDelivery) Validate() error {
+	if p.RecipientName == "" {
+		return errors.New("recipient_name is required")
+	}
+
+	if p.Location != nil {
+		if err := p.Location.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *GeoLocation) Validate() error {
+	if l.Latitude < -90 || l.Latitude > 90 {
+		return fmt.Errorf("latitude %v is out of range", l.Latitude)
+	}
+
+	if l.Longitude < -180 || l.Longitude > 180 {
+		return fmt.Errorf("longitude %v is out of range", l.Longitude)
+	}
+
+	return nil
+}
+
+// Clone returns a deep copy of p.
+func (p *ProofOfDelivery) Clone() *ProofOfDelivery {
+	if p == nil {
+		return nil
+	}
+
+	clone := *p
+	if p.Signature != nil {
+		signature := *p.Signature
+		clone.Signature = &signature
+	}
+	if p.Photo != nil {
+		photo := *p.Photo
+		clone.Photo = &photo
+	}
+	if p.Location != nil {
+		location := *p.Location
+		clone.Location = &location
+	}
+
+	return &clone
+}
+
+// Value stores the proof as a JSON document.
+func (p *ProofOfDelivery) Value() (driver.Value, error) {
+	if p == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(p)
+}
+
+func (p *ProofOfDelivery) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case []byte:
+		return json.Unmarshal(value, p)
+	case string:
+		return json.Unmarshal([]byte(value), p)
+	default:
+		return fmt.Errorf("unable to scan %T into ProofOfDelivery", src)
+	}
+}
@@ -0,0 +1,122 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+type ShipmentState string
+
+const (
+	ShipmentInProgress         ShipmentState = "inProgress"
+	ShipmentPartiallyConfirmed ShipmentState = "partiallyConfirmed"
+	ShipmentConfirmed          ShipmentState = "confirmed"
+	ShipmentCompleted          ShipmentState = "completed"
+	// ShipmentCompletedWithIssues is reached when every parcel is done but
+	// some of them were not delivered, e.g. refunded or failed.
+	ShipmentCompletedWithIssues ShipmentState = "completedWithIssues"
+)
+
+// Shipment groups the parcels of one order. The customer gets a single
+// confirmation link and a single notification for the whole shipment.
+type Shipment struct {
+	ID              string          `gorm:"column:id;primaryKey" json:"id"`
+	CustomerEmail   string          `gorm:"column:customer_email" json:"customer_email"`
+	DeliveryAddress string          `gorm:"column:delivery_address" json:"delivery_address"`
+	Region          string          `gorm:"column:region" json:"region,omitempty"`
+	Status          ShipmentState   `gorm:"column:status" json:"status"`
+	Parcels         ShipmentParcels `gorm:"column:parcels" json:"parcels"`
+}
+
+// ShipmentParcel is the state of one parcel as last reported by its
+// delivery workflow.
+type ShipmentParcel struct {
+	PackageID    string                `json:"package_id"`
+	Status       PackageDeliveryState  `json:"status"`
+	Confirmation *DeliveryConfirmation `json:"confirmation,omitempty"`
+	Completed    bool                  `json:"completed"`
+}
+
+// Delivered reports whether the parcel completed with a confirmed delivery.
+func (p *ShipmentParcel) Delivered() bool {
+	return p.Completed && p.Status == PackageDeliveryNotified
+}
+
+// Package returns the delivery package of a parcel of the shipment.
+func (s *Shipment) Package(parcel ShipmentParcel) *DeliveryPackage {
+	return &DeliveryPackage{
+		ID:              parcel.PackageID,
+		CustomerEmail:   s.CustomerEmail,
+		DeliveryAddress: s.DeliveryAddress,
+		Region:          s.Region,
+	}
+}
+
+// AggregateStatus derives the shipment status from the state of its
+// parcels.
+func (s *Shipment) AggregateStatus() ShipmentState {
+	confirmed, completed, delivered := 0, 0, 0
+	for _, parcel := range s.Parcels {
+		if parcel.Confirmation != nil {
+			confirmed++
+		}
+		if parcel.Completed {
+			completed++
+		}
+		if parcel.Delivered() {
+			delivered++
+		}
+	}
+
+	switch {
+	case delivered == len(s.Parcels):
+		return ShipmentCompleted
+	case completed == len(s.Parcels):
+		return ShipmentCompletedWithIssues
+	case confirmed == len(s.Parcels):
+		return ShipmentConfirmed
+	case confirmed > 0:
+		return ShipmentPartiallyConfirmed
+	default:
+		return ShipmentInProgress
+	}
+}
+
+// Clone returns a deep copy of the shipment.
+func (s *Shipment) Clone() *Shipment {
+	clone := *s
+	clone.Parcels = make(ShipmentParcels, len(s.Parcels))
+	for i, parcel := range s.Parcels {
+		if parcel.Confirmation != nil {
+			confirmation := *parcel.Confirmation
+			confirmation.Proof = confirmation.Proof.Clone()
+			parcel.Confirmation = &confirmation
+		}
+		clone.Parcels[i] = parcel
+	}
+
+	return &clone
+}
+
+// ShipmentParcels is stored as a jsonb column.
+type ShipmentParcels []ShipmentParcel
+
+func (p ShipmentParcels) Value() (driver.Value, error) {
+	if p == nil {
+		return "[]", nil
+	}
+
+	return json.Marshal(p)
+}
+
+func (p *ShipmentParcels) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case []byte:
+		return json.Unmarshal(value, p)
+	case string:
+		return json.Unmarshal([]byte(value), p)
+	default:
+		return fmt.Errorf("unable to scan %T into ShipmentParcels", src)
+	}
+}
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// StuckPackage is a package that has kept its status for longer than the
+// threshold configured for that status.
+type StuckPackage struct {
+	PackageID       string               `json:"package_id"`
+	RunID           string               `json:"run_id"`
+	Status          PackageDeliveryState `json:"status"`
+	StatusChangedAt time.Time            `json:"status_changed_at"`
+	// Running is unset for packages whose workflow has failed.
+	Running bool `json:"running"`
+}
+
+// StuckPackagesAlert asks operators to look into the packages found by one
+// scan.
+type StuckPackagesAlert struct {
+	ScannedAt time.Time      `json:"scanned_at"`
+	Packages  []StuckPackage `json:"packages"`
+}
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// DisabledWebhookSubscription is a webhook URL that answered 410 Gone. No
+// more webhooks are sent to it until an operator enables it again; other
+// URLs, including a new one configured for the same destination, are still
+// called.
+type DisabledWebhookSubscription struct {
+	URL string `gorm:"column:url;primaryKey" json:"url" example:"https://partner.example.com/hooks/notifications"`
+	// Destination is the destination the URL was configured for when it
+	// answered.
+	Destination string `gorm:"column:destination" json:"destination" example:"notifications"`
+	StatusCode  int    `gorm:"column:status_code" json:"status_code" example:"410"`
+	// ResponseExcerpt is the start of the body of the response that
+	// disabled the subscription.
+	ResponseExcerpt string    `gorm:"column:response_excerpt" json:"response_excerpt,omitempty"`
+	DisabledAt      time.Time `gorm:"column:disabled_at" json:"disabled_at"`
+}
+
+func (DisabledWebhookSubscription) TableName() string {
+	return "disabled_webhook_subscriptions"
+}
+
+// WebhookDisabledAlert asks operators to look into a webhook subscription
+// disabled after a 410 Gone, as the partner stopped receiving webhooks.
+type WebhookDisabledAlert struct {
+	Subscription DisabledWebhookSubscription `json:"subscription"`
+}
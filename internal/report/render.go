@@ -0,0 +1,81 @@
+// Package report renders the daily delivery report.
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"go-test/internal/model"
+	"html/template"
+	"strconv"
+	"time"
+)
+
+var htmlTemplate = template.Must(template.New("delivery-report").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Delivery report</title></head>
+<body>
+<h1>Delivery report</h1>
+<p>{{.From}} to {{.To}}</p>
+<table>
+<tr><th>Packages created</th><td>{{.Created}}</td></tr>
+<tr><th>Packages confirmed</th><td>{{.Confirmed}}</td></tr>
+<tr><th>Confirmation links expired</th><td>{{.Expired}}</td></tr>
+<tr><th>Packages errored</th><td>{{.Errored}}</td></tr>
+<tr><th>Median time to confirm</th><td>{{.MedianTimeToConfirm}}</td></tr>
+</table>
+</body>
+</html>
+`))
+
+// HTML renders the summary as a standalone HTML page.
+func HTML(summary model.DeliverySummary) (string, error) {
+	medianTimeToConfirm := "n/a"
+	if summary.MedianTimeToConfirm != nil {
+		medianTimeToConfirm = summary.MedianTimeToConfirm.Round(time.Second).String()
+	}
+
+	var buf bytes.Buffer
+	err := htmlTemplate.Execute(&buf, map[string]interface{}{
+		"From":                summary.From.Format(time.RFC3339),
+		"To":                  summary.To.Format(time.RFC3339),
+		"Created":             summary.Created,
+		"Confirmed":           summary.Confirmed,
+		"Expired":             summary.Expired,
+		"Errored":             summary.Errored,
+		"MedianTimeToConfirm": medianTimeToConfirm,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render the HTML report: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// CSV renders the summary as a header and a single row. The median time to
+// confirm is in seconds, and empty when no package was confirmed.
+func CSV(summary model.DeliverySummary) (string, error) {
+	medianTimeToConfirm := ""
+	if summary.MedianTimeToConfirm != nil {
+		medianTimeToConfirm = strconv.FormatInt(int64(summary.MedianTimeToConfirm.Round(time.Second)/time.Second), 10)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"from", "to", "created", "confirmed", "expired", "errored", "median_time_to_confirm_seconds"})
+	_ = w.Write([]string{
+		summary.From.Format(time.RFC3339),
+		summary.To.Format(time.RFC3339),
+		strconv.FormatInt(summary.Created, 10),
+		strconv.FormatInt(summary.Confirmed, 10),
+		strconv.FormatInt(summary.Expired, 10),
+		strconv.FormatInt(summary.Errored, 10),
+		medianTimeToConfirm,
+	})
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", fmt.Errorf("failed to render the CSV report: %w", err)
+	}
+
+	return buf.String(), nil
+}
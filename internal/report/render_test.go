@@ -0,0 +1,68 @@
+package report
+
+import (
+	"go-test/internal/model"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestSummary() model.DeliverySummary {
+	from := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	median := 4*time.Hour + 30*time.Minute
+
+	return model.DeliverySummary{
+		From:                from,
+		To:                  from.Add(24 * time.Hour),
+		Created:             12,
+		Confirmed:           9,
+		Expired:             2,
+		Errored:             1,
+		MedianTimeToConfirm: &median,
+	}
+}
+
+func TestHTML(t *testing.T) {
+	html, err := HTML(newTestSummary())
+	if err != nil {
+		t.Fatalf("HTML: %v", err)
+	}
+
+	for _, want := range []string{
+		"<p>2024-01-02T00:00:00Z to 2024-01-03T00:00:00Z</p>",
+		"<th>Packages created</th><td>12</td>",
+		"<th>Packages confirmed</th><td>9</td>",
+		"<th>Confirmation links expired</th><td>2</td>",
+		"<th>Packages errored</th><td>1</td>",
+		"<th>Median time to confirm</th><td>4h30m0s</td>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML does not contain %q:\n%s", want, html)
+		}
+	}
+
+	summary := newTestSummary()
+	summary.MedianTimeToConfirm = nil
+	if html, _ := HTML(summary); !strings.Contains(html, "<td>n/a</td>") {
+		t.Errorf("HTML without confirmations:\n%s", html)
+	}
+}
+
+func TestCSV(t *testing.T) {
+	csv, err := CSV(newTestSummary())
+	if err != nil {
+		t.Fatalf("CSV: %v", err)
+	}
+
+	want := "from,to,created,confirmed,expired,errored,median_time_to_confirm_seconds\n" +
+		"2024-01-02T00:00:00Z,2024-01-03T00:00:00Z,12,9,2,1,16200\n"
+	if csv != want {
+		t.Fatalf("CSV = %q, want %q", csv, want)
+	}
+
+	summary := newTestSummary()
+	summary.MedianTimeToConfirm = nil
+	if csv, _ := CSV(summary); !strings.HasSuffix(csv, ",1,\n") {
+		t.Errorf("CSV without confirmations = %q", csv)
+	}
+}
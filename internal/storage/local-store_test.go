@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+
+	if err := store.Put(ctx, "packages/pkg-1/proof/signature", "image/png", []byte("signature")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	body, err := store.Get(ctx, "packages/pkg-1/proof/signature")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer body.Close()
+
+	content, err := io.ReadAll(body)
+	if err != nil || string(content) != "signature" {
+		t.Fatalf("Get returned %q, %v", content, err)
+	}
+
+	if _, err := store.Get(ctx, "packages/pkg-1/proof/photo"); !errors.Is(err, ErrObjectNotFound) {
+		t.Fatalf("Get missing object error = %v, want ErrObjectNotFound", err)
+	}
+
+	if err := store.Delete(ctx, "packages/pkg-1/proof/signature"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, "packages/pkg-1/proof/signature"); !errors.Is(err, ErrObjectNotFound) {
+		t.Fatalf("Get deleted object error = %v, want ErrObjectNotFound", err)
+	}
+	if err := store.Delete(ctx, "packages/pkg-1/proof/signature"); err != nil {
+		t.Fatalf("Delete missing object: %v", err)
+	}
+
+	for _, key := range []string{"", "../outside", "/etc/passwd", "packages/../../outside"} {
+		if err := store.Put(ctx, key, "image/png", []byte("x")); err == nil {
+			t.Errorf("Put(%q) succeeded, want an invalid key error", key)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+// Package storage keeps binary objects, such as proof of delivery images,
+// outside of the database.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-test/internal/config"
+	"io"
+)
+
+var ErrObjectNotFound = errors.New("object not found")
+
+// ObjectStore stores opaque objects under slash separated keys.
+type ObjectStore interface {
+	Put(ctx context.Context, key string, contentType string, data []byte) error
+	// Get returns the object content. The caller must close the reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+const defaultLocalDir = "data/objects"
+
+// New creates the object store selected by cfg.
+func New(cfg config.StorageConfig) (ObjectStore, error) {
+	switch cfg.Backend {
+	case "", config.StorageBackendLocal:
+		dir := cfg.Local.Dir
+		if dir == "" {
+			dir = defaultLocalDir
+		}
+		return NewLocalStore(dir)
+	case config.StorageBackendS3:
+		return NewS3Store(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+	}
+}
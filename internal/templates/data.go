@@ -0,0 +1,83 @@
+package templates
+
+import (
+	"go-test/internal/model"
+	"time"
+)
+
+// Data are the variables available to a template. Only the fields that
+// belong to the event of the notification are set; the others are nil.
+type Data struct {
+	Package model.DeliveryPackage
+	// ConfirmationLink and LinkExpiresAt are set for confirmation requests.
+	ConfirmationLink string
+	LinkExpiresAt    *time.Time
+	// DeliveryWindow is set for arrival notifications, and for the other
+	// events of a package delivered within a window.
+	DeliveryWindow *model.DeliveryWindow
+	// Attempt is set for failed attempts and returns to the sender, and
+	// NextAttemptAt for failed attempts that are retried.
+	Attempt       *model.DeliveryAttempt
+	NextAttemptAt *time.Time
+	// Shipment is set for delivered shipments, whose Package is empty.
+	Shipment *model.Shipment
+}
+
+// SampleData returns the data of a made-up notification of the event, used
+// to validate and preview templates.
+func SampleData(event model.NotificationKind) Data {
+	now := time.Date(2026, time.March, 2, 9, 30, 0, 0, time.UTC)
+
+	data := Data{
+		Package: model.DeliveryPackage{
+			ID:              "PKG-12345",
+			CustomerEmail:   "jane.doe@example.com",
+			DeliveryAddress: "221B Baker Street, London",
+			Region:          "eu-west",
+			Status:          model.PackageDeliveryInProgress,
+		},
+	}
+
+	switch event {
+	case model.NotificationConfirmationRequest:
+		expiresAt := now.Add(72 * time.Hour)
+		data.ConfirmationLink = "https://delivery.example.com/confirm?token=sample"
+		data.LinkExpiresAt = &expiresAt
+	case model.NotificationArrival:
+		data.DeliveryWindow = &model.DeliveryWindow{
+			Start:    now.Add(2 * time.Hour),
+			End:      now.Add(4 * time.Hour),
+			TimeZone: "Europe/London",
+		}
+	case model.NotificationDelivered:
+		data.Package.Status = model.PackageDeliveryNotified
+	case model.NotificationFailedAttempt, model.NotificationReturnedToSender:
+		data.Attempt = &model.DeliveryAttempt{
+			Number:      1,
+			Outcome:     model.AttemptNobodyHome,
+			Driver:      "driver-7",
+			AttemptedAt: now,
+		}
+		if event == model.NotificationFailedAttempt {
+			nextAttemptAt := now.Add(24 * time.Hour)
+			data.NextAttemptAt = &nextAttemptAt
+		} else {
+			data.Attempt.Number = 3
+			data.Package.Status = model.PackageDeliveryReturnedToSender
+		}
+	case model.NotificationShipmentDelivered:
+		data.Package = model.DeliveryPackage{}
+		data.Shipment = &model.Shipment{
+			ID:              "SHP-6789",
+			CustomerEmail:   "jane.doe@example.com",
+			DeliveryAddress: "221B Baker Street, London",
+			Status:          model.ShipmentCompleted,
+			Parcels: model.ShipmentParcels{
+				{PackageID: "PKG-12345", Status: model.PackageDeliveryNotified, Completed: true},
+				{PackageID: "PKG-12346", Status: model.PackageDeliveryNotified, Completed: true},
+			},
+		}
+	}
+
+	return data
+}
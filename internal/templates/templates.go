@@ -0,0 +1,267 @@
+// Package templates renders the content of customer notifications from
+// per-event, per-channel and per-locale templates. Templates stored in the
+// database override the defaults embedded in the service.
+package templates
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"go-test/internal/model"
+	"go-test/repository"
+	"go.uber.org/zap"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultLocale is the last locale tried for every notification. Every
+// event has embedded templates in it for every channel.
+const DefaultLocale = model.DefaultNotificationLanguage
+
+type Source string
+
+const (
+	SourceDatabase Source = "database"
+	SourceEmbedded Source = "embedded"
+)
+
+var ErrInvalidTemplate = errors.New("invalid notification template")
+
+//go:embed defaults
+var defaultFiles embed.FS
+
+// defaults are the embedded templates, laid out as
+// defaults/<locale>/<event>.<channel>.txt. An email template starts with a
+// "Subject:" line and a blank line before its body.
+var defaults = loadDefaults()
+
+type key struct {
+	event   model.NotificationKind
+	channel model.NotificationChannel
+	locale  string
+}
+
+var funcs = map[string]interface{}{
+	// local returns t in the IANA time zone, or t unchanged when the zone is
+	// unknown.
+	"local": func(t time.Time, zone string) time.Time {
+		location, err := time.LoadLocation(zone)
+		if err != nil {
+			return t
+		}
+		return t.In(location)
+	},
+}
+
+func loadDefaults() map[key]model.NotificationTemplate {
+	templates := make(map[key]model.NotificationTemplate)
+
+	err := fs.WalkDir(defaultFiles, "defaults", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		content, err := defaultFiles.ReadFile(name)
+		if err != nil {
+			return err
+		}
+
+		locale := path.Base(path.Dir(name))
+		event, channel, ok := strings.Cut(strings.TrimSuffix(path.Base(name), ".txt"), ".")
+		if !ok {
+			return fmt.Errorf("unexpected template file %s", name)
+		}
+
+		template := model.NotificationTemplate{
+			Event:   model.NotificationKind(event),
+			Channel: model.NotificationChannel(channel),
+			Locale:  locale,
+			Body:    string(content),
+		}
+		if subject, body, ok := strings.Cut(template.Body, "\n\n"); ok && strings.HasPrefix(subject, "Subject: ") {
+			template.Subject = strings.TrimPrefix(subject, "Subject: ")
+			template.Body = body
+		}
+		template.Body = strings.TrimRight(template.Body, "\n")
+
+		templates[key{template.Event, template.Channel, template.Locale}] = template
+		return nil
+	})
+	if err != nil {
+		panic(fmt.Sprintf("templates: unable to load the embedded templates: %v", err))
+	}
+
+	return templates
+}
+
+// Locales returns the locales tried for a notification in locale, most
+// specific first: the locale itself, its language and the default locale.
+func Locales(locale string) []string {
+	language, region, _ := strings.Cut(locale, "-")
+	language = strings.ToLower(language)
+
+	var locales []string
+	if language != "" && region != "" {
+		locales = append(locales, language+"-"+strings.ToUpper(region))
+	}
+	if language != "" && language != DefaultLocale {
+		locales = append(locales, language)
+	}
+
+	return append(locales, DefaultLocale)
+}
+
+type Engine struct {
+	Store  repository.NotificationTemplateStore
+	Logger *zap.Logger
+}
+
+func NewEngine(store repository.NotificationTemplateStore, logger *zap.Logger) *Engine {
+	return &Engine{Store: store, Logger: logger}
+}
+
+// Resolve returns the template of the event and channel for the first of
+// the fallback locales that has one, preferring stored templates over the
+// embedded ones.
+func (e *Engine) Resolve(ctx context.Context, event model.NotificationKind, channel model.NotificationChannel, locale string) (*model.NotificationTemplate, Source, error) {
+	for _, candidate := range Locales(locale) {
+		template, err := e.Store.GetNotificationTemplate(ctx, event, channel, candidate)
+		if err == nil {
+			return template, SourceDatabase, nil
+		}
+		if !errors.Is(err, repository.ErrTemplateNotFound) {
+			return nil, "", err
+		}
+
+		if template, ok := defaults[key{event, channel, candidate}]; ok {
+			return &template, SourceEmbedded, nil
+		}
+	}
+
+	return nil, "", fmt.Errorf("%w: %s/%s/%s", repository.ErrTemplateNotFound, event, channel, locale)
+}
+
+// Render renders the notification of the event for every channel of the
+// routing. A stored template that fails to render is logged and replaced
+// by the embedded one, so that a broken override never holds up a
+// notification.
+func (e *Engine) Render(ctx context.Context, event model.NotificationKind, routing model.NotificationRouting, data Data) ([]model.NotificationMessage, error) {
+	channels := routing.Channels
+	if len(channels) == 0 {
+		channels = model.NotificationChannels{model.NotificationChannelEmail}
+	}
+
+	messages := make([]model.NotificationMessage, 0, len(channels))
+	for _, channel := range channels {
+		template, source, err := e.Resolve(ctx, event, channel, routing.Language)
+		if err != nil {
+			return nil, err
+		}
+
+		message, err := RenderTemplate(*template, data)
+		if err != nil && source == SourceDatabase {
+			e.Logger.Error("Failed to render stored notification template, using the embedded one",
+				zap.String("event", string(event)),
+				zap.String("channel", string(channel)),
+				zap.String("locale", template.Locale),
+				zap.Error(err))
+
+			message, err = renderDefault(event, channel, routing.Language, data)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+func renderDefault(event model.NotificationKind, channel model.NotificationChannel, locale string, data Data) (model.NotificationMessage, error) {
+	for _, candidate := range Locales(locale) {
+		if template, ok := defaults[key{event, channel, candidate}]; ok {
+			return RenderTemplate(template, data)
+		}
+	}
+
+	return model.NotificationMessage{}, fmt.Errorf("%w: %s/%s/%s", repository.ErrTemplateNotFound, event, channel, locale)
+}
+
+// RenderTemplate renders a single template. Email bodies are HTML and
+// escape the data; subjects and the bodies of other channels are text.
+func RenderTemplate(tmpl model.NotificationTemplate, data Data) (model.NotificationMessage, error) {
+	message := model.NotificationMessage{Channel: tmpl.Channel, Locale: tmpl.Locale}
+
+	var err error
+	if tmpl.Subject != "" {
+		if message.Subject, err = renderText("subject", tmpl.Subject, data); err != nil {
+			return model.NotificationMessage{}, err
+		}
+	}
+
+	if tmpl.Channel == model.NotificationChannelEmail {
+		message.Body, err = renderHTML("body", tmpl.Body, data)
+	} else {
+		message.Body, err = renderText("body", tmpl.Body, data)
+	}
+	if err != nil {
+		return model.NotificationMessage{}, err
+	}
+
+	return message, nil
+}
+
+// Validate checks the event, channel and locale of the template, and that
+// it renders with the sample data of its event.
+func Validate(tmpl model.NotificationTemplate) error {
+	if !tmpl.Event.Valid() {
+		return fmt.Errorf("%w: unknown event %q", ErrInvalidTemplate, tmpl.Event)
+	}
+	if !tmpl.Channel.Valid() {
+		return fmt.Errorf("%w: unknown channel %q", ErrInvalidTemplate, tmpl.Channel)
+	}
+	if !model.ValidLanguage(tmpl.Locale) {
+		return fmt.Errorf("%w: locale %q is not a language tag such as en or de-AT", ErrInvalidTemplate, tmpl.Locale)
+	}
+	if strings.TrimSpace(tmpl.Body) == "" {
+		return fmt.Errorf("%w: the body is empty", ErrInvalidTemplate)
+	}
+
+	_, err := RenderTemplate(tmpl, SampleData(tmpl.Event))
+	return err
+}
+
+func renderText(name, text string, data Data) (string, error) {
+	parsed, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidTemplate, err)
+	}
+
+	var out bytes.Buffer
+	if err := parsed.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidTemplate, err)
+	}
+
+	return out.String(), nil
+}
+
+func renderHTML(name, text string, data Data) (string, error) {
+	parsed, err := htmltemplate.New(name).Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidTemplate, err)
+	}
+
+	var out bytes.Buffer
+	if err := parsed.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidTemplate, err)
+	}
+
+	return out.String(), nil
+}
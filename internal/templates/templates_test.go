@@ -0,0 +1,194 @@
+package templates
+
+import (
+	"context"
+	"errors"
+	"go-test/internal/model"
+	"go-test/repository"
+	"go.uber.org/zap"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLocales(t *testing.T) {
+	for locale, want := range map[string][]string{
+		"":      {"en"},
+		"en":    {"en"},
+		"en-GB": {"en-GB", "en"},
+		"de":    {"de", "en"},
+		"de-AT": {"de-AT", "de", "en"},
+		"DE-at": {"de-AT", "de", "en"},
+	} {
+		if got := Locales(locale); !reflect.DeepEqual(got, want) {
+			t.Errorf("Locales(%q) = %v, want %v", locale, got, want)
+		}
+	}
+}
+
+func TestEmbeddedTemplatesRender(t *testing.T) {
+	events := []model.NotificationKind{
+		model.NotificationConfirmationRequest,
+		model.NotificationDelivered,
+		model.NotificationArrival,
+		model.NotificationFailedAttempt,
+		model.NotificationReturnedToSender,
+		model.NotificationShipmentDelivered,
+	}
+	channels := []model.NotificationChannel{
+		model.NotificationChannelEmail,
+		model.NotificationChannelSMS,
+		model.NotificationChannelPush,
+	}
+
+	for _, locale := range []string{"en", "de"} {
+		for _, event := range events {
+			for _, channel := range channels {
+				template, ok := defaults[key{event, channel, locale}]
+				if !ok {
+					t.Errorf("no embedded template for %s/%s/%s", event, channel, locale)
+					continue
+				}
+				if err := Validate(template); err != nil {
+					t.Errorf("Validate %s/%s/%s: %v", event, channel, locale, err)
+				}
+				if channel == model.NotificationChannelEmail && template.Subject == "" {
+					t.Errorf("embedded email template %s/%s has no subject", event, locale)
+				}
+			}
+		}
+	}
+}
+
+func TestRenderFallsBackToLanguageAndDefaultLocale(t *testing.T) {
+	engine := NewEngine(repository.NewMemoryRepository(), zap.NewNop())
+	data := SampleData(model.NotificationDelivered)
+
+	messages, err := engine.Render(context.Background(), model.NotificationDelivered, model.NotificationRouting{
+		Channels: model.NotificationChannels{model.NotificationChannelEmail, model.NotificationChannelSMS},
+		Language: "de-AT",
+	}, data)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("Render returned %d messages, want 2", len(messages))
+	}
+	if messages[0].Channel != model.NotificationChannelEmail || messages[0].Locale != "de" {
+		t.Errorf("email message = %s/%s, want email/de", messages[0].Channel, messages[0].Locale)
+	}
+	if messages[0].Subject != "Paket PKG-12345 wurde zugestellt" {
+		t.Errorf("email subject = %q", messages[0].Subject)
+	}
+	if messages[1].Channel != model.NotificationChannelSMS || !strings.Contains(messages[1].Body, "PKG-12345") {
+		t.Errorf("sms message = %+v", messages[1])
+	}
+
+	messages, err = engine.Render(context.Background(), model.NotificationDelivered, model.NotificationRouting{Language: "fr-FR"}, data)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if len(messages) != 1 || messages[0].Channel != model.NotificationChannelEmail || messages[0].Locale != "en" {
+		t.Errorf("Render fr-FR = %+v, want a single English email", messages)
+	}
+}
+
+func TestRenderPrefersStoredTemplates(t *testing.T) {
+	store := repository.NewMemoryRepository()
+	engine := NewEngine(store, zap.NewNop())
+
+	err := store.SaveNotificationTemplate(context.Background(), &model.NotificationTemplate{
+		Event:   model.NotificationDelivered,
+		Channel: model.NotificationChannelSMS,
+		Locale:  "de-AT",
+		Body:    "Servus, {{.Package.ID}} ist da.",
+	})
+	if err != nil {
+		t.Fatalf("SaveNotificationTemplate: %v", err)
+	}
+
+	template, source, err := engine.Resolve(context.Background(), model.NotificationDelivered, model.NotificationChannelSMS, "de-AT")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if source != SourceDatabase || template.Locale != "de-AT" {
+		t.Errorf("Resolve = %s from %s, want de-AT from the database", template.Locale, source)
+	}
+
+	messages, err := engine.Render(context.Background(), model.NotificationDelivered, model.NotificationRouting{
+		Channels: model.NotificationChannels{model.NotificationChannelSMS},
+		Language: "de-AT",
+	}, SampleData(model.NotificationDelivered))
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if messages[0].Body != "Servus, PKG-12345 ist da." {
+		t.Errorf("body = %q", messages[0].Body)
+	}
+}
+
+func TestRenderReplacesBrokenStoredTemplate(t *testing.T) {
+	store := repository.NewMemoryRepository()
+	engine := NewEngine(store, zap.NewNop())
+
+	err := store.SaveNotificationTemplate(context.Background(), &model.NotificationTemplate{
+		Event:   model.NotificationDelivered,
+		Channel: model.NotificationChannelPush,
+		Locale:  "en",
+		Body:    "{{.Attempt.Number}}",
+	})
+	if err != nil {
+		t.Fatalf("SaveNotificationTemplate: %v", err)
+	}
+
+	messages, err := engine.Render(context.Background(), model.NotificationDelivered, model.NotificationRouting{
+		Channels: model.NotificationChannels{model.NotificationChannelPush},
+	}, SampleData(model.NotificationDelivered))
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if messages[0].Body != "Package PKG-12345 was delivered." {
+		t.Errorf("body = %q, want the embedded template", messages[0].Body)
+	}
+}
+
+func TestRenderEscapesEmailBodies(t *testing.T) {
+	data := SampleData(model.NotificationDelivered)
+	data.Package.DeliveryAddress = `<script>alert("x")</script>`
+
+	message, err := RenderTemplate(defaults[key{model.NotificationDelivered, model.NotificationChannelEmail, "en"}], data)
+	if err != nil {
+		t.Fatalf("RenderTemplate: %v", err)
+	}
+	if strings.Contains(message.Body, "<script>") {
+		t.Errorf("email body is not escaped: %s", message.Body)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := model.NotificationTemplate{
+		Event:   model.NotificationArrival,
+		Channel: model.NotificationChannelSMS,
+		Locale:  "de-AT",
+		Body:    "{{.Package.ID}} {{.DeliveryWindow.Start}}",
+	}
+	if err := Validate(valid); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	for name, change := range map[string]func(*model.NotificationTemplate){
+		"unknown event":     func(tmpl *model.NotificationTemplate) { tmpl.Event = "lost" },
+		"unknown channel":   func(tmpl *model.NotificationTemplate) { tmpl.Channel = "fax" },
+		"invalid locale":    func(tmpl *model.NotificationTemplate) { tmpl.Locale = "de_at" },
+		"empty body":        func(tmpl *model.NotificationTemplate) { tmpl.Body = " " },
+		"syntax error":      func(tmpl *model.NotificationTemplate) { tmpl.Body = "{{.Package.ID" },
+		"unknown variable":  func(tmpl *model.NotificationTemplate) { tmpl.Body = "{{.Parcel}}" },
+		"data of the event": func(tmpl *model.NotificationTemplate) { tmpl.Body = "{{.Shipment.ID}}" },
+	} {
+		tmpl := valid
+		change(&tmpl)
+		if err := Validate(tmpl); !errors.Is(err, ErrInvalidTemplate) {
+			t.Errorf("Validate %s = %v, want ErrInvalidTemplate", name, err)
+		}
+	}
+}
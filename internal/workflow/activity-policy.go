@@ -0,0 +1,119 @@
+package workflow
+
+import (
+	"fmt"
+	"go-test/internal/activities"
+	"go-test/internal/config"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+	"slices"
+	"time"
+)
+
+var defaultActivityPolicy = config.ActivityPolicy{
+	StartToCloseTimeout: config.Duration(time.Minute),
+	MaximumAttempts:     3,
+}
+
+// DefaultActivityPolicies are applied before any configured policy. The
+// compensation steps and forced transitions retry longer since giving up on
+// them leaves the package in an inconsistent state.
+func DefaultActivityPolicies() map[string]config.ActivityPolicy {
+	compensation := defaultActivityPolicy.Merge(config.ActivityPolicy{MaximumAttempts: 10})
+
+	return map[string]config.ActivityPolicy{
+		activities.SaveDeliveryActivityName:             defaultActivityPolicy,
+		activities.NotifyDeliveryActivityName:           defaultActivityPolicy,
+		activities.MarkNotificationFailedActivityName:   compensation,
+		activities.RollbackDeliveryActivityName:         compensation,
+		activities.PublishCompensationEventActivityName: compensation,
+		activities.ForceTransitionActivityName:          compensation,
+	}
+}
+
+// packageActivityNames are the activities run by the package delivery
+// workflow and the dispute resolution workflows it starts, the ones a
+// package can override the policy of.
+var packageActivityNames = []string{
+	activities.RequestConfirmationActivityName,
+	activities.SaveDeliveryActivityName,
+	activities.NotifyDeliveryActivityName,
+	activities.LoadCustomerPreferencesActivityName,
+	activities.NotifyArrivalActivityName,
+	activities.MarkNotificationFailedActivityName,
+	activities.RollbackDeliveryActivityName,
+	activities.PublishCompensationEventActivityName,
+	activities.RecordDisputeActivityName,
+	activities.NotifySupportActivityName,
+	activities.ResolveDisputeActivityName,
+	activities.RecordAttemptActivityName,
+	activities.NotifyFailedAttemptActivityName,
+	activities.ReturnToSenderActivityName,
+	activities.ForceTransitionActivityName,
+	activities.RecordPackageEventActivityName,
+}
+
+// ValidatePackageActivityPolicies checks the activity policies a package
+// overrides: each must name an activity of the package delivery and be a
+// valid policy.
+func ValidatePackageActivityPolicies(overrides map[string]config.ActivityPolicy) error {
+	for name, policy := range overrides {
+		if !slices.Contains(packageActivityNames, name) {
+			return fmt.Errorf("unknown activity %q", name)
+		}
+		if err := policy.Validate(); err != nil {
+			return fmt.Errorf("activity policy of %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// ActivityPolicyRegistry resolves the activity options for an activity by
+// layering the defaults, the configured policy and per-workflow overrides.
+type ActivityPolicyRegistry struct {
+	policies map[string]config.ActivityPolicy
+}
+
+func NewActivityPolicyRegistry(configured map[string]config.ActivityPolicy) *ActivityPolicyRegistry {
+	policies := DefaultActivityPolicies()
+	for name, policy := range configured {
+		base, ok := policies[name]
+		if !ok {
+			base = defaultActivityPolicy
+		}
+		policies[name] = base.Merge(policy)
+	}
+
+	return &ActivityPolicyRegistry{policies: policies}
+}
+
+func (r *ActivityPolicyRegistry) Policy(activityName string, overrides map[string]config.ActivityPolicy) config.ActivityPolicy {
+	policy, ok := r.policies[activityName]
+	if !ok {
+		policy = defaultActivityPolicy
+	}
+
+	if override, ok := overrides[activityName]; ok {
+		policy = policy.Merge(override)
+	}
+
+	return policy
+}
+
+func (r *ActivityPolicyRegistry) Options(activityName string, overrides map[string]config.ActivityPolicy) workflow.ActivityOptions {
+	policy := r.Policy(activityName, overrides)
+
+	return workflow.ActivityOptions{
+		StartToCloseTimeout:    policy.StartToCloseTimeout.Duration(),
+		ScheduleToCloseTimeout: policy.ScheduleToCloseTimeout.Duration(),
+		HeartbeatTimeout:       policy.HeartbeatTimeout.Duration(),
+		RetryPolicy: &temporal.RetryPolicy{
+			InitialInterval:        policy.InitialInterval.Duration(),
+			BackoffCoefficient:     policy.BackoffCoefficient,
+			MaximumInterval:        policy.MaximumInterval.Duration(),
+			MaximumAttempts:        policy.MaximumAttempts,
+			NonRetryableErrorTypes: policy.NonRetryableErrorTypes,
+		},
+	}
+}
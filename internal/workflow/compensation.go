@@ -0,0 +1,185 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"go-test/internal/activities"
+	"go-test/internal/model"
+	"go.temporal.io/sdk/workflow"
+	"go.uber.org/zap"
+)
+
+type CompensationAction string
+
+const (
+	// CompensationMarkFailed keeps the saved package but flags it as not notified.
+	CompensationMarkFailed CompensationAction = "markFailed"
+	// CompensationRollback removes the saved package.
+	CompensationRollback CompensationAction = "rollback"
+	// CompensationPark leaves everything as is and waits for an operator to
+	// send a ManualResolution through the resolve signal.
+	CompensationPark CompensationAction = "park"
+	// CompensationRetry is only valid in a ManualResolution and re-runs the
+	// failed step.
+	CompensationRetry CompensationAction = "retry"
+)
+
+type ManualResolution struct {
+	Action   CompensationAction `json:"action"`
+	Operator string             `json:"operator"`
+	Reason   string             `json:"reason"`
+}
+
+func (r ManualResolution) Valid() bool {
+	switch r.Action {
+	case CompensationRetry, CompensationMarkFailed, CompensationRollback:
+		return true
+	default:
+		return false
+	}
+}
+
+func DefaultCompensationPolicies() map[string]CompensationAction {
+	return map[string]CompensationAction{
+		activities.SaveDeliveryActivityName:   CompensationMarkFailed,
+		activities.NotifyDeliveryActivityName: CompensationMarkFailed,
+	}
+}
+
+// NewCompensationPolicies layers the configured policies over the defaults.
+// Actions that cannot be configured, such as retry, are logged and ignored.
+func NewCompensationPolicies(configured map[string]string, logger *zap.Logger) map[string]CompensationAction {
+	policies := DefaultCompensationPolicies()
+	for step, action := range configured {
+		switch action := CompensationAction(action); action {
+		case CompensationMarkFailed, CompensationRollback, CompensationPark:
+			policies[step] = action
+		default:
+			logger.Warn("Ignoring invalid compensation policy", zap.String("step", step), zap.String("action", string(action)))
+		}
+	}
+
+	return policies
+}
+
+func (c *PackageDeliveryWorkflowConfig) compensationAction(step string) CompensationAction {
+	if action, ok := c.CompensationPolicies[step]; ok {
+		return action
+	}
+	return CompensationMarkFailed
+}
+
+// runStep executes a workflow step and, once it has failed for good, applies
+// the compensation policy configured for that step. A parked step is retried
+// or compensated depending on the operator's resolution.
+func (c *PackageDeliveryWorkflowConfig) runStep(w *PackageDeliveryWorkflow, step string, execute func() error) error {
+	for {
+		err := execute()
+		if err == nil {
+			return nil
+		}
+
+		c.Logger.Error("Package delivery step failed", zap.String("step", step), zap.Error(err))
+
+		if !hasChange(w.Ctx, changeCompensation) {
+			c.setStatus(w, model.PackageDeliveryErrored)
+			return err
+		}
+
+		action := c.compensationAction(step)
+		if action == CompensationPark {
+			resolution, err := c.park(w, step)
+			if err != nil {
+				return err
+			}
+			if resolution.Action == CompensationRetry {
+				continue
+			}
+			action = resolution.Action
+		}
+
+		if compensationErr := c.compensate(w, step, action, err); compensationErr != nil {
+			c.Logger.Error("Package delivery compensation failed", zap.String("step", step), zap.Error(compensationErr))
+			return errors.Join(err, compensationErr)
+		}
+
+		return err
+	}
+}
+
+// park waits for an operator to either resolve the failed step or force the
+// final status of the delivery, in which case it returns
+// errTransitionForced once the transition is done.
+func (c *PackageDeliveryWorkflowConfig) park(w *PackageDeliveryWorkflow, step string) (ManualResolution, error) {
+	c.setStatus(w, model.PackageDeliveryParked)
+
+	c.Logger.Warn("Package delivery parked for manual intervention", zap.String("step", step))
+
+	resolve := workflow.GetSignalChannel(w.Ctx, PackageDeliverySignalResolve)
+	for {
+		err := workflow.Await(w.Ctx, func() bool {
+			return resolve.Len() > 0 || w.State.ForcedTransition != nil
+		})
+		if err != nil {
+			return ManualResolution{}, err
+		}
+
+		if w.State.ForcedTransition != nil {
+			if err := c.forceTransition(w); err != nil {
+				return ManualResolution{}, err
+			}
+			return ManualResolution{}, errTransitionForced
+		}
+
+		var resolution ManualResolution
+		resolve.Receive(w.Ctx, &resolution)
+
+		if resolution.Valid() {
+			c.Logger.Info("Received manual resolution", zap.String("step", step), zap.String("action", string(resolution.Action)), zap.String("operator", resolution.Operator))
+			return resolution, nil
+		}
+
+		c.Logger.Warn("Ignoring invalid manual resolution", zap.String("step", step), zap.String("action", string(resolution.Action)))
+	}
+}
+
+func (c *PackageDeliveryWorkflowConfig) compensate(w *PackageDeliveryWorkflow, step string, action CompensationAction, cause error) error {
+	input := &activities.CompensateDeliveryInput{PackageID: w.Package.ID}
+
+	status := model.PackageDeliveryErrored
+	if w.State.Saved {
+		switch action {
+		case CompensationRollback:
+			ctx := c.activityContext(w, activities.RollbackDeliveryActivityName)
+			if err := workflow.ExecuteActivity(ctx, activities.RollbackDeliveryActivityName, input).Get(ctx, nil); err != nil {
+				return fmt.Errorf("rollback delivery: %w", err)
+			}
+			status = model.PackageDeliveryRolledBack
+		default:
+			ctx := c.activityContext(w, activities.MarkNotificationFailedActivityName)
+			if err := workflow.ExecuteActivity(ctx, activities.MarkNotificationFailedActivityName, input).Get(ctx, nil); err != nil {
+				return fmt.Errorf("mark notification failed: %w", err)
+			}
+			status = model.PackageDeliveryNotificationFailed
+		}
+	}
+
+	c.setStatus(w, status)
+
+	event := &model.CompensationEvent{
+		PackageID:  w.Package.ID,
+		FailedStep: step,
+		Action:     string(action),
+		Status:     status,
+		Reason:     cause.Error(),
+		OccurredAt: workflow.Now(w.Ctx),
+	}
+
+	ctx := c.activityContext(w, activities.PublishCompensationEventActivityName)
+	err := workflow.ExecuteActivity(ctx, activities.PublishCompensationEventActivityName, &activities.PublishCompensationEventInput{Event: event}).Get(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("publish compensation event: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package workflow
+
+import (
+	"go-test/internal/activities"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestNewCompensationPolicies(t *testing.T) {
+	policies := NewCompensationPolicies(map[string]string{
+		activities.NotifyDeliveryActivityName: "park",
+		activities.SaveDeliveryActivityName:   "retry",
+		activities.RecordAttemptActivityName:  "rollback",
+	}, zap.NewNop())
+
+	for step, want := range map[string]CompensationAction{
+		activities.NotifyDeliveryActivityName: CompensationPark,
+		activities.SaveDeliveryActivityName:   CompensationMarkFailed,
+		activities.RecordAttemptActivityName:  CompensationRollback,
+	} {
+		if policies[step] != want {
+			t.Errorf("policy of %s = %q, want %q", step, policies[step], want)
+		}
+	}
+}
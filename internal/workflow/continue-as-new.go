@@ -0,0 +1,83 @@
+package workflow
+
+import (
+	"go-test/internal/config"
+	"go.temporal.io/sdk/workflow"
+	"go.uber.org/zap"
+)
+
+const (
+	// The defaults stay well below the limits enforced by the Temporal
+	// server, which terminates workflows at 50k events or 50MB.
+	DefaultMaxHistoryEvents = 10000
+	DefaultMaxHistorySize   = 10 << 20
+)
+
+func withHistoryDefaults(cfg config.HistoryLimitsConfig) config.HistoryLimitsConfig {
+	if cfg.MaxEvents <= 0 {
+		cfg.MaxEvents = DefaultMaxHistoryEvents
+	}
+	if cfg.MaxSizeBytes <= 0 {
+		cfg.MaxSizeBytes = DefaultMaxHistorySize
+	}
+
+	return cfg
+}
+
+// recordHistoryLimits records the configured history limits for this run,
+// so that a configuration change cannot alter the point at which a
+// replayed run continues as new.
+func (c *PackageDeliveryWorkflowConfig) recordHistoryLimits(w *PackageDeliveryWorkflow) {
+	var limits config.HistoryLimitsConfig
+	err := workflow.SideEffect(w.Ctx, func(workflow.Context) interface{} {
+		return c.HistoryLimits
+	}).Get(&limits)
+	if err != nil {
+		limits = withHistoryDefaults(config.HistoryLimitsConfig{})
+	}
+
+	w.HistoryLimits = &limits
+}
+
+// shouldContinueAsNew reports whether the history of the run has reached
+// one of its limits, or the server suggests continuing as new.
+func (c *PackageDeliveryWorkflowConfig) shouldContinueAsNew(w *PackageDeliveryWorkflow) bool {
+	if w.HistoryLimits == nil {
+		return false
+	}
+
+	info := workflow.GetInfo(w.Ctx)
+
+	return info.GetContinueAsNewSuggested() ||
+		info.GetCurrentHistoryLength() >= w.HistoryLimits.MaxEvents ||
+		info.GetCurrentHistorySize() >= w.HistoryLimits.MaxSizeBytes
+}
+
+// continueAsNew hands the state of the workflow over to a new run. The
+// signals are stopped and drained first, so that none received by this run
+// are lost.
+func (c *PackageDeliveryWorkflowConfig) continueAsNew(w *PackageDeliveryWorkflow, stopSignals func() error, validate bool) error {
+	if err := stopSignals(); err != nil {
+		return err
+	}
+	c.drainSignals(w, validate)
+
+	info := workflow.GetInfo(w.Ctx)
+	c.Logger.Info("Continuing package delivery workflow as new",
+		zap.String("packageId", w.Package.ID),
+		zap.Int("historyLength", info.GetCurrentHistoryLength()),
+		zap.Int("historySize", info.GetCurrentHistorySize()),
+	)
+
+	w.WorkflowResult.ContinuedAsNew++
+
+	return workflow.NewContinueAsNewError(w.Ctx, PackageDeliveryWorkflowName, &PackageDeliveryWorkflowParams{
+		DeliveryPackage:  w.Package,
+		ActivityPolicies: w.ActivityPolicies,
+		ShipmentID:       w.ShipmentID,
+		Continued: &PackageDeliveryContinuation{
+			State:  w.State,
+			Result: w.WorkflowResult,
+		},
+	})
+}
@@ -0,0 +1,131 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/mock"
+	"go-test/internal/activities"
+	"go-test/internal/config"
+	"go-test/internal/model"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/workflow"
+	"time"
+)
+
+const testMaxHistoryEvents = 500
+
+func newHistoryLimitsConfig() config.WorkflowConfig {
+	cfg := newAttemptsConfig(24*time.Hour, 3)
+	cfg.HistoryLimits = config.HistoryLimitsConfig{MaxEvents: testMaxHistoryEvents}
+
+	return cfg
+}
+
+// runUntilContinuedAsNew fails a first attempt, buffers the attempts
+// reported by later drivers without running the workflow, then grows the
+// history past its limit. It returns the params the workflow continued as
+// new with.
+func (s *PackageDeliveryWorkflowTestSuite) runUntilContinuedAsNew(outcomes ...model.AttemptOutcome) *PackageDeliveryWorkflowParams {
+	s.fixture = newPackageDeliveryFixture(&s.WorkflowTestSuite, newHistoryLimitsConfig())
+	f := s.fixture
+
+	f.attemptAfter(time.Hour, model.AttemptNobodyHome)
+	f.env.RegisterDelayedCallback(func() {
+		for i, outcome := range outcomes {
+			f.env.SignalWorkflowSkippingWorkflowTask(PackageDeliverySignalAttempt, &model.DeliveryAttempt{
+				Outcome:     outcome,
+				Driver:      fmt.Sprintf("driver-%d", i+2),
+				AttemptedAt: f.env.Now(),
+			})
+		}
+	}, 2*time.Hour)
+	f.env.RegisterDelayedCallback(func() {
+		f.env.SetCurrentHistoryLength(testMaxHistoryEvents)
+	}, 3*time.Hour)
+
+	f.execute(newTestParams())
+
+	s.True(f.env.IsWorkflowCompleted())
+
+	var continueAsNew *workflow.ContinueAsNewError
+	s.Require().True(errors.As(f.env.GetWorkflowError(), &continueAsNew))
+	s.Equal(PackageDeliveryWorkflowName, continueAsNew.WorkflowType.Name)
+
+	var params PackageDeliveryWorkflowParams
+	s.Require().NoError(converter.GetDefaultDataConverter().FromPayloads(continueAsNew.Input, &params))
+
+	return &params
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestContinuesAsNewOnceHistoryLimitReached() {
+	params := s.runUntilContinuedAsNew(model.AttemptNobodyHome, model.AttemptDelivered)
+
+	s.Equal(testPackageID, params.DeliveryPackage.ID)
+	s.Require().NotNil(params.Continued)
+
+	result := params.Continued.Result
+	s.Equal(1, result.ContinuedAsNew)
+	s.Equal(model.PackageDeliveryAttemptFailed, result.Status)
+	s.Len(result.Attempts, 1)
+	s.Equal(1, result.FailedAttempts)
+	s.NotNil(result.NextAttemptAt)
+
+	// The attempts signalled before the switch are carried over in order.
+	state := params.Continued.State
+	s.Require().Len(state.PendingAttempts, 2)
+	s.Equal("driver-2", state.PendingAttempts[0].Driver)
+	s.Equal("driver-3", state.PendingAttempts[1].Driver)
+	s.Require().NotNil(state.AttemptPolicy)
+	s.Equal(3, state.AttemptPolicy.MaxFailedAttempts)
+	s.True(state.Pending)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestContinuedRunResumesCarriedState() {
+	params := s.runUntilContinuedAsNew(model.AttemptNobodyHome, model.AttemptDelivered)
+	s.Equal(1, s.fixture.confirmationRequests)
+
+	s.fixture = newPackageDeliveryFixture(&s.WorkflowTestSuite, newHistoryLimitsConfig())
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).Return(nil)
+
+	f.execute(params)
+
+	result, err := f.result()
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryNotified, result.Status)
+	s.Equal(1, result.ContinuedAsNew)
+	s.Require().Len(result.Attempts, 3)
+	s.Equal(3, result.Attempts[2].Number)
+	s.Equal("driver-3", result.Confirmation.ConfirmedBy)
+	s.Equal(2, result.FailedAttempts)
+
+	// The customer already has the link sent by the first run.
+	s.Zero(f.confirmationRequests)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestContinuedRunWaitsForRestOfReattemptDelay() {
+	params := s.runUntilContinuedAsNew()
+	s.Empty(params.Continued.State.PendingAttempts)
+
+	s.fixture = newPackageDeliveryFixture(&s.WorkflowTestSuite, newHistoryLimitsConfig())
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).Return(nil)
+
+	nextAttemptAt := *params.Continued.Result.NextAttemptAt
+	f.env.SetStartTime(nextAttemptAt.Add(-time.Hour))
+
+	var waiting, due model.PackageDeliveryState
+	f.queryStatusAfter(30*time.Minute, &waiting)
+	f.queryStatusAfter(90*time.Minute, &due)
+	f.confirmAfter(2 * time.Hour)
+
+	f.execute(params)
+
+	s.Equal(model.PackageDeliveryAttemptFailed, waiting)
+	s.Equal(model.PackageDeliveryInProgress, due)
+
+	result, err := f.result()
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryNotified, result.Status)
+	s.Nil(result.NextAttemptAt)
+}
@@ -0,0 +1,121 @@
+package workflow
+
+import (
+	"go-test/internal/activities"
+	"go-test/internal/model"
+	"go.temporal.io/sdk/workflow"
+	"go.uber.org/zap"
+	"time"
+)
+
+// customerPreferences loads the notification preferences of the customer.
+// Workflows started before customers had preferences get nil, as do failed
+// lookups, so that a notification is never lost to them.
+func (c *PackageDeliveryWorkflowConfig) customerPreferences(w *PackageDeliveryWorkflow) *model.CustomerPreferences {
+	if !hasChange(w.Ctx, changeCustomerPreferences) {
+		return nil
+	}
+
+	ctx := c.activityContext(w, activities.LoadCustomerPreferencesActivityName)
+
+	preferences, err := loadCustomerPreferences(w.Ctx, ctx, w.Package.CustomerEmail)
+	if err != nil {
+		c.Logger.Warn("Failed to load customer preferences", zap.String("packageId", w.Package.ID), zap.Error(err))
+		return nil
+	}
+
+	return preferences
+}
+
+// loadCustomerPreferences runs the activity loading the preferences of the
+// customer with the options of activityCtx.
+func loadCustomerPreferences(ctx workflow.Context, activityCtx workflow.Context, email string) (*model.CustomerPreferences, error) {
+	var preferences model.CustomerPreferences
+	err := workflow.ExecuteActivity(activityCtx, activities.LoadCustomerPreferencesActivityName, &activities.LoadCustomerPreferencesInput{
+		Email: email,
+	}).Get(ctx, &preferences)
+	if err != nil {
+		return nil, err
+	}
+
+	return &preferences, nil
+}
+
+// allowsNotification reports whether the customer wants messages of kind,
+// and records the ones they opted out of in the workflow result.
+func (c *PackageDeliveryWorkflowConfig) allowsNotification(w *PackageDeliveryWorkflow, preferences *model.CustomerPreferences, kind model.NotificationKind) bool {
+	if preferences == nil || preferences.Allows(kind) {
+		return true
+	}
+
+	c.Logger.Info("Skipping notification the customer opted out of", zap.String("packageId", w.Package.ID), zap.String("kind", string(kind)))
+	w.WorkflowResult.OptedOutNotifications = append(w.WorkflowResult.OptedOutNotifications, kind)
+
+	return false
+}
+
+// allowsNotificationNow reports whether to send a notification of kind while
+// the delivery is under way, once the quiet hours of the customer are over.
+// An operator forcing the final status ends the wait, and the notification
+// is no longer sent. Failures only cost the customer the notification.
+func (c *PackageDeliveryWorkflowConfig) allowsNotificationNow(w *PackageDeliveryWorkflow, preferences *model.CustomerPreferences, kind model.NotificationKind) bool {
+	if !c.allowsNotification(w, preferences, kind) {
+		return false
+	}
+
+	due, err := c.awaitQuietHours(w, preferences, func() bool {
+		return w.State.ForcedTransition != nil
+	})
+	if err != nil {
+		c.Logger.Warn("Failed to wait for the end of the quiet hours", zap.String("packageId", w.Package.ID), zap.String("kind", string(kind)), zap.Error(err))
+		return false
+	}
+
+	return due
+}
+
+// awaitQuietHours defers a notification with a timer until the quiet hours
+// of the customer are over. When interrupt is set, the wait ends as soon as
+// it returns true, and the notification is reported as no longer due.
+func (c *PackageDeliveryWorkflowConfig) awaitQuietHours(w *PackageDeliveryWorkflow, preferences *model.CustomerPreferences, interrupt func() bool) (bool, error) {
+	wait := quietHoursDelay(w.Ctx, preferences)
+	if wait <= 0 {
+		return true, nil
+	}
+
+	c.Logger.Info("Deferring notification until the end of the quiet hours", zap.String("packageId", w.Package.ID), zap.Duration("wait", wait))
+
+	if interrupt == nil {
+		return true, workflow.Sleep(w.Ctx, wait)
+	}
+
+	interrupted, err := workflow.AwaitWithTimeout(w.Ctx, wait, interrupt)
+
+	return !interrupted, err
+}
+
+// quietHoursDelay returns how long a notification has to wait for the quiet
+// hours of the customer to end, zero outside of them.
+func quietHoursDelay(ctx workflow.Context, preferences *model.CustomerPreferences) time.Duration {
+	if preferences == nil {
+		return 0
+	}
+
+	now := workflow.Now(ctx)
+	until, quiet := preferences.QuietUntil(now)
+	if !quiet {
+		return 0
+	}
+
+	return until.Sub(now)
+}
+
+// notificationRouting returns the channels and language to notify the
+// customer on, empty when they have no preferences.
+func notificationRouting(preferences *model.CustomerPreferences) model.NotificationRouting {
+	if preferences == nil {
+		return model.NotificationRouting{}
+	}
+
+	return preferences.Routing()
+}
@@ -0,0 +1,190 @@
+package workflow
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"go-test/internal/activities"
+	"go-test/internal/model"
+	"time"
+)
+
+// recordDeliveryNotifications mocks the delivery notification and collects
+// its inputs with the workflow time they were sent at.
+func (f *packageDeliveryFixture) recordDeliveryNotifications(inputs *[]activities.NotifyDeliveryInput, sentAt *[]time.Time) {
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).
+		Return(func(_ context.Context, input *activities.NotifyDeliveryInput) error {
+			*inputs = append(*inputs, *input)
+			*sentAt = append(*sentAt, f.env.Now())
+			return nil
+		}).
+		Maybe()
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestDeliveryNotificationUsesPreferences() {
+	f := s.fixture
+	saveTestPreferences(f.store, &model.CustomerPreferences{
+		Channels: model.NotificationChannels{model.NotificationChannelSMS},
+		Language: "de",
+	})
+
+	var inputs []activities.NotifyDeliveryInput
+	var sentAt []time.Time
+	f.recordDeliveryNotifications(&inputs, &sentAt)
+	f.confirmAfter(time.Hour)
+
+	f.execute(newTestParams())
+
+	result, err := f.result()
+	s.Require().NoError(err)
+	s.Equal(model.PackageDeliveryNotified, result.Status)
+	s.Require().Len(inputs, 1)
+	s.Require().NotNil(inputs[0].Preferences)
+	s.Equal(model.NotificationChannels{model.NotificationChannelSMS}, inputs[0].Preferences.Channels)
+	s.Equal("de", inputs[0].Preferences.Language)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestDeliveryNotificationDefaultsWithoutPreferences() {
+	f := s.fixture
+
+	var inputs []activities.NotifyDeliveryInput
+	var sentAt []time.Time
+	f.recordDeliveryNotifications(&inputs, &sentAt)
+	f.confirmAfter(time.Hour)
+
+	f.execute(newTestParams())
+
+	_, err := f.result()
+	s.Require().NoError(err)
+	s.Require().Len(inputs, 1)
+	s.Require().NotNil(inputs[0].Preferences)
+	s.Equal(model.NotificationChannels{model.NotificationChannelEmail}, inputs[0].Preferences.Channels)
+	s.Equal(model.DefaultNotificationLanguage, inputs[0].Preferences.Language)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestDeliveryNotificationSkippedOnOptOut() {
+	f := s.fixture
+	saveTestPreferences(f.store, &model.CustomerPreferences{OptOutDelivered: true})
+
+	var inputs []activities.NotifyDeliveryInput
+	var sentAt []time.Time
+	f.recordDeliveryNotifications(&inputs, &sentAt)
+	f.confirmAfter(time.Hour)
+
+	f.execute(newTestParams())
+
+	result, err := f.result()
+	s.Require().NoError(err)
+	s.Equal(model.PackageDeliverySaved, result.Status, "the customer was not notified")
+	s.Empty(inputs)
+	s.Equal([]model.NotificationKind{model.NotificationDelivered}, result.OptedOutNotifications)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestDeliveryNotificationDeferredDuringQuietHours() {
+	f := s.fixture
+	saveTestPreferences(f.store, &model.CustomerPreferences{
+		TimeZone:        "Europe/Berlin",
+		QuietHoursStart: "22:00",
+		QuietHoursEnd:   "07:00",
+	})
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	s.Require().NoError(err)
+	f.env.SetStartTime(time.Date(2026, 10, 1, 21, 0, 0, 0, berlin))
+
+	var inputs []activities.NotifyDeliveryInput
+	var sentAt []time.Time
+	f.recordDeliveryNotifications(&inputs, &sentAt)
+	f.confirmAfter(2 * time.Hour)
+
+	f.execute(newTestParams())
+
+	_, err = f.result()
+	s.Require().NoError(err)
+	s.Require().Len(sentAt, 1)
+	s.True(sentAt[0].Equal(time.Date(2026, 10, 2, 7, 0, 0, 0, berlin)), "sent at %s", sentAt[0])
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestReturnToSenderNotifiedDespiteOptOut() {
+	s.fixture = newPackageDeliveryFixture(&s.WorkflowTestSuite, newAttemptsConfig(time.Hour, 2))
+	f := s.fixture
+	saveTestPreferences(f.store, &model.CustomerPreferences{OptOutAll: true})
+	f.attemptAfter(time.Minute, model.AttemptNobodyHome)
+	f.attemptAfter(2*time.Hour, model.AttemptRefused)
+
+	f.execute(newTestParams())
+
+	result, err := f.result()
+	s.Require().NoError(err)
+	s.Equal(model.PackageDeliveryReturnedToSender, result.Status)
+	s.Equal([]model.NotificationKind{model.NotificationConfirmationRequest, model.NotificationFailedAttempt}, result.OptedOutNotifications)
+	s.Zero(f.confirmationRequests)
+
+	s.Require().Len(f.failedAttemptNotifications, 1)
+	s.True(f.failedAttemptNotifications[0].ReturnToSender)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestArrivalNotificationSkippedOnOptOut() {
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).Return(nil)
+	f.env.SetStartTime(testWindowStartedAt)
+	saveTestPreferences(f.store, &model.CustomerPreferences{OptOutArrival: true})
+	f.confirmAfter(6 * time.Hour)
+
+	f.execute(newTestWindowParams())
+
+	result, err := f.result()
+	s.Require().NoError(err)
+	s.Equal(model.PackageDeliveryNotified, result.Status)
+	s.Empty(f.arrivalNotifications)
+	s.Equal(1, f.confirmationRequests)
+	s.Equal([]model.NotificationKind{model.NotificationArrival}, result.OptedOutNotifications)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestFailedAttemptNotificationDeferredDuringQuietHours() {
+	s.fixture = newPackageDeliveryFixture(&s.WorkflowTestSuite, newAttemptsConfig(24*time.Hour, 3))
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).Return(nil)
+	saveTestPreferences(f.store, &model.CustomerPreferences{
+		TimeZone:        "Europe/Berlin",
+		QuietHoursStart: "22:00",
+		QuietHoursEnd:   "07:00",
+	})
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	s.Require().NoError(err)
+	f.env.SetStartTime(time.Date(2026, 10, 1, 21, 0, 0, 0, berlin))
+
+	f.attemptAfter(90*time.Minute, model.AttemptNobodyHome)
+	f.confirmAfter(20 * time.Hour)
+
+	f.execute(newTestParams())
+
+	_, err = f.result()
+	s.Require().NoError(err)
+	s.Equal(1, f.confirmationRequests)
+	s.Require().Len(f.failedAttemptNotifiedAt, 1)
+	s.True(f.failedAttemptNotifiedAt[0].Equal(time.Date(2026, 10, 2, 7, 0, 0, 0, berlin)), "sent at %s", f.failedAttemptNotifiedAt[0])
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestConfirmationRequestDeferredDuringQuietHours() {
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).Return(nil)
+	saveTestPreferences(f.store, &model.CustomerPreferences{
+		TimeZone:        "Europe/Berlin",
+		QuietHoursStart: "22:00",
+		QuietHoursEnd:   "07:00",
+	})
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	s.Require().NoError(err)
+	f.env.SetStartTime(time.Date(2026, 10, 1, 23, 0, 0, 0, berlin))
+
+	f.confirmAfter(10 * time.Hour)
+
+	f.execute(newTestParams())
+
+	_, err = f.result()
+	s.Require().NoError(err)
+	s.Require().Len(f.confirmationRequestedAt, 1)
+	s.True(f.confirmationRequestedAt[0].Equal(time.Date(2026, 10, 2, 7, 0, 0, 0, berlin)), "sent at %s", f.confirmationRequestedAt[0])
+}
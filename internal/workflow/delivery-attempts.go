@@ -0,0 +1,179 @@
+package workflow
+
+import (
+	"go-test/internal/activities"
+	"go-test/internal/config"
+	"go-test/internal/model"
+	"go.temporal.io/sdk/workflow"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	DefaultReattemptDelay    = 24 * time.Hour
+	DefaultMaxFailedAttempts = 3
+)
+
+func withAttemptDefaults(cfg config.DeliveryAttemptsConfig) config.DeliveryAttemptsConfig {
+	if cfg.ReattemptDelay <= 0 {
+		cfg.ReattemptDelay = config.Duration(DefaultReattemptDelay)
+	}
+	if cfg.MaxFailedAttempts <= 0 {
+		cfg.MaxFailedAttempts = DefaultMaxFailedAttempts
+	}
+
+	return cfg
+}
+
+// attemptPolicy returns the delivery attempts policy of the workflow,
+// recording the configured one on first use.
+func (c *PackageDeliveryWorkflowConfig) attemptPolicy(w *PackageDeliveryWorkflow) config.DeliveryAttemptsConfig {
+	if w.State.AttemptPolicy == nil {
+		var policy config.DeliveryAttemptsConfig
+		err := workflow.SideEffect(w.Ctx, func(workflow.Context) interface{} {
+			return c.DeliveryAttempts
+		}).Get(&policy)
+		if err != nil {
+			policy = withAttemptDefaults(config.DeliveryAttemptsConfig{})
+		}
+		w.State.AttemptPolicy = &policy
+	}
+
+	return *w.State.AttemptPolicy
+}
+
+// handleAttempt records the next pending delivery attempt. A delivered
+// attempt confirms the delivery. A failed one schedules a reattempt or,
+// once the policy's limit is reached, returns the package to the sender,
+// which ends the delivery.
+func (c *PackageDeliveryWorkflowConfig) handleAttempt(w *PackageDeliveryWorkflow) (bool, error) {
+	attempt := w.State.PendingAttempts[0]
+	w.State.PendingAttempts = w.State.PendingAttempts[1:]
+
+	// A confirmation or dispute accepted while the attempt was queued wins.
+	if w.State.Confirmation != nil || w.State.Dispute != nil {
+		c.Logger.Info("Dropping delivery attempt of a confirmed or disputed package", zap.String("packageId", w.Package.ID))
+		return false, nil
+	}
+
+	attempt.Number = len(w.WorkflowResult.Attempts) + 1
+	w.WorkflowResult.Attempts = append(w.WorkflowResult.Attempts, attempt)
+	w.WorkflowResult.NextAttemptAt = nil
+
+	recordCtx := c.activityContext(w, activities.RecordAttemptActivityName)
+
+	err := workflow.ExecuteActivity(recordCtx, activities.RecordAttemptActivityName, &activities.RecordAttemptInput{
+		PackageID: w.Package.ID,
+		Attempt:   attempt,
+	}).Get(w.Ctx, nil)
+	if err != nil {
+		// The attempt is still part of the workflow result.
+		c.Logger.Error("Failed to record delivery attempt", zap.String("packageId", w.Package.ID), zap.Int("number", attempt.Number), zap.Error(err))
+	}
+
+	if !attempt.Failed() {
+		w.State.Confirmation = &model.DeliveryConfirmation{
+			ConfirmedBy: attempt.Driver,
+			ConfirmedAt: attempt.AttemptedAt,
+			Channel:     model.ConfirmationChannelDriver,
+		}
+		w.WorkflowResult.Confirmation = w.State.Confirmation
+
+		return false, nil
+	}
+
+	w.WorkflowResult.FailedAttempts++
+	policy := c.attemptPolicy(w)
+
+	if w.WorkflowResult.FailedAttempts >= policy.MaxFailedAttempts {
+		c.notifyFailedAttempt(w, attempt, nil)
+
+		return true, c.returnToSender(w)
+	}
+
+	delay := policy.ReattemptDelay.Duration()
+	nextAttemptAt := workflow.Now(w.Ctx).Add(delay)
+
+	c.setStatus(w, model.PackageDeliveryAttemptFailed)
+	w.WorkflowResult.NextAttemptAt = &nextAttemptAt
+	c.notifyFailedAttempt(w, attempt, &nextAttemptAt)
+
+	return false, nil
+}
+
+// awaitReattempt waits for the reattempt scheduled after a failed attempt,
+// unless the customer confirms or disputes, the driver reports an attempt
+// earlier, or the workflow has to continue as new. A continued run waits
+// for the rest of the delay.
+func (c *PackageDeliveryWorkflowConfig) awaitReattempt(w *PackageDeliveryWorkflow) error {
+	if w.WorkflowResult.Status != model.PackageDeliveryAttemptFailed || w.WorkflowResult.NextAttemptAt == nil {
+		return nil
+	}
+
+	if remaining := w.WorkflowResult.NextAttemptAt.Sub(workflow.Now(w.Ctx)); remaining > 0 {
+		acted, err := workflow.AwaitWithTimeout(w.Ctx, remaining, func() bool {
+			return w.State.NeedsAction() || c.shouldContinueAsNew(w)
+		})
+		if err != nil || acted {
+			return err
+		}
+	}
+
+	c.setStatus(w, model.PackageDeliveryInProgress)
+	w.WorkflowResult.NextAttemptAt = nil
+
+	return nil
+}
+
+// notifyFailedAttempt tells the customer about a failed attempt. Without
+// nextAttemptAt, the package goes back to the sender.
+func (c *PackageDeliveryWorkflowConfig) notifyFailedAttempt(w *PackageDeliveryWorkflow, attempt model.DeliveryAttempt, nextAttemptAt *time.Time) {
+	kind := model.NotificationFailedAttempt
+	if nextAttemptAt == nil {
+		kind = model.NotificationReturnedToSender
+	}
+
+	preferences := c.customerPreferences(w)
+	if hasChange(w.Ctx, changeQuietHoursEverywhere) {
+		if !c.allowsNotificationNow(w, preferences, kind) {
+			return
+		}
+	} else if !c.allowsNotification(w, preferences, kind) {
+		return
+	}
+
+	ctx := c.activityContext(w, activities.NotifyFailedAttemptActivityName)
+
+	err := workflow.ExecuteActivity(ctx, activities.NotifyFailedAttemptActivityName, &activities.NotifyFailedAttemptInput{
+		Notification: model.FailedAttemptNotification{
+			DeliveryPackage:     w.Package,
+			Attempt:             attempt,
+			NextAttemptAt:       nextAttemptAt,
+			ReturnToSender:      nextAttemptAt == nil,
+			NotificationRouting: notificationRouting(preferences),
+		},
+	}).Get(w.Ctx, nil)
+	if err != nil {
+		c.Logger.Warn("Failed to notify failed delivery attempt", zap.String("packageId", w.Package.ID), zap.Error(err))
+	}
+}
+
+func (c *PackageDeliveryWorkflowConfig) returnToSender(w *PackageDeliveryWorkflow) error {
+	c.Logger.Info("Returning package to sender", zap.String("packageId", w.Package.ID), zap.Int("failedAttempts", w.WorkflowResult.FailedAttempts))
+
+	ctx := c.activityContext(w, activities.ReturnToSenderActivityName)
+
+	err := workflow.ExecuteActivity(ctx, activities.ReturnToSenderActivityName, &activities.ReturnToSenderInput{
+		DeliveryPackage: w.Package,
+	}).Get(w.Ctx, nil)
+	if err != nil {
+		c.Logger.Error("Failed to return package to sender", zap.String("packageId", w.Package.ID), zap.Error(err))
+		c.setStatus(w, model.PackageDeliveryErrored)
+
+		return err
+	}
+
+	c.setStatus(w, model.PackageDeliveryReturnedToSender)
+
+	return nil
+}
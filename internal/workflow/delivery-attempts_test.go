@@ -0,0 +1,138 @@
+package workflow
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"go-test/internal/activities"
+	"go-test/internal/config"
+	"go-test/internal/model"
+	"time"
+)
+
+func newAttemptsConfig(delay time.Duration, maxFailed int) config.WorkflowConfig {
+	return config.WorkflowConfig{
+		DeliveryAttempts: config.DeliveryAttemptsConfig{
+			ReattemptDelay:    config.Duration(delay),
+			MaxFailedAttempts: maxFailed,
+		},
+	}
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestDeliveredAttemptConfirmsDelivery() {
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).Return(nil)
+	f.attemptAfter(time.Hour, model.AttemptDelivered)
+
+	f.execute(newTestParams())
+
+	result, err := f.result()
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryNotified, result.Status)
+	s.Equal("driver-1", result.Confirmation.ConfirmedBy)
+	s.Equal(model.ConfirmationChannelDriver, result.Confirmation.Channel)
+	s.Len(result.Attempts, 1)
+	s.Zero(result.FailedAttempts)
+
+	attempts, err := f.store.ListDeliveryAttempts(context.Background(), testPackageID)
+	s.NoError(err)
+	s.Len(attempts, 1)
+	s.Equal(model.AttemptDelivered, attempts[0].Outcome)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestFailedAttemptSchedulesReattempt() {
+	s.fixture = newPackageDeliveryFixture(&s.WorkflowTestSuite, newAttemptsConfig(4*time.Hour, 3))
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).Return(nil)
+
+	var waiting, due PackageDeliveryWorkflowResult
+	f.attemptAfter(time.Hour, model.AttemptNobodyHome)
+	f.env.RegisterDelayedCallback(func() {
+		value, err := f.env.QueryWorkflow(PackageDeliveryStateQuery)
+		s.NoError(err)
+		s.NoError(value.Get(&waiting))
+	}, 2*time.Hour)
+	f.env.RegisterDelayedCallback(func() {
+		value, err := f.env.QueryWorkflow(PackageDeliveryStateQuery)
+		s.NoError(err)
+		s.NoError(value.Get(&due))
+	}, 6*time.Hour)
+	f.attemptAfter(7*time.Hour, model.AttemptDelivered)
+
+	f.execute(newTestParams())
+
+	s.Equal(model.PackageDeliveryAttemptFailed, waiting.Status)
+	s.Require().NotNil(waiting.NextAttemptAt)
+	s.Equal(4*time.Hour, waiting.NextAttemptAt.Sub(waiting.Attempts[0].AttemptedAt).Round(time.Minute))
+	s.Equal(model.PackageDeliveryInProgress, due.Status)
+	s.Nil(due.NextAttemptAt)
+
+	result, err := f.result()
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryNotified, result.Status)
+	s.Len(result.Attempts, 2)
+	s.Equal(2, result.Attempts[1].Number)
+	s.Equal(1, result.FailedAttempts)
+
+	s.Len(f.failedAttemptNotifications, 1)
+	s.False(f.failedAttemptNotifications[0].ReturnToSender)
+	s.NotNil(f.failedAttemptNotifications[0].NextAttemptAt)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestReturnToSenderAfterMaxFailedAttempts() {
+	s.fixture = newPackageDeliveryFixture(&s.WorkflowTestSuite, newAttemptsConfig(time.Hour, 2))
+	f := s.fixture
+	f.attemptAfter(time.Minute, model.AttemptNobodyHome)
+	f.attemptAfter(2*time.Hour, model.AttemptRefused)
+
+	f.execute(newTestParams())
+
+	result, err := f.result()
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryReturnedToSender, result.Status)
+	s.Equal(2, result.FailedAttempts)
+	s.Nil(result.Confirmation)
+
+	s.Len(f.failedAttemptNotifications, 2)
+	s.True(f.failedAttemptNotifications[1].ReturnToSender)
+	s.Nil(f.failedAttemptNotifications[1].NextAttemptAt)
+
+	stored, err := f.store.GetPackageDelivery(context.Background(), testPackageID)
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryReturnedToSender, stored.Status)
+
+	attempts, err := f.store.ListDeliveryAttempts(context.Background(), testPackageID)
+	s.NoError(err)
+	s.Len(attempts, 2)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestConfirmationDuringReattemptDelay() {
+	s.fixture = newPackageDeliveryFixture(&s.WorkflowTestSuite, newAttemptsConfig(24*time.Hour, 3))
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).Return(nil)
+	f.attemptAfter(time.Minute, model.AttemptNobodyHome)
+	f.confirmAfter(time.Hour)
+	f.attemptAfter(2*time.Hour, model.AttemptNobodyHome)
+
+	f.execute(newTestParams())
+
+	result, err := f.result()
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryNotified, result.Status)
+	s.Equal("customer@example.com", result.Confirmation.ConfirmedBy)
+	s.Len(result.Attempts, 1, "attempts after the confirmation are ignored")
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestInvalidAttemptsAreIgnored() {
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).Return(nil)
+	signalAfter(f.env, time.Minute, PackageDeliverySignalAttempt, &model.DeliveryAttempt{Outcome: "lost", Driver: "driver-1", AttemptedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)})
+	signalAfter(f.env, 2*time.Minute, PackageDeliverySignalAttempt, &model.DeliveryAttempt{Outcome: model.AttemptRefused, AttemptedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)})
+	f.confirmAfter(time.Hour)
+
+	f.execute(newTestParams())
+
+	result, err := f.result()
+	s.NoError(err)
+	s.Empty(result.Attempts)
+	s.Empty(f.failedAttemptNotifications)
+}
@@ -0,0 +1,200 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-test/internal/activities"
+	"go-test/internal/config"
+	"go-test/internal/model"
+	"go.temporal.io/api/common/v1"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/workflow"
+	"go.uber.org/zap"
+	"net/mail"
+	"time"
+)
+
+const (
+	DeliveryReportWorkflowName = "delivery-report-workflow"
+	DeliveryReportScheduleID   = "delivery-report"
+)
+
+const (
+	// DeliveryReportPeriod is the time covered by one report, up to the
+	// time it runs.
+	DeliveryReportPeriod = 24 * time.Hour
+	// DefaultDeliveryReportTime is used when no time is configured.
+	DefaultDeliveryReportTime = "06:00"
+)
+
+var ErrInvalidReportSchedule = errors.New("invalid delivery report schedule")
+
+type DeliveryReportParams struct {
+	Recipients []string
+}
+
+// DeliveryReportSchedule is the part of the delivery report schedule
+// operators can change.
+type DeliveryReportSchedule struct {
+	// Time is the time of day the report runs at, as HH:MM in TimeZone.
+	Time string `json:"time"`
+	// TimeZone is an IANA time zone name.
+	TimeZone   string   `json:"time_zone"`
+	Recipients []string `json:"recipients"`
+}
+
+// Validate checks the time, time zone and recipients, and fills in UTC when
+// no time zone is set.
+func (s *DeliveryReportSchedule) Validate() error {
+	if _, err := time.Parse("15:04", s.Time); err != nil {
+		return fmt.Errorf("%w: time %q is not HH:MM", ErrInvalidReportSchedule, s.Time)
+	}
+
+	if s.TimeZone == "" {
+		s.TimeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(s.TimeZone); err != nil {
+		return fmt.Errorf("%w: unknown time zone %q", ErrInvalidReportSchedule, s.TimeZone)
+	}
+
+	for _, recipient := range s.Recipients {
+		if _, err := mail.ParseAddress(recipient); err != nil {
+			return fmt.Errorf("%w: invalid recipient %q", ErrInvalidReportSchedule, recipient)
+		}
+	}
+
+	return nil
+}
+
+func (s DeliveryReportSchedule) spec() client.ScheduleSpec {
+	at, _ := time.Parse("15:04", s.Time)
+
+	return client.ScheduleSpec{
+		Calendars: []client.ScheduleCalendarSpec{{
+			Second: []client.ScheduleRange{{Start: 0}},
+			Minute: []client.ScheduleRange{{Start: at.Minute()}},
+			Hour:   []client.ScheduleRange{{Start: at.Hour()}},
+		}},
+		TimeZoneName: s.TimeZone,
+	}
+}
+
+func (s DeliveryReportSchedule) action() *client.ScheduleWorkflowAction {
+	return &client.ScheduleWorkflowAction{
+		ID:        DeliveryReportWorkflowName,
+		Workflow:  DeliveryReportWorkflowName,
+		TaskQueue: PackageDeliveryTaskQueueName,
+		Args:      []interface{}{&DeliveryReportParams{Recipients: s.Recipients}},
+	}
+}
+
+// DeliveryReportWorkflow summarizes the packages of the period up to the
+// time it runs and sends the report to the recipients. It is started by the
+// delivery report schedule.
+func (c *PackageDeliveryWorkflowConfig) DeliveryReportWorkflow(ctx workflow.Context, params *DeliveryReportParams) (*model.DeliverySummary, error) {
+	to := workflow.Now(ctx).UTC().Truncate(time.Minute)
+
+	var summary model.DeliverySummary
+
+	summarizeCtx := c.activityOptions(ctx, activities.SummarizeDeliveriesActivityName, nil)
+	err := workflow.ExecuteActivity(summarizeCtx, activities.SummarizeDeliveriesActivityName, &activities.SummarizeDeliveriesInput{
+		From: to.Add(-DeliveryReportPeriod),
+		To:   to,
+	}).Get(ctx, &summary)
+	if err != nil {
+		c.Logger.Error("Failed to summarize deliveries", zap.Error(err))
+		return nil, err
+	}
+
+	if params == nil || len(params.Recipients) == 0 {
+		c.Logger.Warn("Delivery report has no recipients")
+		return &summary, nil
+	}
+
+	sendCtx := c.activityOptions(ctx, activities.SendDeliveryReportActivityName, nil)
+	err = workflow.ExecuteActivity(sendCtx, activities.SendDeliveryReportActivityName, &activities.SendDeliveryReportInput{
+		Summary:    summary,
+		Recipients: params.Recipients,
+	}).Get(ctx, nil)
+	if err != nil {
+		c.Logger.Error("Failed to send delivery report", zap.Error(err))
+		return nil, err
+	}
+
+	return &summary, nil
+}
+
+// EnsureDeliveryReportSchedule creates the daily delivery report schedule
+// from the configuration. An existing schedule is left as is, since
+// operators may have changed it since.
+func EnsureDeliveryReportSchedule(ctx context.Context, c client.Client, cfg config.DeliveryReportConfig) error {
+	schedule := DeliveryReportSchedule{Time: cfg.Time, TimeZone: cfg.TimeZone, Recipients: cfg.Recipients}
+	if schedule.Time == "" {
+		schedule.Time = DefaultDeliveryReportTime
+	}
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+
+	_, err := createSchedule(ctx, c, client.ScheduleOptions{
+		ID:      DeliveryReportScheduleID,
+		Spec:    schedule.spec(),
+		Action:  schedule.action(),
+		Overlap: enums.SCHEDULE_OVERLAP_POLICY_SKIP,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to schedule the delivery report: %w", err)
+	}
+
+	return nil
+}
+
+// GetDeliveryReportSchedule reads the time and recipients back from the
+// delivery report schedule.
+func GetDeliveryReportSchedule(ctx context.Context, c client.Client) (*DeliveryReportSchedule, error) {
+	description, err := c.ScheduleClient().GetHandle(ctx, DeliveryReportScheduleID).Describe(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to describe the delivery report schedule: %w", err)
+	}
+
+	schedule := &DeliveryReportSchedule{Recipients: []string{}}
+
+	if spec := description.Schedule.Spec; spec != nil {
+		schedule.TimeZone = spec.TimeZoneName
+		if len(spec.Calendars) > 0 && len(spec.Calendars[0].Hour) > 0 && len(spec.Calendars[0].Minute) > 0 {
+			calendar := spec.Calendars[0]
+			schedule.Time = fmt.Sprintf("%02d:%02d", calendar.Hour[0].Start, calendar.Minute[0].Start)
+		}
+	}
+
+	if action, ok := description.Schedule.Action.(*client.ScheduleWorkflowAction); ok && len(action.Args) > 0 {
+		var params DeliveryReportParams
+		if payload, ok := action.Args[0].(*common.Payload); ok {
+			if err := converter.GetDefaultDataConverter().FromPayload(payload, &params); err != nil {
+				return nil, fmt.Errorf("unable to decode the delivery report params: %w", err)
+			}
+		}
+		if params.Recipients != nil {
+			schedule.Recipients = params.Recipients
+		}
+	}
+
+	return schedule, nil
+}
+
+// UpdateDeliveryReportSchedule validates the schedule and applies it to the
+// delivery report schedule.
+func UpdateDeliveryReportSchedule(ctx context.Context, c client.Client, schedule DeliveryReportSchedule) (*DeliveryReportSchedule, error) {
+	if err := schedule.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := updateSchedule(ctx, c, DeliveryReportScheduleID, schedule.spec(), schedule.action()); err != nil {
+		return nil, err
+	}
+
+	return &schedule, nil
+}
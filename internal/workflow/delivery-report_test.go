@@ -0,0 +1,169 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go-test/internal/activities"
+	"go-test/internal/config"
+	"go-test/internal/model"
+	"go-test/repository"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func (s *PackageDeliveryWorkflowTestSuite) TestRecordsPackageEvents() {
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).Return(nil)
+
+	startedAt := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	f.env.SetStartTime(startedAt)
+	f.confirmAfter(2 * time.Hour)
+
+	f.execute(newTestParams())
+
+	_, err := f.result()
+	s.Require().NoError(err)
+
+	summary, err := f.store.SummarizeDeliveries(context.Background(), startedAt, startedAt.Add(DeliveryReportPeriod))
+	s.Require().NoError(err)
+	s.Equal(int64(1), summary.Created)
+	s.Equal(int64(1), summary.Confirmed)
+	s.Zero(summary.Errored)
+	s.Require().NotNil(summary.MedianTimeToConfirm)
+	s.Equal(2*time.Hour, *summary.MedianTimeToConfirm)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestRecordsErroredPackageEvent() {
+	f := s.fixture
+
+	startedAt := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	f.env.SetStartTime(startedAt)
+	f.env.OnActivity(activities.SaveDeliveryActivityName, mock.Anything, mock.Anything).Return(nil, errors.New("database unavailable"))
+	f.confirmAfter(time.Hour)
+
+	f.execute(newTestParams())
+
+	summary, err := f.store.SummarizeDeliveries(context.Background(), startedAt, startedAt.Add(DeliveryReportPeriod))
+	s.Require().NoError(err)
+	s.Equal(int64(1), summary.Created)
+	s.Equal(int64(1), summary.Confirmed)
+	s.Equal(int64(1), summary.Errored)
+}
+
+// DeliveryReportWorkflowTestSuite runs the delivery report workflow over
+// the package events of an in-memory store and collects the reports it
+// sends.
+type DeliveryReportWorkflowTestSuite struct {
+	suite.Suite
+	testsuite.WorkflowTestSuite
+
+	env   *testsuite.TestWorkflowEnvironment
+	store *repository.MemoryRepository
+
+	// reports collects the delivery reports sent to recipients.
+	reports []activities.SendDeliveryReportInput
+}
+
+func TestDeliveryReportWorkflow(t *testing.T) {
+	suite.Run(t, new(DeliveryReportWorkflowTestSuite))
+}
+
+func (s *DeliveryReportWorkflowTestSuite) SetupTest() {
+	s.env = s.NewTestWorkflowEnvironment()
+	s.store = repository.NewMemoryRepository()
+	s.reports = nil
+
+	workflowConfig := NewPackageDeliveryWorkflowConfig(zap.NewNop(), config.WorkflowConfig{})
+	s.env.RegisterWorkflowWithOptions(workflowConfig.DeliveryReportWorkflow, workflow.RegisterOptions{
+		Name: DeliveryReportWorkflowName,
+	})
+
+	registerActivities(s.env, testActivities{store: s.store},
+		activities.SummarizeDeliveriesActivityName,
+		activities.SendDeliveryReportActivityName,
+	)
+
+	s.env.OnActivity(activities.SendDeliveryReportActivityName, mock.Anything, mock.Anything).
+		Return(func(_ context.Context, input *activities.SendDeliveryReportInput) error {
+			s.reports = append(s.reports, *input)
+			return nil
+		}).
+		Maybe()
+}
+
+func (s *DeliveryReportWorkflowTestSuite) AfterTest(_, _ string) {
+	s.env.AssertExpectations(s.T())
+}
+
+func (s *DeliveryReportWorkflowTestSuite) TestSendsSummary() {
+	ranAt := time.Date(2026, 10, 2, 6, 0, 20, 0, time.UTC)
+	s.env.SetStartTime(ranAt)
+
+	ctx := context.Background()
+	from := time.Date(2026, 10, 1, 6, 0, 0, 0, time.UTC)
+	for _, event := range []model.PackageEvent{
+		{PackageID: "pkg-1", Event: model.PackageEventCreated, OccurredAt: from.Add(-time.Hour)},
+		{PackageID: "pkg-1", Event: model.PackageEventConfirmed, OccurredAt: from.Add(time.Hour)},
+		{PackageID: "pkg-2", Event: model.PackageEventCreated, OccurredAt: from.Add(2 * time.Hour)},
+		{PackageID: "pkg-3", Event: model.PackageEventCreated, OccurredAt: from.Add(-time.Minute)},
+	} {
+		s.Require().NoError(s.store.RecordPackageEvent(ctx, &event))
+	}
+
+	s.env.ExecuteWorkflow(DeliveryReportWorkflowName, &DeliveryReportParams{Recipients: []string{"management@example.com"}})
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	s.Require().Len(s.reports, 1)
+	report := s.reports[0]
+	s.Equal([]string{"management@example.com"}, report.Recipients)
+	s.True(report.Summary.From.Equal(from))
+	s.True(report.Summary.To.Equal(from.Add(DeliveryReportPeriod)))
+	s.Equal(int64(1), report.Summary.Created)
+	s.Equal(int64(1), report.Summary.Confirmed)
+	s.Require().NotNil(report.Summary.MedianTimeToConfirm)
+	s.Equal(2*time.Hour, *report.Summary.MedianTimeToConfirm)
+}
+
+func (s *DeliveryReportWorkflowTestSuite) TestWithoutRecipients() {
+	s.env.ExecuteWorkflow(DeliveryReportWorkflowName, &DeliveryReportParams{})
+
+	s.NoError(s.env.GetWorkflowError())
+	s.Empty(s.reports)
+
+	var summary model.DeliverySummary
+	s.Require().NoError(s.env.GetWorkflowResult(&summary))
+	s.Zero(summary.Created)
+}
+
+func TestDeliveryReportScheduleValidate(t *testing.T) {
+	schedule := DeliveryReportSchedule{Time: "06:30", Recipients: []string{"management@example.com"}}
+	if err := schedule.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if schedule.TimeZone != "UTC" {
+		t.Errorf("TimeZone = %q, want UTC", schedule.TimeZone)
+	}
+
+	spec := schedule.spec()
+	if len(spec.Calendars) != 1 || spec.Calendars[0].Hour[0].Start != 6 || spec.Calendars[0].Minute[0].Start != 30 {
+		t.Errorf("spec = %+v", spec)
+	}
+
+	for _, invalid := range []DeliveryReportSchedule{
+		{Time: "6am"},
+		{Time: "25:00"},
+		{Time: "06:00", TimeZone: "Mars/Olympus"},
+		{Time: "06:00", Recipients: []string{"not an address"}},
+	} {
+		if err := invalid.Validate(); !errors.Is(err, ErrInvalidReportSchedule) {
+			t.Errorf("Validate(%+v) = %v, want ErrInvalidReportSchedule", invalid, err)
+		}
+	}
+}
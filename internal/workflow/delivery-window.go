@@ -0,0 +1,90 @@
+package workflow
+
+import (
+	"go-test/internal/activities"
+	"go-test/internal/config"
+	"go-test/internal/model"
+	"go.temporal.io/sdk/workflow"
+	"go.uber.org/zap"
+	"time"
+)
+
+// DefaultNoticeLead is used when no notice lead is configured.
+const DefaultNoticeLead = time.Hour
+
+func withDeliveryWindowDefaults(cfg config.DeliveryWindowsConfig) config.DeliveryWindowsConfig {
+	if cfg.NoticeLead <= 0 {
+		cfg.NoticeLead = config.Duration(DefaultNoticeLead)
+	}
+
+	return cfg
+}
+
+// awaitDeliveryWindow holds the package as scheduled until the notice lead
+// before its delivery window, then tells the customer the package arrives
+// soon. It reports whether the confirmation window may open, which is not
+// the case once an operator forced a transition during the wait.
+func (c *PackageDeliveryWorkflowConfig) awaitDeliveryWindow(w *PackageDeliveryWorkflow) (bool, error) {
+	window := *w.Package.DeliveryWindow
+
+	var lead config.Duration
+	err := workflow.SideEffect(w.Ctx, func(workflow.Context) interface{} {
+		return c.DeliveryWindows.NoticeLead
+	}).Get(&lead)
+	if err != nil {
+		lead = config.Duration(DefaultNoticeLead)
+	}
+
+	w.WorkflowResult.DeliveryWindow = &window
+	w.State.AwaitingWindow = true
+	c.setStatus(w, model.PackageDeliveryScheduled)
+
+	if wait := window.Start.Add(-lead.Duration()).Sub(workflow.Now(w.Ctx)); wait > 0 {
+		c.Logger.Info("Waiting for the delivery window",
+			zap.String("packageId", w.Package.ID),
+			zap.Time("windowStart", window.Start),
+			zap.Duration("wait", wait))
+
+		if _, err := workflow.AwaitWithTimeout(w.Ctx, wait, func() bool {
+			return w.State.ForcedTransition != nil
+		}); err != nil {
+			return false, err
+		}
+	}
+
+	w.State.AwaitingWindow = false
+	if w.State.ForcedTransition != nil {
+		return false, nil
+	}
+
+	c.notifyArrival(w, window)
+
+	c.setStatus(w, model.PackageDeliveryInProgress)
+
+	return true, nil
+}
+
+// notifyArrival tells the customer the package arrives soon. A failure only
+// costs the customer the heads-up, they can still confirm the delivery.
+func (c *PackageDeliveryWorkflowConfig) notifyArrival(w *PackageDeliveryWorkflow, window model.DeliveryWindow) {
+	preferences := c.customerPreferences(w)
+	if hasChange(w.Ctx, changeQuietHoursEverywhere) {
+		if !c.allowsNotificationNow(w, preferences, model.NotificationArrival) {
+			return
+		}
+	} else if !c.allowsNotification(w, preferences, model.NotificationArrival) {
+		return
+	}
+
+	ctx := c.activityContext(w, activities.NotifyArrivalActivityName)
+	err := workflow.ExecuteActivity(ctx, activities.NotifyArrivalActivityName, &activities.NotifyArrivalInput{
+		Notification: model.ArrivalNotification{
+			DeliveryPackage:     w.Package,
+			DeliveryWindow:      window,
+			NotificationRouting: notificationRouting(preferences),
+		},
+	}).Get(w.Ctx, nil)
+	if err != nil {
+		c.Logger.Warn("Failed to notify arrival", zap.String("packageId", w.Package.ID), zap.Error(err))
+	}
+}
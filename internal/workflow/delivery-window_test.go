@@ -0,0 +1,108 @@
+package workflow
+
+import (
+	"github.com/stretchr/testify/mock"
+	"go-test/internal/activities"
+	"go-test/internal/model"
+	"time"
+)
+
+var testWindowStartedAt = time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+
+// newTestWindowParams returns params for a package delivered between 14:00
+// and 16:00 on the day the workflow starts.
+func newTestWindowParams() *PackageDeliveryWorkflowParams {
+	params := newTestParams()
+	params.DeliveryPackage.DeliveryWindow = &model.DeliveryWindow{
+		Start:    testWindowStartedAt.Add(6 * time.Hour),
+		End:      testWindowStartedAt.Add(8 * time.Hour),
+		TimeZone: "UTC",
+	}
+
+	return params
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestDeliveryWindowNotifiesArrivalBeforeConfirmation() {
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).Return(nil)
+	f.env.SetStartTime(testWindowStartedAt)
+
+	var scheduled, open model.PackageDeliveryState
+	f.queryStatusAfter(time.Hour, &scheduled)
+	f.queryStatusAfter(5*time.Hour+time.Minute, &open)
+	f.env.RegisterDelayedCallback(func() {
+		s.Zero(f.confirmationRequests)
+	}, 4*time.Hour)
+	f.confirmAfter(6 * time.Hour)
+
+	params := newTestWindowParams()
+	f.execute(params)
+
+	result, err := f.result()
+	s.Require().NoError(err)
+	s.Equal(model.PackageDeliveryNotified, result.Status)
+	s.Equal(model.PackageDeliveryScheduled, scheduled)
+	s.Equal(model.PackageDeliveryInProgress, open)
+	s.Equal(1, f.confirmationRequests)
+
+	s.Require().Len(f.arrivalNotifications, 1)
+	s.Equal(testPackageID, f.arrivalNotifications[0].DeliveryPackage.ID)
+	s.True(f.arrivalNotifications[0].DeliveryWindow.Start.Equal(params.DeliveryPackage.DeliveryWindow.Start))
+	s.True(f.arrivalNotifiedAt[0].Equal(testWindowStartedAt.Add(5 * time.Hour)))
+
+	s.Require().NotNil(result.DeliveryWindow)
+	s.True(result.DeliveryWindow.End.Equal(params.DeliveryPackage.DeliveryWindow.End))
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestDeliveryWindowIgnoresEarlyConfirmation() {
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).Return(nil)
+	f.env.SetStartTime(testWindowStartedAt)
+
+	f.confirmAfter(time.Hour)
+	f.confirmAfter(7 * time.Hour)
+
+	f.execute(newTestWindowParams())
+
+	result, err := f.result()
+	s.Require().NoError(err)
+	s.Equal(model.PackageDeliveryNotified, result.Status)
+	s.Zero(result.DuplicateConfirmations)
+	s.Require().NotNil(result.Confirmation)
+	s.True(result.Confirmation.ConfirmedAt.Equal(testWindowStartedAt.Add(7 * time.Hour)))
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestDeliveryWindowStartingSoonOpensAtOnce() {
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).Return(nil)
+	f.env.SetStartTime(testWindowStartedAt)
+	f.confirmAfter(time.Minute)
+
+	params := newTestParams()
+	params.DeliveryPackage.DeliveryWindow = &model.DeliveryWindow{
+		Start:    testWindowStartedAt.Add(30 * time.Minute),
+		End:      testWindowStartedAt.Add(2 * time.Hour),
+		TimeZone: "UTC",
+	}
+	f.execute(params)
+
+	result, err := f.result()
+	s.Require().NoError(err)
+	s.Equal(model.PackageDeliveryNotified, result.Status)
+	s.Len(f.arrivalNotifications, 1)
+	s.True(f.arrivalNotifiedAt[0].Equal(testWindowStartedAt))
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestForcedTransitionEndsDeliveryWindowWait() {
+	f := s.fixture
+	f.env.SetStartTime(testWindowStartedAt)
+	signalAfter(f.env, time.Hour, PackageDeliverySignalForceTransition, newTestTransition(model.PackageDeliveryReturnedToSender))
+
+	f.execute(newTestWindowParams())
+
+	result, err := f.result()
+	s.Require().NoError(err)
+	s.Equal(model.PackageDeliveryReturnedToSender, result.Status)
+	s.Empty(f.arrivalNotifications)
+	s.Zero(f.confirmationRequests)
+}
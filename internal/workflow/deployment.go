@@ -0,0 +1,53 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-test/internal/config"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/worker"
+)
+
+// ApplyWorkerVersioning sets the build ID options of the worker.
+func ApplyWorkerVersioning(options *worker.Options, cfg config.WorkerConfig) error {
+	if cfg.UseBuildIDVersioning && cfg.BuildID == "" {
+		return errors.New("worker build ID versioning requires a build ID")
+	}
+
+	options.BuildID = cfg.BuildID
+	options.UseBuildIDForVersioning = cfg.UseBuildIDVersioning
+
+	return nil
+}
+
+// RegisterBuildID publishes the worker's build ID in the task queue's
+// version sets. A new incompatible build becomes the default for new
+// workflows while workers of older builds keep serving the workflows started
+// on them; a compatible build also takes over running workflows.
+func RegisterBuildID(ctx context.Context, c client.Client, taskQueue string, cfg config.WorkerConfig) error {
+	if !cfg.UseBuildIDVersioning {
+		return nil
+	}
+
+	options := &client.UpdateWorkerBuildIdCompatibilityOptions{
+		TaskQueue: taskQueue,
+		Operation: &client.BuildIDOpAddNewIDInNewDefaultSet{
+			BuildID: cfg.BuildID,
+		},
+	}
+	if cfg.CompatibleWithBuildID != "" {
+		options.Operation = &client.BuildIDOpAddNewCompatibleVersion{
+			BuildID:                   cfg.BuildID,
+			ExistingCompatibleBuildID: cfg.CompatibleWithBuildID,
+			MakeSetDefault:            true,
+		}
+	}
+
+	err := c.UpdateWorkerBuildIdCompatibility(ctx, options)
+	if err != nil {
+		return fmt.Errorf("unable to register build ID %s: %w", cfg.BuildID, err)
+	}
+
+	return nil
+}
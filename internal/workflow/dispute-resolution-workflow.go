@@ -0,0 +1,140 @@
+package workflow
+
+import (
+	"fmt"
+	"go-test/internal/activities"
+	"go-test/internal/model"
+	"go.temporal.io/sdk/workflow"
+	"go.uber.org/zap"
+)
+
+// DisputeWorkflowID returns the ID of the dispute resolution workflow for the
+// n-th dispute of a package. It doubles as the dispute ID.
+func DisputeWorkflowID(packageID string, n int) string {
+	return fmt.Sprintf("%s-dispute-%d", packageID, n)
+}
+
+// DisputeResolutionWorkflow records a dispute, hands it to support and waits
+// for an operator to resolve it.
+func (c *PackageDeliveryWorkflowConfig) DisputeResolutionWorkflow(
+	ctx workflow.Context,
+	params *DisputeResolutionParams,
+) (*model.DisputeOutcome, error) {
+	dispute := params.Dispute
+
+	c.Logger.Info("Starting dispute resolution workflow", zap.String("disputeId", dispute.ID))
+
+	recordCtx := c.activityOptions(ctx, activities.RecordDisputeActivityName, params.ActivityPolicies)
+
+	err := workflow.ExecuteActivity(recordCtx, activities.RecordDisputeActivityName, &activities.RecordDisputeInput{
+		PackageID: params.DeliveryPackage.ID,
+		Dispute:   dispute,
+	}).Get(ctx, nil)
+	if err != nil {
+		c.Logger.Error("Failed to record dispute", zap.String("disputeId", dispute.ID), zap.Error(err))
+
+		return nil, err
+	}
+
+	// The recorded dispute stays visible to operators, so a failed
+	// notification does not hold up the resolution.
+	notifyCtx := c.activityOptions(ctx, activities.NotifySupportActivityName, params.ActivityPolicies)
+
+	err = workflow.ExecuteActivity(notifyCtx, activities.NotifySupportActivityName, &activities.NotifySupportInput{
+		DeliveryPackage: params.DeliveryPackage,
+		Dispute:         dispute,
+	}).Get(ctx, nil)
+	if err != nil {
+		c.Logger.Warn("Failed to notify support", zap.String("disputeId", dispute.ID), zap.Error(err))
+	}
+
+	resolution := c.receiveResolution(ctx, dispute.ID)
+
+	resolveCtx := c.activityOptions(ctx, activities.ResolveDisputeActivityName, params.ActivityPolicies)
+
+	err = workflow.ExecuteActivity(resolveCtx, activities.ResolveDisputeActivityName, &activities.ResolveDisputeInput{
+		DisputeID:  dispute.ID,
+		Resolution: resolution,
+	}).Get(ctx, nil)
+	if err != nil {
+		c.Logger.Error("Failed to resolve dispute", zap.String("disputeId", dispute.ID), zap.Error(err))
+
+		return nil, err
+	}
+
+	return &model.DisputeOutcome{Dispute: dispute, Resolution: resolution}, nil
+}
+
+// receiveResolution blocks until a valid resolution is signalled. Invalid
+// resolutions are logged and dropped.
+func (c *PackageDeliveryWorkflowConfig) receiveResolution(ctx workflow.Context, disputeID string) model.DisputeResolution {
+	ch := workflow.GetSignalChannel(ctx, DisputeResolutionSignalResolve)
+
+	for {
+		var resolution model.DisputeResolution
+		ch.Receive(ctx, &resolution)
+
+		if !resolution.Valid() {
+			c.Logger.Warn("Ignoring invalid dispute resolution", zap.String("disputeId", disputeID), zap.String("action", string(resolution.Action)))
+			continue
+		}
+
+		if resolution.ResolvedAt.IsZero() {
+			resolution.ResolvedAt = workflow.Now(ctx)
+		}
+
+		return resolution
+	}
+}
+
+// resolveDispute runs the resolution workflow for the open dispute and
+// applies its outcome. It reports whether the delivery ends with it.
+func (c *PackageDeliveryWorkflowConfig) resolveDispute(w *PackageDeliveryWorkflow) (bool, error) {
+	dispute := w.State.Dispute
+	// Disputes signalled by workflows started before the ID was assigned on
+	// acceptance carry none.
+	if dispute.ID == "" {
+		dispute.ID = DisputeWorkflowID(w.Package.ID, len(w.WorkflowResult.DisputeOutcomes)+1)
+	}
+
+	w.WorkflowResult.Dispute = dispute
+	c.setStatus(w, model.PackageDeliveryDisputed)
+	c.reportToShipment(w)
+
+	ctx := workflow.WithChildOptions(w.Ctx, workflow.ChildWorkflowOptions{
+		WorkflowID: dispute.ID,
+	})
+
+	var outcome model.DisputeOutcome
+
+	err := workflow.ExecuteChildWorkflow(ctx, DisputeResolutionWorkflowName, &DisputeResolutionParams{
+		DeliveryPackage:  w.Package,
+		Dispute:          *dispute,
+		ActivityPolicies: w.ActivityPolicies,
+	}).Get(ctx, &outcome)
+	if err != nil {
+		c.Logger.Error("Failed to resolve dispute", zap.String("disputeId", dispute.ID), zap.Error(err))
+		c.setStatus(w, model.PackageDeliveryErrored)
+
+		return true, err
+	}
+
+	w.State.Dispute = nil
+	w.WorkflowResult.Dispute = nil
+	w.WorkflowResult.DisputeOutcomes = append(w.WorkflowResult.DisputeOutcomes, outcome)
+
+	switch outcome.Resolution.Action {
+	case model.DisputeRedeliver:
+		c.setStatus(w, model.PackageDeliveryRedelivery)
+		c.reportToShipment(w)
+		c.requestConfirmation(w)
+
+		return false, nil
+	case model.DisputeRefund:
+		c.setStatus(w, model.PackageDeliveryRefunded)
+	default:
+		c.setStatus(w, model.PackageDeliveryDisputeClosed)
+	}
+
+	return true, nil
+}
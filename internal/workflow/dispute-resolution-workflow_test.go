@@ -0,0 +1,140 @@
+package workflow
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"go-test/internal/activities"
+	"go-test/internal/model"
+	"go-test/repository"
+	"time"
+)
+
+func (s *PackageDeliveryWorkflowTestSuite) TestDisputeRefundEndsDelivery() {
+	f := s.fixture
+
+	var whileOpen PackageDeliveryWorkflowResult
+	f.disputeAfter(time.Minute)
+	f.env.RegisterDelayedCallback(func() {
+		value, err := f.env.QueryWorkflow(PackageDeliveryStateQuery)
+		s.NoError(err)
+		s.NoError(value.Get(&whileOpen))
+	}, time.Hour)
+	f.resolveDisputeAfter(2*time.Hour, 1, model.DisputeRefund)
+
+	f.execute(newTestParams())
+
+	result, err := f.result()
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryRefunded, result.Status)
+	s.Nil(result.Dispute)
+	s.Len(result.DisputeOutcomes, 1)
+	s.Equal(model.DisputeRefund, result.DisputeOutcomes[0].Resolution.Action)
+
+	s.Equal(model.PackageDeliveryDisputed, whileOpen.Status)
+	s.Equal(DisputeWorkflowID(testPackageID, 1), whileOpen.Dispute.ID)
+	s.Equal(1, f.supportNotifications)
+
+	stored, err := f.store.GetDispute(context.Background(), DisputeWorkflowID(testPackageID, 1))
+	s.NoError(err)
+	s.Equal(testPackageID, stored.PackageID)
+	s.Equal(model.DisputeRefund, stored.Action)
+	s.NotNil(stored.ResolvedAt)
+
+	_, err = f.store.GetPackageDelivery(context.Background(), testPackageID)
+	s.ErrorIs(err, repository.ErrPackageNotFound, "a refunded package is never saved as delivered")
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestDisputeCloseEndsDelivery() {
+	f := s.fixture
+	f.disputeAfter(time.Minute)
+	f.resolveDisputeAfter(time.Hour, 1, model.DisputeClose)
+
+	f.execute(newTestParams())
+
+	result, err := f.result()
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryDisputeClosed, result.Status)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestRedeliveryWaitsForNewConfirmation() {
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).Return(nil)
+
+	var afterRedelivery model.PackageDeliveryState
+	f.disputeAfter(time.Minute)
+	f.resolveDisputeAfter(time.Hour, 1, model.DisputeRedeliver)
+	f.queryStatusAfter(2*time.Hour, &afterRedelivery)
+	f.disputeAfter(3 * time.Hour)
+	f.resolveDisputeAfter(4*time.Hour, 2, model.DisputeRedeliver)
+	f.confirmAfter(5 * time.Hour)
+
+	f.execute(newTestParams())
+
+	result, err := f.result()
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryRedelivery, afterRedelivery)
+	s.Equal(model.PackageDeliveryNotified, result.Status)
+	s.Len(result.DisputeOutcomes, 2)
+	s.Equal(DisputeWorkflowID(testPackageID, 2), result.DisputeOutcomes[1].Dispute.ID)
+	s.Equal(3, f.confirmationRequests, "every redelivery sends a new confirmation link")
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestConfirmationIsIgnoredWhileDisputed() {
+	f := s.fixture
+	f.disputeAfter(time.Minute)
+	f.confirmAfter(2 * time.Minute)
+	f.disputeAfter(3 * time.Minute)
+	f.resolveDisputeAfter(time.Hour, 1, model.DisputeRefund)
+
+	f.execute(newTestParams())
+
+	result, err := f.result()
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryRefunded, result.Status)
+	s.Nil(result.Confirmation)
+	s.Len(result.DisputeOutcomes, 1)
+	s.Equal(1, f.supportNotifications)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestDisputeIsIgnoredAfterConfirmation() {
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).
+		After(time.Hour).
+		Return(nil)
+	f.confirmAfter(time.Minute)
+	f.disputeAfter(2 * time.Minute)
+
+	f.execute(newTestParams())
+
+	result, err := f.result()
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryNotified, result.Status)
+	s.Empty(result.DisputeOutcomes)
+	s.Zero(f.supportNotifications)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestInvalidDisputesAndResolutionsAreIgnored() {
+	f := s.fixture
+	signalAfter(f.env, time.Minute, PackageDeliverySignalDispute, &model.Dispute{
+		Category: model.DisputeOther,
+		RaisedBy: "customer@example.com",
+		RaisedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	signalAfter(f.env, 2*time.Minute, PackageDeliverySignalDispute, "not received")
+	f.disputeAfter(3 * time.Minute)
+	f.env.RegisterDelayedCallback(func() {
+		s.NoError(f.env.SignalWorkflowByID(DisputeWorkflowID(testPackageID, 1), DisputeResolutionSignalResolve, &model.DisputeResolution{
+			Action: "ignore",
+		}))
+	}, time.Hour)
+	f.resolveDisputeAfter(2*time.Hour, 1, model.DisputeClose)
+
+	f.execute(newTestParams())
+
+	result, err := f.result()
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryDisputeClosed, result.Status)
+	s.Len(result.DisputeOutcomes, 1)
+	s.Equal(model.DisputeNotReceived, result.DisputeOutcomes[0].Dispute.Category)
+	s.False(result.DisputeOutcomes[0].Resolution.ResolvedAt.IsZero(), "the workflow stamps resolutions sent without a time")
+}
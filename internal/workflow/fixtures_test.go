@@ -0,0 +1,201 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"go-test/internal/activities"
+	"go-test/internal/adapters"
+	"go-test/internal/auth"
+	"go-test/internal/config"
+	"go-test/internal/model"
+	"go-test/repository"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/testsuite"
+	"go.uber.org/zap"
+	"slices"
+	"sync"
+	"time"
+)
+
+const testPackageID = "pkg-test"
+
+func newTestPackage() *model.DeliveryPackage {
+	return &model.DeliveryPackage{
+		ID:              testPackageID,
+		CustomerEmail:   "customer@example.com",
+		DeliveryAddress: "123 Main Street",
+	}
+}
+
+func newTestParams() *PackageDeliveryWorkflowParams {
+	return &PackageDeliveryWorkflowParams{DeliveryPackage: newTestPackage()}
+}
+
+func newTestConfirmation(at time.Time) *model.DeliveryConfirmation {
+	return &model.DeliveryConfirmation{
+		ConfirmedBy: "customer@example.com",
+		ConfirmedAt: at,
+		Channel:     model.ConfirmationChannelAPI,
+	}
+}
+
+const testShipmentID = ShipmentIDPrefix + "test"
+
+func newTestShipment(parcels int) *model.Shipment {
+	shipment := &model.Shipment{
+		ID:              testShipmentID,
+		CustomerEmail:   "customer@example.com",
+		DeliveryAddress: "123 Main Street",
+	}
+	for i := 1; i <= parcels; i++ {
+		shipment.Parcels = append(shipment.Parcels, model.ShipmentParcel{PackageID: fmt.Sprintf("%s-%d", testShipmentID, i)})
+	}
+
+	return shipment
+}
+
+func newTestDispute(at time.Time) *model.Dispute {
+	return &model.Dispute{
+		Category: model.DisputeNotReceived,
+		RaisedBy: "customer@example.com",
+		RaisedAt: at,
+		Channel:  model.ConfirmationChannelLink,
+	}
+}
+
+// saveTestPreferences stores preferences for the customer of the test
+// package and shipment.
+func saveTestPreferences(store *repository.MemoryRepository, preferences *model.CustomerPreferences) {
+	preferences.Email = newTestPackage().CustomerEmail
+	if err := preferences.Validate(); err != nil {
+		panic(err)
+	}
+	if err := store.CreateCustomerPreferences(context.Background(), preferences); err != nil {
+		panic(err)
+	}
+}
+
+// recordingEventSender collects the events published by activities.
+type recordingEventSender struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recordingEventSender) SendEvent(message string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, message)
+	return nil
+}
+
+func (r *recordingEventSender) Events() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.events...)
+}
+
+// stubStuckPackageFinder returns the stuck packages of a status and records
+// the time each status was searched with.
+type stubStuckPackageFinder struct {
+	mu       sync.Mutex
+	packages map[model.PackageDeliveryState][]model.StuckPackage
+	searched map[model.PackageDeliveryState]time.Time
+}
+
+func newStubStuckPackageFinder() *stubStuckPackageFinder {
+	return &stubStuckPackageFinder{
+		packages: make(map[model.PackageDeliveryState][]model.StuckPackage),
+		searched: make(map[model.PackageDeliveryState]time.Time),
+	}
+}
+
+func (f *stubStuckPackageFinder) FindStuckPackages(_ context.Context, status model.PackageDeliveryState, changedBefore time.Time, _ int) ([]model.StuckPackage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.searched[status] = changedBefore
+	return f.packages[status], nil
+}
+
+// packageDeliveryActivities are the activities run by the package delivery
+// workflow and the dispute resolution workflows it starts.
+var packageDeliveryActivities = []string{
+	activities.RequestConfirmationActivityName,
+	activities.SaveDeliveryActivityName,
+	activities.NotifyDeliveryActivityName,
+	activities.LoadCustomerPreferencesActivityName,
+	activities.NotifyArrivalActivityName,
+	activities.MarkNotificationFailedActivityName,
+	activities.RollbackDeliveryActivityName,
+	activities.PublishCompensationEventActivityName,
+	activities.RecordDisputeActivityName,
+	activities.NotifySupportActivityName,
+	activities.ResolveDisputeActivityName,
+	activities.RecordAttemptActivityName,
+	activities.NotifyFailedAttemptActivityName,
+	activities.ReturnToSenderActivityName,
+	activities.ForceTransitionActivityName,
+	activities.RecordPackageEventActivityName,
+}
+
+// testActivities holds the dependencies of the real activities registered
+// with a test environment.
+type testActivities struct {
+	store  *repository.MemoryRepository
+	events activities.EventSender
+	finder activities.StuckPackageFinder
+}
+
+// registerActivities registers the real implementations of the named
+// activities with env, as the worker would, and leaves the others out.
+func registerActivities(env *testsuite.TestWorkflowEnvironment, deps testActivities, names ...string) {
+	if deps.events == nil {
+		deps.events = &recordingEventSender{}
+	}
+
+	links, err := auth.NewConfirmationLinks(config.AuthConfig{ConfirmationSecret: "test-confirmation-secret-of-32-bytes"}, deps.store, zap.NewNop())
+	if err != nil {
+		panic(err)
+	}
+	webhooks, err := adapters.NewWebhooks(config.WebhooksConfig{}, deps.store)
+	if err != nil {
+		panic(err)
+	}
+
+	register := func(a interface{}, options activity.RegisterOptions) {
+		if slices.Contains(names, options.Name) {
+			env.RegisterActivityWithOptions(a, options)
+		}
+	}
+	SetupActivities(register, deps.store, deps.events, links, deps.finder, webhooks, zap.NewNop())
+}
+
+func signalAfter(env *testsuite.TestWorkflowEnvironment, delay time.Duration, name string, payload interface{}) {
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(name, payload)
+	}, delay)
+}
+
+// updateAfter sends an update at the given point in workflow time and
+// records its result, or the error it was rejected or failed with. Updates
+// are identified by name and delay, as the test environment returns the
+// result of the first update of an ID to the later ones.
+func updateAfter(env *testsuite.TestWorkflowEnvironment, delay time.Duration, name string, payload interface{}, result *PackageUpdateResult, updateErr *error) {
+	env.RegisterDelayedCallback(func() {
+		env.UpdateWorkflow(name, fmt.Sprintf("%s-%s", name, delay), &testsuite.TestUpdateCallback{
+			OnAccept: func() {},
+			OnReject: func(err error) {
+				*updateErr = err
+			},
+			OnComplete: func(value interface{}, err error) {
+				if err != nil {
+					*updateErr = err
+					return
+				}
+				*result = *value.(*PackageUpdateResult)
+			},
+		}, payload)
+	}, delay)
+}
@@ -0,0 +1,75 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"go.temporal.io/api/common/v1"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/history/v1"
+	"go.temporal.io/api/temporalproto"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+	"io"
+)
+
+// ExportHistory writes the full event history of a workflow execution as
+// JSON, in the format read by worker.WorkflowReplayer. An empty runID
+// exports the latest run.
+func ExportHistory(ctx context.Context, c client.Client, workflowID, runID string, out io.Writer) error {
+	iter := c.GetWorkflowHistory(ctx, workflowID, runID, false, enums.HISTORY_EVENT_FILTER_TYPE_ALL_EVENT)
+
+	h := &history.History{}
+	for iter.HasNext() {
+		event, err := iter.Next()
+		if err != nil {
+			return fmt.Errorf("unable to read history of %s: %w", workflowID, err)
+		}
+		h.Events = append(h.Events, event)
+	}
+
+	data, err := temporalproto.CustomJSONMarshalOptions{Indent: "  "}.Marshal(h)
+	if err != nil {
+		return fmt.Errorf("unable to marshal history of %s: %w", workflowID, err)
+	}
+
+	if _, err := out.Write(data); err != nil {
+		return fmt.Errorf("unable to write history of %s: %w", workflowID, err)
+	}
+
+	return nil
+}
+
+// ListExecutions returns up to limit package delivery workflow executions
+// matching the visibility query.
+func ListExecutions(ctx context.Context, c client.Client, query string, limit int) ([]*common.WorkflowExecution, error) {
+	typeFilter := fmt.Sprintf("WorkflowType = '%s'", PackageDeliveryWorkflowName)
+	if query != "" {
+		query = fmt.Sprintf("%s AND (%s)", typeFilter, query)
+	} else {
+		query = typeFilter
+	}
+
+	var executions []*common.WorkflowExecution
+	var nextPageToken []byte
+	for {
+		resp, err := c.ListWorkflow(ctx, &workflowservice.ListWorkflowExecutionsRequest{
+			Query:         query,
+			NextPageToken: nextPageToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to list workflows: %w", err)
+		}
+
+		for _, info := range resp.Executions {
+			executions = append(executions, info.Execution)
+			if len(executions) == limit {
+				return executions, nil
+			}
+		}
+
+		nextPageToken = resp.NextPageToken
+		if len(nextPageToken) == 0 {
+			return executions, nil
+		}
+	}
+}
@@ -1,27 +1,53 @@
 package workflow
 
 import (
+	"errors"
 	"go-test/internal/activities"
+	"go-test/internal/config"
 	"go-test/internal/model"
-	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/workflow"
 	"go.uber.org/zap"
-	"time"
 )
 
-func NewPackageDeliveryWorkflowConfig(logger *zap.Logger) *PackageDeliveryWorkflowConfig {
+func NewPackageDeliveryWorkflowConfig(logger *zap.Logger, cfg config.WorkflowConfig) *PackageDeliveryWorkflowConfig {
 	return &PackageDeliveryWorkflowConfig{
-		Logger: logger,
+		Logger:               logger,
+		CompensationPolicies: NewCompensationPolicies(cfg.CompensationPolicies, logger),
+		ActivityPolicies:     NewActivityPolicyRegistry(cfg.ActivityPolicies),
+		DeliveryAttempts:     withAttemptDefaults(cfg.DeliveryAttempts),
+		HistoryLimits:        withHistoryDefaults(cfg.HistoryLimits),
+		StuckDetection:       withStuckDetectionDefaults(cfg.StuckDetection),
+		DeliveryWindows:      withDeliveryWindowDefaults(cfg.DeliveryWindows),
+		CustomerEmailHasher:  NewCustomerEmailHasher(cfg.CustomerEmailHashSecret),
 	}
 }
 
 func newPackageDeliveryWorkflow(ctx workflow.Context, params *PackageDeliveryWorkflowParams) *PackageDeliveryWorkflow {
-	return &PackageDeliveryWorkflow{
-		Ctx:            ctx,
-		State:          NewPackageDeliveryWorkflowState(),
-		Package:        params.DeliveryPackage,
-		WorkflowResult: &PackageDeliveryWorkflowResult{Status: model.PackageDeliveryInProgress},
+	w := &PackageDeliveryWorkflow{
+		Ctx:              ctx,
+		State:            NewPackageDeliveryWorkflowState(),
+		Package:          params.DeliveryPackage,
+		ActivityPolicies: params.ActivityPolicies,
+		ShipmentID:       params.ShipmentID,
+		WorkflowResult:   &PackageDeliveryWorkflowResult{Status: model.PackageDeliveryInProgress},
 	}
+
+	if params.Continued != nil {
+		w.State = params.Continued.State
+		w.WorkflowResult = params.Continued.Result
+	}
+
+	return w
+}
+
+// activityContext returns ctx with the options resolved for the activity,
+// including the overrides passed in the workflow params.
+func (c *PackageDeliveryWorkflowConfig) activityContext(w *PackageDeliveryWorkflow, activityName string) workflow.Context {
+	return c.activityOptions(w.Ctx, activityName, w.ActivityPolicies)
+}
+
+func (c *PackageDeliveryWorkflowConfig) activityOptions(ctx workflow.Context, activityName string, overrides map[string]config.ActivityPolicy) workflow.Context {
+	return workflow.WithActivityOptions(ctx, c.ActivityPolicies.Options(activityName, overrides))
 }
 
 func (c *PackageDeliveryWorkflowConfig) PackageDeliveryWorkflow(
@@ -32,83 +58,218 @@ func (c *PackageDeliveryWorkflowConfig) PackageDeliveryWorkflow(
 
 	c.Logger.Info("Starting package delivery workflow", zap.String("workflowId", workflow.GetInfo(ctx).WorkflowExecution.ID))
 
-	workflow.Go(ctx, func(goCtx workflow.Context) {
-		sel := workflow.NewSelector(goCtx)
+	validateConfirmations := hasChange(ctx, changeTypedConfirmation)
 
-		confirm := workflow.GetSignalChannel(goCtx, PackageDeliverySignalConfirm)
+	if hasChange(ctx, changeContinueAsNew) {
+		c.recordHistoryLimits(w)
+	}
 
-		sel.AddReceive(confirm, func(ch workflow.ReceiveChannel, more bool) {
-			ch.Receive(goCtx, w.State.DeliveryConfirmed)
-		})
+	if hasChange(ctx, changeSearchAttributes) {
+		w.SearchAttributes = true
+		c.publishSearchAttributes(w)
+	}
 
-		for {
-			sel.Select(goCtx)
+	if hasChange(ctx, changePackageEvents) {
+		w.PackageEvents = true
+		if params.Continued == nil {
+			c.recordPackageEvent(w, model.PackageEventCreated, workflow.GetInfo(ctx).WorkflowStartTime)
 		}
+	}
+
+	confirmCtx, stopConfirmations := workflow.WithCancel(ctx)
+	defer stopConfirmations()
+
+	signalsStopped := false
+	workflow.Go(confirmCtx, func(goCtx workflow.Context) {
+		c.receiveSignals(goCtx, w, validateConfirmations)
+		signalsStopped = true
 	})
 
+	// stopSignals waits for receiveSignals to return, as it may still hold a
+	// signal it has been handed but not yet processed.
+	stopSignals := func() error {
+		stopConfirmations()
+		return workflow.Await(ctx, func() bool { return signalsStopped })
+	}
+
 	if err := workflow.SetQueryHandler(ctx, PackageDeliveryStateQuery, func() (PackageDeliveryWorkflowResult, error) {
 		return *w.WorkflowResult, nil
 	}); err != nil {
-		w.WorkflowResult.Status = model.PackageDeliveryErrored
+		c.setStatus(w, model.PackageDeliveryErrored)
 
 		return w.WorkflowResult, err
 	}
 
-	workflow.Await(ctx, w.State.ShouldHandlePackageDeliveryConfirm)
+	if err := c.setUpdateHandlers(w); err != nil {
+		c.setStatus(w, model.PackageDeliveryErrored)
 
-	w.WorkflowResult.Status = model.PackageDeliveryConfirmed
+		return w.WorkflowResult, err
+	}
 
-	saveDeliveryActivityOptions := workflow.ActivityOptions{
-		StartToCloseTimeout: time.Minute,
-		RetryPolicy: &temporal.RetryPolicy{
-			MaximumAttempts: 3,
-		},
+	// A package with a delivery window only opens the confirmation window
+	// shortly before it.
+	windowOpen := true
+	if params.Continued == nil && w.Package.DeliveryWindow != nil && hasChange(ctx, changeDeliveryWindow) {
+		if windowOpen, err = c.awaitDeliveryWindow(w); err != nil {
+			return w.WorkflowResult, err
+		}
 	}
 
-	saveDeliveryActivityCtx := workflow.WithActivityOptions(ctx, saveDeliveryActivityOptions)
+	// A continued run already sent the link.
+	if windowOpen && hasChange(ctx, changeConfirmationLink) && w.ShipmentID == "" && params.Continued == nil {
+		c.requestConfirmation(w)
+	}
 
-	err = workflow.ExecuteActivity(
-		saveDeliveryActivityCtx,
-		activities.SaveDeliveryActivityName,
-		&activities.SaveDeliveryInput{
-			DeliveryPackage: params.DeliveryPackage,
-		},
-	).Get(ctx, nil)
+	// Failed delivery attempts and disputes either end the delivery or
+	// return to waiting for the confirmation. Once the history grows too
+	// large, the workflow continues as new while it waits.
+	for {
+		if err := c.awaitReattempt(w); err != nil {
+			return w.WorkflowResult, err
+		}
+
+		if err := workflow.Await(ctx, func() bool {
+			return w.State.NeedsAction() || c.shouldContinueAsNew(w)
+		}); err != nil {
+			return w.WorkflowResult, err
+		}
+
+		if !w.State.NeedsAction() {
+			return w.WorkflowResult, c.continueAsNew(w, stopSignals, validateConfirmations)
+		}
+
+		if w.State.ForcedTransition != nil {
+			return w.WorkflowResult, c.forceTransition(w)
+		}
+
+		if len(w.State.PendingAttempts) > 0 {
+			if done, err := c.handleAttempt(w); done || err != nil {
+				return w.WorkflowResult, err
+			}
+			continue
+		}
+
+		if w.State.Confirmed() {
+			break
+		}
+
+		if done, err := c.resolveDispute(w); done || err != nil {
+			return w.WorkflowResult, err
+		}
+	}
+
+	c.setStatus(w, model.PackageDeliveryConfirmed)
+	c.reportToShipment(w)
+
+	// The proof collected with the confirmation is persisted with the package.
+	deliveryPackage := *params.DeliveryPackage
+	deliveryPackage.Proof = w.State.Confirmation.Proof
 
+	saveDeliveryActivityCtx := c.activityContext(w, activities.SaveDeliveryActivityName)
+
+	err = c.runStep(w, activities.SaveDeliveryActivityName, func() error {
+		return workflow.ExecuteActivity(
+			saveDeliveryActivityCtx,
+			activities.SaveDeliveryActivityName,
+			&activities.SaveDeliveryInput{
+				DeliveryPackage: &deliveryPackage,
+			},
+		).Get(ctx, nil)
+	})
+
+	if errors.Is(err, errTransitionForced) {
+		return w.WorkflowResult, nil
+	}
 	if err != nil {
-		w.WorkflowResult.Status = model.PackageDeliveryErrored
 		c.Logger.Error("Failed to save delivery activity", zap.Error(err))
 
 		return w.WorkflowResult, err
 	}
 
-	w.WorkflowResult.Status = model.PackageDeliverySaved
+	w.State.Saved = true
+	c.setStatus(w, model.PackageDeliverySaved)
 
-	notifyDeliveryActivityOptions := workflow.ActivityOptions{
-		StartToCloseTimeout: time.Minute,
-		RetryPolicy: &temporal.RetryPolicy{
-			MaximumAttempts: 3,
-		},
+	if w.ShipmentID != "" {
+		return w.WorkflowResult, nil
 	}
 
-	notifyDeliveryActivityCtx := workflow.WithActivityOptions(ctx, notifyDeliveryActivityOptions)
+	preferences := c.customerPreferences(w)
+	if !c.allowsNotification(w, preferences, model.NotificationDelivered) {
+		// The package stays saved, as the customer was not notified.
+		if !hasChange(ctx, changeQuietHoursEverywhere) {
+			c.setStatus(w, model.PackageDeliveryNotified)
+		}
+		return w.WorkflowResult, nil
+	}
+	if _, err := c.awaitQuietHours(w, preferences, nil); err != nil {
+		return w.WorkflowResult, err
+	}
 
-	err = workflow.ExecuteActivity(
-		notifyDeliveryActivityCtx,
-		activities.NotifyDeliveryActivityName,
-		&activities.NotifyDeliveryInput{
-			DeliveryPackage: params.DeliveryPackage,
-		},
-	).Get(ctx, nil)
+	notifyDeliveryActivityCtx := c.activityContext(w, activities.NotifyDeliveryActivityName)
+
+	err = c.runStep(w, activities.NotifyDeliveryActivityName, func() error {
+		return workflow.ExecuteActivity(
+			notifyDeliveryActivityCtx,
+			activities.NotifyDeliveryActivityName,
+			&activities.NotifyDeliveryInput{
+				DeliveryPackage: params.DeliveryPackage,
+				Preferences:     preferences,
+			},
+		).Get(ctx, nil)
+	})
 
+	if errors.Is(err, errTransitionForced) {
+		return w.WorkflowResult, nil
+	}
 	if err != nil {
-		w.WorkflowResult.Status = model.PackageDeliveryErrored
 		c.Logger.Error("Failed to notify delivery activity", zap.Error(err))
 
 		return w.WorkflowResult, err
 	}
 
-	w.WorkflowResult.Status = model.PackageDeliveryNotified
+	c.setStatus(w, model.PackageDeliveryNotified)
 
 	return w.WorkflowResult, nil
 }
+
+// requestConfirmation sends the customer a confirmation link, unless they
+// opted out of all notifications, once their quiet hours are over. A
+// failure only costs the customer the link, operators can still confirm the
+// delivery.
+func (c *PackageDeliveryWorkflowConfig) requestConfirmation(w *PackageDeliveryWorkflow) {
+	if hasChange(w.Ctx, changeQuietHoursEverywhere) {
+		preferences := c.customerPreferences(w)
+		if !c.allowsNotificationNow(w, preferences, model.NotificationConfirmationRequest) {
+			return
+		}
+	}
+
+	ctx := c.activityContext(w, activities.RequestConfirmationActivityName)
+
+	err := workflow.ExecuteActivity(
+		ctx,
+		activities.RequestConfirmationActivityName,
+		&activities.RequestConfirmationInput{DeliveryPackage: w.Package},
+	).Get(ctx, nil)
+	if err != nil {
+		c.Logger.Warn("Failed to request delivery confirmation", zap.String("packageId", w.Package.ID), zap.Error(err))
+	}
+}
+
+// reportToShipment lets the shipment of a parcel know about its progress.
+// The shipment also learns the final state once the parcel completes, so a
+// lost report only delays the aggregated state.
+func (c *PackageDeliveryWorkflowConfig) reportToShipment(w *PackageDeliveryWorkflow) {
+	if w.ShipmentID == "" {
+		return
+	}
+
+	err := workflow.SignalExternalWorkflow(w.Ctx, w.ShipmentID, "", ShipmentSignalParcelUpdate, &model.ShipmentParcel{
+		PackageID:    w.Package.ID,
+		Status:       w.WorkflowResult.Status,
+		Confirmation: w.WorkflowResult.Confirmation,
+	}).Get(w.Ctx, nil)
+	if err != nil {
+		c.Logger.Warn("Failed to report parcel state to shipment", zap.String("packageId", w.Package.ID), zap.String("shipmentId", w.ShipmentID), zap.Error(err))
+	}
+}
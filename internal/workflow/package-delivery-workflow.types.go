@@ -1,9 +1,11 @@
 package workflow
 
 import (
+	"go-test/internal/config"
 	"go-test/internal/model"
 	"go.temporal.io/sdk/workflow"
 	"go.uber.org/zap"
+	"time"
 )
 
 const (
@@ -13,42 +15,152 @@ const (
 
 const (
 	PackageDeliverySignalConfirm = "confirm"
-	PackageDeliveryStateQuery    = "current-state"
+	PackageDeliverySignalResolve = "resolve"
+	PackageDeliverySignalDispute = "dispute"
+	PackageDeliverySignalAttempt = "delivery-attempt"
+	// PackageDeliverySignalForceTransition carries a ForcedTransition.
+	PackageDeliverySignalForceTransition = "force-transition"
+	PackageDeliveryStateQuery            = "current-state"
+)
+
+// Updates of the package delivery workflow. Unlike the signals, they tell the
+// caller whether the workflow took the request into account.
+const (
+	// PackageDeliveryUpdateConfirm carries a model.DeliveryConfirmation.
+	PackageDeliveryUpdateConfirm = "confirm-delivery"
+	// PackageDeliveryUpdateDispute carries a model.Dispute.
+	PackageDeliveryUpdateDispute = "raise-dispute"
+	// PackageDeliveryUpdateAttempt carries a model.DeliveryAttempt.
+	PackageDeliveryUpdateAttempt = "report-attempt"
+)
+
+const (
+	DisputeResolutionWorkflowName  = "dispute-resolution-workflow"
+	DisputeResolutionSignalResolve = "resolve-dispute"
 )
 
 type PackageDeliveryWorkflowConfig struct {
 	Logger *zap.Logger
+	// CompensationPolicies decides, per step name, what happens once the step
+	// has exhausted its retries. Steps without an entry are marked as failed.
+	CompensationPolicies map[string]CompensationAction
+	ActivityPolicies     *ActivityPolicyRegistry
+	DeliveryAttempts     config.DeliveryAttemptsConfig
+	HistoryLimits        config.HistoryLimitsConfig
+	StuckDetection       config.StuckDetectionConfig
+	DeliveryWindows      config.DeliveryWindowsConfig
+	CustomerEmailHasher  *CustomerEmailHasher
+}
+
+// PackageDeliveryRequest is the event that starts the delivery of a package:
+// the package itself and the activity policies overriding the configured
+// ones for this package only.
+type PackageDeliveryRequest struct {
+	*model.DeliveryPackage
+	ActivityPolicies map[string]config.ActivityPolicy `json:"activity_policies,omitempty"`
 }
 
 type PackageDeliveryWorkflowParams struct {
 	DeliveryPackage *model.DeliveryPackage
+	// ActivityPolicies overrides the configured activity policies for this
+	// package only, keyed by activity name.
+	ActivityPolicies map[string]config.ActivityPolicy `json:",omitempty"`
+	// ShipmentID is set for parcels of a shipment. The shipment workflow
+	// sends the confirmation link and the notification for all its parcels.
+	ShipmentID string `json:",omitempty"`
+	// Continued carries the state of the previous run once the workflow has
+	// continued as new.
+	Continued *PackageDeliveryContinuation `json:",omitempty"`
+}
+
+// PackageDeliveryContinuation is the state handed from one run of the
+// workflow to the next when it continues as new.
+type PackageDeliveryContinuation struct {
+	State  *PackageDeliveryWorkflowState
+	Result *PackageDeliveryWorkflowResult
 }
 
 type PackageDeliveryWorkflowResult struct {
-	Status model.PackageDeliveryState `json:"status"`
+	Status                 model.PackageDeliveryState  `json:"status"`
+	Confirmation           *model.DeliveryConfirmation `json:"confirmation,omitempty"`
+	DuplicateConfirmations int                         `json:"duplicateConfirmations,omitempty"`
+	// Dispute is the dispute currently waiting for a resolution.
+	Dispute         *model.Dispute          `json:"dispute,omitempty"`
+	DisputeOutcomes []model.DisputeOutcome  `json:"disputeOutcomes,omitempty"`
+	Attempts        []model.DeliveryAttempt `json:"attempts,omitempty"`
+	FailedAttempts  int                     `json:"failedAttempts,omitempty"`
+	// NextAttemptAt is set while waiting for the reattempt after a failed
+	// attempt.
+	NextAttemptAt *time.Time `json:"nextAttemptAt,omitempty"`
+	// ContinuedAsNew counts the runs that handed over to a new one because
+	// of the size of their history.
+	ContinuedAsNew int `json:"continuedAsNew,omitempty"`
+	// ForcedTransition is set once an operator has forced the final status.
+	ForcedTransition *ForcedTransition `json:"forcedTransition,omitempty"`
+	// DeliveryWindow is the delivery window the customer chose.
+	DeliveryWindow *model.DeliveryWindow `json:"deliveryWindow,omitempty"`
+	// OptedOutNotifications are the notifications not sent because the
+	// customer opted out of them.
+	OptedOutNotifications []model.NotificationKind `json:"optedOutNotifications,omitempty"`
+}
+
+type DisputeResolutionParams struct {
+	DeliveryPackage  *model.DeliveryPackage
+	Dispute          model.Dispute
+	ActivityPolicies map[string]config.ActivityPolicy `json:",omitempty"`
 }
 
 type PackageDeliveryWorkflowState struct {
-	DeliveryConfirmed *model.DeliveryPackageWorkflowStatus
+	Confirmation *model.DeliveryConfirmation
+	Dispute      *model.Dispute
+	// PendingAttempts are the attempts signalled by drivers and not yet
+	// handled by the workflow.
+	PendingAttempts []model.DeliveryAttempt
+	// AttemptPolicy is recorded on the first attempt, so that the attempts
+	// of a package follow one policy even if the configuration changes.
+	AttemptPolicy *config.DeliveryAttemptsConfig
+	// ForcedTransition is the status an operator asked the workflow to end
+	// with.
+	ForcedTransition *ForcedTransition
+	// AwaitingWindow is set while the workflow waits for the delivery
+	// window. Confirmations are not accepted until it opens.
+	AwaitingWindow bool
 
 	Pending   bool
 	Completed bool
+	Saved     bool
+}
+
+func (s *PackageDeliveryWorkflowState) Confirmed() bool {
+	return s.Confirmation != nil
 }
 
-func (s *PackageDeliveryWorkflowState) ShouldHandlePackageDeliveryConfirm() bool {
-	return s.DeliveryConfirmed.ShouldHandle()
+// NeedsAction reports whether the workflow has a confirmation, dispute,
+// delivery attempt or forced transition to act on.
+func (s *PackageDeliveryWorkflowState) NeedsAction() bool {
+	return s.Confirmation != nil || s.Dispute != nil || len(s.PendingAttempts) > 0 || s.ForcedTransition != nil
 }
 
 func NewPackageDeliveryWorkflowState() *PackageDeliveryWorkflowState {
 	return &PackageDeliveryWorkflowState{
-		DeliveryConfirmed: &model.DeliveryPackageWorkflowStatus{},
-		Pending:           true,
+		Pending: true,
 	}
 }
 
 type PackageDeliveryWorkflow struct {
-	Ctx            workflow.Context
-	State          *PackageDeliveryWorkflowState
-	Package        *model.DeliveryPackage
-	WorkflowResult *PackageDeliveryWorkflowResult
+	Ctx              workflow.Context
+	State            *PackageDeliveryWorkflowState
+	Package          *model.DeliveryPackage
+	ActivityPolicies map[string]config.ActivityPolicy
+	ShipmentID       string
+	WorkflowResult   *PackageDeliveryWorkflowResult
+	// HistoryLimits is recorded at the start of every run. Workflows started
+	// before continue-as-new was introduced have none and never continue.
+	HistoryLimits *config.HistoryLimitsConfig
+	// SearchAttributes is set for workflows that publish their status as a
+	// search attribute.
+	SearchAttributes bool
+	// PackageEvents is set for workflows that record the package events
+	// aggregated by the delivery report.
+	PackageEvents bool
 }
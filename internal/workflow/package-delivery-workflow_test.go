@@ -0,0 +1,569 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go-test/internal/activities"
+	"go-test/internal/config"
+	"go-test/internal/model"
+	"go-test/repository"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+type PackageDeliveryWorkflowTestSuite struct {
+	suite.Suite
+	testsuite.WorkflowTestSuite
+
+	fixture *packageDeliveryFixture
+}
+
+func TestPackageDeliveryWorkflow(t *testing.T) {
+	suite.Run(t, new(PackageDeliveryWorkflowTestSuite))
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) SetupTest() {
+	s.fixture = newPackageDeliveryFixture(&s.WorkflowTestSuite, config.WorkflowConfig{})
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) AfterTest(_, _ string) {
+	s.fixture.env.AssertExpectations(s.T())
+}
+
+// packageDeliveryFixture wires a test environment with the package delivery
+// and dispute resolution workflows and their activities, backed by an
+// in-memory store. Tests mock the activities they want to control with
+// env.OnActivity.
+type packageDeliveryFixture struct {
+	env    *testsuite.TestWorkflowEnvironment
+	store  *repository.MemoryRepository
+	events *recordingEventSender
+
+	// confirmationRequests counts the confirmation links sent to the
+	// customer, the activity itself is mocked as it calls the webhook.
+	confirmationRequests    int
+	confirmationRequestedAt []time.Time
+	confirmationRequestErr  error
+
+	// supportNotifications counts the disputes handed to support.
+	supportNotifications int
+	// failedAttemptNotifications collects the failed delivery attempts
+	// reported to the customer, with the workflow time they were sent at.
+	failedAttemptNotifications []model.FailedAttemptNotification
+	failedAttemptNotifiedAt    []time.Time
+	// arrivalNotifications collects the arrival notifications sent ahead of
+	// a delivery window, with the workflow time they were sent at.
+	arrivalNotifications []model.ArrivalNotification
+	arrivalNotifiedAt    []time.Time
+}
+
+type fixtureOption func(c *PackageDeliveryWorkflowConfig)
+
+func withCompensationPolicy(step string, action CompensationAction) fixtureOption {
+	return func(c *PackageDeliveryWorkflowConfig) {
+		c.CompensationPolicies[step] = action
+	}
+}
+
+func newPackageDeliveryFixture(s *testsuite.WorkflowTestSuite, cfg config.WorkflowConfig, opts ...fixtureOption) *packageDeliveryFixture {
+	f := &packageDeliveryFixture{
+		env:    s.NewTestWorkflowEnvironment(),
+		store:  repository.NewMemoryRepository(),
+		events: &recordingEventSender{},
+	}
+
+	workflowConfig := NewPackageDeliveryWorkflowConfig(zap.NewNop(), cfg)
+	for _, opt := range opts {
+		opt(workflowConfig)
+	}
+
+	f.env.RegisterWorkflowWithOptions(workflowConfig.PackageDeliveryWorkflow, workflow.RegisterOptions{
+		Name: PackageDeliveryWorkflowName,
+	})
+	f.env.RegisterWorkflowWithOptions(workflowConfig.DisputeResolutionWorkflow, workflow.RegisterOptions{
+		Name: DisputeResolutionWorkflowName,
+	})
+
+	registerActivities(f.env, testActivities{store: f.store, events: f.events}, packageDeliveryActivities...)
+
+	f.env.OnActivity(activities.RequestConfirmationActivityName, mock.Anything, mock.Anything).
+		Return(func(context.Context, *activities.RequestConfirmationInput) error {
+			f.confirmationRequests++
+			f.confirmationRequestedAt = append(f.confirmationRequestedAt, f.env.Now())
+			return f.confirmationRequestErr
+		}).
+		Maybe()
+
+	f.env.OnActivity(activities.NotifySupportActivityName, mock.Anything, mock.Anything).
+		Return(func(context.Context, *activities.NotifySupportInput) error {
+			f.supportNotifications++
+			return nil
+		}).
+		Maybe()
+
+	f.env.OnActivity(activities.NotifyFailedAttemptActivityName, mock.Anything, mock.Anything).
+		Return(func(_ context.Context, input *activities.NotifyFailedAttemptInput) error {
+			f.failedAttemptNotifications = append(f.failedAttemptNotifications, input.Notification)
+			f.failedAttemptNotifiedAt = append(f.failedAttemptNotifiedAt, f.env.Now())
+			return nil
+		}).
+		Maybe()
+
+	f.env.OnActivity(activities.NotifyArrivalActivityName, mock.Anything, mock.Anything).
+		Return(func(_ context.Context, input *activities.NotifyArrivalInput) error {
+			f.arrivalNotifications = append(f.arrivalNotifications, input.Notification)
+			f.arrivalNotifiedAt = append(f.arrivalNotifiedAt, f.env.Now())
+			return nil
+		}).
+		Maybe()
+
+	return f
+}
+
+func (f *packageDeliveryFixture) execute(params *PackageDeliveryWorkflowParams) {
+	f.env.ExecuteWorkflow(PackageDeliveryWorkflowName, params)
+}
+
+func (f *packageDeliveryFixture) confirmAfter(delay time.Duration) {
+	f.env.RegisterDelayedCallback(func() {
+		f.env.SignalWorkflow(PackageDeliverySignalConfirm, newTestConfirmation(f.env.Now()))
+	}, delay)
+}
+
+func (f *packageDeliveryFixture) attemptAfter(delay time.Duration, outcome model.AttemptOutcome) {
+	f.env.RegisterDelayedCallback(func() {
+		f.env.SignalWorkflow(PackageDeliverySignalAttempt, &model.DeliveryAttempt{
+			Outcome:     outcome,
+			Driver:      "driver-1",
+			AttemptedAt: f.env.Now(),
+		})
+	}, delay)
+}
+
+func (f *packageDeliveryFixture) disputeAfter(delay time.Duration) {
+	f.env.RegisterDelayedCallback(func() {
+		f.env.SignalWorkflow(PackageDeliverySignalDispute, newTestDispute(f.env.Now()))
+	}, delay)
+}
+
+// resolveDisputeAfter signals the resolution workflow of the n-th dispute.
+func (f *packageDeliveryFixture) resolveDisputeAfter(delay time.Duration, n int, action model.DisputeAction) {
+	f.env.RegisterDelayedCallback(func() {
+		err := f.env.SignalWorkflowByID(DisputeWorkflowID(testPackageID, n), DisputeResolutionSignalResolve, &model.DisputeResolution{
+			Action:     action,
+			ResolvedBy: "operator",
+		})
+		if err != nil {
+			panic(err)
+		}
+	}, delay)
+}
+
+// queryStatusAfter records the status reported by the state query at the
+// given point in workflow time.
+func (f *packageDeliveryFixture) queryStatusAfter(delay time.Duration, status *model.PackageDeliveryState) {
+	f.env.RegisterDelayedCallback(func() {
+		if current, err := f.queryStatus(); err == nil {
+			*status = current
+		}
+	}, delay)
+}
+
+// queryStateAfter records the state reported by the state query at the
+// given point in workflow time.
+func (f *packageDeliveryFixture) queryStateAfter(delay time.Duration, state *PackageDeliveryWorkflowResult) {
+	f.env.RegisterDelayedCallback(func() {
+		if current, err := f.queryState(); err == nil {
+			*state = *current
+		}
+	}, delay)
+}
+
+func (f *packageDeliveryFixture) queryStatus() (model.PackageDeliveryState, error) {
+	state, err := f.queryState()
+	if err != nil {
+		return "", err
+	}
+
+	return state.Status, nil
+}
+
+func (f *packageDeliveryFixture) queryState() (*PackageDeliveryWorkflowResult, error) {
+	value, err := f.env.QueryWorkflow(PackageDeliveryStateQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	var result PackageDeliveryWorkflowResult
+	if err := value.Get(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (f *packageDeliveryFixture) result() (*PackageDeliveryWorkflowResult, error) {
+	if err := f.env.GetWorkflowError(); err != nil {
+		return nil, err
+	}
+
+	var result PackageDeliveryWorkflowResult
+	if err := f.env.GetWorkflowResult(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestConfirmSavesAndNotifies() {
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).Return(nil).Once()
+	f.confirmAfter(time.Hour)
+
+	f.execute(newTestParams())
+
+	s.True(f.env.IsWorkflowCompleted())
+	result, err := f.result()
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryNotified, result.Status)
+
+	stored, err := f.store.GetPackageDelivery(context.Background(), testPackageID)
+	s.NoError(err)
+	s.True(stored.SamePayload(newTestPackage()))
+	s.Empty(f.events.Events())
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestWaitsForConfirmation() {
+	f := s.fixture
+	var status model.PackageDeliveryState
+	f.queryStatusAfter(time.Hour, &status)
+	f.env.SetTestTimeout(time.Second)
+	f.env.SetWorkflowRunTimeout(2 * time.Hour)
+
+	f.execute(newTestParams())
+
+	s.Equal(model.PackageDeliveryInProgress, status)
+	var timeoutErr *temporal.TimeoutError
+	s.ErrorAs(f.env.GetWorkflowError(), &timeoutErr)
+
+	_, err := f.store.GetPackageDelivery(context.Background(), testPackageID)
+	s.Error(err, "package must not be saved before it is confirmed")
+}
+
+// The confirmed, saved and notified statuses share one value, so the
+// transitions between them are told apart by the confirmation and the
+// activities that ran when the state was queried.
+func (s *PackageDeliveryWorkflowTestSuite) TestQueryReportsStateTransitions() {
+	f := s.fixture
+	var saves, notifications int
+	f.env.OnActivity(activities.SaveDeliveryActivityName, mock.Anything, mock.Anything).
+		After(time.Minute / 2).
+		Return(func(context.Context, *activities.SaveDeliveryInput) (*model.DeliveryPackage, error) {
+			saves++
+			return newTestPackage(), nil
+		})
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).
+		After(time.Minute / 2).
+		Return(func(context.Context, *activities.NotifyDeliveryInput) error {
+			notifications++
+			return nil
+		})
+
+	var beforeConfirm, whileSaving, whileNotifying PackageDeliveryWorkflowResult
+	var savesWhileSaving, notificationsWhileSaving, savesWhileNotifying, notificationsWhileNotifying int
+	f.queryStateAfter(time.Minute, &beforeConfirm)
+	f.confirmAfter(2 * time.Minute)
+	f.queryStateAfter(2*time.Minute+time.Second, &whileSaving)
+	f.env.RegisterDelayedCallback(func() {
+		savesWhileSaving, notificationsWhileSaving = saves, notifications
+	}, 2*time.Minute+time.Second)
+	f.queryStateAfter(2*time.Minute+45*time.Second, &whileNotifying)
+	f.env.RegisterDelayedCallback(func() {
+		savesWhileNotifying, notificationsWhileNotifying = saves, notifications
+	}, 2*time.Minute+45*time.Second)
+
+	f.execute(newTestParams())
+
+	s.Equal(model.PackageDeliveryInProgress, beforeConfirm.Status)
+	s.Nil(beforeConfirm.Confirmation)
+
+	s.Equal(model.PackageDeliveryConfirmed, whileSaving.Status)
+	s.NotNil(whileSaving.Confirmation)
+	s.Equal(0, savesWhileSaving, "the save is still running")
+	s.Equal(0, notificationsWhileSaving)
+
+	s.Equal(model.PackageDeliverySaved, whileNotifying.Status)
+	s.Equal(1, savesWhileNotifying)
+	s.Equal(0, notificationsWhileNotifying, "the notification is still running")
+
+	state, err := f.queryState()
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryNotified, state.Status)
+	s.Equal(1, saves)
+	s.Equal(1, notifications)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestSaveFailureDoesNotNotify() {
+	f := s.fixture
+	f.env.OnActivity(activities.SaveDeliveryActivityName, mock.Anything, mock.Anything).
+		Return(nil, temporal.NewNonRetryableApplicationError("conflict", activities.ErrTypePackageConflict, nil)).
+		Once()
+	f.confirmAfter(time.Minute)
+
+	f.execute(newTestParams())
+
+	var appErr *temporal.ApplicationError
+	s.ErrorAs(f.env.GetWorkflowError(), &appErr)
+	s.Equal(activities.ErrTypePackageConflict, appErr.Type())
+
+	status, err := f.queryStatus()
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryErrored, status)
+	s.Len(f.events.Events(), 1, "a compensation event is published")
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestNotifyRetryExhaustionCompensates() {
+	f := s.fixture
+	attempts := 0
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).
+		Return(func(context.Context, *activities.NotifyDeliveryInput) error {
+			attempts++
+			return errors.New("webhook responded with status code: 503")
+		})
+	f.confirmAfter(time.Minute)
+
+	f.execute(newTestParams())
+
+	s.Error(f.env.GetWorkflowError())
+	s.Equal(3, attempts)
+
+	stored, err := f.store.GetPackageDelivery(context.Background(), testPackageID)
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryNotificationFailed, stored.Status)
+	s.Len(f.events.Events(), 1)
+	s.Contains(f.events.Events()[0], `"action":"markFailed"`)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestRollbackPolicyRemovesSavedPackage() {
+	s.fixture = newPackageDeliveryFixture(&s.WorkflowTestSuite, config.WorkflowConfig{
+		CompensationPolicies: map[string]string{
+			activities.NotifyDeliveryActivityName: string(CompensationRollback),
+		},
+	})
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).
+		Return(errors.New("webhook responded with status code: 503"))
+	f.confirmAfter(time.Minute)
+
+	f.execute(newTestParams())
+
+	s.Error(f.env.GetWorkflowError())
+	_, err := f.store.GetPackageDelivery(context.Background(), testPackageID)
+	s.Error(err)
+	s.Len(f.events.Events(), 1)
+	s.Contains(f.events.Events()[0], `"status":"rolledBack"`)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestParkedStepIsRetriedOnResolution() {
+	s.fixture = newPackageDeliveryFixture(&s.WorkflowTestSuite, config.WorkflowConfig{},
+		withCompensationPolicy(activities.NotifyDeliveryActivityName, CompensationPark))
+	f := s.fixture
+	attempts := 0
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).
+		Return(func(context.Context, *activities.NotifyDeliveryInput) error {
+			attempts++
+			if attempts <= 3 {
+				return errors.New("webhook responded with status code: 503")
+			}
+			return nil
+		})
+
+	var parked model.PackageDeliveryState
+	f.confirmAfter(time.Minute)
+	f.queryStatusAfter(time.Hour, &parked)
+	signalAfter(f.env, 2*time.Hour, PackageDeliverySignalResolve, ManualResolution{Action: "unknown"})
+	signalAfter(f.env, 3*time.Hour, PackageDeliverySignalResolve, ManualResolution{Action: CompensationRetry, Operator: "ops"})
+
+	f.execute(newTestParams())
+
+	s.Equal(model.PackageDeliveryParked, parked)
+	result, err := f.result()
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryNotified, result.Status)
+	s.Equal(4, attempts)
+	s.Empty(f.events.Events())
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestActivityPolicyOverrideFromParams() {
+	f := s.fixture
+	attempts := 0
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).
+		Return(func(context.Context, *activities.NotifyDeliveryInput) error {
+			attempts++
+			return errors.New("webhook responded with status code: 503")
+		})
+	f.confirmAfter(time.Minute)
+
+	params := newTestParams()
+	params.ActivityPolicies = map[string]config.ActivityPolicy{
+		activities.NotifyDeliveryActivityName: {MaximumAttempts: 1},
+	}
+	f.execute(params)
+
+	s.Error(f.env.GetWorkflowError())
+	s.Equal(1, attempts)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestNotifyStartToCloseTimeout() {
+	s.fixture = newPackageDeliveryFixture(&s.WorkflowTestSuite, config.WorkflowConfig{
+		ActivityPolicies: map[string]config.ActivityPolicy{
+			activities.NotifyDeliveryActivityName: {
+				StartToCloseTimeout: config.Duration(100 * time.Millisecond),
+				MaximumAttempts:     1,
+			},
+		},
+	})
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, _ *activities.NotifyDeliveryInput) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	f.confirmAfter(time.Minute)
+
+	f.execute(newTestParams())
+
+	var timeoutErr *temporal.TimeoutError
+	s.ErrorAs(f.env.GetWorkflowError(), &timeoutErr)
+
+	status, err := f.queryStatus()
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryNotificationFailed, status)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestDuplicateConfirmationsAreCounted() {
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).
+		After(time.Hour).
+		Return(nil)
+
+	var afterDuplicates PackageDeliveryWorkflowResult
+	f.confirmAfter(time.Minute)
+	f.confirmAfter(2 * time.Minute)
+	signalAfter(f.env, 3*time.Minute, PackageDeliverySignalConfirm, &model.DeliveryConfirmation{
+		ConfirmedBy: "courier",
+		ConfirmedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Channel:     model.ConfirmationChannelAPI,
+	})
+	f.env.RegisterDelayedCallback(func() {
+		value, err := f.env.QueryWorkflow(PackageDeliveryStateQuery)
+		s.NoError(err)
+		s.NoError(value.Get(&afterDuplicates))
+	}, 4*time.Minute)
+
+	f.execute(newTestParams())
+
+	s.True(f.env.IsWorkflowCompleted())
+	s.NoError(f.env.GetWorkflowError())
+	s.Equal(2, afterDuplicates.DuplicateConfirmations)
+	s.Equal("customer@example.com", afterDuplicates.Confirmation.ConfirmedBy)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestInvalidConfirmationsAreIgnored() {
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).Return(nil)
+
+	var afterInvalid model.PackageDeliveryState
+	signalAfter(f.env, time.Minute, PackageDeliverySignalConfirm, &model.DeliveryConfirmation{Channel: model.ConfirmationChannelAPI})
+	signalAfter(f.env, 2*time.Minute, PackageDeliverySignalConfirm, "confirmed")
+	signalAfter(f.env, 3*time.Minute, PackageDeliverySignalConfirm, &model.DeliveryConfirmation{
+		ConfirmedBy: "courier",
+		ConfirmedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Channel:     "carrier-pigeon",
+	})
+	f.queryStatusAfter(4*time.Minute, &afterInvalid)
+	f.confirmAfter(5 * time.Minute)
+
+	f.execute(newTestParams())
+
+	s.Equal(model.PackageDeliveryInProgress, afterInvalid)
+	result, err := f.result()
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryNotified, result.Status)
+	s.Equal("customer@example.com", result.Confirmation.ConfirmedBy)
+	s.Zero(result.DuplicateConfirmations)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestProofIsSavedWithPackage() {
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).Return(nil)
+
+	confirmation := newTestConfirmation(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	confirmation.Proof = &model.ProofOfDelivery{
+		RecipientName: "Jane Doe",
+		Photo:         &model.ObjectReference{Key: "packages/pkg-test/proof/photo", ContentType: "image/jpeg", Size: 1024},
+		Location:      &model.GeoLocation{Latitude: 52.52, Longitude: 13.405},
+	}
+	signalAfter(f.env, time.Minute, PackageDeliverySignalConfirm, confirmation)
+
+	f.execute(newTestParams())
+
+	result, err := f.result()
+	s.NoError(err)
+	s.Equal("Jane Doe", result.Confirmation.Proof.RecipientName)
+
+	stored, err := f.store.GetPackageDelivery(context.Background(), testPackageID)
+	s.NoError(err)
+	s.Equal(confirmation.Proof, stored.Proof)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestConfirmationWithInvalidProofIsIgnored() {
+	f := s.fixture
+
+	confirmation := newTestConfirmation(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	confirmation.Proof = &model.ProofOfDelivery{RecipientName: "Jane Doe", Location: &model.GeoLocation{Latitude: 91}}
+	signalAfter(f.env, time.Minute, PackageDeliverySignalConfirm, confirmation)
+	f.env.SetWorkflowRunTimeout(time.Hour)
+
+	f.execute(newTestParams())
+
+	var timeoutErr *temporal.TimeoutError
+	s.ErrorAs(f.env.GetWorkflowError(), &timeoutErr)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestConfirmationLinkIsRequestedBeforeConfirmation() {
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).Return(nil)
+
+	requestsBeforeConfirm := 0
+	f.env.RegisterDelayedCallback(func() {
+		requestsBeforeConfirm = f.confirmationRequests
+	}, time.Minute)
+	f.confirmAfter(time.Hour)
+
+	f.execute(newTestParams())
+
+	s.NoError(f.env.GetWorkflowError())
+	s.Equal(1, requestsBeforeConfirm)
+	s.Equal(1, f.confirmationRequests)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestConfirmationLinkFailureStillAcceptsConfirmation() {
+	f := s.fixture
+	f.confirmationRequestErr = errors.New("webhook responded with status code: 503")
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).Return(nil)
+	f.confirmAfter(time.Hour)
+
+	f.execute(newTestParams())
+
+	result, err := f.result()
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryNotified, result.Status)
+	s.Equal(3, f.confirmationRequests, "the request is retried with the default policy")
+}
@@ -0,0 +1,34 @@
+package workflow
+
+import (
+	"go-test/internal/activities"
+	"go-test/internal/model"
+	"go.temporal.io/sdk/workflow"
+	"go.uber.org/zap"
+	"time"
+)
+
+// packageStatusEvents maps the statuses counted by the delivery report to
+// the event recorded when a package reaches them.
+var packageStatusEvents = map[model.PackageDeliveryState]model.PackageEventType{
+	model.PackageDeliveryConfirmed: model.PackageEventConfirmed,
+	model.PackageDeliveryErrored:   model.PackageEventErrored,
+}
+
+// recordPackageEvent stores an event for the delivery report, unless the
+// workflow started before package events were introduced. A failure only
+// costs the report one event.
+func (c *PackageDeliveryWorkflowConfig) recordPackageEvent(w *PackageDeliveryWorkflow, event model.PackageEventType, at time.Time) {
+	if !w.PackageEvents {
+		return
+	}
+
+	ctx := c.activityContext(w, activities.RecordPackageEventActivityName)
+
+	err := workflow.ExecuteActivity(ctx, activities.RecordPackageEventActivityName, &activities.RecordPackageEventInput{
+		Event: model.PackageEvent{PackageID: w.Package.ID, Event: event, OccurredAt: at.UTC()},
+	}).Get(ctx, nil)
+	if err != nil {
+		c.Logger.Warn("Failed to record package event", zap.String("packageId", w.Package.ID), zap.String("event", string(event)), zap.Error(err))
+	}
+}
@@ -0,0 +1,274 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-test/internal/activities"
+	"go-test/internal/model"
+	"go-test/repository"
+	"go.temporal.io/api/common/v1"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/workflow"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrNothingToRetry    = errors.New("package delivery is neither parked nor failed on an activity")
+	ErrDisputeOpen       = errors.New("package has an open dispute")
+	ErrInvalidTransition = errors.New("invalid forced transition")
+
+	// errTransitionForced ends a workflow step once an operator has forced
+	// the final status of the delivery.
+	errTransitionForced = errors.New("transition forced")
+)
+
+// ForcedTransitionStatuses are the statuses an operator can force a delivery
+// into.
+var ForcedTransitionStatuses = []model.PackageDeliveryState{
+	model.PackageDeliveryConfirmed,
+	model.PackageDeliveryReturnedToSender,
+	model.PackageDeliveryRefunded,
+	model.PackageDeliveryDisputeClosed,
+	model.PackageDeliveryErrored,
+}
+
+// ForcedTransition is the payload of the force transition signal, which
+// ends the delivery with the given status.
+type ForcedTransition struct {
+	Status   model.PackageDeliveryState `json:"status"`
+	Operator string                     `json:"operator"`
+	Reason   string                     `json:"reason"`
+}
+
+func (t *ForcedTransition) Validate() error {
+	if t.Operator == "" {
+		return fmt.Errorf("%w: operator is required", ErrInvalidTransition)
+	}
+	if t.Reason == "" {
+		return fmt.Errorf("%w: reason is required", ErrInvalidTransition)
+	}
+
+	for _, status := range ForcedTransitionStatuses {
+		if t.Status == status {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: cannot force status %q", ErrInvalidTransition, t.Status)
+}
+
+// forceTransition stores the forced status and ends the delivery with it.
+func (c *PackageDeliveryWorkflowConfig) forceTransition(w *PackageDeliveryWorkflow) error {
+	transition := w.State.ForcedTransition
+
+	c.Logger.Warn("Forcing package delivery status",
+		zap.String("packageId", w.Package.ID),
+		zap.String("status", string(transition.Status)),
+		zap.String("operator", transition.Operator),
+	)
+
+	ctx := c.activityContext(w, activities.ForceTransitionActivityName)
+	err := workflow.ExecuteActivity(ctx, activities.ForceTransitionActivityName, &activities.ForceTransitionInput{
+		DeliveryPackage: w.Package,
+		Status:          transition.Status,
+	}).Get(ctx, nil)
+	if err != nil {
+		c.Logger.Error("Failed to force package delivery status", zap.String("packageId", w.Package.ID), zap.Error(err))
+		return fmt.Errorf("force transition: %w", err)
+	}
+
+	w.WorkflowResult.ForcedTransition = transition
+	c.setStatus(w, transition.Status)
+	c.reportToShipment(w)
+
+	return nil
+}
+
+// RetriedStep tells how RetryFailedStep retried the failed step.
+type RetriedStep struct {
+	// Reset is set when the failed workflow was reset to a new run, and
+	// unset when a parked workflow was asked to retry the step.
+	Reset bool   `json:"reset"`
+	RunID string `json:"run_id"`
+}
+
+// RetryFailedStep retries the step a package delivery is stuck on. A parked
+// workflow is sent the retry resolution. A failed workflow is reset to
+// the workflow task that scheduled its last failed activity, which runs the
+// activity again in a new run. The package is claimed at expectedVersion
+// first, so that only one of several operators retrying it goes ahead.
+func RetryFailedStep(ctx context.Context, c client.Client, packages repository.PackageStore, namespace, workflowID string, expectedVersion int64, operator, reason string) (*RetriedStep, error) {
+	description, err := c.DescribeWorkflowExecution(ctx, workflowID, "")
+	if err != nil {
+		return nil, err
+	}
+	info := description.GetWorkflowExecutionInfo()
+	runID := info.GetExecution().GetRunId()
+
+	switch info.GetStatus() {
+	case enums.WORKFLOW_EXECUTION_STATUS_RUNNING:
+		state, err := queryState(ctx, c, workflowID)
+		if err != nil {
+			return nil, err
+		}
+		if state.Status != model.PackageDeliveryParked {
+			return nil, ErrNothingToRetry
+		}
+		if _, err := repository.ClaimPackageVersion(ctx, packages, workflowID, expectedVersion); err != nil {
+			return nil, err
+		}
+
+		err = c.SignalWorkflow(ctx, workflowID, runID, PackageDeliverySignalResolve, &ManualResolution{
+			Action:   CompensationRetry,
+			Operator: operator,
+			Reason:   reason,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to signal workflow %s: %w", workflowID, err)
+		}
+
+		return &RetriedStep{RunID: runID}, nil
+	case enums.WORKFLOW_EXECUTION_STATUS_FAILED:
+		resetPoint, err := lastFailedActivityTask(ctx, c, workflowID, runID)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := repository.ClaimPackageVersion(ctx, packages, workflowID, expectedVersion); err != nil {
+			return nil, err
+		}
+
+		resp, err := c.ResetWorkflowExecution(ctx, &workflowservice.ResetWorkflowExecutionRequest{
+			Namespace:                 namespace,
+			WorkflowExecution:         &common.WorkflowExecution{WorkflowId: workflowID, RunId: runID},
+			Reason:                    fmt.Sprintf("retry requested by %s: %s", operator, reason),
+			WorkflowTaskFinishEventId: resetPoint,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to reset workflow %s: %w", workflowID, err)
+		}
+
+		return &RetriedStep{Reset: true, RunID: resp.GetRunId()}, nil
+	default:
+		return nil, ErrNothingToRetry
+	}
+}
+
+// lastFailedActivityTask returns the ID of the completed workflow task that
+// scheduled the last activity to fail in the run.
+func lastFailedActivityTask(ctx context.Context, c client.Client, workflowID, runID string) (int64, error) {
+	scheduledBy := make(map[int64]int64)
+	var resetPoint int64
+
+	iter := c.GetWorkflowHistory(ctx, workflowID, runID, false, enums.HISTORY_EVENT_FILTER_TYPE_ALL_EVENT)
+	for iter.HasNext() {
+		event, err := iter.Next()
+		if err != nil {
+			return 0, fmt.Errorf("unable to read history of workflow %s: %w", workflowID, err)
+		}
+
+		switch event.GetEventType() {
+		case enums.EVENT_TYPE_ACTIVITY_TASK_SCHEDULED:
+			scheduledBy[event.GetEventId()] = event.GetActivityTaskScheduledEventAttributes().GetWorkflowTaskCompletedEventId()
+		case enums.EVENT_TYPE_ACTIVITY_TASK_FAILED:
+			resetPoint = scheduledBy[event.GetActivityTaskFailedEventAttributes().GetScheduledEventId()]
+		case enums.EVENT_TYPE_ACTIVITY_TASK_TIMED_OUT:
+			resetPoint = scheduledBy[event.GetActivityTaskTimedOutEventAttributes().GetScheduledEventId()]
+		}
+	}
+
+	if resetPoint == 0 {
+		return 0, ErrNothingToRetry
+	}
+
+	return resetPoint, nil
+}
+
+// ForcePackageTransition ends the delivery of a package with the status of
+// the transition, provided the stored package is still at expectedVersion.
+// A running workflow is signalled once the package is claimed and stores the
+// status itself, the status of a closed one is stored directly. It reports
+// whether the workflow was running.
+func ForcePackageTransition(ctx context.Context, c client.Client, packages repository.PackageStore, workflowID string, transition *ForcedTransition, expectedVersion int64) (bool, error) {
+	if err := transition.Validate(); err != nil {
+		return false, err
+	}
+
+	description, err := c.DescribeWorkflowExecution(ctx, workflowID, "")
+	if err != nil {
+		return false, err
+	}
+	info := description.GetWorkflowExecutionInfo()
+	runID := info.GetExecution().GetRunId()
+
+	if info.GetStatus() == enums.WORKFLOW_EXECUTION_STATUS_RUNNING {
+		state, err := queryState(ctx, c, workflowID)
+		if err != nil {
+			return false, err
+		}
+		if state.Dispute != nil {
+			return false, ErrDisputeOpen
+		}
+		if _, err := repository.ClaimPackageVersion(ctx, packages, workflowID, expectedVersion); err != nil {
+			return false, err
+		}
+
+		if err := c.SignalWorkflow(ctx, workflowID, runID, PackageDeliverySignalForceTransition, transition); err != nil {
+			return false, fmt.Errorf("unable to signal workflow %s: %w", workflowID, err)
+		}
+
+		return true, nil
+	}
+
+	deliveryPackage, err := packages.GetPackageDelivery(ctx, workflowID)
+	if errors.Is(err, repository.ErrPackageNotFound) {
+		deliveryPackage, err = startedPackage(ctx, c, workflowID, runID)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	_, err = repository.SetPackageStatus(ctx, packages, deliveryPackage, transition.Status, expectedVersion)
+	return false, err
+}
+
+// startedPackage reads the package a workflow run was started with.
+func startedPackage(ctx context.Context, c client.Client, workflowID, runID string) (*model.DeliveryPackage, error) {
+	iter := c.GetWorkflowHistory(ctx, workflowID, runID, false, enums.HISTORY_EVENT_FILTER_TYPE_ALL_EVENT)
+	if !iter.HasNext() {
+		return nil, fmt.Errorf("workflow %s has no history", workflowID)
+	}
+
+	event, err := iter.Next()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read history of workflow %s: %w", workflowID, err)
+	}
+
+	var params PackageDeliveryWorkflowParams
+	input := event.GetWorkflowExecutionStartedEventAttributes().GetInput()
+	if err := converter.GetDefaultDataConverter().FromPayloads(input, &params); err != nil {
+		return nil, fmt.Errorf("unable to decode input of workflow %s: %w", workflowID, err)
+	}
+	if params.DeliveryPackage == nil {
+		return nil, fmt.Errorf("workflow %s has no package", workflowID)
+	}
+
+	return params.DeliveryPackage, nil
+}
+
+func queryState(ctx context.Context, c client.Client, workflowID string) (*PackageDeliveryWorkflowResult, error) {
+	value, err := c.QueryWorkflow(ctx, workflowID, "", PackageDeliveryStateQuery)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query workflow %s: %w", workflowID, err)
+	}
+
+	var state PackageDeliveryWorkflowResult
+	if err := value.Get(&state); err != nil {
+		return nil, fmt.Errorf("unable to decode state of workflow %s: %w", workflowID, err)
+	}
+
+	return &state, nil
+}
@@ -0,0 +1,73 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"go-test/internal/activities"
+	"go-test/internal/config"
+	"go-test/internal/model"
+	"time"
+)
+
+func newTestTransition(status model.PackageDeliveryState) *ForcedTransition {
+	return &ForcedTransition{Status: status, Operator: "ops", Reason: "lost in the depot"}
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestForcedTransitionEndsWaitingDelivery() {
+	f := s.fixture
+	signalAfter(f.env, time.Hour, PackageDeliverySignalForceTransition, newTestTransition(model.PackageDeliveryReturnedToSender))
+
+	f.execute(newTestParams())
+
+	result, err := f.result()
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryReturnedToSender, result.Status)
+	s.Require().NotNil(result.ForcedTransition)
+	s.Equal("ops", result.ForcedTransition.Operator)
+
+	stored, err := f.store.GetPackageDelivery(context.Background(), testPackageID)
+	s.Require().NoError(err)
+	s.Equal(model.PackageDeliveryReturnedToSender, stored.Status)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestInvalidForcedTransitionIsIgnored() {
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).Return(nil)
+
+	signalAfter(f.env, time.Hour, PackageDeliverySignalForceTransition, &ForcedTransition{Status: model.PackageDeliveryReturnedToSender, Operator: "ops"})
+	signalAfter(f.env, 2*time.Hour, PackageDeliverySignalForceTransition, newTestTransition(model.PackageDeliveryParked))
+	f.confirmAfter(3 * time.Hour)
+
+	f.execute(newTestParams())
+
+	result, err := f.result()
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryNotified, result.Status)
+	s.Nil(result.ForcedTransition)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestForcedTransitionEndsParkedStep() {
+	s.fixture = newPackageDeliveryFixture(&s.WorkflowTestSuite, config.WorkflowConfig{},
+		withCompensationPolicy(activities.NotifyDeliveryActivityName, CompensationPark))
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).
+		Return(errors.New("webhook responded with status code: 503"))
+
+	var parked model.PackageDeliveryState
+	f.confirmAfter(time.Minute)
+	f.queryStatusAfter(time.Hour, &parked)
+	signalAfter(f.env, 2*time.Hour, PackageDeliverySignalForceTransition, newTestTransition(model.PackageDeliveryErrored))
+
+	f.execute(newTestParams())
+
+	s.Equal(model.PackageDeliveryParked, parked)
+	result, err := f.result()
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryErrored, result.Status)
+	s.Empty(f.events.Events())
+
+	stored, err := f.store.GetPackageDelivery(context.Background(), testPackageID)
+	s.Require().NoError(err)
+	s.Equal(model.PackageDeliveryErrored, stored.Status)
+}
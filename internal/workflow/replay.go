@@ -0,0 +1,52 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"go-test/internal/config"
+	"go.temporal.io/sdk/log"
+	"go.temporal.io/sdk/worker"
+	"go.uber.org/zap"
+	"path/filepath"
+	"sort"
+)
+
+// NewReplayer returns a workflow replayer with the same workflow
+// registrations as the worker.
+func NewReplayer(cfg config.WorkflowConfig, logger *zap.Logger) worker.WorkflowReplayer {
+	replayer := worker.NewWorkflowReplayer()
+	RegisterWorkflows(replayer, cfg, logger)
+
+	return replayer
+}
+
+// HistoryFiles lists the exported workflow histories in dir.
+func HistoryFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// ReplayHistories replays every history in dir against the current workflow
+// code and returns the errors of all histories that failed to replay, e.g.
+// because of nondeterminism.
+func ReplayHistories(replayer worker.WorkflowReplayer, logger log.Logger, dir string) error {
+	files, err := HistoryFiles(dir)
+	if err != nil {
+		return fmt.Errorf("unable to list workflow histories: %w", err)
+	}
+
+	var errs []error
+	for _, file := range files {
+		if err := replayer.ReplayWorkflowHistoryFromJSONFile(logger, file); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", filepath.Base(file), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,38 @@
+// Package replaytest replays exported package delivery and shipment histories
+// against the current workflow code. Export new histories with
+// "histories export" whenever a workflow change is guarded by a change ID.
+package replaytest
+
+import (
+	"go-test/internal/config"
+	"go-test/internal/workflow"
+	"go.temporal.io/sdk/log"
+	"go.uber.org/zap"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+)
+
+const historiesDir = "testdata/histories"
+
+func TestReplayWorkflowHistories(t *testing.T) {
+	files, err := workflow.HistoryFiles(historiesDir)
+	if err != nil {
+		t.Fatalf("HistoryFiles: %v", err)
+	}
+	if len(files) == 0 {
+		t.Fatalf("no workflow histories found in %s", historiesDir)
+	}
+
+	replayer := workflow.NewReplayer(config.WorkflowConfig{}, zap.NewNop())
+	logger := log.NewStructuredLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			if err := replayer.ReplayWorkflowHistoryFromJSONFile(logger, file); err != nil {
+				t.Fatalf("replay failed: %v", err)
+			}
+		})
+	}
+}
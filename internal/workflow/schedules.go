@@ -0,0 +1,54 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
+)
+
+// createSchedule creates the schedule unless it exists already, and reports
+// whether it did.
+func createSchedule(ctx context.Context, c client.Client, options client.ScheduleOptions) (bool, error) {
+	_, err := c.ScheduleClient().Create(ctx, options)
+	if errors.Is(err, temporal.ErrScheduleAlreadyRunning) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("unable to create schedule %s: %w", options.ID, err)
+	}
+
+	return true, nil
+}
+
+// ensureSchedule creates the schedule, or brings the spec and action of the
+// existing one in line with options, so that every worker start applies the
+// current configuration.
+func ensureSchedule(ctx context.Context, c client.Client, options client.ScheduleOptions) error {
+	created, err := createSchedule(ctx, c, options)
+	if err != nil || created {
+		return err
+	}
+
+	return updateSchedule(ctx, c, options.ID, options.Spec, options.Action)
+}
+
+// updateSchedule replaces the spec and action of an existing schedule.
+func updateSchedule(ctx context.Context, c client.Client, scheduleID string, spec client.ScheduleSpec, action client.ScheduleAction) error {
+	handle := c.ScheduleClient().GetHandle(ctx, scheduleID)
+	err := handle.Update(ctx, client.ScheduleUpdateOptions{
+		DoUpdate: func(input client.ScheduleUpdateInput) (*client.ScheduleUpdate, error) {
+			schedule := input.Description.Schedule
+			schedule.Spec = &spec
+			schedule.Action = action
+
+			return &client.ScheduleUpdate{Schedule: &schedule}, nil
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to update schedule %s: %w", scheduleID, err)
+	}
+
+	return nil
+}
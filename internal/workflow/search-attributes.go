@@ -0,0 +1,198 @@
+package workflow
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go-test/internal/model"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/operatorservice/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+	"go.uber.org/zap"
+	"strings"
+)
+
+// Custom search attributes of the package delivery workflow, usable in
+// visibility queries once registered with RegisterSearchAttributes.
+const (
+	SearchAttributePackageStatus     = "PackageStatus"
+	SearchAttributeCustomerEmailHash = "CustomerEmailHash"
+	SearchAttributeCreatedAt         = "CreatedAt"
+	SearchAttributeRegion            = "Region"
+	SearchAttributeStatusChangedAt   = "StatusChangedAt"
+)
+
+var (
+	packageStatusKey     = temporal.NewSearchAttributeKeyKeyword(SearchAttributePackageStatus)
+	customerEmailHashKey = temporal.NewSearchAttributeKeyKeyword(SearchAttributeCustomerEmailHash)
+	createdAtKey         = temporal.NewSearchAttributeKeyTime(SearchAttributeCreatedAt)
+	regionKey            = temporal.NewSearchAttributeKeyKeyword(SearchAttributeRegion)
+	statusChangedAtKey   = temporal.NewSearchAttributeKeyTime(SearchAttributeStatusChangedAt)
+)
+
+var searchAttributeTypes = map[string]enums.IndexedValueType{
+	SearchAttributePackageStatus:     enums.INDEXED_VALUE_TYPE_KEYWORD,
+	SearchAttributeCustomerEmailHash: enums.INDEXED_VALUE_TYPE_KEYWORD,
+	SearchAttributeCreatedAt:         enums.INDEXED_VALUE_TYPE_DATETIME,
+	SearchAttributeRegion:            enums.INDEXED_VALUE_TYPE_KEYWORD,
+	SearchAttributeStatusChangedAt:   enums.INDEXED_VALUE_TYPE_DATETIME,
+}
+
+// CustomerEmailHasher computes the CustomerEmailHash search attribute, so
+// that customers can be searched for without exposing their address in the
+// visibility store. The hash is keyed with a configured secret, as a plain
+// hash of an address is easily guessed back.
+type CustomerEmailHasher struct {
+	key []byte
+}
+
+func NewCustomerEmailHasher(secret string) *CustomerEmailHasher {
+	return &CustomerEmailHasher{key: []byte(secret)}
+}
+
+// Enabled reports whether a secret is configured. Without one, the attribute
+// is neither published nor searched.
+func (h *CustomerEmailHasher) Enabled() bool {
+	return len(h.key) > 0
+}
+
+// Hash returns the CustomerEmailHash of the address.
+func (h *CustomerEmailHasher) Hash(email string) string {
+	mac := hmac.New(sha256.New, h.key)
+	mac.Write([]byte(normalizeEmail(email)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SearchHashes returns the values the CustomerEmailHash of the address may
+// have: the keyed hash, and the plain one published by workflows started
+// before the hash was keyed.
+func (h *CustomerEmailHasher) SearchHashes(email string) []string {
+	return []string{h.Hash(email), legacyCustomerEmailHash(email)}
+}
+
+// legacyCustomerEmailHash is the CustomerEmailHash published by workflows
+// started before changeKeyedEmailHash.
+func legacyCustomerEmailHash(email string) string {
+	sum := sha256.Sum256([]byte(normalizeEmail(email)))
+	return hex.EncodeToString(sum[:])
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// RegisterSearchAttributes adds the custom search attributes missing from
+// the namespace. Workflows fail to upsert attributes that are not
+// registered.
+func RegisterSearchAttributes(ctx context.Context, c client.Client, namespace string) error {
+	registered, err := c.OperatorService().ListSearchAttributes(ctx, &operatorservice.ListSearchAttributesRequest{
+		Namespace: namespace,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to list search attributes: %w", err)
+	}
+
+	missing := make(map[string]enums.IndexedValueType)
+	for name, valueType := range searchAttributeTypes {
+		if _, ok := registered.GetCustomAttributes()[name]; !ok {
+			missing[name] = valueType
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	_, err = c.OperatorService().AddSearchAttributes(ctx, &operatorservice.AddSearchAttributesRequest{
+		Namespace:        namespace,
+		SearchAttributes: missing,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to add search attributes: %w", err)
+	}
+
+	return nil
+}
+
+// publishSearchAttributes upserts the search attributes that differ from the
+// ones of the run, which a continued run inherits from the previous one.
+// StatusChangedAt only moves when the status does.
+func (c *PackageDeliveryWorkflowConfig) publishSearchAttributes(w *PackageDeliveryWorkflow) {
+	current := workflow.GetTypedSearchAttributes(w.Ctx)
+
+	var updates []temporal.SearchAttributeUpdate
+	if status, _ := current.GetKeyword(packageStatusKey); status != string(w.WorkflowResult.Status) {
+		updates = append(updates,
+			packageStatusKey.ValueSet(string(w.WorkflowResult.Status)),
+			statusChangedAtKey.ValueSet(workflow.Now(w.Ctx).UTC()),
+		)
+	} else if _, ok := current.GetTime(statusChangedAtKey); !ok {
+		updates = append(updates, statusChangedAtKey.ValueSet(workflow.Now(w.Ctx).UTC()))
+	}
+
+	if emailHash := c.customerEmailHash(w); emailHash != "" {
+		if hash, _ := current.GetKeyword(customerEmailHashKey); hash != emailHash {
+			updates = append(updates, customerEmailHashKey.ValueSet(emailHash))
+		}
+	}
+
+	if _, ok := current.GetTime(createdAtKey); !ok {
+		updates = append(updates, createdAtKey.ValueSet(workflow.GetInfo(w.Ctx).WorkflowStartTime.UTC()))
+	}
+
+	if region, _ := current.GetKeyword(regionKey); w.Package.Region != "" && region != w.Package.Region {
+		updates = append(updates, regionKey.ValueSet(w.Package.Region))
+	}
+
+	c.upsertSearchAttributes(w, updates...)
+}
+
+// customerEmailHash returns the CustomerEmailHash of the package, or "" when
+// no secret is configured.
+func (c *PackageDeliveryWorkflowConfig) customerEmailHash(w *PackageDeliveryWorkflow) string {
+	if !hasChange(w.Ctx, changeKeyedEmailHash) {
+		return legacyCustomerEmailHash(w.Package.CustomerEmail)
+	}
+
+	if !c.CustomerEmailHasher.Enabled() {
+		return ""
+	}
+
+	return c.CustomerEmailHasher.Hash(w.Package.CustomerEmail)
+}
+
+// setStatus changes the status of the workflow and publishes it, along with
+// the time of the change. Reaching a status the delivery report counts also
+// records the matching package event.
+func (c *PackageDeliveryWorkflowConfig) setStatus(w *PackageDeliveryWorkflow, status model.PackageDeliveryState) {
+	previous := w.WorkflowResult.Status
+	w.WorkflowResult.Status = status
+
+	if published, _ := workflow.GetTypedSearchAttributes(w.Ctx).GetKeyword(packageStatusKey); published != string(status) {
+		c.upsertSearchAttributes(w,
+			packageStatusKey.ValueSet(string(status)),
+			statusChangedAtKey.ValueSet(workflow.Now(w.Ctx).UTC()),
+		)
+	}
+
+	if event, ok := packageStatusEvents[status]; ok && previous != status {
+		c.recordPackageEvent(w, event, workflow.Now(w.Ctx))
+	}
+}
+
+// upsertSearchAttributes publishes updates, unless the workflow started
+// before search attributes were introduced. A failed upsert only costs the
+// visibility of the workflow.
+func (c *PackageDeliveryWorkflowConfig) upsertSearchAttributes(w *PackageDeliveryWorkflow, updates ...temporal.SearchAttributeUpdate) {
+	if !w.SearchAttributes || len(updates) == 0 {
+		return
+	}
+
+	if err := workflow.UpsertTypedSearchAttributes(w.Ctx, updates...); err != nil {
+		c.Logger.Warn("Failed to upsert search attributes", zap.String("packageId", w.Package.ID), zap.Error(err))
+	}
+}
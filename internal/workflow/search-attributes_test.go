@@ -0,0 +1,123 @@
+package workflow
+
+import (
+	"github.com/stretchr/testify/mock"
+	"go-test/internal/activities"
+	"go-test/internal/config"
+	"go-test/internal/model"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/temporal"
+	"testing"
+	"time"
+)
+
+func (s *PackageDeliveryWorkflowTestSuite) TestPublishesSearchAttributes() {
+	s.fixture = newPackageDeliveryFixture(&s.WorkflowTestSuite, config.WorkflowConfig{CustomerEmailHashSecret: "secret"})
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).Return(nil)
+
+	var statuses []string
+	var emailHash, region string
+	var createdAt time.Time
+	var statusChanges []time.Time
+	f.env.OnUpsertTypedSearchAttributes(mock.Anything).Run(func(args mock.Arguments) {
+		attributes := args.Get(0).(temporal.SearchAttributes)
+		if status, ok := attributes.GetKeyword(packageStatusKey); ok {
+			statuses = append(statuses, status)
+		}
+		if hash, ok := attributes.GetKeyword(customerEmailHashKey); ok {
+			emailHash = hash
+		}
+		if value, ok := attributes.GetKeyword(regionKey); ok {
+			region = value
+		}
+		if value, ok := attributes.GetTime(createdAtKey); ok {
+			createdAt = value
+		}
+		if value, ok := attributes.GetTime(statusChangedAtKey); ok {
+			statusChanges = append(statusChanges, value)
+		}
+	}).Return(nil)
+
+	f.attemptAfter(time.Hour, model.AttemptNobodyHome)
+	f.confirmAfter(2 * time.Hour)
+
+	params := newTestParams()
+	params.DeliveryPackage.Region = "eu-central"
+	f.execute(params)
+
+	_, err := f.result()
+	s.NoError(err)
+
+	// Confirmed, saved and notified share one value, which is published once.
+	s.Equal([]string{
+		string(model.PackageDeliveryInProgress),
+		string(model.PackageDeliveryAttemptFailed),
+		string(model.PackageDeliveryConfirmed),
+	}, statuses)
+	s.Equal(NewCustomerEmailHasher("secret").Hash("Customer@Example.com "), emailHash)
+	s.NotEqual(legacyCustomerEmailHash("customer@example.com"), emailHash)
+	s.Equal("eu-central", region)
+	s.False(createdAt.IsZero())
+
+	// Every published status comes with the time it was set.
+	s.Require().Len(statusChanges, 3)
+	s.Equal(time.Hour, statusChanges[1].Sub(statusChanges[0]))
+}
+
+func TestExecutionFilterVisibilityQuery(t *testing.T) {
+	query, err := ExecutionFilter{
+		Status:              model.PackageDeliveryAttemptFailed,
+		CustomerEmailHashes: []string{"abc123", "def456"},
+		Region:              "eu-central",
+		CreatedBefore:       time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		StatusChangedBefore: time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC),
+		Running:             true,
+		ExecutionStatuses:   []enums.WorkflowExecutionStatus{enums.WORKFLOW_EXECUTION_STATUS_RUNNING, enums.WORKFLOW_EXECUTION_STATUS_FAILED},
+	}.VisibilityQuery()
+	if err != nil {
+		t.Fatalf("VisibilityQuery: %v", err)
+	}
+
+	want := "WorkflowType = 'package-delivery-workflow'" +
+		" AND PackageStatus = 'attemptFailed'" +
+		" AND Region = 'eu-central'" +
+		" AND CustomerEmailHash IN ('abc123', 'def456')" +
+		" AND CreatedAt < '2026-10-01T00:00:00Z'" +
+		" AND StatusChangedAt < '2026-10-02T00:00:00Z'" +
+		" AND ExecutionStatus = 'Running'" +
+		" AND ExecutionStatus IN ('Running', 'Failed')"
+	if query != want {
+		t.Fatalf("VisibilityQuery = %q, want %q", query, want)
+	}
+
+	if _, err := (ExecutionFilter{Region: "eu' OR 1=1"}).VisibilityQuery(); err == nil {
+		t.Fatal("VisibilityQuery accepted a quoted region")
+	}
+	if _, err := (ExecutionFilter{CustomerEmailHashes: []string{"ab') OR ('1"}}).VisibilityQuery(); err == nil {
+		t.Fatal("VisibilityQuery accepted a quoted customer email hash")
+	}
+}
+
+func TestCustomerEmailHasher(t *testing.T) {
+	hasher := NewCustomerEmailHasher("secret")
+	if !hasher.Enabled() || NewCustomerEmailHasher("").Enabled() {
+		t.Fatal("Enabled does not follow the secret")
+	}
+
+	hash := hasher.Hash("Customer@Example.com ")
+	if hash != hasher.Hash("customer@example.com") {
+		t.Errorf("Hash does not normalize the address")
+	}
+	if hash == NewCustomerEmailHasher("other").Hash("customer@example.com") {
+		t.Errorf("Hash does not depend on the secret")
+	}
+	if hash == legacyCustomerEmailHash("customer@example.com") {
+		t.Errorf("Hash is the unkeyed hash")
+	}
+
+	want := []string{hash, legacyCustomerEmailHash("customer@example.com")}
+	if got := hasher.SearchHashes("customer@example.com"); len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("SearchHashes = %v, want %v", got, want)
+	}
+}
@@ -2,6 +2,9 @@ package workflow
 
 import (
 	"go-test/internal/activities"
+	"go-test/internal/adapters"
+	"go-test/internal/config"
+	"go-test/internal/templates"
 	"go-test/repository"
 	"go.temporal.io/sdk/activity"
 	"go.temporal.io/sdk/worker"
@@ -9,20 +12,143 @@ import (
 	"go.uber.org/zap"
 )
 
-func SetupWorkflow(w worker.Worker, r *repository.Repository, logger *zap.Logger) {
-	w.RegisterWorkflowWithOptions(NewPackageDeliveryWorkflowConfig(logger).PackageDeliveryWorkflow, workflow.RegisterOptions{
+// WorkflowRegistry is implemented by both worker.Worker and
+// worker.WorkflowReplayer, so replays run the exact registrations of the
+// worker.
+type WorkflowRegistry interface {
+	RegisterWorkflowWithOptions(w interface{}, options workflow.RegisterOptions)
+}
+
+func SetupWorkflow(w worker.Worker, cfg config.WorkflowConfig, r repository.Store, events activities.EventSender, links activities.ConfirmationLinkIssuer, finder activities.StuckPackageFinder, webhooks *adapters.Webhooks, logger *zap.Logger) {
+	RegisterWorkflows(w, cfg, logger)
+
+	SetupActivities(w.RegisterActivityWithOptions, r, events, links, finder, webhooks, logger)
+}
+
+func RegisterWorkflows(registry WorkflowRegistry, cfg config.WorkflowConfig, logger *zap.Logger) {
+	workflowConfig := NewPackageDeliveryWorkflowConfig(logger, cfg)
+
+	registry.RegisterWorkflowWithOptions(workflowConfig.PackageDeliveryWorkflow, workflow.RegisterOptions{
 		Name: PackageDeliveryWorkflowName,
 	})
 
-	SetupActivities(w.RegisterActivityWithOptions, r, logger)
+	registry.RegisterWorkflowWithOptions(workflowConfig.DisputeResolutionWorkflow, workflow.RegisterOptions{
+		Name: DisputeResolutionWorkflowName,
+	})
+
+	registry.RegisterWorkflowWithOptions(workflowConfig.ShipmentWorkflow, workflow.RegisterOptions{
+		Name: ShipmentWorkflowName,
+	})
+
+	registry.RegisterWorkflowWithOptions(workflowConfig.StuckPackageScanWorkflow, workflow.RegisterOptions{
+		Name: StuckPackageScanWorkflowName,
+	})
+
+	registry.RegisterWorkflowWithOptions(workflowConfig.DeliveryReportWorkflow, workflow.RegisterOptions{
+		Name: DeliveryReportWorkflowName,
+	})
 }
 
-func SetupActivities(RegisterActivityWithOptions func(a interface{}, options activity.RegisterOptions), r *repository.Repository, logger *zap.Logger) {
+func SetupActivities(RegisterActivityWithOptions func(a interface{}, options activity.RegisterOptions), r repository.Store, events activities.EventSender, links activities.ConfirmationLinkIssuer, finder activities.StuckPackageFinder, webhooks *adapters.Webhooks, logger *zap.Logger) {
+	renderer := templates.NewEngine(r, logger)
+
+	RegisterActivityWithOptions(activities.NewRequestConfirmation(links, r, renderer, webhooks, logger).RequestConfirmationActivity, activity.RegisterOptions{
+		Name: activities.RequestConfirmationActivityName,
+	})
+
 	RegisterActivityWithOptions(activities.NewSaveDelivery(r, logger).SaveDeliveryActivity, activity.RegisterOptions{
 		Name: activities.SaveDeliveryActivityName,
 	})
 
-	RegisterActivityWithOptions(activities.NewNotifyDelivery(logger).NotifyDeliveryActivity, activity.RegisterOptions{
+	RegisterActivityWithOptions(activities.NewNotifyDelivery(renderer, webhooks, logger).NotifyDeliveryActivity, activity.RegisterOptions{
 		Name: activities.NotifyDeliveryActivityName,
 	})
+
+	RegisterActivityWithOptions(activities.NewCustomerPreferences(r, logger).LoadCustomerPreferencesActivity, activity.RegisterOptions{
+		Name: activities.LoadCustomerPreferencesActivityName,
+	})
+
+	RegisterActivityWithOptions(activities.NewNotifyArrival(renderer, webhooks, logger).NotifyArrivalActivity, activity.RegisterOptions{
+		Name: activities.NotifyArrivalActivityName,
+	})
+
+	compensateDelivery := activities.NewCompensateDelivery(r, events, logger)
+
+	RegisterActivityWithOptions(compensateDelivery.MarkNotificationFailedActivity, activity.RegisterOptions{
+		Name: activities.MarkNotificationFailedActivityName,
+	})
+
+	RegisterActivityWithOptions(compensateDelivery.RollbackDeliveryActivity, activity.RegisterOptions{
+		Name: activities.RollbackDeliveryActivityName,
+	})
+
+	RegisterActivityWithOptions(compensateDelivery.PublishCompensationEventActivity, activity.RegisterOptions{
+		Name: activities.PublishCompensationEventActivityName,
+	})
+
+	disputes := activities.NewDisputes(r, webhooks, logger)
+
+	RegisterActivityWithOptions(disputes.RecordDisputeActivity, activity.RegisterOptions{
+		Name: activities.RecordDisputeActivityName,
+	})
+
+	RegisterActivityWithOptions(disputes.NotifySupportActivity, activity.RegisterOptions{
+		Name: activities.NotifySupportActivityName,
+	})
+
+	RegisterActivityWithOptions(disputes.ResolveDisputeActivity, activity.RegisterOptions{
+		Name: activities.ResolveDisputeActivityName,
+	})
+
+	deliveryAttempts := activities.NewDeliveryAttempts(r, r, renderer, webhooks, logger)
+
+	RegisterActivityWithOptions(deliveryAttempts.RecordAttemptActivity, activity.RegisterOptions{
+		Name: activities.RecordAttemptActivityName,
+	})
+
+	RegisterActivityWithOptions(deliveryAttempts.NotifyFailedAttemptActivity, activity.RegisterOptions{
+		Name: activities.NotifyFailedAttemptActivityName,
+	})
+
+	RegisterActivityWithOptions(deliveryAttempts.ReturnToSenderActivity, activity.RegisterOptions{
+		Name: activities.ReturnToSenderActivityName,
+	})
+
+	shipments := activities.NewShipments(r, r, renderer, webhooks, logger)
+
+	RegisterActivityWithOptions(shipments.SaveShipmentActivity, activity.RegisterOptions{
+		Name: activities.SaveShipmentActivityName,
+	})
+
+	RegisterActivityWithOptions(shipments.NotifyShipmentActivity, activity.RegisterOptions{
+		Name: activities.NotifyShipmentActivityName,
+	})
+
+	stuckPackages := activities.NewStuckPackages(finder, r, webhooks, logger)
+
+	RegisterActivityWithOptions(stuckPackages.FindStuckPackagesActivity, activity.RegisterOptions{
+		Name: activities.FindStuckPackagesActivityName,
+	})
+
+	RegisterActivityWithOptions(stuckPackages.AlertStuckPackagesActivity, activity.RegisterOptions{
+		Name: activities.AlertStuckPackagesActivityName,
+	})
+
+	RegisterActivityWithOptions(activities.NewRemediation(r, logger).ForceTransitionActivity, activity.RegisterOptions{
+		Name: activities.ForceTransitionActivityName,
+	})
+
+	deliveryReports := activities.NewDeliveryReports(r, webhooks, logger)
+
+	RegisterActivityWithOptions(deliveryReports.RecordPackageEventActivity, activity.RegisterOptions{
+		Name: activities.RecordPackageEventActivityName,
+	})
+
+	RegisterActivityWithOptions(deliveryReports.SummarizeDeliveriesActivity, activity.RegisterOptions{
+		Name: activities.SummarizeDeliveriesActivityName,
+	})
+
+	RegisterActivityWithOptions(deliveryReports.SendDeliveryReportActivity, activity.RegisterOptions{
+		Name: activities.SendDeliveryReportActivityName,
+	})
 }
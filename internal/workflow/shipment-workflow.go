@@ -0,0 +1,276 @@
+package workflow
+
+import (
+	"encoding/json"
+	"go-test/internal/activities"
+	"go-test/internal/model"
+	"go.temporal.io/sdk/workflow"
+	"go.uber.org/zap"
+)
+
+// ShipmentWorkflow delivers the parcels of a shipment as child package
+// delivery workflows. It sends one confirmation link for the shipment,
+// aggregates the state of the parcels and, once every parcel is done,
+// stores the shipment and sends a single notification.
+func (c *PackageDeliveryWorkflowConfig) ShipmentWorkflow(
+	ctx workflow.Context,
+	params *ShipmentWorkflowParams,
+) (*ShipmentWorkflowResult, error) {
+	result := &ShipmentWorkflowResult{Shipment: *params.Shipment.Clone()}
+	for i := range result.Parcels {
+		result.Parcels[i].Status = model.PackageDeliveryInProgress
+	}
+	result.Status = result.AggregateStatus()
+
+	c.Logger.Info("Starting shipment workflow", zap.String("shipmentId", result.ID), zap.Int("parcels", len(result.Parcels)))
+
+	if err := workflow.SetQueryHandler(ctx, ShipmentStateQuery, func() (ShipmentWorkflowResult, error) {
+		return *result, nil
+	}); err != nil {
+		return result, err
+	}
+
+	sel := workflow.NewSelector(ctx)
+	parcels := make([]workflow.ChildWorkflowFuture, len(result.Parcels))
+	pending := len(result.Parcels)
+	started := false
+
+	// Confirmations are passed on to the parcels, so the update waits for
+	// them to start.
+	if err := workflow.SetUpdateHandlerWithOptions(ctx, ShipmentUpdateConfirm,
+		func(ctx workflow.Context, confirmation *model.DeliveryConfirmation) (*PackageUpdateResult, error) {
+			if err := workflow.Await(ctx, func() bool { return started }); err != nil {
+				return nil, err
+			}
+
+			// Only duplicates are refused: the shipment is then confirmed,
+			// whatever the state of its parcels.
+			return &PackageUpdateResult{
+				Outcome:      c.acceptShipmentConfirmation(ctx, result, parcels, confirmation),
+				Status:       model.PackageDeliveryConfirmed,
+				Confirmation: result.Confirmation,
+			}, nil
+		},
+		workflow.UpdateHandlerOptions{
+			Validator: func(ctx workflow.Context, confirmation *model.DeliveryConfirmation) error {
+				return confirmation.Validate()
+			},
+		}); err != nil {
+		return result, err
+	}
+
+	for i, parcel := range result.Parcels {
+		childCtx := workflow.WithChildOptions(ctx, workflow.ChildWorkflowOptions{
+			WorkflowID: parcel.PackageID,
+		})
+
+		parcels[i] = workflow.ExecuteChildWorkflow(childCtx, PackageDeliveryWorkflowName, &PackageDeliveryWorkflowParams{
+			DeliveryPackage:  result.Package(parcel),
+			ActivityPolicies: params.ActivityPolicies,
+			ShipmentID:       result.ID,
+		})
+
+		i := i
+		sel.AddFuture(parcels[i], func(f workflow.Future) {
+			pending--
+			c.completeParcel(ctx, result, i, f)
+		})
+	}
+
+	// Parcels are signalled by the shipment, so they must have started. A
+	// parcel that failed to start completes with the error.
+	for i, parcel := range parcels {
+		if err := parcel.GetChildWorkflowExecution().Get(ctx, nil); err != nil {
+			c.Logger.Error("Failed to start parcel delivery", zap.String("packageId", result.Parcels[i].PackageID), zap.Error(err))
+		}
+	}
+
+	started = true
+
+	c.requestShipmentConfirmation(ctx, result, params)
+
+	for _, name := range []string{ShipmentSignalConfirm, shipmentSignalConfirmLegacy} {
+		sel.AddReceive(workflow.GetSignalChannel(ctx, name), func(ch workflow.ReceiveChannel, more bool) {
+			var payload json.RawMessage
+			ch.Receive(ctx, &payload)
+
+			c.confirmShipment(ctx, result, parcels, payload)
+		})
+	}
+
+	sel.AddReceive(workflow.GetSignalChannel(ctx, ShipmentSignalParcelUpdate), func(ch workflow.ReceiveChannel, more bool) {
+		var update model.ShipmentParcel
+		ch.Receive(ctx, &update)
+
+		c.updateParcel(result, update)
+	})
+
+	for pending > 0 {
+		sel.Select(ctx)
+		result.Status = result.AggregateStatus()
+	}
+
+	saveCtx := c.activityOptions(ctx, activities.SaveShipmentActivityName, params.ActivityPolicies)
+
+	err := workflow.ExecuteActivity(saveCtx, activities.SaveShipmentActivityName, &activities.ShipmentInput{
+		Shipment: &result.Shipment,
+	}).Get(ctx, nil)
+	if err != nil {
+		c.Logger.Error("Failed to save shipment", zap.String("shipmentId", result.ID), zap.Error(err))
+
+		return result, err
+	}
+
+	// Parcels of a shipment are not notified on their own, the customer gets
+	// a single notification for the delivered ones.
+	if !c.allowsShipmentNotification(ctx, result, params, model.NotificationShipmentDelivered) {
+		return result, nil
+	}
+
+	notifyCtx := c.activityOptions(ctx, activities.NotifyShipmentActivityName, params.ActivityPolicies)
+
+	err = workflow.ExecuteActivity(notifyCtx, activities.NotifyShipmentActivityName, &activities.ShipmentInput{
+		Shipment: &result.Shipment,
+	}).Get(ctx, nil)
+	if err != nil {
+		c.Logger.Warn("Failed to notify shipment", zap.String("shipmentId", result.ID), zap.Error(err))
+	}
+
+	return result, nil
+}
+
+// requestShipmentConfirmation sends the customer one confirmation link for
+// the whole shipment. Parcels can still be confirmed one by one.
+func (c *PackageDeliveryWorkflowConfig) requestShipmentConfirmation(ctx workflow.Context, result *ShipmentWorkflowResult, params *ShipmentWorkflowParams) {
+	if !c.allowsShipmentNotification(ctx, result, params, model.NotificationConfirmationRequest) {
+		return
+	}
+
+	requestCtx := c.activityOptions(ctx, activities.RequestConfirmationActivityName, params.ActivityPolicies)
+
+	err := workflow.ExecuteActivity(requestCtx, activities.RequestConfirmationActivityName, &activities.RequestConfirmationInput{
+		DeliveryPackage: &model.DeliveryPackage{
+			ID:              result.ID,
+			CustomerEmail:   result.CustomerEmail,
+			DeliveryAddress: result.DeliveryAddress,
+			Region:          result.Region,
+		},
+	}).Get(ctx, nil)
+	if err != nil {
+		c.Logger.Warn("Failed to request shipment confirmation", zap.String("shipmentId", result.ID), zap.Error(err))
+	}
+}
+
+// allowsShipmentNotification reports whether to send the customer of the
+// shipment a notification of kind, once their quiet hours are over. Failed
+// lookups of the preferences let the notification through.
+func (c *PackageDeliveryWorkflowConfig) allowsShipmentNotification(ctx workflow.Context, result *ShipmentWorkflowResult, params *ShipmentWorkflowParams, kind model.NotificationKind) bool {
+	if !hasChange(ctx, changeQuietHoursEverywhere) {
+		return true
+	}
+
+	loadCtx := c.activityOptions(ctx, activities.LoadCustomerPreferencesActivityName, params.ActivityPolicies)
+
+	preferences, err := loadCustomerPreferences(ctx, loadCtx, result.CustomerEmail)
+	if err != nil {
+		c.Logger.Warn("Failed to load customer preferences", zap.String("shipmentId", result.ID), zap.Error(err))
+		return true
+	}
+
+	if !preferences.Allows(kind) {
+		c.Logger.Info("Skipping notification the customer opted out of", zap.String("shipmentId", result.ID), zap.String("kind", string(kind)))
+		return false
+	}
+
+	if wait := quietHoursDelay(ctx, preferences); wait > 0 {
+		c.Logger.Info("Deferring notification until the end of the quiet hours", zap.String("shipmentId", result.ID), zap.Duration("wait", wait))
+
+		if err := workflow.Sleep(ctx, wait); err != nil {
+			c.Logger.Warn("Failed to wait for the end of the quiet hours", zap.String("shipmentId", result.ID), zap.Error(err))
+			return false
+		}
+	}
+
+	return true
+}
+
+// confirmShipment passes a valid confirm signal on to the parcels.
+func (c *PackageDeliveryWorkflowConfig) confirmShipment(ctx workflow.Context, result *ShipmentWorkflowResult, parcels []workflow.ChildWorkflowFuture, payload json.RawMessage) {
+	confirmation := &model.DeliveryConfirmation{}
+	if err := json.Unmarshal(payload, confirmation); err != nil {
+		c.Logger.Warn("Ignoring malformed shipment confirmation", zap.String("shipmentId", result.ID), zap.Error(err))
+		return
+	}
+
+	if err := confirmation.Validate(); err != nil {
+		c.Logger.Warn("Ignoring invalid shipment confirmation", zap.String("shipmentId", result.ID), zap.Error(err))
+		return
+	}
+
+	c.acceptShipmentConfirmation(ctx, result, parcels, confirmation)
+}
+
+// acceptShipmentConfirmation passes the first confirmation of the shipment on
+// to every parcel that is not confirmed yet. Parcels with an open dispute
+// ignore it.
+func (c *PackageDeliveryWorkflowConfig) acceptShipmentConfirmation(ctx workflow.Context, result *ShipmentWorkflowResult, parcels []workflow.ChildWorkflowFuture, confirmation *model.DeliveryConfirmation) UpdateOutcome {
+	if result.Confirmation != nil {
+		result.DuplicateConfirmations++
+		c.Logger.Info("Ignoring duplicate shipment confirmation", zap.String("shipmentId", result.ID), zap.String("confirmedBy", confirmation.ConfirmedBy))
+		return UpdateDuplicate
+	}
+
+	result.Confirmation = confirmation
+
+	for i, parcel := range result.Parcels {
+		if parcel.Completed || parcel.Confirmation != nil {
+			continue
+		}
+
+		if err := parcels[i].SignalChildWorkflow(ctx, PackageDeliverySignalConfirm, confirmation).Get(ctx, nil); err != nil {
+			c.Logger.Warn("Failed to confirm parcel", zap.String("packageId", parcel.PackageID), zap.Error(err))
+		}
+	}
+
+	return UpdateAccepted
+}
+
+func (c *PackageDeliveryWorkflowConfig) updateParcel(result *ShipmentWorkflowResult, update model.ShipmentParcel) {
+	for i := range result.Parcels {
+		parcel := &result.Parcels[i]
+		if parcel.PackageID != update.PackageID {
+			continue
+		}
+
+		// The final state recorded on completion wins over late updates.
+		if parcel.Completed {
+			return
+		}
+
+		parcel.Status = update.Status
+		if update.Confirmation != nil {
+			parcel.Confirmation = update.Confirmation
+		}
+		return
+	}
+
+	c.Logger.Warn("Ignoring update of unknown parcel", zap.String("shipmentId", result.ID), zap.String("packageId", update.PackageID))
+}
+
+// completeParcel records the final state of a parcel.
+func (c *PackageDeliveryWorkflowConfig) completeParcel(ctx workflow.Context, result *ShipmentWorkflowResult, i int, f workflow.Future) {
+	parcel := &result.Parcels[i]
+	parcel.Completed = true
+
+	var parcelResult PackageDeliveryWorkflowResult
+	if err := f.Get(ctx, &parcelResult); err != nil {
+		c.Logger.Error("Parcel delivery failed", zap.String("packageId", parcel.PackageID), zap.Error(err))
+		parcel.Status = model.PackageDeliveryErrored
+		return
+	}
+
+	parcel.Status = parcelResult.Status
+	if parcelResult.Confirmation != nil {
+		parcel.Confirmation = parcelResult.Confirmation
+	}
+}
@@ -0,0 +1,38 @@
+package workflow
+
+import (
+	"go-test/internal/config"
+	"go-test/internal/model"
+)
+
+const ShipmentWorkflowName = "shipment-workflow"
+
+// ShipmentIDPrefix starts the IDs of shipments, which share the workflow ID
+// space with packages.
+const ShipmentIDPrefix = "shipment-"
+
+const (
+	ShipmentSignalConfirm = "confirm-shipment"
+	ShipmentUpdateConfirm = "confirm-shipment-delivery"
+	ShipmentStateQuery    = "shipment-state"
+
+	// ShipmentSignalParcelUpdate is sent by the parcels of a shipment.
+	ShipmentSignalParcelUpdate = "parcel-update"
+
+	// shipmentSignalConfirmLegacy is the confirm signal of shipments started
+	// when they shared the signal names of packages.
+	shipmentSignalConfirmLegacy = PackageDeliverySignalConfirm
+)
+
+type ShipmentWorkflowParams struct {
+	Shipment *model.Shipment
+	// ActivityPolicies is passed on to every parcel.
+	ActivityPolicies map[string]config.ActivityPolicy `json:",omitempty"`
+}
+
+type ShipmentWorkflowResult struct {
+	model.Shipment
+	// Confirmation is the confirmation of the whole shipment, if any.
+	Confirmation           *model.DeliveryConfirmation `json:"confirmation,omitempty"`
+	DuplicateConfirmations int                         `json:"duplicateConfirmations,omitempty"`
+}
@@ -0,0 +1,223 @@
+package workflow
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go-test/internal/activities"
+	"go-test/internal/config"
+	"go-test/internal/model"
+	"go-test/repository"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+// ShipmentWorkflowTestSuite runs the shipment workflow with the package
+// delivery workflows of its parcels and the activities both register.
+type ShipmentWorkflowTestSuite struct {
+	suite.Suite
+	testsuite.WorkflowTestSuite
+
+	env   *testsuite.TestWorkflowEnvironment
+	store *repository.MemoryRepository
+
+	// confirmationRequests counts the confirmation links sent to the
+	// customer.
+	confirmationRequests int
+	// shipmentNotifications collects the shipments sent to the customer.
+	shipmentNotifications []*model.Shipment
+}
+
+func TestShipmentWorkflow(t *testing.T) {
+	suite.Run(t, new(ShipmentWorkflowTestSuite))
+}
+
+func (s *ShipmentWorkflowTestSuite) SetupTest() {
+	s.env = s.NewTestWorkflowEnvironment()
+	s.store = repository.NewMemoryRepository()
+	s.confirmationRequests = 0
+	s.shipmentNotifications = nil
+
+	workflowConfig := NewPackageDeliveryWorkflowConfig(zap.NewNop(), config.WorkflowConfig{})
+	s.env.RegisterWorkflowWithOptions(workflowConfig.ShipmentWorkflow, workflow.RegisterOptions{
+		Name: ShipmentWorkflowName,
+	})
+	s.env.RegisterWorkflowWithOptions(workflowConfig.PackageDeliveryWorkflow, workflow.RegisterOptions{
+		Name: PackageDeliveryWorkflowName,
+	})
+	s.env.RegisterWorkflowWithOptions(workflowConfig.DisputeResolutionWorkflow, workflow.RegisterOptions{
+		Name: DisputeResolutionWorkflowName,
+	})
+
+	names := append([]string{
+		activities.SaveShipmentActivityName,
+		activities.NotifyShipmentActivityName,
+	}, packageDeliveryActivities...)
+	registerActivities(s.env, testActivities{store: s.store}, names...)
+
+	s.env.OnActivity(activities.RequestConfirmationActivityName, mock.Anything, mock.Anything).
+		Return(func(context.Context, *activities.RequestConfirmationInput) error {
+			s.confirmationRequests++
+			return nil
+		}).
+		Maybe()
+
+	s.env.OnActivity(activities.NotifyShipmentActivityName, mock.Anything, mock.Anything).
+		Return(func(_ context.Context, input *activities.ShipmentInput) error {
+			s.shipmentNotifications = append(s.shipmentNotifications, input.Shipment.Clone())
+			return nil
+		}).
+		Maybe()
+
+	s.env.OnActivity(activities.NotifySupportActivityName, mock.Anything, mock.Anything).Return(nil).Maybe()
+}
+
+func (s *ShipmentWorkflowTestSuite) AfterTest(_, _ string) {
+	s.env.AssertExpectations(s.T())
+}
+
+// executeShipment runs the shipment workflow under the shipment ID, which
+// its parcels report to.
+func (s *ShipmentWorkflowTestSuite) executeShipment(shipment *model.Shipment) {
+	s.env.SetStartWorkflowOptions(client.StartWorkflowOptions{ID: shipment.ID})
+	s.env.ExecuteWorkflow(ShipmentWorkflowName, &ShipmentWorkflowParams{Shipment: shipment})
+}
+
+func (s *ShipmentWorkflowTestSuite) TestShipmentConfirmationCompletesEveryParcel() {
+	parcelNotifications := 0
+	s.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).
+		Return(func(context.Context, *activities.NotifyDeliveryInput) error {
+			parcelNotifications++
+			return nil
+		}).
+		Maybe()
+	signalAfter(s.env, time.Hour, ShipmentSignalConfirm, newTestConfirmation(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	s.executeShipment(newTestShipment(3))
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+	var result ShipmentWorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(model.ShipmentCompleted, result.Status)
+	for _, parcel := range result.Parcels {
+		s.True(parcel.Delivered(), parcel.PackageID)
+		s.Equal("customer@example.com", parcel.Confirmation.ConfirmedBy)
+
+		_, err := s.store.GetPackageDelivery(context.Background(), parcel.PackageID)
+		s.NoError(err)
+	}
+
+	s.Equal(1, s.confirmationRequests, "one confirmation link for the whole shipment")
+	s.Zero(parcelNotifications, "parcels are not notified on their own")
+	s.Len(s.shipmentNotifications, 1)
+
+	stored, err := s.store.GetShipment(context.Background(), testShipmentID)
+	s.NoError(err)
+	s.Equal(model.ShipmentCompleted, stored.Status)
+	s.Len(stored.Parcels, 3)
+}
+
+func (s *ShipmentWorkflowTestSuite) TestShipmentPartialConfirmation() {
+	shipment := newTestShipment(2)
+
+	var partial ShipmentWorkflowResult
+	s.env.RegisterDelayedCallback(func() {
+		courier := newTestConfirmation(s.env.Now())
+		courier.ConfirmedBy = "courier"
+		s.NoError(s.env.SignalWorkflowByID(shipment.Parcels[0].PackageID, PackageDeliverySignalConfirm, courier))
+	}, time.Minute)
+	s.env.RegisterDelayedCallback(func() {
+		value, err := s.env.QueryWorkflow(ShipmentStateQuery)
+		s.NoError(err)
+		s.NoError(value.Get(&partial))
+	}, time.Hour)
+	signalAfter(s.env, 2*time.Hour, ShipmentSignalConfirm, newTestConfirmation(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	s.executeShipment(shipment)
+
+	s.NoError(s.env.GetWorkflowError())
+	s.Equal(model.ShipmentPartiallyConfirmed, partial.Status)
+	s.NotNil(partial.Parcels[0].Confirmation)
+	s.Nil(partial.Parcels[1].Confirmation)
+
+	var result ShipmentWorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(model.ShipmentCompleted, result.Status)
+	s.Equal("courier", result.Parcels[0].Confirmation.ConfirmedBy, "confirming the shipment keeps earlier parcel confirmations")
+	s.Equal("customer@example.com", result.Parcels[1].Confirmation.ConfirmedBy)
+}
+
+func (s *ShipmentWorkflowTestSuite) TestShipmentWithRefundedParcelCompletesWithIssues() {
+	shipment := newTestShipment(2)
+	disputed := shipment.Parcels[1].PackageID
+
+	s.env.RegisterDelayedCallback(func() {
+		s.NoError(s.env.SignalWorkflowByID(disputed, PackageDeliverySignalDispute, newTestDispute(s.env.Now())))
+	}, time.Minute)
+	signalAfter(s.env, time.Hour, ShipmentSignalConfirm, newTestConfirmation(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+	s.env.RegisterDelayedCallback(func() {
+		s.NoError(s.env.SignalWorkflowByID(DisputeWorkflowID(disputed, 1), DisputeResolutionSignalResolve, &model.DisputeResolution{
+			Action:     model.DisputeRefund,
+			ResolvedBy: "operator",
+		}))
+	}, 2*time.Hour)
+
+	s.executeShipment(shipment)
+
+	s.NoError(s.env.GetWorkflowError())
+	var result ShipmentWorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(model.ShipmentCompletedWithIssues, result.Status)
+	s.True(result.Parcels[0].Delivered())
+	s.Equal(model.PackageDeliveryRefunded, result.Parcels[1].Status)
+	s.Nil(result.Parcels[1].Confirmation, "a disputed parcel ignores the shipment confirmation")
+	s.Len(s.shipmentNotifications, 1)
+}
+
+func (s *ShipmentWorkflowTestSuite) TestShipmentConfirmUpdateCompletesEveryParcel() {
+
+	var update PackageUpdateResult
+	var updateErr error
+	updateAfter(s.env, time.Hour, ShipmentUpdateConfirm, newTestConfirmation(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)), &update, &updateErr)
+
+	s.executeShipment(newTestShipment(2))
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+	var result ShipmentWorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(model.ShipmentCompleted, result.Status)
+
+	s.NoError(updateErr)
+	s.Equal(UpdateAccepted, update.Outcome)
+	s.Equal("customer@example.com", update.Confirmation.ConfirmedBy)
+}
+
+func (s *ShipmentWorkflowTestSuite) TestShipmentAcceptsLegacyConfirmSignal() {
+	signalAfter(s.env, time.Hour, shipmentSignalConfirmLegacy, newTestConfirmation(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	s.executeShipment(newTestShipment(2))
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+	var result ShipmentWorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(model.ShipmentCompleted, result.Status)
+}
+
+func (s *ShipmentWorkflowTestSuite) TestShipmentNotificationSkippedOnOptOut() {
+	saveTestPreferences(s.store, &model.CustomerPreferences{OptOutAll: true})
+	signalAfter(s.env, time.Hour, ShipmentSignalConfirm, newTestConfirmation(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	s.executeShipment(newTestShipment(2))
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+	s.Zero(s.confirmationRequests)
+	s.Empty(s.shipmentNotifications)
+}
@@ -0,0 +1,151 @@
+package workflow
+
+import (
+	"encoding/json"
+	"go-test/internal/model"
+	"go.temporal.io/sdk/workflow"
+	"go.uber.org/zap"
+)
+
+// receiveSignals consumes the confirm, dispute, delivery attempt and force
+// transition signals until ctx is cancelled. The first valid confirmation is accepted, later ones are
+// counted as duplicates and reported through the state query.
+func (c *PackageDeliveryWorkflowConfig) receiveSignals(ctx workflow.Context, w *PackageDeliveryWorkflow, validate bool) {
+	sel := workflow.NewSelector(ctx)
+
+	sel.AddReceive(workflow.GetSignalChannel(ctx, PackageDeliverySignalConfirm), func(ch workflow.ReceiveChannel, more bool) {
+		var payload json.RawMessage
+		ch.Receive(ctx, &payload)
+
+		c.handleConfirmation(w, payload, validate)
+	})
+
+	sel.AddReceive(workflow.GetSignalChannel(ctx, PackageDeliverySignalDispute), func(ch workflow.ReceiveChannel, more bool) {
+		var payload json.RawMessage
+		ch.Receive(ctx, &payload)
+
+		c.handleDispute(w, payload)
+	})
+
+	sel.AddReceive(workflow.GetSignalChannel(ctx, PackageDeliverySignalAttempt), func(ch workflow.ReceiveChannel, more bool) {
+		var payload json.RawMessage
+		ch.Receive(ctx, &payload)
+
+		c.handleAttemptSignal(w, payload)
+	})
+
+	sel.AddReceive(workflow.GetSignalChannel(ctx, PackageDeliverySignalForceTransition), func(ch workflow.ReceiveChannel, more bool) {
+		var payload json.RawMessage
+		ch.Receive(ctx, &payload)
+
+		c.handleForceTransition(w, payload)
+	})
+
+	sel.AddReceive(ctx.Done(), func(ch workflow.ReceiveChannel, more bool) {})
+
+	for ctx.Err() == nil {
+		sel.Select(ctx)
+	}
+}
+
+// handleConfirmation records a confirm signal. Workflows started before
+// confirmations were typed accept any payload, as they always did.
+func (c *PackageDeliveryWorkflowConfig) handleConfirmation(w *PackageDeliveryWorkflow, payload json.RawMessage, validate bool) {
+	confirmation := &model.DeliveryConfirmation{}
+	if err := json.Unmarshal(payload, confirmation); err != nil && validate {
+		c.Logger.Warn("Ignoring malformed delivery confirmation", zap.String("packageId", w.Package.ID), zap.Error(err))
+		return
+	}
+
+	if validate {
+		if err := confirmation.Validate(); err != nil {
+			c.Logger.Warn("Ignoring invalid delivery confirmation", zap.String("packageId", w.Package.ID), zap.Error(err))
+			return
+		}
+	}
+
+	c.acceptConfirmation(w, confirmation)
+}
+
+// handleDispute records a dispute signal.
+func (c *PackageDeliveryWorkflowConfig) handleDispute(w *PackageDeliveryWorkflow, payload json.RawMessage) {
+	dispute := &model.Dispute{}
+	if err := json.Unmarshal(payload, dispute); err != nil {
+		c.Logger.Warn("Ignoring malformed dispute", zap.String("packageId", w.Package.ID), zap.Error(err))
+		return
+	}
+
+	if err := dispute.Validate(); err != nil {
+		c.Logger.Warn("Ignoring invalid dispute", zap.String("packageId", w.Package.ID), zap.Error(err))
+		return
+	}
+
+	c.acceptDispute(w, dispute)
+}
+
+// handleAttemptSignal queues a delivery attempt signal.
+func (c *PackageDeliveryWorkflowConfig) handleAttemptSignal(w *PackageDeliveryWorkflow, payload json.RawMessage) {
+	attempt := model.DeliveryAttempt{}
+	if err := json.Unmarshal(payload, &attempt); err != nil {
+		c.Logger.Warn("Ignoring malformed delivery attempt", zap.String("packageId", w.Package.ID), zap.Error(err))
+		return
+	}
+
+	if err := attempt.Validate(); err != nil {
+		c.Logger.Warn("Ignoring invalid delivery attempt", zap.String("packageId", w.Package.ID), zap.Error(err))
+		return
+	}
+
+	c.acceptAttempt(w, attempt)
+}
+
+// handleForceTransition records the status an operator forces the delivery
+// into. The first valid transition wins.
+func (c *PackageDeliveryWorkflowConfig) handleForceTransition(w *PackageDeliveryWorkflow, payload json.RawMessage) {
+	transition := &ForcedTransition{}
+	if err := json.Unmarshal(payload, transition); err != nil {
+		c.Logger.Warn("Ignoring malformed forced transition", zap.String("packageId", w.Package.ID), zap.Error(err))
+		return
+	}
+
+	if err := transition.Validate(); err != nil {
+		c.Logger.Warn("Ignoring invalid forced transition", zap.String("packageId", w.Package.ID), zap.Error(err))
+		return
+	}
+
+	if w.State.ForcedTransition != nil {
+		c.Logger.Info("Ignoring forced transition while another one is pending", zap.String("packageId", w.Package.ID), zap.String("operator", transition.Operator))
+		return
+	}
+
+	w.State.ForcedTransition = transition
+}
+
+// drainSignals handles the signals still buffered once receiveSignals has
+// stopped, before the workflow continues as new.
+func (c *PackageDeliveryWorkflowConfig) drainSignals(w *PackageDeliveryWorkflow, validate bool) {
+	drain := func(name string, handle func(payload json.RawMessage)) {
+		ch := workflow.GetSignalChannel(w.Ctx, name)
+		for {
+			var payload json.RawMessage
+			if !ch.ReceiveAsync(&payload) {
+				return
+			}
+
+			handle(payload)
+		}
+	}
+
+	drain(PackageDeliverySignalConfirm, func(payload json.RawMessage) {
+		c.handleConfirmation(w, payload, validate)
+	})
+	drain(PackageDeliverySignalDispute, func(payload json.RawMessage) {
+		c.handleDispute(w, payload)
+	})
+	drain(PackageDeliverySignalAttempt, func(payload json.RawMessage) {
+		c.handleAttemptSignal(w, payload)
+	})
+	drain(PackageDeliverySignalForceTransition, func(payload json.RawMessage) {
+		c.handleForceTransition(w, payload)
+	})
+}
@@ -0,0 +1,171 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"go-test/internal/activities"
+	"go-test/internal/config"
+	"go-test/internal/model"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/workflow"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	StuckPackageScanWorkflowName = "stuck-package-scan-workflow"
+	StuckPackageScanScheduleID   = "stuck-package-scan"
+)
+
+const DefaultStuckScanInterval = 15 * time.Minute
+
+// DefaultStuckThresholds are used when no threshold is configured. Packages
+// waiting for the customer are given much longer than the ones waiting for
+// an operator.
+func DefaultStuckThresholds() map[string]config.Duration {
+	return map[string]config.Duration{
+		string(model.PackageDeliveryInProgress):         config.Duration(14 * 24 * time.Hour),
+		string(model.PackageDeliveryAttemptFailed):      config.Duration(72 * time.Hour),
+		string(model.PackageDeliveryDisputed):           config.Duration(72 * time.Hour),
+		string(model.PackageDeliveryParked):             config.Duration(time.Hour),
+		string(model.PackageDeliveryErrored):            config.Duration(15 * time.Minute),
+		string(model.PackageDeliveryNotificationFailed): config.Duration(time.Hour),
+	}
+}
+
+func withStuckDetectionDefaults(cfg config.StuckDetectionConfig) config.StuckDetectionConfig {
+	if cfg.ScanInterval <= 0 {
+		cfg.ScanInterval = config.Duration(DefaultStuckScanInterval)
+	}
+	if len(cfg.Thresholds) == 0 {
+		cfg.Thresholds = DefaultStuckThresholds()
+	}
+
+	return cfg
+}
+
+type StuckPackageScanResult struct {
+	ScannedAt time.Time            `json:"scannedAt"`
+	Packages  []model.StuckPackage `json:"packages,omitempty"`
+}
+
+// StuckPackageScanWorkflow looks for packages that have kept their status
+// for longer than its threshold and raises a single alert for all of them.
+// It is started by the stuck package scan schedule.
+func (c *PackageDeliveryWorkflowConfig) StuckPackageScanWorkflow(ctx workflow.Context) (*StuckPackageScanResult, error) {
+	result := &StuckPackageScanResult{ScannedAt: workflow.Now(ctx).UTC()}
+
+	thresholds := make(map[model.PackageDeliveryState]time.Duration, len(c.StuckDetection.Thresholds))
+	for status, threshold := range c.StuckDetection.Thresholds {
+		thresholds[model.PackageDeliveryState(status)] = threshold.Duration()
+	}
+
+	findCtx := c.activityOptions(ctx, activities.FindStuckPackagesActivityName, nil)
+	err := workflow.ExecuteActivity(findCtx, activities.FindStuckPackagesActivityName, &activities.FindStuckPackagesInput{
+		ScannedAt:  result.ScannedAt,
+		Thresholds: thresholds,
+	}).Get(ctx, &result.Packages)
+	if err != nil {
+		c.Logger.Error("Failed to find stuck packages", zap.Error(err))
+		return nil, err
+	}
+
+	if len(result.Packages) == 0 {
+		return result, nil
+	}
+
+	c.Logger.Warn("Found stuck packages", zap.Int("packages", len(result.Packages)))
+
+	alertCtx := c.activityOptions(ctx, activities.AlertStuckPackagesActivityName, nil)
+	err = workflow.ExecuteActivity(alertCtx, activities.AlertStuckPackagesActivityName, &activities.AlertStuckPackagesInput{
+		Alert: model.StuckPackagesAlert{ScannedAt: result.ScannedAt, Packages: result.Packages},
+	}).Get(ctx, nil)
+	if err != nil {
+		c.Logger.Error("Failed to alert stuck packages", zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// EnsureStuckPackageScanSchedule creates the schedule that starts the stuck
+// package scan every scan interval. A scan still running when the next one
+// is due makes the schedule skip it.
+func EnsureStuckPackageScanSchedule(ctx context.Context, c client.Client, cfg config.StuckDetectionConfig) error {
+	cfg = withStuckDetectionDefaults(cfg)
+
+	err := ensureSchedule(ctx, c, client.ScheduleOptions{
+		ID: StuckPackageScanScheduleID,
+		Spec: client.ScheduleSpec{
+			Intervals: []client.ScheduleIntervalSpec{{Every: cfg.ScanInterval.Duration()}},
+		},
+		Action: &client.ScheduleWorkflowAction{
+			ID:        StuckPackageScanWorkflowName,
+			Workflow:  StuckPackageScanWorkflowName,
+			TaskQueue: PackageDeliveryTaskQueueName,
+		},
+		Overlap: enums.SCHEDULE_OVERLAP_POLICY_SKIP,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to schedule the stuck package scan: %w", err)
+	}
+
+	return nil
+}
+
+// VisibilityStuckPackageFinder finds stuck packages through the search
+// attributes of their workflows. Only workflows that publish
+// StatusChangedAt can be found.
+type VisibilityStuckPackageFinder struct {
+	Client client.Client
+}
+
+func NewVisibilityStuckPackageFinder(c client.Client) *VisibilityStuckPackageFinder {
+	return &VisibilityStuckPackageFinder{Client: c}
+}
+
+// FindStuckPackages returns the running workflows with the status, and the
+// failed ones that have not been reset or restarted since.
+func (f *VisibilityStuckPackageFinder) FindStuckPackages(ctx context.Context, status model.PackageDeliveryState, changedBefore time.Time, limit int) ([]model.StuckPackage, error) {
+	summaries, _, err := ListExecutionPage(ctx, f.Client, ExecutionFilter{
+		Status:              status,
+		StatusChangedBefore: changedBefore,
+		ExecutionStatuses: []enums.WorkflowExecutionStatus{
+			enums.WORKFLOW_EXECUTION_STATUS_RUNNING,
+			enums.WORKFLOW_EXECUTION_STATUS_FAILED,
+		},
+	}, limit, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	stuck := make([]model.StuckPackage, 0, len(summaries))
+	for _, summary := range summaries {
+		running := summary.ExecutionStatus == enums.WORKFLOW_EXECUTION_STATUS_RUNNING.String()
+
+		if !running {
+			current, err := f.Client.DescribeWorkflowExecution(ctx, summary.WorkflowID, "")
+			if err != nil {
+				return nil, fmt.Errorf("unable to describe workflow %s: %w", summary.WorkflowID, err)
+			}
+			if current.GetWorkflowExecutionInfo().GetExecution().GetRunId() != summary.RunID {
+				continue
+			}
+		}
+
+		stuckPackage := model.StuckPackage{
+			PackageID: summary.WorkflowID,
+			RunID:     summary.RunID,
+			Status:    summary.PackageStatus,
+			Running:   running,
+		}
+		if summary.StatusChangedAt != nil {
+			stuckPackage.StatusChangedAt = *summary.StatusChangedAt
+		}
+
+		stuck = append(stuck, stuckPackage)
+	}
+
+	return stuck, nil
+}
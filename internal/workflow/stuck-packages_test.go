@@ -0,0 +1,123 @@
+package workflow
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go-test/internal/activities"
+	"go-test/internal/config"
+	"go-test/internal/model"
+	"go-test/repository"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+// StuckPackageScanTestSuite runs the stuck package scan against a stub
+// finder and collects the alerts it raises.
+type StuckPackageScanTestSuite struct {
+	suite.Suite
+	testsuite.WorkflowTestSuite
+
+	env   *testsuite.TestWorkflowEnvironment
+	store *repository.MemoryRepository
+	stuck *stubStuckPackageFinder
+
+	// alerts collects the alerts raised by the scan.
+	alerts []model.StuckPackagesAlert
+}
+
+func TestStuckPackageScan(t *testing.T) {
+	suite.Run(t, new(StuckPackageScanTestSuite))
+}
+
+func newStuckDetectionConfig() config.WorkflowConfig {
+	return config.WorkflowConfig{
+		StuckDetection: config.StuckDetectionConfig{
+			Thresholds: map[string]config.Duration{
+				string(model.PackageDeliveryParked):  config.Duration(time.Hour),
+				string(model.PackageDeliveryErrored): config.Duration(15 * time.Minute),
+			},
+		},
+	}
+}
+
+func (s *StuckPackageScanTestSuite) SetupTest() {
+	s.env = s.NewTestWorkflowEnvironment()
+	s.store = repository.NewMemoryRepository()
+	s.stuck = newStubStuckPackageFinder()
+	s.alerts = nil
+
+	workflowConfig := NewPackageDeliveryWorkflowConfig(zap.NewNop(), newStuckDetectionConfig())
+	s.env.RegisterWorkflowWithOptions(workflowConfig.StuckPackageScanWorkflow, workflow.RegisterOptions{
+		Name: StuckPackageScanWorkflowName,
+	})
+
+	registerActivities(s.env, testActivities{store: s.store, finder: s.stuck},
+		activities.FindStuckPackagesActivityName,
+		activities.AlertStuckPackagesActivityName,
+	)
+
+	s.env.OnActivity(activities.AlertStuckPackagesActivityName, mock.Anything, mock.Anything).
+		Return(func(_ context.Context, input *activities.AlertStuckPackagesInput) error {
+			s.alerts = append(s.alerts, input.Alert)
+			return nil
+		}).
+		Maybe()
+}
+
+func (s *StuckPackageScanTestSuite) AfterTest(_, _ string) {
+	s.env.AssertExpectations(s.T())
+}
+
+func (s *StuckPackageScanTestSuite) TestAlertsStuckPackages() {
+
+	scannedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	s.env.SetStartTime(scannedAt)
+
+	s.stuck.packages[model.PackageDeliveryParked] = []model.StuckPackage{
+		{PackageID: "pkg-parked", Status: model.PackageDeliveryParked, Running: true},
+	}
+	s.stuck.packages[model.PackageDeliveryErrored] = []model.StuckPackage{
+		{PackageID: "pkg-failed", Status: model.PackageDeliveryErrored},
+		{PackageID: "pkg-settled", Status: model.PackageDeliveryErrored},
+	}
+
+	// An operator has already moved the package of the failed workflow on.
+	settled := newTestPackage()
+	settled.ID = "pkg-settled"
+	settled.Status = model.PackageDeliveryReturnedToSender
+	_, err := s.store.CreatePackageDelivery(context.Background(), settled)
+	s.Require().NoError(err)
+
+	s.env.ExecuteWorkflow(StuckPackageScanWorkflowName)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	s.True(s.stuck.searched[model.PackageDeliveryParked].Equal(scannedAt.Add(-time.Hour)))
+	s.True(s.stuck.searched[model.PackageDeliveryErrored].Equal(scannedAt.Add(-15 * time.Minute)))
+	s.Len(s.stuck.searched, 2)
+
+	s.Require().Len(s.alerts, 1)
+	alert := s.alerts[0]
+	s.True(alert.ScannedAt.Equal(scannedAt))
+	s.Require().Len(alert.Packages, 2)
+	s.Equal("pkg-failed", alert.Packages[0].PackageID)
+	s.Equal("pkg-parked", alert.Packages[1].PackageID)
+
+	var result StuckPackageScanResult
+	s.Require().NoError(s.env.GetWorkflowResult(&result))
+	s.Len(result.Packages, 2)
+}
+
+func (s *StuckPackageScanTestSuite) TestWithoutStuckPackages() {
+
+	s.env.ExecuteWorkflow(StuckPackageScanWorkflowName)
+
+	s.NoError(s.env.GetWorkflowError())
+	s.Empty(s.alerts)
+	s.Len(s.stuck.searched, 2)
+}
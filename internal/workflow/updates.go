@@ -0,0 +1,146 @@
+package workflow
+
+import (
+	"go-test/internal/model"
+	"go.temporal.io/sdk/workflow"
+	"go.uber.org/zap"
+)
+
+// UpdateOutcome tells the caller of an update what the workflow made of it.
+type UpdateOutcome string
+
+const (
+	UpdateAccepted UpdateOutcome = "accepted"
+	// UpdateDuplicate is returned for a confirmation of a package that is
+	// already confirmed.
+	UpdateDuplicate UpdateOutcome = "duplicate"
+	// UpdateConflict is returned when the state of the delivery does not
+	// allow the update, e.g. a dispute of a confirmed package.
+	UpdateConflict UpdateOutcome = "conflict"
+)
+
+// PackageUpdateResult is the result of the package delivery updates.
+type PackageUpdateResult struct {
+	Outcome UpdateOutcome              `json:"outcome"`
+	Status  model.PackageDeliveryState `json:"status"`
+	// Confirmation is the confirmation that counts, which is not the one of
+	// the update for duplicates.
+	Confirmation *model.DeliveryConfirmation `json:"confirmation,omitempty"`
+	// Dispute is the open dispute, with the ID assigned by the workflow.
+	Dispute *model.Dispute `json:"dispute,omitempty"`
+}
+
+// setUpdateHandlers registers the confirm, dispute and delivery attempt
+// updates. Their validators reject malformed requests before they reach the
+// history; the handlers apply the request to the state at once, so
+// concurrent updates are decided one after the other.
+func (c *PackageDeliveryWorkflowConfig) setUpdateHandlers(w *PackageDeliveryWorkflow) error {
+	err := workflow.SetUpdateHandlerWithOptions(w.Ctx, PackageDeliveryUpdateConfirm,
+		func(ctx workflow.Context, confirmation *model.DeliveryConfirmation) (*PackageUpdateResult, error) {
+			return c.updateResult(w, c.acceptConfirmation(w, confirmation)), nil
+		},
+		workflow.UpdateHandlerOptions{
+			Validator: func(ctx workflow.Context, confirmation *model.DeliveryConfirmation) error {
+				return confirmation.Validate()
+			},
+		})
+	if err != nil {
+		return err
+	}
+
+	err = workflow.SetUpdateHandlerWithOptions(w.Ctx, PackageDeliveryUpdateDispute,
+		func(ctx workflow.Context, dispute *model.Dispute) (*PackageUpdateResult, error) {
+			return c.updateResult(w, c.acceptDispute(w, dispute)), nil
+		},
+		workflow.UpdateHandlerOptions{
+			Validator: func(ctx workflow.Context, dispute *model.Dispute) error {
+				return dispute.Validate()
+			},
+		})
+	if err != nil {
+		return err
+	}
+
+	return workflow.SetUpdateHandlerWithOptions(w.Ctx, PackageDeliveryUpdateAttempt,
+		func(ctx workflow.Context, attempt *model.DeliveryAttempt) (*PackageUpdateResult, error) {
+			return c.updateResult(w, c.acceptAttempt(w, *attempt)), nil
+		},
+		workflow.UpdateHandlerOptions{
+			Validator: func(ctx workflow.Context, attempt *model.DeliveryAttempt) error {
+				return attempt.Validate()
+			},
+		})
+}
+
+func (c *PackageDeliveryWorkflowConfig) updateResult(w *PackageDeliveryWorkflow, outcome UpdateOutcome) *PackageUpdateResult {
+	return &PackageUpdateResult{
+		Outcome:      outcome,
+		Status:       w.WorkflowResult.Status,
+		Confirmation: w.State.Confirmation,
+		Dispute:      w.State.Dispute,
+	}
+}
+
+// acceptConfirmation records the first confirmation of the delivery. Later
+// ones are counted as duplicates. Confirmations are refused before the
+// delivery window and while a dispute is open.
+func (c *PackageDeliveryWorkflowConfig) acceptConfirmation(w *PackageDeliveryWorkflow, confirmation *model.DeliveryConfirmation) UpdateOutcome {
+	if w.State.AwaitingWindow {
+		c.Logger.Warn("Ignoring delivery confirmation before the delivery window", zap.String("packageId", w.Package.ID))
+		return UpdateConflict
+	}
+
+	if w.State.Dispute != nil {
+		c.Logger.Warn("Ignoring delivery confirmation of a disputed package", zap.String("packageId", w.Package.ID))
+		return UpdateConflict
+	}
+
+	if w.State.Confirmation != nil {
+		w.WorkflowResult.DuplicateConfirmations++
+
+		c.Logger.Info("Ignoring duplicate delivery confirmation",
+			zap.String("packageId", w.Package.ID),
+			zap.String("confirmedBy", confirmation.ConfirmedBy),
+			zap.Int("duplicates", w.WorkflowResult.DuplicateConfirmations),
+		)
+		return UpdateDuplicate
+	}
+
+	w.State.Confirmation = confirmation
+	w.WorkflowResult.Confirmation = confirmation
+
+	return UpdateAccepted
+}
+
+// acceptDispute records a dispute, unless the delivery is already confirmed
+// or another dispute is open. The dispute gets the ID of the resolution
+// workflow that will handle it.
+func (c *PackageDeliveryWorkflowConfig) acceptDispute(w *PackageDeliveryWorkflow, dispute *model.Dispute) UpdateOutcome {
+	if w.State.Confirmation != nil {
+		c.Logger.Warn("Ignoring dispute of a confirmed delivery", zap.String("packageId", w.Package.ID))
+		return UpdateConflict
+	}
+
+	if w.State.Dispute != nil {
+		c.Logger.Info("Ignoring dispute while another one is open", zap.String("packageId", w.Package.ID), zap.String("raisedBy", dispute.RaisedBy))
+		return UpdateConflict
+	}
+
+	dispute.ID = DisputeWorkflowID(w.Package.ID, len(w.WorkflowResult.DisputeOutcomes)+1)
+	w.State.Dispute = dispute
+
+	return UpdateAccepted
+}
+
+// acceptAttempt queues a delivery attempt for the workflow, unless the
+// delivery is already confirmed or disputed.
+func (c *PackageDeliveryWorkflowConfig) acceptAttempt(w *PackageDeliveryWorkflow, attempt model.DeliveryAttempt) UpdateOutcome {
+	if w.State.Confirmation != nil || w.State.Dispute != nil {
+		c.Logger.Warn("Ignoring delivery attempt of a confirmed or disputed package", zap.String("packageId", w.Package.ID), zap.String("driver", attempt.Driver))
+		return UpdateConflict
+	}
+
+	w.State.PendingAttempts = append(w.State.PendingAttempts, attempt)
+
+	return UpdateAccepted
+}
@@ -0,0 +1,110 @@
+package workflow
+
+import (
+	"github.com/stretchr/testify/mock"
+	"go-test/internal/activities"
+	"go-test/internal/model"
+	"time"
+)
+
+func (s *PackageDeliveryWorkflowTestSuite) TestConfirmUpdateReportsDuplicates() {
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).
+		After(time.Hour).
+		Return(nil)
+
+	var first, second PackageUpdateResult
+	var firstErr, secondErr error
+	duplicate := newTestConfirmation(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	duplicate.ConfirmedBy = "operator"
+
+	updateAfter(f.env, time.Minute, PackageDeliveryUpdateConfirm, newTestConfirmation(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)), &first, &firstErr)
+	updateAfter(f.env, time.Minute+time.Second, PackageDeliveryUpdateConfirm, duplicate, &second, &secondErr)
+
+	f.execute(newTestParams())
+
+	result, err := f.result()
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryNotified, result.Status)
+	s.Equal(1, result.DuplicateConfirmations)
+
+	s.NoError(firstErr)
+	s.Equal(UpdateAccepted, first.Outcome)
+	s.NoError(secondErr)
+	s.Equal(UpdateDuplicate, second.Outcome)
+	s.Equal("customer@example.com", second.Confirmation.ConfirmedBy, "duplicates get the confirmation that counted")
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestConfirmUpdateConflictsWithOpenDispute() {
+	f := s.fixture
+
+	var dispute, confirm PackageUpdateResult
+	var disputeErr, confirmErr error
+
+	updateAfter(f.env, time.Minute, PackageDeliveryUpdateDispute, newTestDispute(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)), &dispute, &disputeErr)
+	updateAfter(f.env, 2*time.Minute, PackageDeliveryUpdateConfirm, newTestConfirmation(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)), &confirm, &confirmErr)
+	f.resolveDisputeAfter(time.Hour, 1, model.DisputeClose)
+
+	f.execute(newTestParams())
+
+	result, err := f.result()
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryDisputeClosed, result.Status)
+	s.Nil(result.Confirmation)
+
+	s.NoError(disputeErr)
+	s.Equal(UpdateAccepted, dispute.Outcome)
+	s.Equal(DisputeWorkflowID(testPackageID, 1), dispute.Dispute.ID)
+
+	s.NoError(confirmErr)
+	s.Equal(UpdateConflict, confirm.Outcome)
+	s.NotNil(confirm.Dispute)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestAttemptUpdateConflictsAfterConfirmation() {
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).
+		After(time.Hour).
+		Return(nil)
+
+	var attempt PackageUpdateResult
+	var attemptErr error
+
+	f.confirmAfter(time.Minute)
+	updateAfter(f.env, time.Minute+time.Second, PackageDeliveryUpdateAttempt, &model.DeliveryAttempt{
+		Outcome:     model.AttemptNobodyHome,
+		Driver:      "driver-1",
+		AttemptedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, &attempt, &attemptErr)
+
+	f.execute(newTestParams())
+
+	result, err := f.result()
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryNotified, result.Status)
+	s.Zero(result.FailedAttempts)
+
+	s.NoError(attemptErr)
+	s.Equal(UpdateConflict, attempt.Outcome)
+}
+
+func (s *PackageDeliveryWorkflowTestSuite) TestInvalidUpdateIsRejected() {
+	f := s.fixture
+	f.env.OnActivity(activities.NotifyDeliveryActivityName, mock.Anything, mock.Anything).Return(nil).Once()
+
+	var update PackageUpdateResult
+	var updateErr error
+
+	updateAfter(f.env, time.Minute, PackageDeliveryUpdateConfirm, &model.DeliveryConfirmation{}, &update, &updateErr)
+	f.confirmAfter(time.Hour)
+
+	f.execute(newTestParams())
+
+	result, err := f.result()
+	s.NoError(err)
+	s.Equal(model.PackageDeliveryNotified, result.Status)
+	s.Zero(result.DuplicateConfirmations)
+
+	s.Error(updateErr)
+	s.Empty(update.Outcome)
+}
@@ -0,0 +1,63 @@
+package workflow
+
+import (
+	"go.temporal.io/sdk/workflow"
+)
+
+// Change IDs for workflow.GetVersion. Any change that adds, removes or
+// reorders commands of PackageDeliveryWorkflow must be guarded by a change
+// ID, so that histories recorded by older code keep replaying. Never reuse
+// or remove an ID while workflows started before it may still be running
+// or queried, and export a history covering the old path to
+// replaytest/testdata.
+const (
+	// changeCompensation guards the compensation steps run once a step has
+	// exhausted its retries. Older workflows simply failed.
+	changeCompensation = "package-delivery-compensation"
+
+	// changeTypedConfirmation guards the validation of confirm signal
+	// payloads. Older workflows accepted any payload.
+	changeTypedConfirmation = "package-delivery-typed-confirmation"
+
+	// changeConfirmationLink guards the confirmation link sent to the
+	// customer before waiting for the confirmation.
+	changeConfirmationLink = "package-delivery-confirmation-link"
+
+	// changeContinueAsNew guards the history limits checked while waiting,
+	// which continue the workflow as new once reached.
+	changeContinueAsNew = "package-delivery-continue-as-new"
+
+	// changeSearchAttributes guards the search attributes upserted on start
+	// and on every status change.
+	changeSearchAttributes = "package-delivery-search-attributes"
+
+	// changePackageEvents guards the package events recorded for the
+	// delivery report on creation, confirmation and errors.
+	changePackageEvents = "package-delivery-package-events"
+
+	// changeDeliveryWindow guards the wait for the delivery window chosen by
+	// the customer and the arrival notification sent before it.
+	changeDeliveryWindow = "package-delivery-delivery-window"
+
+	// changeCustomerPreferences guards the lookup of the customer's
+	// notification preferences before notifying them, which may skip or
+	// defer the notification.
+	changeCustomerPreferences = "package-delivery-customer-preferences"
+
+	// changeKeyedEmailHash guards the keyed CustomerEmailHash. Older
+	// workflows published a plain SHA-256 of the address.
+	changeKeyedEmailHash = "package-delivery-keyed-email-hash"
+
+	// changeQuietHoursEverywhere guards the preferences and quiet hours
+	// applied to every notification. Older workflows only deferred the
+	// delivered notification, sent the confirmation request and the
+	// shipment notification regardless, and reported customers who opted
+	// out of the delivered notification as notified.
+	changeQuietHoursEverywhere = "package-delivery-quiet-hours-everywhere"
+)
+
+// hasChange reports whether the current execution runs the code introduced
+// with changeID, recording the decision in the history on first use.
+func hasChange(ctx workflow.Context, changeID string) bool {
+	return workflow.GetVersion(ctx, changeID, workflow.DefaultVersion, 1) >= 1
+}
@@ -0,0 +1,181 @@
+package workflow
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-test/internal/model"
+	"go.temporal.io/api/common/v1"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/workflow/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"strings"
+	"time"
+)
+
+// ExecutionFilter selects package delivery workflows by their search
+// attributes and execution status.
+type ExecutionFilter struct {
+	Status model.PackageDeliveryState
+	// CustomerEmailHashes selects workflows whose CustomerEmailHash is one
+	// of them, see CustomerEmailHasher.SearchHashes.
+	CustomerEmailHashes []string
+	Region              string
+	CreatedAfter        time.Time
+	CreatedBefore       time.Time
+	// StatusChangedBefore selects workflows whose status has not changed
+	// since, which only workflows publishing StatusChangedAt can match.
+	StatusChangedBefore time.Time
+	Running             bool
+	// ExecutionStatuses selects workflows in one of the statuses.
+	ExecutionStatuses []enums.WorkflowExecutionStatus
+}
+
+// VisibilityQuery returns the visibility query for the filter, restricted to
+// package delivery workflows.
+func (f ExecutionFilter) VisibilityQuery() (string, error) {
+	conditions := []string{fmt.Sprintf("WorkflowType = '%s'", PackageDeliveryWorkflowName)}
+
+	keywords := [][2]string{
+		{SearchAttributePackageStatus, string(f.Status)},
+		{SearchAttributeRegion, f.Region},
+	}
+	for _, keyword := range keywords {
+		name, value := keyword[0], keyword[1]
+		if value == "" {
+			continue
+		}
+		if strings.ContainsAny(value, `'"\`) {
+			return "", fmt.Errorf("invalid %s: %q", name, value)
+		}
+		conditions = append(conditions, fmt.Sprintf("%s = '%s'", name, value))
+	}
+
+	if len(f.CustomerEmailHashes) > 0 {
+		hashes := make([]string, len(f.CustomerEmailHashes))
+		for i, hash := range f.CustomerEmailHashes {
+			if _, err := hex.DecodeString(hash); err != nil || hash == "" {
+				return "", fmt.Errorf("invalid %s: %q", SearchAttributeCustomerEmailHash, hash)
+			}
+			hashes[i] = "'" + hash + "'"
+		}
+		conditions = append(conditions, fmt.Sprintf("%s IN (%s)", SearchAttributeCustomerEmailHash, strings.Join(hashes, ", ")))
+	}
+
+	if !f.CreatedAfter.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("%s >= '%s'", SearchAttributeCreatedAt, f.CreatedAfter.UTC().Format(time.RFC3339)))
+	}
+	if !f.CreatedBefore.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("%s < '%s'", SearchAttributeCreatedAt, f.CreatedBefore.UTC().Format(time.RFC3339)))
+	}
+
+	if !f.StatusChangedBefore.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("%s < '%s'", SearchAttributeStatusChangedAt, f.StatusChangedBefore.UTC().Format(time.RFC3339)))
+	}
+
+	if f.Running {
+		conditions = append(conditions, "ExecutionStatus = 'Running'")
+	}
+
+	if len(f.ExecutionStatuses) > 0 {
+		statuses := make([]string, len(f.ExecutionStatuses))
+		for i, status := range f.ExecutionStatuses {
+			statuses[i] = "'" + status.String() + "'"
+		}
+		conditions = append(conditions, fmt.Sprintf("ExecutionStatus IN (%s)", strings.Join(statuses, ", ")))
+	}
+
+	return strings.Join(conditions, " AND "), nil
+}
+
+// ExecutionSummary is a package delivery workflow as seen by the visibility
+// store.
+type ExecutionSummary struct {
+	WorkflowID      string                     `json:"workflow_id"`
+	RunID           string                     `json:"run_id"`
+	ExecutionStatus string                     `json:"execution_status"`
+	StartTime       time.Time                  `json:"start_time"`
+	CloseTime       *time.Time                 `json:"close_time,omitempty"`
+	PackageStatus   model.PackageDeliveryState `json:"package_status,omitempty"`
+	Region          string                     `json:"region,omitempty"`
+	CreatedAt       *time.Time                 `json:"created_at,omitempty"`
+	StatusChangedAt *time.Time                 `json:"status_changed_at,omitempty"`
+}
+
+var ErrInvalidPageSize = errors.New("page size must be between 1 and 1000")
+
+// ListExecutionPage returns one page of the workflows matching the filter,
+// and the token of the next page, empty on the last page.
+func ListExecutionPage(ctx context.Context, c client.Client, filter ExecutionFilter, pageSize int, pageToken []byte) ([]ExecutionSummary, []byte, error) {
+	if pageSize < 1 || pageSize > 1000 {
+		return nil, nil, ErrInvalidPageSize
+	}
+
+	query, err := filter.VisibilityQuery()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := c.ListWorkflow(ctx, &workflowservice.ListWorkflowExecutionsRequest{
+		Query:         query,
+		PageSize:      int32(pageSize),
+		NextPageToken: pageToken,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to list workflows: %w", err)
+	}
+
+	summaries := make([]ExecutionSummary, 0, len(resp.Executions))
+	for _, info := range resp.Executions {
+		summaries = append(summaries, newExecutionSummary(info))
+	}
+
+	return summaries, resp.NextPageToken, nil
+}
+
+func newExecutionSummary(info *workflow.WorkflowExecutionInfo) ExecutionSummary {
+	summary := ExecutionSummary{
+		WorkflowID:      info.GetExecution().GetWorkflowId(),
+		RunID:           info.GetExecution().GetRunId(),
+		ExecutionStatus: info.GetStatus().String(),
+		StartTime:       info.GetStartTime().AsTime(),
+	}
+
+	if info.GetCloseTime() != nil {
+		closeTime := info.GetCloseTime().AsTime()
+		summary.CloseTime = &closeTime
+	}
+
+	fields := info.GetSearchAttributes().GetIndexedFields()
+
+	var status string
+	if decodeSearchAttribute(fields, SearchAttributePackageStatus, &status) {
+		summary.PackageStatus = model.PackageDeliveryState(status)
+	}
+
+	decodeSearchAttribute(fields, SearchAttributeRegion, &summary.Region)
+
+	var createdAt time.Time
+	if decodeSearchAttribute(fields, SearchAttributeCreatedAt, &createdAt) {
+		summary.CreatedAt = &createdAt
+	}
+
+	var statusChangedAt time.Time
+	if decodeSearchAttribute(fields, SearchAttributeStatusChangedAt, &statusChangedAt) {
+		summary.StatusChangedAt = &statusChangedAt
+	}
+
+	return summary
+}
+
+func decodeSearchAttribute(fields map[string]*common.Payload, name string, valuePtr interface{}) bool {
+	payload, ok := fields[name]
+	if !ok {
+		return false
+	}
+
+	return converter.GetDefaultDataConverter().FromPayload(payload, valuePtr) == nil
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"go-test/internal/model"
+	"go.uber.org/zap"
+)
+
+func (r *Repository) RecordAuditEntry(ctx context.Context, entry *model.AuditEntry) error {
+	if err := r.Connection.WithContext(ctx).Create(entry).Error; err != nil {
+		r.Logger.Error("Failed to record audit entry", zap.String("subject_type", string(entry.SubjectType)), zap.String("subject_id", entry.SubjectID), zap.String("action", string(entry.Action)), zap.Error(err))
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) ListAuditEntries(ctx context.Context, subjectType model.AuditSubjectType, subjectID string) ([]model.AuditEntry, error) {
+	entries := []model.AuditEntry{}
+
+	query := r.Connection.WithContext(ctx).Where("subject_type = ? AND subject_id = ?", subjectType, subjectID)
+	if err := query.Order("id").Find(&entries).Error; err != nil {
+		r.Logger.Error("Failed to list audit entries", zap.String("subject_type", string(subjectType)), zap.String("subject_id", subjectID), zap.Error(err))
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-test/internal/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"time"
+)
+
+func (r *Repository) CreateConfirmationToken(ctx context.Context, token *model.ConfirmationToken) error {
+	if err := r.Connection.WithContext(ctx).Create(token).Error; err != nil {
+		r.Logger.Error("Failed to create confirmation token", zap.String("package_id", token.PackageID), zap.Error(err))
+		return fmt.Errorf("failed to create confirmation token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetConfirmationToken(ctx context.Context, id string) (*model.ConfirmationToken, error) {
+	var token model.ConfirmationToken
+
+	if err := r.Connection.WithContext(ctx).Where("id = ?", id).Take(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to get confirmation token %s: %w", id, ErrTokenNotFound)
+		}
+
+		r.Logger.Error("Failed to get confirmation token", zap.String("token_id", id), zap.Error(err))
+		return nil, fmt.Errorf("failed to get confirmation token: %w", err)
+	}
+
+	return &token, nil
+}
+
+func (r *Repository) ConsumeConfirmationToken(ctx context.Context, id string, at time.Time) (*model.ConfirmationToken, error) {
+	result := r.Connection.WithContext(ctx).
+		Model(&model.ConfirmationToken{}).
+		Where("id = ? AND consumed_at IS NULL", id).
+		Update("consumed_at", at)
+	if result.Error != nil {
+		r.Logger.Error("Failed to consume confirmation token", zap.String("token_id", id), zap.Error(result.Error))
+		return nil, fmt.Errorf("failed to consume confirmation token: %w", result.Error)
+	}
+
+	token, err := r.GetConfirmationToken(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("failed to consume confirmation token %s: %w", id, ErrTokenConsumed)
+	}
+
+	return token, nil
+}
+
+func (r *Repository) ReleaseConfirmationToken(ctx context.Context, id string, consumedAt time.Time) error {
+	result := r.Connection.WithContext(ctx).
+		Model(&model.ConfirmationToken{}).
+		Where("id = ? AND consumed_at = ?", id, consumedAt).
+		Update("consumed_at", nil)
+	if result.Error != nil {
+		r.Logger.Error("Failed to release confirmation token", zap.String("token_id", id), zap.Error(result.Error))
+		return fmt.Errorf("failed to release confirmation token: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		if _, err := r.GetConfirmationToken(ctx, id); err != nil {
+			return err
+		}
+		return fmt.Errorf("failed to release confirmation token %s: %w", id, ErrTokenConsumed)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-test/internal/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func (r *Repository) CreateCustomerPreferences(ctx context.Context, preferences *model.CustomerPreferences) error {
+	if err := r.Connection.WithContext(ctx).Create(preferences).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return fmt.Errorf("failed to create customer preferences %s: %w", preferences.Email, ErrCustomerAlreadyExists)
+		}
+
+		r.Logger.Error("Failed to create customer preferences", zap.String("email", preferences.Email), zap.Error(err))
+		return fmt.Errorf("failed to create customer preferences: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetCustomerPreferences(ctx context.Context, email string) (*model.CustomerPreferences, error) {
+	var preferences model.CustomerPreferences
+
+	if err := r.Connection.WithContext(ctx).Where("email = ?", email).Take(&preferences).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to get customer preferences %s: %w", email, ErrCustomerNotFound)
+		}
+
+		r.Logger.Error("Failed to get customer preferences", zap.String("email", email), zap.Error(err))
+		return nil, fmt.Errorf("failed to get customer preferences: %w", err)
+	}
+
+	return &preferences, nil
+}
+
+func (r *Repository) UpdateCustomerPreferences(ctx context.Context, preferences *model.CustomerPreferences) error {
+	result := r.Connection.WithContext(ctx).
+		Model(&model.CustomerPreferences{}).
+		Where("email = ?", preferences.Email).
+		Select("*").
+		Updates(preferences)
+	if result.Error != nil {
+		r.Logger.Error("Failed to update customer preferences", zap.String("email", preferences.Email), zap.Error(result.Error))
+		return fmt.Errorf("failed to update customer preferences: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("failed to update customer preferences %s: %w", preferences.Email, ErrCustomerNotFound)
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteCustomerPreferences(ctx context.Context, email string) error {
+	result := r.Connection.WithContext(ctx).Where("email = ?", email).Delete(&model.CustomerPreferences{})
+	if result.Error != nil {
+		r.Logger.Error("Failed to delete customer preferences", zap.String("email", email), zap.Error(result.Error))
+		return fmt.Errorf("failed to delete customer preferences: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("failed to delete customer preferences %s: %w", email, ErrCustomerNotFound)
+	}
+
+	return nil
+}
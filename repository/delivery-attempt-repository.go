@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-test/internal/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func (r *Repository) RecordDeliveryAttempt(ctx context.Context, attempt *model.PackageDeliveryAttempt) error {
+	if err := r.Connection.WithContext(ctx).Create(attempt).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return fmt.Errorf("failed to record delivery attempt %d of %s: %w", attempt.Number, attempt.PackageID, ErrAttemptAlreadyExists)
+		}
+
+		r.Logger.Error("Failed to record delivery attempt", zap.String("package_id", attempt.PackageID), zap.Int("number", attempt.Number), zap.Error(err))
+		return fmt.Errorf("failed to record delivery attempt: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) ListDeliveryAttempts(ctx context.Context, packageID string) ([]model.PackageDeliveryAttempt, error) {
+	attempts := []model.PackageDeliveryAttempt{}
+
+	if err := r.Connection.WithContext(ctx).Where("package_id = ?", packageID).Order("number").Find(&attempts).Error; err != nil {
+		r.Logger.Error("Failed to list delivery attempts", zap.String("package_id", packageID), zap.Error(err))
+		return nil, fmt.Errorf("failed to list delivery attempts: %w", err)
+	}
+
+	return attempts, nil
+}
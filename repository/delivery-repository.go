@@ -1,19 +1,22 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"go-test/internal/model"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
-func (r *Repository) CreatePackageDelivery(payload *model.DeliveryPackage) (*model.DeliveryPackage, error) {
-	deliveryPackage := &model.DeliveryPackage{
-		ID:              payload.ID,
-		CustomerEmail:   payload.CustomerEmail,
-		DeliveryAddress: payload.DeliveryAddress,
-	}
+func (r *Repository) CreatePackageDelivery(ctx context.Context, payload *model.DeliveryPackage) (*model.DeliveryPackage, error) {
+	deliveryPackage := newStoredPackage(payload)
+
+	if err := r.Connection.WithContext(ctx).Create(deliveryPackage).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, fmt.Errorf("failed to create package delivery %s: %w", payload.ID, ErrPackageAlreadyExists)
+		}
 
-	if err := r.Connection.Create(deliveryPackage).Error; err != nil {
 		r.Logger.Error("Failed to create delivery package", zap.String("package_id", payload.ID), zap.Error(err))
 		return nil, fmt.Errorf("failed to create package delivery: %w", err)
 	}
@@ -22,3 +25,93 @@ func (r *Repository) CreatePackageDelivery(payload *model.DeliveryPackage) (*mod
 
 	return deliveryPackage, nil
 }
+
+func (r *Repository) GetPackageDelivery(ctx context.Context, id string) (*model.DeliveryPackage, error) {
+	var deliveryPackage model.DeliveryPackage
+
+	if err := r.Connection.WithContext(ctx).Where("id = ?", id).Take(&deliveryPackage).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to get package delivery %s: %w", id, ErrPackageNotFound)
+		}
+
+		r.Logger.Error("Failed to get delivery package", zap.String("package_id", id), zap.Error(err))
+		return nil, fmt.Errorf("failed to get package delivery: %w", err)
+	}
+
+	return &deliveryPackage, nil
+}
+
+func (r *Repository) UpdatePackageDelivery(ctx context.Context, payload *model.DeliveryPackage, expectedVersion int64) (*model.DeliveryPackage, error) {
+	updated := newStoredPackage(payload)
+	updated.Version = expectedVersion + 1
+
+	result := r.Connection.WithContext(ctx).
+		Model(&model.DeliveryPackage{}).
+		Where("id = ? AND version = ?", payload.ID, expectedVersion).
+		Updates(map[string]interface{}{
+			"customer_email":   updated.CustomerEmail,
+			"delivery_address": updated.DeliveryAddress,
+			"region":           updated.Region,
+			"status":           updated.Status,
+			"proof":            updated.Proof,
+			"version":          gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		r.Logger.Error("Failed to update delivery package", zap.String("package_id", payload.ID), zap.Error(result.Error))
+		return nil, fmt.Errorf("failed to update package delivery: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, r.versionConflict(ctx, payload.ID, expectedVersion)
+	}
+
+	r.Logger.Info("Successfully updated delivery package", zap.String("package_id", payload.ID), zap.Int64("version", updated.Version))
+
+	return updated, nil
+}
+
+func (r *Repository) DeletePackageDelivery(ctx context.Context, id string, expectedVersion int64) error {
+	result := r.Connection.WithContext(ctx).
+		Where("id = ? AND version = ?", id, expectedVersion).
+		Delete(&model.DeliveryPackage{})
+	if result.Error != nil {
+		r.Logger.Error("Failed to delete delivery package", zap.String("package_id", id), zap.Error(result.Error))
+		return fmt.Errorf("failed to delete package delivery: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return r.versionConflict(ctx, id, expectedVersion)
+	}
+
+	r.Logger.Info("Successfully deleted delivery package", zap.String("package_id", id))
+
+	return nil
+}
+
+// versionConflict explains why a conditional write matched no row: either
+// the package is gone or it has moved on to another version.
+func (r *Repository) versionConflict(ctx context.Context, id string, expectedVersion int64) error {
+	current, err := r.GetPackageDelivery(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return &VersionConflictError{PackageID: id, ExpectedVersion: expectedVersion, ActualVersion: current.Version}
+}
+
+func newStoredPackage(payload *model.DeliveryPackage) *model.DeliveryPackage {
+	status := payload.Status
+	if status == "" {
+		status = model.PackageDeliverySaved
+	}
+
+	return &model.DeliveryPackage{
+		ID:              payload.ID,
+		CustomerEmail:   payload.CustomerEmail,
+		DeliveryAddress: payload.DeliveryAddress,
+		Region:          payload.Region,
+		Status:          status,
+		Version:         1,
+		Proof:           payload.Proof.Clone(),
+	}
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-test/internal/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func (r *Repository) CreateDispute(ctx context.Context, dispute *model.PackageDispute) error {
+	if err := r.Connection.WithContext(ctx).Create(dispute).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return fmt.Errorf("failed to create dispute %s: %w", dispute.ID, ErrDisputeAlreadyExists)
+		}
+
+		r.Logger.Error("Failed to create dispute", zap.String("dispute_id", dispute.ID), zap.Error(err))
+		return fmt.Errorf("failed to create dispute: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetDispute(ctx context.Context, id string) (*model.PackageDispute, error) {
+	var dispute model.PackageDispute
+
+	if err := r.Connection.WithContext(ctx).Where("id = ?", id).Take(&dispute).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to get dispute %s: %w", id, ErrDisputeNotFound)
+		}
+
+		r.Logger.Error("Failed to get dispute", zap.String("dispute_id", id), zap.Error(err))
+		return nil, fmt.Errorf("failed to get dispute: %w", err)
+	}
+
+	return &dispute, nil
+}
+
+func (r *Repository) ResolveDispute(ctx context.Context, id string, resolution model.DisputeResolution) (*model.PackageDispute, error) {
+	result := r.Connection.WithContext(ctx).
+		Model(&model.PackageDispute{}).
+		Where("id = ? AND resolved_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"action":      resolution.Action,
+			"resolved_by": resolution.ResolvedBy,
+			"note":        resolution.Note,
+			"resolved_at": resolution.ResolvedAt,
+		})
+	if result.Error != nil {
+		r.Logger.Error("Failed to resolve dispute", zap.String("dispute_id", id), zap.Error(result.Error))
+		return nil, fmt.Errorf("failed to resolve dispute: %w", result.Error)
+	}
+
+	dispute, err := r.GetDispute(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("failed to resolve dispute %s: %w", id, ErrDisputeResolved)
+	}
+
+	return dispute, nil
+}
@@ -0,0 +1,547 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"go-test/internal/model"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryRepository is a thread-safe in-memory store, meant for tests and
+// local runs without Postgres. It copies values on the way in and out so
+// callers never share state with the store.
+type MemoryRepository struct {
+	mu        sync.RWMutex
+	packages  map[string]model.DeliveryPackage
+	tokens    map[string]model.ConfirmationToken
+	disputes  map[string]model.PackageDispute
+	shipments map[string]model.Shipment
+	attempts  map[string][]model.PackageDeliveryAttempt
+	audit     []model.AuditEntry
+	events    map[string]map[model.PackageEventType]time.Time
+	customers map[string]model.CustomerPreferences
+	templates map[templateKey]model.NotificationTemplate
+	webhooks  map[string]model.DisabledWebhookSubscription
+}
+
+type templateKey struct {
+	event   model.NotificationKind
+	channel model.NotificationChannel
+	locale  string
+}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		packages:  make(map[string]model.DeliveryPackage),
+		tokens:    make(map[string]model.ConfirmationToken),
+		disputes:  make(map[string]model.PackageDispute),
+		shipments: make(map[string]model.Shipment),
+		attempts:  make(map[string][]model.PackageDeliveryAttempt),
+		events:    make(map[string]map[model.PackageEventType]time.Time),
+		customers: make(map[string]model.CustomerPreferences),
+		templates: make(map[templateKey]model.NotificationTemplate),
+		webhooks:  make(map[string]model.DisabledWebhookSubscription),
+	}
+}
+
+func (m *MemoryRepository) CreatePackageDelivery(_ context.Context, payload *model.DeliveryPackage) (*model.DeliveryPackage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.packages[payload.ID]; ok {
+		return nil, fmt.Errorf("failed to create package delivery %s: %w", payload.ID, ErrPackageAlreadyExists)
+	}
+
+	deliveryPackage := *newStoredPackage(payload)
+	m.packages[payload.ID] = deliveryPackage
+
+	return copyPackage(deliveryPackage), nil
+}
+
+func (m *MemoryRepository) GetPackageDelivery(_ context.Context, id string) (*model.DeliveryPackage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	deliveryPackage, ok := m.packages[id]
+	if !ok {
+		return nil, fmt.Errorf("failed to get package delivery %s: %w", id, ErrPackageNotFound)
+	}
+
+	return copyPackage(deliveryPackage), nil
+}
+
+func (m *MemoryRepository) UpdatePackageDelivery(_ context.Context, payload *model.DeliveryPackage, expectedVersion int64) (*model.DeliveryPackage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkVersion(payload.ID, expectedVersion); err != nil {
+		return nil, err
+	}
+
+	deliveryPackage := *newStoredPackage(payload)
+	deliveryPackage.Version = expectedVersion + 1
+	m.packages[payload.ID] = deliveryPackage
+
+	return copyPackage(deliveryPackage), nil
+}
+
+func (m *MemoryRepository) DeletePackageDelivery(_ context.Context, id string, expectedVersion int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkVersion(id, expectedVersion); err != nil {
+		return err
+	}
+
+	delete(m.packages, id)
+
+	return nil
+}
+
+func (m *MemoryRepository) checkVersion(id string, expectedVersion int64) error {
+	current, ok := m.packages[id]
+	if !ok {
+		return fmt.Errorf("failed to get package delivery %s: %w", id, ErrPackageNotFound)
+	}
+	if current.Version != expectedVersion {
+		return &VersionConflictError{PackageID: id, ExpectedVersion: expectedVersion, ActualVersion: current.Version}
+	}
+
+	return nil
+}
+
+func (m *MemoryRepository) CreateConfirmationToken(_ context.Context, token *model.ConfirmationToken) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.tokens[token.ID]; ok {
+		return fmt.Errorf("failed to create confirmation token %s: already exists", token.ID)
+	}
+
+	m.tokens[token.ID] = *copyToken(*token)
+
+	return nil
+}
+
+func (m *MemoryRepository) GetConfirmationToken(_ context.Context, id string) (*model.ConfirmationToken, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	token, ok := m.tokens[id]
+	if !ok {
+		return nil, fmt.Errorf("failed to get confirmation token %s: %w", id, ErrTokenNotFound)
+	}
+
+	return copyToken(token), nil
+}
+
+func (m *MemoryRepository) ConsumeConfirmationToken(_ context.Context, id string, at time.Time) (*model.ConfirmationToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	token, ok := m.tokens[id]
+	if !ok {
+		return nil, fmt.Errorf("failed to get confirmation token %s: %w", id, ErrTokenNotFound)
+	}
+	if token.ConsumedAt != nil {
+		return nil, fmt.Errorf("failed to consume confirmation token %s: %w", id, ErrTokenConsumed)
+	}
+
+	token.ConsumedAt = &at
+	m.tokens[id] = token
+
+	return copyToken(token), nil
+}
+
+func (m *MemoryRepository) ReleaseConfirmationToken(_ context.Context, id string, consumedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	token, ok := m.tokens[id]
+	if !ok {
+		return fmt.Errorf("failed to get confirmation token %s: %w", id, ErrTokenNotFound)
+	}
+	if token.ConsumedAt == nil || !token.ConsumedAt.Equal(consumedAt) {
+		return fmt.Errorf("failed to release confirmation token %s: %w", id, ErrTokenConsumed)
+	}
+
+	token.ConsumedAt = nil
+	m.tokens[id] = token
+
+	return nil
+}
+
+func copyToken(token model.ConfirmationToken) *model.ConfirmationToken {
+	if token.ConsumedAt != nil {
+		consumedAt := *token.ConsumedAt
+		token.ConsumedAt = &consumedAt
+	}
+	return &token
+}
+
+func (m *MemoryRepository) CreateDispute(_ context.Context, dispute *model.PackageDispute) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.disputes[dispute.ID]; ok {
+		return fmt.Errorf("failed to create dispute %s: %w", dispute.ID, ErrDisputeAlreadyExists)
+	}
+
+	m.disputes[dispute.ID] = *copyDispute(*dispute)
+
+	return nil
+}
+
+func (m *MemoryRepository) GetDispute(_ context.Context, id string) (*model.PackageDispute, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	dispute, ok := m.disputes[id]
+	if !ok {
+		return nil, fmt.Errorf("failed to get dispute %s: %w", id, ErrDisputeNotFound)
+	}
+
+	return copyDispute(dispute), nil
+}
+
+func (m *MemoryRepository) ResolveDispute(_ context.Context, id string, resolution model.DisputeResolution) (*model.PackageDispute, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	dispute, ok := m.disputes[id]
+	if !ok {
+		return nil, fmt.Errorf("failed to get dispute %s: %w", id, ErrDisputeNotFound)
+	}
+	if dispute.ResolvedAt != nil {
+		return nil, fmt.Errorf("failed to resolve dispute %s: %w", id, ErrDisputeResolved)
+	}
+
+	resolvedAt := resolution.ResolvedAt
+	dispute.Action = resolution.Action
+	dispute.ResolvedBy = resolution.ResolvedBy
+	dispute.Note = resolution.Note
+	dispute.ResolvedAt = &resolvedAt
+	m.disputes[id] = dispute
+
+	return copyDispute(dispute), nil
+}
+
+func copyDispute(dispute model.PackageDispute) *model.PackageDispute {
+	if dispute.ResolvedAt != nil {
+		resolvedAt := *dispute.ResolvedAt
+		dispute.ResolvedAt = &resolvedAt
+	}
+	return &dispute
+}
+
+// copyPackage returns a copy of a stored package that shares no memory with
+// the store.
+func (m *MemoryRepository) SaveShipment(_ context.Context, shipment *model.Shipment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.shipments[shipment.ID] = *shipment.Clone()
+
+	return nil
+}
+
+func (m *MemoryRepository) GetShipment(_ context.Context, id string) (*model.Shipment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	shipment, ok := m.shipments[id]
+	if !ok {
+		return nil, fmt.Errorf("failed to get shipment %s: %w", id, ErrShipmentNotFound)
+	}
+
+	return shipment.Clone(), nil
+}
+
+func (m *MemoryRepository) RecordDeliveryAttempt(_ context.Context, attempt *model.PackageDeliveryAttempt) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	attempts := m.attempts[attempt.PackageID]
+	for _, recorded := range attempts {
+		if recorded.Number == attempt.Number {
+			return fmt.Errorf("failed to record delivery attempt %d of %s: %w", attempt.Number, attempt.PackageID, ErrAttemptAlreadyExists)
+		}
+	}
+
+	attempts = append(attempts, *attempt)
+	sort.Slice(attempts, func(i, j int) bool { return attempts[i].Number < attempts[j].Number })
+	m.attempts[attempt.PackageID] = attempts
+
+	return nil
+}
+
+func (m *MemoryRepository) ListDeliveryAttempts(_ context.Context, packageID string) ([]model.PackageDeliveryAttempt, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return append([]model.PackageDeliveryAttempt{}, m.attempts[packageID]...), nil
+}
+
+func (m *MemoryRepository) RecordAuditEntry(_ context.Context, entry *model.AuditEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry.ID = int64(len(m.audit) + 1)
+	m.audit = append(m.audit, *entry)
+
+	return nil
+}
+
+func (m *MemoryRepository) ListAuditEntries(_ context.Context, subjectType model.AuditSubjectType, subjectID string) ([]model.AuditEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entries := []model.AuditEntry{}
+	for _, entry := range m.audit {
+		if entry.SubjectType == subjectType && entry.SubjectID == subjectID {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+func (m *MemoryRepository) RecordPackageEvent(_ context.Context, event *model.PackageEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	events, ok := m.events[event.PackageID]
+	if !ok {
+		events = make(map[model.PackageEventType]time.Time)
+		m.events[event.PackageID] = events
+	}
+	if _, ok := events[event.Event]; !ok {
+		events[event.Event] = event.OccurredAt
+	}
+
+	return nil
+}
+
+func (m *MemoryRepository) SummarizeDeliveries(_ context.Context, from, to time.Time) (*model.DeliverySummary, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	summary := &model.DeliverySummary{From: from, To: to}
+	within := func(t time.Time) bool {
+		return !t.Before(from) && t.Before(to)
+	}
+
+	var timesToConfirm []time.Duration
+	for _, events := range m.events {
+		if createdAt, ok := events[model.PackageEventCreated]; ok && within(createdAt) {
+			summary.Created++
+		}
+		if erroredAt, ok := events[model.PackageEventErrored]; ok && within(erroredAt) {
+			summary.Errored++
+		}
+
+		confirmedAt, ok := events[model.PackageEventConfirmed]
+		if !ok || !within(confirmedAt) {
+			continue
+		}
+		summary.Confirmed++
+		if createdAt, ok := events[model.PackageEventCreated]; ok {
+			timesToConfirm = append(timesToConfirm, confirmedAt.Sub(createdAt))
+		}
+	}
+
+	expired := make(map[string]bool)
+	for _, token := range m.tokens {
+		if token.ConsumedAt != nil || !within(token.ExpiresAt) {
+			continue
+		}
+		if confirmedAt, ok := m.events[token.PackageID][model.PackageEventConfirmed]; ok && confirmedAt.Before(token.ExpiresAt) {
+			continue
+		}
+		expired[token.PackageID] = true
+	}
+	summary.Expired = int64(len(expired))
+
+	if len(timesToConfirm) > 0 {
+		sort.Slice(timesToConfirm, func(i, j int) bool { return timesToConfirm[i] < timesToConfirm[j] })
+
+		middle := len(timesToConfirm) / 2
+		median := timesToConfirm[middle]
+		if len(timesToConfirm)%2 == 0 {
+			median = (timesToConfirm[middle-1] + timesToConfirm[middle]) / 2
+		}
+		summary.MedianTimeToConfirm = &median
+	}
+
+	return summary, nil
+}
+
+func copyPackage(deliveryPackage model.DeliveryPackage) *model.DeliveryPackage {
+	deliveryPackage.Proof = deliveryPackage.Proof.Clone()
+	return &deliveryPackage
+}
+
+func (m *MemoryRepository) CreateCustomerPreferences(_ context.Context, preferences *model.CustomerPreferences) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.customers[preferences.Email]; ok {
+		return fmt.Errorf("failed to create customer preferences %s: %w", preferences.Email, ErrCustomerAlreadyExists)
+	}
+
+	m.customers[preferences.Email] = *copyCustomerPreferences(*preferences)
+
+	return nil
+}
+
+func (m *MemoryRepository) GetCustomerPreferences(_ context.Context, email string) (*model.CustomerPreferences, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	preferences, ok := m.customers[email]
+	if !ok {
+		return nil, fmt.Errorf("failed to get customer preferences %s: %w", email, ErrCustomerNotFound)
+	}
+
+	return copyCustomerPreferences(preferences), nil
+}
+
+func (m *MemoryRepository) UpdateCustomerPreferences(_ context.Context, preferences *model.CustomerPreferences) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.customers[preferences.Email]; !ok {
+		return fmt.Errorf("failed to update customer preferences %s: %w", preferences.Email, ErrCustomerNotFound)
+	}
+
+	m.customers[preferences.Email] = *copyCustomerPreferences(*preferences)
+
+	return nil
+}
+
+func (m *MemoryRepository) DeleteCustomerPreferences(_ context.Context, email string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.customers[email]; !ok {
+		return fmt.Errorf("failed to delete customer preferences %s: %w", email, ErrCustomerNotFound)
+	}
+
+	delete(m.customers, email)
+
+	return nil
+}
+
+func copyCustomerPreferences(preferences model.CustomerPreferences) *model.CustomerPreferences {
+	if preferences.Channels != nil {
+		preferences.Channels = append(model.NotificationChannels(nil), preferences.Channels...)
+	}
+
+	return &preferences
+}
+
+func (m *MemoryRepository) SaveNotificationTemplate(_ context.Context, template *model.NotificationTemplate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.templates[templateKey{template.Event, template.Channel, template.Locale}] = *template
+
+	return nil
+}
+
+func (m *MemoryRepository) GetNotificationTemplate(_ context.Context, event model.NotificationKind, channel model.NotificationChannel, locale string) (*model.NotificationTemplate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	template, ok := m.templates[templateKey{event, channel, locale}]
+	if !ok {
+		return nil, fmt.Errorf("failed to get notification template %s/%s/%s: %w", event, channel, locale, ErrTemplateNotFound)
+	}
+
+	return &template, nil
+}
+
+func (m *MemoryRepository) ListNotificationTemplates(_ context.Context) ([]model.NotificationTemplate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	templates := make([]model.NotificationTemplate, 0, len(m.templates))
+	for _, template := range m.templates {
+		templates = append(templates, template)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		a, b := templates[i], templates[j]
+		if a.Event != b.Event {
+			return a.Event < b.Event
+		}
+		if a.Channel != b.Channel {
+			return a.Channel < b.Channel
+		}
+		return a.Locale < b.Locale
+	})
+
+	return templates, nil
+}
+
+func (m *MemoryRepository) DeleteNotificationTemplate(_ context.Context, event model.NotificationKind, channel model.NotificationChannel, locale string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := templateKey{event, channel, locale}
+	if _, ok := m.templates[key]; !ok {
+		return fmt.Errorf("failed to delete notification template %s/%s/%s: %w", event, channel, locale, ErrTemplateNotFound)
+	}
+
+	delete(m.templates, key)
+
+	return nil
+}
+
+func (m *MemoryRepository) DisableWebhookSubscription(_ context.Context, subscription *model.DisabledWebhookSubscription) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.webhooks[subscription.URL] = *subscription
+
+	return nil
+}
+
+func (m *MemoryRepository) GetDisabledWebhookSubscription(_ context.Context, url string) (*model.DisabledWebhookSubscription, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	subscription, ok := m.webhooks[url]
+	if !ok {
+		return nil, fmt.Errorf("failed to get disabled webhook subscription %s: %w", url, ErrSubscriptionNotDisabled)
+	}
+
+	return &subscription, nil
+}
+
+func (m *MemoryRepository) ListDisabledWebhookSubscriptions(_ context.Context) ([]model.DisabledWebhookSubscription, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	subscriptions := make([]model.DisabledWebhookSubscription, 0, len(m.webhooks))
+	for _, subscription := range m.webhooks {
+		subscriptions = append(subscriptions, subscription)
+	}
+	sort.Slice(subscriptions, func(i, j int) bool { return subscriptions[i].URL < subscriptions[j].URL })
+
+	return subscriptions, nil
+}
+
+func (m *MemoryRepository) EnableWebhookSubscription(_ context.Context, url string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.webhooks[url]; !ok {
+		return fmt.Errorf("failed to enable webhook subscription %s: %w", url, ErrSubscriptionNotDisabled)
+	}
+
+	delete(m.webhooks, url)
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package repository_test
+
+import (
+	"go-test/repository"
+	"go-test/repository/storetest"
+	"testing"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	storetest.TestPackageStore(t, func(t *testing.T) repository.PackageStore {
+		return repository.NewMemoryRepository()
+	})
+
+	storetest.TestConfirmationTokenStore(t, func(t *testing.T) repository.ConfirmationTokenStore {
+		return repository.NewMemoryRepository()
+	})
+
+	storetest.TestDisputeStore(t, func(t *testing.T) repository.DisputeStore {
+		return repository.NewMemoryRepository()
+	})
+
+	storetest.TestShipmentStore(t, func(t *testing.T) repository.ShipmentStore {
+		return repository.NewMemoryRepository()
+	})
+
+	storetest.TestDeliveryAttemptStore(t, func(t *testing.T) repository.DeliveryAttemptStore {
+		return repository.NewMemoryRepository()
+	})
+
+	storetest.TestAuditLogStore(t, func(t *testing.T) repository.AuditLogStore {
+		return repository.NewMemoryRepository()
+	})
+
+	storetest.TestPackageEventStore(t, func(t *testing.T) storetest.PackageEventStore {
+		return repository.NewMemoryRepository()
+	})
+
+	storetest.TestCustomerPreferencesStore(t, func(t *testing.T) repository.CustomerPreferencesStore {
+		return repository.NewMemoryRepository()
+	})
+
+	storetest.TestNotificationTemplateStore(t, func(t *testing.T) repository.NotificationTemplateStore {
+		return repository.NewMemoryRepository()
+	})
+
+	storetest.TestWebhookSubscriptionStore(t, func(t *testing.T) repository.WebhookSubscriptionStore {
+		return repository.NewMemoryRepository()
+	})
+}
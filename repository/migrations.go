@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const MigrationsDir = "repository/migrations"
+
+// migrationLockKey is the key of the Postgres advisory lock held while
+// migrations are read or applied, so instances starting together run them
+// one after the other.
+const migrationLockKey int64 = 7_346_512_001
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+var (
+	ErrSchemaNotMigrated = errors.New("database schema is not migrated")
+	ErrNoMigrationToRoll = errors.New("no applied migration to roll back")
+
+	migrationFileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+	migrationNameChar = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+}
+
+func (s MigrationStatus) Applied() bool {
+	return s.AppliedAt != nil
+}
+
+type schemaMigration struct {
+	Version   int64     `gorm:"column:version;primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"column:name"`
+	AppliedAt time.Time `gorm:"column:applied_at"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+func LoadMigrations() ([]Migration, error) {
+	return loadMigrations(migrationFiles, "migrations")
+}
+
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read migrations directory: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		matches := migrationFileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || matches == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != matches[2] {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, migration.Name, matches[2])
+		}
+
+		if matches[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down files", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func (r *Repository) MigrateUp() error {
+	return r.withMigrationLock(func(db *gorm.DB) error {
+		return r.migrateUp(db)
+	})
+}
+
+func (r *Repository) migrateUp(db *gorm.DB) error {
+	migrations, applied, err := r.loadMigrationState(db)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(migration.Up).Error; err != nil {
+				return err
+			}
+
+			return tx.Create(&schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now().UTC()}).Error
+		})
+		if err != nil {
+			r.Logger.Error("Failed to apply migration", zap.Int64("version", migration.Version), zap.String("name", migration.Name), zap.Error(err))
+			return fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		r.Logger.Info("Applied migration", zap.Int64("version", migration.Version), zap.String("name", migration.Name))
+		count++
+	}
+
+	r.Logger.Info("All migrations ran successfully", zap.Int("applied", count))
+	return nil
+}
+
+func (r *Repository) MigrateDown() error {
+	return r.withMigrationLock(func(db *gorm.DB) error {
+		return r.migrateDown(db)
+	})
+}
+
+func (r *Repository) migrateDown(db *gorm.DB) error {
+	migrations, applied, err := r.loadMigrationState(db)
+	if err != nil {
+		return err
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		migration := migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(migration.Down).Error; err != nil {
+				return err
+			}
+
+			return tx.Delete(&schemaMigration{Version: migration.Version}).Error
+		})
+		if err != nil {
+			r.Logger.Error("Failed to roll back migration", zap.Int64("version", migration.Version), zap.String("name", migration.Name), zap.Error(err))
+			return fmt.Errorf("failed to roll back migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		r.Logger.Info("Rolled back migration", zap.Int64("version", migration.Version), zap.String("name", migration.Name))
+		return nil
+	}
+
+	return ErrNoMigrationToRoll
+}
+
+func (r *Repository) MigrationStatus() ([]MigrationStatus, error) {
+	var migrations []Migration
+	var applied map[int64]schemaMigration
+
+	err := r.withMigrationLock(func(db *gorm.DB) error {
+		var err error
+		migrations, applied, err = r.loadMigrationState(db)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// EnsureMigrated returns ErrSchemaNotMigrated when the database is missing
+// any of the migrations embedded in the binary.
+func (r *Repository) EnsureMigrated() error {
+	statuses, err := r.MigrationStatus()
+	if err != nil {
+		return err
+	}
+
+	var pending []string
+	for _, status := range statuses {
+		if !status.Applied() {
+			pending = append(pending, fmt.Sprintf("%d_%s", status.Version, status.Name))
+		}
+	}
+
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: pending migrations %s", ErrSchemaNotMigrated, strings.Join(pending, ", "))
+	}
+
+	return nil
+}
+
+// withMigrationLock runs fc holding the migration lock, waiting for the
+// instance holding it to finish first. The lock belongs to the session, so
+// fc must run its statements on db, which is pinned to a single connection.
+func (r *Repository) withMigrationLock(fc func(db *gorm.DB) error) error {
+	return r.Connection.Connection(func(db *gorm.DB) error {
+		if err := db.Exec("SELECT pg_advisory_lock(?)", migrationLockKey).Error; err != nil {
+			r.Logger.Error("Failed to take the migration lock", zap.Error(err))
+			return fmt.Errorf("unable to take the migration lock: %w", err)
+		}
+		defer func() {
+			if err := db.Exec("SELECT pg_advisory_unlock(?)", migrationLockKey).Error; err != nil {
+				r.Logger.Error("Failed to release the migration lock", zap.Error(err))
+			}
+		}()
+
+		return fc(db)
+	})
+}
+
+func (r *Repository) loadMigrationState(db *gorm.DB) ([]Migration, map[int64]schemaMigration, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    bigint PRIMARY KEY,
+		name       text NOT NULL,
+		applied_at timestamptz NOT NULL
+	)`).Error; err != nil {
+		r.Logger.Error("Failed to create schema_migrations table", zap.Error(err))
+		return nil, nil, fmt.Errorf("unable to create schema_migrations table: %w", err)
+	}
+
+	var records []schemaMigration
+	if err := db.Order("version").Find(&records).Error; err != nil {
+		r.Logger.Error("Failed to read applied migrations", zap.Error(err))
+		return nil, nil, fmt.Errorf("unable to read applied migrations: %w", err)
+	}
+
+	applied := make(map[int64]schemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+
+	return migrations, applied, nil
+}
+
+// CreateMigration writes an empty up/down migration pair to dir, numbered
+// after the highest version already present there.
+func CreateMigration(dir, name string) (string, string, error) {
+	name = strings.Trim(migrationNameChar.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "", "", errors.New("migration name is required")
+	}
+
+	migrations, err := loadMigrations(os.DirFS(dir), ".")
+	if err != nil {
+		return "", "", err
+	}
+
+	var version int64 = 1
+	if len(migrations) > 0 {
+		version = migrations[len(migrations)-1].Version + 1
+	}
+
+	prefix := filepath.Join(dir, fmt.Sprintf("%04d_%s", version, name))
+	upPath, downPath := prefix+".up.sql", prefix+".down.sql"
+
+	for _, p := range []string{upPath, downPath} {
+		if err := os.WriteFile(p, []byte("-- "+filepath.Base(p)+"\n"), 0o644); err != nil {
+			return "", "", fmt.Errorf("unable to write migration file: %w", err)
+		}
+	}
+
+	return upPath, downPath, nil
+}
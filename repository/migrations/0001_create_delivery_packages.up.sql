@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS delivery_packages (
+    id               text PRIMARY KEY,
+    delivery_address text,
+    customer_email   text
+);
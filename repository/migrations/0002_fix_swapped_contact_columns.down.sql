@@ -0,0 +1,3 @@
+ALTER TABLE delivery_packages RENAME COLUMN customer_email TO customer_email_swap;
+ALTER TABLE delivery_packages RENAME COLUMN delivery_address TO customer_email;
+ALTER TABLE delivery_packages RENAME COLUMN customer_email_swap TO delivery_address;
@@ -0,0 +1,5 @@
+-- The customer email used to be stored in delivery_address and the address in
+-- customer_email. Swap the column names so they match their contents.
+ALTER TABLE delivery_packages RENAME COLUMN delivery_address TO customer_email_swap;
+ALTER TABLE delivery_packages RENAME COLUMN customer_email TO delivery_address;
+ALTER TABLE delivery_packages RENAME COLUMN customer_email_swap TO customer_email;
@@ -0,0 +1 @@
+ALTER TABLE delivery_packages ADD COLUMN version bigint NOT NULL DEFAULT 1;
@@ -0,0 +1,2 @@
+-- Rows written before this migration were saved after a confirmation.
+ALTER TABLE delivery_packages ADD COLUMN status text NOT NULL DEFAULT 'confirmed';
@@ -0,0 +1 @@
+ALTER TABLE delivery_packages DROP COLUMN proof;
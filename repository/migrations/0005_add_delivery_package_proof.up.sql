@@ -0,0 +1,2 @@
+-- Proof of delivery collected at confirmation; the images live in object storage.
+ALTER TABLE delivery_packages ADD COLUMN proof jsonb;
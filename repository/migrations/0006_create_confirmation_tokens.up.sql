@@ -0,0 +1,10 @@
+CREATE TABLE confirmation_tokens (
+    id             text PRIMARY KEY,
+    package_id     text NOT NULL,
+    customer_email text NOT NULL,
+    expires_at     timestamptz NOT NULL,
+    consumed_at    timestamptz,
+    created_at     timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX confirmation_tokens_package_id_idx ON confirmation_tokens (package_id);
@@ -0,0 +1 @@
+DROP TABLE package_disputes;
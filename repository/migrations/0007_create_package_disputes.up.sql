@@ -0,0 +1,14 @@
+CREATE TABLE package_disputes (
+    id          text PRIMARY KEY,
+    package_id  text NOT NULL,
+    category    text NOT NULL,
+    reason      text NOT NULL DEFAULT '',
+    raised_by   text NOT NULL,
+    raised_at   timestamptz NOT NULL,
+    action      text NOT NULL DEFAULT '',
+    resolved_by text NOT NULL DEFAULT '',
+    note        text NOT NULL DEFAULT '',
+    resolved_at timestamptz
+);
+
+CREATE INDEX package_disputes_package_id_idx ON package_disputes (package_id);
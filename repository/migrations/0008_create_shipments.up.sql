@@ -0,0 +1,7 @@
+CREATE TABLE shipments (
+    id               text PRIMARY KEY,
+    customer_email   text NOT NULL,
+    delivery_address text NOT NULL,
+    status           text NOT NULL,
+    parcels          jsonb NOT NULL DEFAULT '[]'
+);
@@ -0,0 +1,9 @@
+CREATE TABLE delivery_attempts (
+    package_id   text NOT NULL,
+    number       integer NOT NULL,
+    outcome      text NOT NULL,
+    driver       text NOT NULL,
+    note         text NOT NULL DEFAULT '',
+    attempted_at timestamptz NOT NULL,
+    PRIMARY KEY (package_id, number)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE shipments DROP COLUMN region;
+ALTER TABLE delivery_packages DROP COLUMN region;
@@ -0,0 +1,3 @@
+-- Region the package is delivered in, published as a workflow search attribute.
+ALTER TABLE delivery_packages ADD COLUMN region text NOT NULL DEFAULT '';
+ALTER TABLE shipments ADD COLUMN region text NOT NULL DEFAULT '';
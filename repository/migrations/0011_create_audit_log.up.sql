@@ -0,0 +1,11 @@
+CREATE TABLE audit_log (
+    id         bigserial PRIMARY KEY,
+    package_id text NOT NULL,
+    operator   text NOT NULL,
+    action     text NOT NULL,
+    reason     text NOT NULL DEFAULT '',
+    detail     text NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL
+);
+
+CREATE INDEX audit_log_package_id_idx ON audit_log (package_id, id);
@@ -0,0 +1,2 @@
+DROP INDEX confirmation_tokens_expires_at_idx;
+DROP TABLE package_events;
@@ -0,0 +1,9 @@
+CREATE TABLE package_events (
+    package_id  text NOT NULL,
+    event       text NOT NULL,
+    occurred_at timestamptz NOT NULL,
+    PRIMARY KEY (package_id, event)
+);
+
+CREATE INDEX package_events_event_occurred_at_idx ON package_events (event, occurred_at);
+CREATE INDEX confirmation_tokens_expires_at_idx ON confirmation_tokens (expires_at);
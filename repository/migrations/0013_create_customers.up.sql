@@ -0,0 +1,13 @@
+CREATE TABLE customers (
+    email                  text PRIMARY KEY,
+    channels               jsonb NOT NULL DEFAULT '["email"]',
+    language               text NOT NULL DEFAULT 'en',
+    time_zone              text NOT NULL DEFAULT 'UTC',
+    quiet_hours_start      text NOT NULL DEFAULT '',
+    quiet_hours_end        text NOT NULL DEFAULT '',
+    opt_out_all            boolean NOT NULL DEFAULT false,
+    opt_out_delivered      boolean NOT NULL DEFAULT false,
+    opt_out_arrival        boolean NOT NULL DEFAULT false,
+    opt_out_failed_attempt boolean NOT NULL DEFAULT false,
+    updated_at             timestamptz NOT NULL DEFAULT now()
+);
@@ -0,0 +1,9 @@
+CREATE TABLE notification_templates (
+    event      text NOT NULL,
+    channel    text NOT NULL,
+    locale     text NOT NULL,
+    subject    text NOT NULL DEFAULT '',
+    body       text NOT NULL,
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (event, channel, locale)
+);
@@ -0,0 +1 @@
+DROP TABLE disabled_webhook_subscriptions;
@@ -0,0 +1,7 @@
+CREATE TABLE disabled_webhook_subscriptions (
+    destination      text PRIMARY KEY,
+    url              text NOT NULL,
+    status_code      integer NOT NULL,
+    response_excerpt text NOT NULL DEFAULT '',
+    disabled_at      timestamptz NOT NULL
+);
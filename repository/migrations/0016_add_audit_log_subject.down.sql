@@ -0,0 +1,7 @@
+DELETE FROM audit_log WHERE subject_type <> 'package';
+DROP INDEX audit_log_subject_idx;
+CREATE INDEX audit_log_package_id_idx ON audit_log (package_id, id);
+
+ALTER TABLE audit_log ALTER COLUMN package_id DROP DEFAULT;
+ALTER TABLE audit_log DROP COLUMN subject_id;
+ALTER TABLE audit_log DROP COLUMN subject_type;
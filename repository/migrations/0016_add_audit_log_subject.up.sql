@@ -0,0 +1,12 @@
+-- Audit entries used to be about packages only. Record what each entry is
+-- about, so that operator actions on templates, schedules, webhook
+-- subscriptions and customer preferences are kept in the same trail.
+ALTER TABLE audit_log ADD COLUMN subject_type text NOT NULL DEFAULT 'package';
+ALTER TABLE audit_log ADD COLUMN subject_id text NOT NULL DEFAULT '';
+UPDATE audit_log SET subject_id = package_id;
+ALTER TABLE audit_log ALTER COLUMN subject_type DROP DEFAULT;
+ALTER TABLE audit_log ALTER COLUMN subject_id DROP DEFAULT;
+ALTER TABLE audit_log ALTER COLUMN package_id SET DEFAULT '';
+
+DROP INDEX audit_log_package_id_idx;
+CREATE INDEX audit_log_subject_idx ON audit_log (subject_type, subject_id, id);
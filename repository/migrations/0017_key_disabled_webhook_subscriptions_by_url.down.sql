@@ -0,0 +1,7 @@
+DELETE FROM disabled_webhook_subscriptions older
+    USING disabled_webhook_subscriptions newer
+    WHERE older.destination = newer.destination
+      AND (older.disabled_at, older.url) < (newer.disabled_at, newer.url);
+
+ALTER TABLE disabled_webhook_subscriptions DROP CONSTRAINT disabled_webhook_subscriptions_pkey;
+ALTER TABLE disabled_webhook_subscriptions ADD PRIMARY KEY (destination);
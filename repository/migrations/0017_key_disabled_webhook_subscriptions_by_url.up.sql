@@ -0,0 +1,10 @@
+-- A 410 Gone disables the URL that answered it rather than the whole
+-- destination, so that other URLs, and a destination configured with a new
+-- URL, are still called. Only the latest record of a URL is kept.
+DELETE FROM disabled_webhook_subscriptions older
+    USING disabled_webhook_subscriptions newer
+    WHERE older.url = newer.url
+      AND (older.disabled_at, older.destination) < (newer.disabled_at, newer.destination);
+
+ALTER TABLE disabled_webhook_subscriptions DROP CONSTRAINT disabled_webhook_subscriptions_pkey;
+ALTER TABLE disabled_webhook_subscriptions ADD PRIMARY KEY (url);
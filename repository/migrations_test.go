@@ -0,0 +1,101 @@
+package repository_test
+
+import (
+	"errors"
+	"go-test/repository"
+	"sync"
+	"testing"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := repository.LoadMigrations()
+	if err != nil {
+		t.Fatalf("LoadMigrations: %v", err)
+	}
+
+	for i, migration := range migrations {
+		if migration.Version != int64(i+1) {
+			t.Errorf("migration %s has version %d, want %d", migration.Name, migration.Version, i+1)
+		}
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	repo := newTestRepository(t)
+
+	migrations, err := repository.LoadMigrations()
+	if err != nil {
+		t.Fatalf("LoadMigrations: %v", err)
+	}
+	latest := migrations[len(migrations)-1]
+
+	statuses, err := repo.MigrationStatus()
+	if err != nil {
+		t.Fatalf("MigrationStatus: %v", err)
+	}
+	for _, status := range statuses {
+		if !status.Applied() {
+			t.Fatalf("migration %d_%s is not applied after MigrateUp", status.Version, status.Name)
+		}
+	}
+
+	if err := repo.MigrateDown(); err != nil {
+		t.Fatalf("MigrateDown: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := repo.MigrateUp(); err != nil {
+			t.Errorf("MigrateUp: %v", err)
+		}
+	})
+
+	statuses, err = repo.MigrationStatus()
+	if err != nil {
+		t.Fatalf("MigrationStatus: %v", err)
+	}
+	if last := statuses[len(statuses)-1]; last.Version != latest.Version || last.Applied() {
+		t.Fatalf("status after MigrateDown = %+v, want %d_%s pending", last, latest.Version, latest.Name)
+	}
+	if len(statuses) > 1 && !statuses[len(statuses)-2].Applied() {
+		t.Fatalf("MigrateDown rolled back more than the latest migration")
+	}
+
+	if err := repo.EnsureMigrated(); !errors.Is(err, repository.ErrSchemaNotMigrated) {
+		t.Fatalf("EnsureMigrated with a pending migration = %v, want ErrSchemaNotMigrated", err)
+	}
+
+	if err := repo.MigrateUp(); err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+	if err := repo.EnsureMigrated(); err != nil {
+		t.Fatalf("EnsureMigrated after MigrateUp: %v", err)
+	}
+}
+
+func TestConcurrentMigrateUp(t *testing.T) {
+	first := newTestRepository(t)
+	second := newTestRepository(t)
+
+	if err := first.MigrateDown(); err != nil {
+		t.Fatalf("MigrateDown: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i, repo := range []*repository.Repository{first, second} {
+		wg.Add(1)
+		go func(i int, repo *repository.Repository) {
+			defer wg.Done()
+			errs[i] = repo.MigrateUp()
+		}(i, repo)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("MigrateUp of instance %d: %v", i, err)
+		}
+	}
+	if err := first.EnsureMigrated(); err != nil {
+		t.Errorf("EnsureMigrated after concurrent runs: %v", err)
+	}
+}
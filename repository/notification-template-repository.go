@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-test/internal/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *Repository) SaveNotificationTemplate(ctx context.Context, template *model.NotificationTemplate) error {
+	err := r.Connection.WithContext(ctx).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(template).Error
+	if err != nil {
+		r.Logger.Error("Failed to save notification template", zap.String("event", string(template.Event)), zap.String("channel", string(template.Channel)), zap.String("locale", template.Locale), zap.Error(err))
+		return fmt.Errorf("failed to save notification template: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetNotificationTemplate(ctx context.Context, event model.NotificationKind, channel model.NotificationChannel, locale string) (*model.NotificationTemplate, error) {
+	var template model.NotificationTemplate
+
+	err := r.Connection.WithContext(ctx).
+		Where("event = ? AND channel = ? AND locale = ?", event, channel, locale).
+		Take(&template).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to get notification template %s/%s/%s: %w", event, channel, locale, ErrTemplateNotFound)
+		}
+
+		r.Logger.Error("Failed to get notification template", zap.String("event", string(event)), zap.String("channel", string(channel)), zap.String("locale", locale), zap.Error(err))
+		return nil, fmt.Errorf("failed to get notification template: %w", err)
+	}
+
+	return &template, nil
+}
+
+func (r *Repository) ListNotificationTemplates(ctx context.Context) ([]model.NotificationTemplate, error) {
+	var templates []model.NotificationTemplate
+
+	if err := r.Connection.WithContext(ctx).Order("event, channel, locale").Find(&templates).Error; err != nil {
+		r.Logger.Error("Failed to list notification templates", zap.Error(err))
+		return nil, fmt.Errorf("failed to list notification templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+func (r *Repository) DeleteNotificationTemplate(ctx context.Context, event model.NotificationKind, channel model.NotificationChannel, locale string) error {
+	result := r.Connection.WithContext(ctx).
+		Where("event = ? AND channel = ? AND locale = ?", event, channel, locale).
+		Delete(&model.NotificationTemplate{})
+	if result.Error != nil {
+		r.Logger.Error("Failed to delete notification template", zap.String("event", string(event)), zap.String("channel", string(channel)), zap.String("locale", locale), zap.Error(result.Error))
+		return fmt.Errorf("failed to delete notification template: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("failed to delete notification template %s/%s/%s: %w", event, channel, locale, ErrTemplateNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"go-test/internal/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+func (r *Repository) RecordPackageEvent(ctx context.Context, event *model.PackageEvent) error {
+	err := r.Connection.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(event).Error
+	if err != nil {
+		r.Logger.Error("Failed to record package event", zap.String("package_id", event.PackageID), zap.String("event", string(event.Event)), zap.Error(err))
+		return fmt.Errorf("failed to record package event: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) SummarizeDeliveries(ctx context.Context, from, to time.Time) (*model.DeliverySummary, error) {
+	summary := &model.DeliverySummary{From: from, To: to}
+	db := r.Connection.WithContext(ctx)
+
+	var counts []struct {
+		Event model.PackageEventType
+		Count int64
+	}
+	err := db.Model(&model.PackageEvent{}).
+		Select("event, count(*) AS count").
+		Where("occurred_at >= ? AND occurred_at < ?", from, to).
+		Group("event").
+		Scan(&counts).Error
+	if err != nil {
+		r.Logger.Error("Failed to count package events", zap.Error(err))
+		return nil, fmt.Errorf("failed to count package events: %w", err)
+	}
+
+	for _, count := range counts {
+		switch count.Event {
+		case model.PackageEventCreated:
+			summary.Created = count.Count
+		case model.PackageEventConfirmed:
+			summary.Confirmed = count.Count
+		case model.PackageEventErrored:
+			summary.Errored = count.Count
+		}
+	}
+
+	err = db.Table("confirmation_tokens AS t").
+		Where("t.consumed_at IS NULL AND t.expires_at >= ? AND t.expires_at < ?", from, to).
+		Where("NOT EXISTS (SELECT 1 FROM package_events e WHERE e.package_id = t.package_id AND e.event = ? AND e.occurred_at < t.expires_at)", model.PackageEventConfirmed).
+		Distinct("t.package_id").
+		Count(&summary.Expired).Error
+	if err != nil {
+		r.Logger.Error("Failed to count expired confirmation links", zap.Error(err))
+		return nil, fmt.Errorf("failed to count expired confirmation links: %w", err)
+	}
+
+	var median sql.NullFloat64
+	err = db.Raw(`SELECT percentile_cont(0.5) WITHIN GROUP (ORDER BY extract(epoch FROM c.occurred_at - s.occurred_at))
+		FROM package_events c
+		JOIN package_events s ON s.package_id = c.package_id AND s.event = ?
+		WHERE c.event = ? AND c.occurred_at >= ? AND c.occurred_at < ?`,
+		model.PackageEventCreated, model.PackageEventConfirmed, from, to).
+		Row().Scan(&median)
+	if err != nil {
+		r.Logger.Error("Failed to compute the median time to confirm", zap.Error(err))
+		return nil, fmt.Errorf("failed to compute the median time to confirm: %w", err)
+	}
+
+	if median.Valid {
+		medianTime := time.Duration(median.Float64 * float64(time.Second))
+		summary.MedianTimeToConfirm = &medianTime
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-test/internal/model"
+)
+
+// SetPackageStatus stores the package with the given status if its stored
+// version still equals expectedVersion. A version of 0 stands for a package
+// that has never been saved, which is created with the status and conflicts
+// if it has been saved since.
+func SetPackageStatus(ctx context.Context, store PackageStore, deliveryPackage *model.DeliveryPackage, status model.PackageDeliveryState, expectedVersion int64) (*model.DeliveryPackage, error) {
+	changed := *deliveryPackage
+	changed.Status = status
+
+	if expectedVersion == 0 {
+		created, err := store.CreatePackageDelivery(ctx, &changed)
+		if errors.Is(err, ErrPackageAlreadyExists) {
+			return nil, versionConflict(ctx, store, deliveryPackage.ID, expectedVersion)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to store package %s: %w", deliveryPackage.ID, err)
+		}
+		return created, nil
+	}
+
+	updated, err := store.UpdatePackageDelivery(ctx, &changed, expectedVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set status of package %s: %w", deliveryPackage.ID, err)
+	}
+
+	return updated, nil
+}
+
+// ClaimPackageVersion bumps the version of a stored package if it still
+// equals expectedVersion, so that other requests based on the same version
+// conflict. Actions that do not change the stored package itself claim it
+// before they go ahead. A version of 0 only succeeds while the package has
+// not been saved.
+func ClaimPackageVersion(ctx context.Context, store PackageStore, id string, expectedVersion int64) (*model.DeliveryPackage, error) {
+	stored, err := store.GetPackageDelivery(ctx, id)
+	if errors.Is(err, ErrPackageNotFound) && expectedVersion == 0 {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read package %s: %w", id, err)
+	}
+	if expectedVersion == 0 {
+		return nil, &VersionConflictError{PackageID: id, ExpectedVersion: expectedVersion, ActualVersion: stored.Version}
+	}
+
+	claimed, err := store.UpdatePackageDelivery(ctx, stored, expectedVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim package %s: %w", id, err)
+	}
+
+	return claimed, nil
+}
+
+// versionConflict reports that a package expected to be missing has been
+// saved.
+func versionConflict(ctx context.Context, store PackageStore, id string, expectedVersion int64) error {
+	stored, err := store.GetPackageDelivery(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to read package %s: %w", id, err)
+	}
+
+	return &VersionConflictError{PackageID: id, ExpectedVersion: expectedVersion, ActualVersion: stored.Version}
+}
@@ -2,7 +2,6 @@ package repository
 
 import (
 	"fmt"
-	"go-test/internal/model"
 	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -29,7 +28,7 @@ func NewRepository(params *ConnectionParams) (*Repository, error) {
 		params.Host, params.Port, params.User, params.Password, params.DbName, params.SSLMode,
 	)
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		params.Logger.Error("Unable to connect to the database", zap.Error(err))
 		return nil, fmt.Errorf("unable to connect to database: %w", err)
@@ -38,22 +37,6 @@ func NewRepository(params *ConnectionParams) (*Repository, error) {
 	return &Repository{Connection: db, Logger: params.Logger}, nil
 }
 
-func (r *Repository) Migrate() error {
-	models := []interface{}{
-		&model.DeliveryPackage{},
-	}
-
-	for _, migrationModel := range models {
-		if err := r.Connection.AutoMigrate(migrationModel); err != nil {
-			r.Logger.Error("Failed to migrate model", zap.String("model", fmt.Sprintf("%T", migrationModel)), zap.Error(err))
-			return fmt.Errorf("failed to migrate model %v: %w", migrationModel, err)
-		}
-	}
-
-	r.Logger.Info("All migrations ran successfully")
-	return nil
-}
-
 func (r *Repository) CloseConn() error {
 	connection, err := r.Connection.DB()
 	if err != nil {
@@ -0,0 +1,111 @@
+package repository_test
+
+import (
+	"go-test/repository"
+	"go-test/repository/storetest"
+	"go.uber.org/zap"
+	"os"
+	"testing"
+)
+
+// newTestRepository connects to the Postgres instance described by the
+// TEST_POSTGRES_* variables, or skips the test when they are not set.
+func newTestRepository(t *testing.T) *repository.Repository {
+	t.Helper()
+
+	host := os.Getenv("TEST_POSTGRES_HOST")
+	if host == "" {
+		t.Skip("TEST_POSTGRES_HOST is not set")
+	}
+
+	repo, err := repository.NewRepository(&repository.ConnectionParams{
+		Host:     host,
+		Port:     getenv("TEST_POSTGRES_PORT", "5445"),
+		User:     getenv("TEST_POSTGRES_USER", "postgres"),
+		Password: getenv("TEST_POSTGRES_PASSWORD", "password"),
+		DbName:   getenv("TEST_POSTGRES_DB", "test"),
+		SSLMode:  "disable",
+		Logger:   zap.NewNop(),
+	})
+	if err != nil {
+		t.Fatalf("NewRepository: %v", err)
+	}
+	t.Cleanup(func() { repo.CloseConn() })
+
+	if err := repo.MigrateUp(); err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+
+	return repo
+}
+
+func truncate(t *testing.T, repo *repository.Repository, tables ...string) {
+	t.Helper()
+
+	for _, table := range tables {
+		if err := repo.Connection.Exec("TRUNCATE TABLE " + table + " CASCADE").Error; err != nil {
+			t.Fatalf("truncate %s: %v", table, err)
+		}
+	}
+}
+
+func getenv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func TestRepository(t *testing.T) {
+	repo := newTestRepository(t)
+
+	storetest.TestPackageStore(t, func(t *testing.T) repository.PackageStore {
+		truncate(t, repo, "delivery_packages")
+		return repo
+	})
+
+	storetest.TestConfirmationTokenStore(t, func(t *testing.T) repository.ConfirmationTokenStore {
+		truncate(t, repo, "confirmation_tokens")
+		return repo
+	})
+
+	storetest.TestDisputeStore(t, func(t *testing.T) repository.DisputeStore {
+		truncate(t, repo, "package_disputes")
+		return repo
+	})
+
+	storetest.TestShipmentStore(t, func(t *testing.T) repository.ShipmentStore {
+		truncate(t, repo, "shipments")
+		return repo
+	})
+
+	storetest.TestDeliveryAttemptStore(t, func(t *testing.T) repository.DeliveryAttemptStore {
+		truncate(t, repo, "delivery_attempts")
+		return repo
+	})
+
+	storetest.TestAuditLogStore(t, func(t *testing.T) repository.AuditLogStore {
+		truncate(t, repo, "audit_log")
+		return repo
+	})
+
+	storetest.TestPackageEventStore(t, func(t *testing.T) storetest.PackageEventStore {
+		truncate(t, repo, "package_events", "confirmation_tokens")
+		return repo
+	})
+
+	storetest.TestCustomerPreferencesStore(t, func(t *testing.T) repository.CustomerPreferencesStore {
+		truncate(t, repo, "customers")
+		return repo
+	})
+
+	storetest.TestNotificationTemplateStore(t, func(t *testing.T) repository.NotificationTemplateStore {
+		truncate(t, repo, "notification_templates")
+		return repo
+	})
+
+	storetest.TestWebhookSubscriptionStore(t, func(t *testing.T) repository.WebhookSubscriptionStore {
+		truncate(t, repo, "disabled_webhook_subscriptions")
+		return repo
+	})
+}
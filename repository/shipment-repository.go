@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-test/internal/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *Repository) SaveShipment(ctx context.Context, shipment *model.Shipment) error {
+	err := r.Connection.WithContext(ctx).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(shipment).Error
+	if err != nil {
+		r.Logger.Error("Failed to save shipment", zap.String("shipment_id", shipment.ID), zap.Error(err))
+		return fmt.Errorf("failed to save shipment: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetShipment(ctx context.Context, id string) (*model.Shipment, error) {
+	var shipment model.Shipment
+
+	if err := r.Connection.WithContext(ctx).Where("id = ?", id).Take(&shipment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to get shipment %s: %w", id, ErrShipmentNotFound)
+		}
+
+		r.Logger.Error("Failed to get shipment", zap.String("shipment_id", id), zap.Error(err))
+		return nil, fmt.Errorf("failed to get shipment: %w", err)
+	}
+
+	return &shipment, nil
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-test/internal/model"
+	"time"
+)
+
+var (
+	ErrPackageNotFound      = errors.New("package not found")
+	ErrPackageAlreadyExists = errors.New("package already exists")
+	ErrVersionConflict      = errors.New("version conflict")
+
+	ErrTokenNotFound = errors.New("confirmation token not found")
+	ErrTokenConsumed = errors.New("confirmation token already consumed")
+
+	ErrDisputeNotFound      = errors.New("dispute not found")
+	ErrDisputeAlreadyExists = errors.New("dispute already exists")
+	ErrDisputeResolved      = errors.New("dispute already resolved")
+
+	ErrShipmentNotFound = errors.New("shipment not found")
+
+	ErrAttemptAlreadyExists = errors.New("delivery attempt already exists")
+
+	ErrCustomerNotFound      = errors.New("customer preferences not found")
+	ErrCustomerAlreadyExists = errors.New("customer preferences already exist")
+
+	ErrTemplateNotFound = errors.New("notification template not found")
+
+	ErrSubscriptionNotDisabled = errors.New("webhook subscription is not disabled")
+)
+
+// VersionConflictError is returned by conditional updates when the stored
+// row no longer has the version the caller based its changes on.
+type VersionConflictError struct {
+	PackageID       string
+	ExpectedVersion int64
+	ActualVersion   int64
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("package %s: expected version %d, found %d", e.PackageID, e.ExpectedVersion, e.ActualVersion)
+}
+
+func (e *VersionConflictError) Is(target error) bool {
+	return target == ErrVersionConflict
+}
+
+type PackageStore interface {
+	CreatePackageDelivery(ctx context.Context, payload *model.DeliveryPackage) (*model.DeliveryPackage, error)
+	GetPackageDelivery(ctx context.Context, id string) (*model.DeliveryPackage, error)
+	// UpdatePackageDelivery overwrites the package only if its stored version
+	// still equals expectedVersion, and bumps the version on success.
+	UpdatePackageDelivery(ctx context.Context, payload *model.DeliveryPackage, expectedVersion int64) (*model.DeliveryPackage, error)
+	DeletePackageDelivery(ctx context.Context, id string, expectedVersion int64) error
+}
+
+type ConfirmationTokenStore interface {
+	CreateConfirmationToken(ctx context.Context, token *model.ConfirmationToken) error
+	GetConfirmationToken(ctx context.Context, id string) (*model.ConfirmationToken, error)
+	// ConsumeConfirmationToken marks the token as consumed at the given time.
+	// It fails with ErrTokenConsumed when the token has been consumed before,
+	// so that concurrent uses of one token succeed at most once.
+	ConsumeConfirmationToken(ctx context.Context, id string, at time.Time) (*model.ConfirmationToken, error)
+	// ReleaseConfirmationToken makes a token usable again when the action it
+	// was consumed for did not happen. Only the consumption at the given time
+	// is released, so a later use of the token is left alone.
+	ReleaseConfirmationToken(ctx context.Context, id string, consumedAt time.Time) error
+}
+
+type DisputeStore interface {
+	CreateDispute(ctx context.Context, dispute *model.PackageDispute) error
+	GetDispute(ctx context.Context, id string) (*model.PackageDispute, error)
+	// ResolveDispute records the resolution of an open dispute. It fails with
+	// ErrDisputeResolved when the dispute has been resolved before.
+	ResolveDispute(ctx context.Context, id string, resolution model.DisputeResolution) (*model.PackageDispute, error)
+}
+
+type ShipmentStore interface {
+	// SaveShipment creates the shipment or overwrites the stored one.
+	SaveShipment(ctx context.Context, shipment *model.Shipment) error
+	GetShipment(ctx context.Context, id string) (*model.Shipment, error)
+}
+
+type DeliveryAttemptStore interface {
+	// RecordDeliveryAttempt fails with ErrAttemptAlreadyExists when the
+	// package already has an attempt with the same number.
+	RecordDeliveryAttempt(ctx context.Context, attempt *model.PackageDeliveryAttempt) error
+	// ListDeliveryAttempts returns the attempts of a package by number.
+	ListDeliveryAttempts(ctx context.Context, packageID string) ([]model.PackageDeliveryAttempt, error)
+}
+
+type AuditLogStore interface {
+	// RecordAuditEntry appends the entry to the audit log and assigns its ID.
+	RecordAuditEntry(ctx context.Context, entry *model.AuditEntry) error
+	// ListAuditEntries returns the entries of a subject in the order they
+	// were recorded.
+	ListAuditEntries(ctx context.Context, subjectType model.AuditSubjectType, subjectID string) ([]model.AuditEntry, error)
+}
+
+type PackageEventStore interface {
+	// RecordPackageEvent keeps the first occurrence of an event, later ones
+	// of the same package and type are ignored.
+	RecordPackageEvent(ctx context.Context, event *model.PackageEvent) error
+	// SummarizeDeliveries aggregates the package events and confirmation
+	// links of the period from included to to excluded.
+	SummarizeDeliveries(ctx context.Context, from, to time.Time) (*model.DeliverySummary, error)
+}
+
+type CustomerPreferencesStore interface {
+	CreateCustomerPreferences(ctx context.Context, preferences *model.CustomerPreferences) error
+	GetCustomerPreferences(ctx context.Context, email string) (*model.CustomerPreferences, error)
+	// UpdateCustomerPreferences overwrites the stored preferences of the
+	// customer. It fails with ErrCustomerNotFound when they have none.
+	UpdateCustomerPreferences(ctx context.Context, preferences *model.CustomerPreferences) error
+	DeleteCustomerPreferences(ctx context.Context, email string) error
+}
+
+type NotificationTemplateStore interface {
+	// SaveNotificationTemplate creates the template or overwrites the stored
+	// one of the same event, channel and locale.
+	SaveNotificationTemplate(ctx context.Context, template *model.NotificationTemplate) error
+	GetNotificationTemplate(ctx context.Context, event model.NotificationKind, channel model.NotificationChannel, locale string) (*model.NotificationTemplate, error)
+	// ListNotificationTemplates returns the stored templates by event,
+	// channel and locale.
+	ListNotificationTemplates(ctx context.Context) ([]model.NotificationTemplate, error)
+	DeleteNotificationTemplate(ctx context.Context, event model.NotificationKind, channel model.NotificationChannel, locale string) error
+}
+
+type WebhookSubscriptionStore interface {
+	// DisableWebhookSubscription records the URL as disabled, overwriting an
+	// earlier record of it.
+	DisableWebhookSubscription(ctx context.Context, subscription *model.DisabledWebhookSubscription) error
+	// GetDisabledWebhookSubscription fails with ErrSubscriptionNotDisabled
+	// when the URL is enabled.
+	GetDisabledWebhookSubscription(ctx context.Context, url string) (*model.DisabledWebhookSubscription, error)
+	// ListDisabledWebhookSubscriptions returns the disabled subscriptions by
+	// URL.
+	ListDisabledWebhookSubscriptions(ctx context.Context) ([]model.DisabledWebhookSubscription, error)
+	EnableWebhookSubscription(ctx context.Context, url string) error
+}
+
+// Store combines every store, as implemented by Repository and
+// MemoryRepository.
+type Store interface {
+	PackageStore
+	ConfirmationTokenStore
+	DisputeStore
+	ShipmentStore
+	DeliveryAttemptStore
+	AuditLogStore
+	PackageEventStore
+	CustomerPreferencesStore
+	NotificationTemplateStore
+	WebhookSubscriptionStore
+}
+
+var (
+	_ Store = (*Repository)(nil)
+	_ Store = (*MemoryRepository)(nil)
+
+	_ PackageStore = (*Repository)(nil)
+	_ PackageStore = (*MemoryRepository)(nil)
+
+	_ ConfirmationTokenStore = (*Repository)(nil)
+	_ ConfirmationTokenStore = (*MemoryRepository)(nil)
+
+	_ DisputeStore = (*Repository)(nil)
+	_ DisputeStore = (*MemoryRepository)(nil)
+
+	_ ShipmentStore = (*Repository)(nil)
+	_ ShipmentStore = (*MemoryRepository)(nil)
+
+	_ DeliveryAttemptStore = (*Repository)(nil)
+	_ DeliveryAttemptStore = (*MemoryRepository)(nil)
+
+	_ AuditLogStore = (*Repository)(nil)
+	_ AuditLogStore = (*MemoryRepository)(nil)
+
+	_ PackageEventStore = (*Repository)(nil)
+	_ PackageEventStore = (*MemoryRepository)(nil)
+
+	_ CustomerPreferencesStore = (*Repository)(nil)
+	_ CustomerPreferencesStore = (*MemoryRepository)(nil)
+
+	_ NotificationTemplateStore = (*Repository)(nil)
+	_ NotificationTemplateStore = (*MemoryRepository)(nil)
+
+	_ WebhookSubscriptionStore = (*Repository)(nil)
+	_ WebhookSubscriptionStore = (*MemoryRepository)(nil)
+)
@@ -0,0 +1,87 @@
+package storetest
+
+import (
+	"context"
+	"go-test/internal/model"
+	"go-test/repository"
+	"testing"
+	"time"
+)
+
+func TestAuditLogStore(t *testing.T, newStore func(t *testing.T) repository.AuditLogStore) {
+	ctx := context.Background()
+	createdAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	newEntry := func(packageID string, action model.AuditAction) *model.AuditEntry {
+		return &model.AuditEntry{
+			SubjectType: model.AuditSubjectPackage,
+			SubjectID:   packageID,
+			PackageID:   packageID,
+			Operator:    "operator-1",
+			Action:      action,
+			Reason:      "customer called",
+			CreatedAt:   createdAt,
+		}
+	}
+
+	t.Run("record and list", func(t *testing.T) {
+		store := newStore(t)
+
+		entries := []*model.AuditEntry{
+			newEntry("pkg-1", model.AuditRetry),
+			newEntry("pkg-2", model.AuditTerminate),
+			newEntry("pkg-1", model.AuditForceTransition),
+			{
+				SubjectType: model.AuditSubjectNotificationTemplate,
+				SubjectID:   "pkg-1",
+				Operator:    "operator-1",
+				Action:      model.AuditSaveTemplate,
+				CreatedAt:   createdAt,
+			},
+		}
+		entries[2].Detail = string(model.PackageDeliveryReturnedToSender)
+
+		for _, entry := range entries {
+			if err := store.RecordAuditEntry(ctx, entry); err != nil {
+				t.Fatalf("RecordAuditEntry: %v", err)
+			}
+			if entry.ID == 0 {
+				t.Fatalf("RecordAuditEntry did not assign an ID")
+			}
+		}
+
+		got, err := store.ListAuditEntries(ctx, model.AuditSubjectPackage, "pkg-1")
+		if err != nil {
+			t.Fatalf("ListAuditEntries: %v", err)
+		}
+		if len(got) != 2 || got[0].Action != model.AuditRetry || got[1].Action != model.AuditForceTransition {
+			t.Fatalf("ListAuditEntries returned %+v", got)
+		}
+		if got[0].ID != entries[0].ID || got[1].Detail != string(model.PackageDeliveryReturnedToSender) {
+			t.Fatalf("ListAuditEntries returned %+v", got)
+		}
+		if got[0].Operator != "operator-1" || got[0].Reason != "customer called" || !got[0].CreatedAt.Equal(createdAt) {
+			t.Fatalf("ListAuditEntries returned %+v", got[0])
+		}
+
+		got, err = store.ListAuditEntries(ctx, model.AuditSubjectNotificationTemplate, "pkg-1")
+		if err != nil {
+			t.Fatalf("ListAuditEntries: %v", err)
+		}
+		if len(got) != 1 || got[0].Action != model.AuditSaveTemplate || got[0].PackageID != "" {
+			t.Fatalf("ListAuditEntries returned %+v", got)
+		}
+	})
+
+	t.Run("list without entries", func(t *testing.T) {
+		store := newStore(t)
+
+		got, err := store.ListAuditEntries(ctx, model.AuditSubjectPackage, "missing")
+		if err != nil {
+			t.Fatalf("ListAuditEntries: %v", err)
+		}
+		if len(got) != 0 {
+			t.Fatalf("ListAuditEntries returned %+v", got)
+		}
+	})
+}
@@ -0,0 +1,141 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"go-test/internal/model"
+	"go-test/repository"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConfirmationTokenStore(t *testing.T, newStore func(t *testing.T) repository.ConfirmationTokenStore) {
+	ctx := context.Background()
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newToken := func(id string) *model.ConfirmationToken {
+		return &model.ConfirmationToken{ID: id, PackageID: "pkg-1", CustomerEmail: "customer@example.com", ExpiresAt: expiresAt}
+	}
+
+	t.Run("create and get", func(t *testing.T) {
+		store := newStore(t)
+
+		if err := store.CreateConfirmationToken(ctx, newToken("token-1")); err != nil {
+			t.Fatalf("CreateConfirmationToken: %v", err)
+		}
+
+		got, err := store.GetConfirmationToken(ctx, "token-1")
+		if err != nil {
+			t.Fatalf("GetConfirmationToken: %v", err)
+		}
+		if got.PackageID != "pkg-1" || got.CustomerEmail != "customer@example.com" || !got.ExpiresAt.Equal(expiresAt) || got.ConsumedAt != nil {
+			t.Fatalf("GetConfirmationToken returned %+v", got)
+		}
+	})
+
+	t.Run("get missing token", func(t *testing.T) {
+		store := newStore(t)
+
+		_, err := store.GetConfirmationToken(ctx, "missing")
+		if !errors.Is(err, repository.ErrTokenNotFound) {
+			t.Fatalf("GetConfirmationToken error = %v, want ErrTokenNotFound", err)
+		}
+	})
+
+	t.Run("consume once", func(t *testing.T) {
+		store := newStore(t)
+		if err := store.CreateConfirmationToken(ctx, newToken("token-once")); err != nil {
+			t.Fatalf("CreateConfirmationToken: %v", err)
+		}
+
+		consumedAt := time.Date(2029, 6, 1, 12, 0, 0, 0, time.UTC)
+		consumed, err := store.ConsumeConfirmationToken(ctx, "token-once", consumedAt)
+		if err != nil {
+			t.Fatalf("ConsumeConfirmationToken: %v", err)
+		}
+		if consumed.ConsumedAt == nil || !consumed.ConsumedAt.Equal(consumedAt) {
+			t.Fatalf("ConsumeConfirmationToken returned %+v", consumed)
+		}
+
+		_, err = store.ConsumeConfirmationToken(ctx, "token-once", consumedAt.Add(time.Minute))
+		if !errors.Is(err, repository.ErrTokenConsumed) {
+			t.Fatalf("second ConsumeConfirmationToken error = %v, want ErrTokenConsumed", err)
+		}
+	})
+
+	t.Run("release consumption", func(t *testing.T) {
+		store := newStore(t)
+		if err := store.CreateConfirmationToken(ctx, newToken("token-release")); err != nil {
+			t.Fatalf("CreateConfirmationToken: %v", err)
+		}
+
+		consumedAt := time.Date(2029, 6, 1, 12, 0, 0, 0, time.UTC)
+		if _, err := store.ConsumeConfirmationToken(ctx, "token-release", consumedAt); err != nil {
+			t.Fatalf("ConsumeConfirmationToken: %v", err)
+		}
+
+		err := store.ReleaseConfirmationToken(ctx, "token-release", consumedAt.Add(time.Minute))
+		if !errors.Is(err, repository.ErrTokenConsumed) {
+			t.Fatalf("ReleaseConfirmationToken of another consumption error = %v, want ErrTokenConsumed", err)
+		}
+
+		if err := store.ReleaseConfirmationToken(ctx, "token-release", consumedAt); err != nil {
+			t.Fatalf("ReleaseConfirmationToken: %v", err)
+		}
+		got, err := store.GetConfirmationToken(ctx, "token-release")
+		if err != nil {
+			t.Fatalf("GetConfirmationToken: %v", err)
+		}
+		if got.ConsumedAt != nil {
+			t.Fatalf("released token is still consumed at %s", got.ConsumedAt)
+		}
+
+		if _, err := store.ConsumeConfirmationToken(ctx, "token-release", consumedAt.Add(time.Hour)); err != nil {
+			t.Fatalf("ConsumeConfirmationToken after release: %v", err)
+		}
+	})
+
+	t.Run("consume missing token", func(t *testing.T) {
+		store := newStore(t)
+
+		_, err := store.ConsumeConfirmationToken(ctx, "missing", time.Now())
+		if !errors.Is(err, repository.ErrTokenNotFound) {
+			t.Fatalf("ConsumeConfirmationToken error = %v, want ErrTokenNotFound", err)
+		}
+	})
+
+	t.Run("concurrent consumes succeed once", func(t *testing.T) {
+		store := newStore(t)
+		if err := store.CreateConfirmationToken(ctx, newToken("token-race")); err != nil {
+			t.Fatalf("CreateConfirmationToken: %v", err)
+		}
+
+		const workers = 10
+		var wg sync.WaitGroup
+		errs := make(chan error, workers)
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, err := store.ConsumeConfirmationToken(ctx, "token-race", time.Now().Add(time.Duration(i)*time.Second))
+				errs <- err
+			}(i)
+		}
+		wg.Wait()
+		close(errs)
+
+		succeeded := 0
+		for err := range errs {
+			switch {
+			case err == nil:
+				succeeded++
+			case !errors.Is(err, repository.ErrTokenConsumed):
+				t.Errorf("ConsumeConfirmationToken: %v", err)
+			}
+		}
+		if succeeded != 1 {
+			t.Fatalf("%d concurrent consumes succeeded, want 1", succeeded)
+		}
+	})
+}
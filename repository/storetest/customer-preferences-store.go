@@ -0,0 +1,115 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"go-test/internal/model"
+	"go-test/repository"
+	"testing"
+	"time"
+)
+
+func TestCustomerPreferencesStore(t *testing.T, newStore func(t *testing.T) repository.CustomerPreferencesStore) {
+	ctx := context.Background()
+	updatedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	newPreferences := func(email string) *model.CustomerPreferences {
+		return &model.CustomerPreferences{
+			Email:           email,
+			Channels:        model.NotificationChannels{model.NotificationChannelSMS, model.NotificationChannelEmail},
+			Language:        "de-AT",
+			TimeZone:        "Europe/Vienna",
+			QuietHoursStart: "22:00",
+			QuietHoursEnd:   "07:00",
+			OptOutArrival:   true,
+			UpdatedAt:       updatedAt,
+		}
+	}
+
+	t.Run("create and get", func(t *testing.T) {
+		store := newStore(t)
+
+		if err := store.CreateCustomerPreferences(ctx, newPreferences("customer@example.com")); err != nil {
+			t.Fatalf("CreateCustomerPreferences: %v", err)
+		}
+
+		got, err := store.GetCustomerPreferences(ctx, "customer@example.com")
+		if err != nil {
+			t.Fatalf("GetCustomerPreferences: %v", err)
+		}
+		if len(got.Channels) != 2 || got.Channels[0] != model.NotificationChannelSMS || got.Language != "de-AT" ||
+			got.QuietHoursStart != "22:00" || !got.OptOutArrival || got.OptOutAll {
+			t.Fatalf("GetCustomerPreferences returned %+v", got)
+		}
+	})
+
+	t.Run("create existing customer", func(t *testing.T) {
+		store := newStore(t)
+
+		if err := store.CreateCustomerPreferences(ctx, newPreferences("customer@example.com")); err != nil {
+			t.Fatalf("CreateCustomerPreferences: %v", err)
+		}
+
+		err := store.CreateCustomerPreferences(ctx, newPreferences("customer@example.com"))
+		if !errors.Is(err, repository.ErrCustomerAlreadyExists) {
+			t.Fatalf("CreateCustomerPreferences error = %v, want ErrCustomerAlreadyExists", err)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		store := newStore(t)
+
+		preferences := newPreferences("customer@example.com")
+		if err := store.CreateCustomerPreferences(ctx, preferences); err != nil {
+			t.Fatalf("CreateCustomerPreferences: %v", err)
+		}
+
+		preferences.Channels = model.NotificationChannels{model.NotificationChannelPush}
+		preferences.QuietHoursStart = ""
+		preferences.QuietHoursEnd = ""
+		preferences.OptOutArrival = false
+		preferences.OptOutAll = true
+		if err := store.UpdateCustomerPreferences(ctx, preferences); err != nil {
+			t.Fatalf("UpdateCustomerPreferences: %v", err)
+		}
+
+		got, err := store.GetCustomerPreferences(ctx, "customer@example.com")
+		if err != nil {
+			t.Fatalf("GetCustomerPreferences: %v", err)
+		}
+		if len(got.Channels) != 1 || got.Channels[0] != model.NotificationChannelPush || got.QuietHoursStart != "" ||
+			got.OptOutArrival || !got.OptOutAll {
+			t.Fatalf("GetCustomerPreferences returned %+v", got)
+		}
+	})
+
+	t.Run("update missing customer", func(t *testing.T) {
+		store := newStore(t)
+
+		err := store.UpdateCustomerPreferences(ctx, newPreferences("missing@example.com"))
+		if !errors.Is(err, repository.ErrCustomerNotFound) {
+			t.Fatalf("UpdateCustomerPreferences error = %v, want ErrCustomerNotFound", err)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		store := newStore(t)
+
+		if err := store.CreateCustomerPreferences(ctx, newPreferences("customer@example.com")); err != nil {
+			t.Fatalf("CreateCustomerPreferences: %v", err)
+		}
+		if err := store.DeleteCustomerPreferences(ctx, "customer@example.com"); err != nil {
+			t.Fatalf("DeleteCustomerPreferences: %v", err)
+		}
+
+		_, err := store.GetCustomerPreferences(ctx, "customer@example.com")
+		if !errors.Is(err, repository.ErrCustomerNotFound) {
+			t.Fatalf("GetCustomerPreferences error = %v, want ErrCustomerNotFound", err)
+		}
+
+		err = store.DeleteCustomerPreferences(ctx, "customer@example.com")
+		if !errors.Is(err, repository.ErrCustomerNotFound) {
+			t.Fatalf("DeleteCustomerPreferences error = %v, want ErrCustomerNotFound", err)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"go-test/internal/model"
+	"go-test/repository"
+	"testing"
+	"time"
+)
+
+func TestDeliveryAttemptStore(t *testing.T, newStore func(t *testing.T) repository.DeliveryAttemptStore) {
+	ctx := context.Background()
+	attemptedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	newAttempt := func(packageID string, number int, outcome model.AttemptOutcome) *model.PackageDeliveryAttempt {
+		return &model.PackageDeliveryAttempt{
+			PackageID:   packageID,
+			Number:      number,
+			Outcome:     outcome,
+			Driver:      "driver-1",
+			AttemptedAt: attemptedAt.Add(time.Duration(number) * time.Hour),
+		}
+	}
+
+	t.Run("record and list", func(t *testing.T) {
+		store := newStore(t)
+
+		for _, attempt := range []*model.PackageDeliveryAttempt{
+			newAttempt("pkg-1", 2, model.AttemptDelivered),
+			newAttempt("pkg-1", 1, model.AttemptNobodyHome),
+			newAttempt("pkg-2", 1, model.AttemptRefused),
+		} {
+			if err := store.RecordDeliveryAttempt(ctx, attempt); err != nil {
+				t.Fatalf("RecordDeliveryAttempt: %v", err)
+			}
+		}
+
+		got, err := store.ListDeliveryAttempts(ctx, "pkg-1")
+		if err != nil {
+			t.Fatalf("ListDeliveryAttempts: %v", err)
+		}
+		if len(got) != 2 || got[0].Number != 1 || got[0].Outcome != model.AttemptNobodyHome || got[1].Number != 2 {
+			t.Fatalf("ListDeliveryAttempts returned %+v", got)
+		}
+		if !got[0].AttemptedAt.Equal(attemptedAt.Add(time.Hour)) {
+			t.Fatalf("AttemptedAt = %v", got[0].AttemptedAt)
+		}
+	})
+
+	t.Run("record duplicate attempt", func(t *testing.T) {
+		store := newStore(t)
+
+		if err := store.RecordDeliveryAttempt(ctx, newAttempt("pkg-dup", 1, model.AttemptRefused)); err != nil {
+			t.Fatalf("RecordDeliveryAttempt: %v", err)
+		}
+
+		err := store.RecordDeliveryAttempt(ctx, newAttempt("pkg-dup", 1, model.AttemptNobodyHome))
+		if !errors.Is(err, repository.ErrAttemptAlreadyExists) {
+			t.Fatalf("RecordDeliveryAttempt error = %v, want ErrAttemptAlreadyExists", err)
+		}
+	})
+
+	t.Run("list without attempts", func(t *testing.T) {
+		store := newStore(t)
+
+		got, err := store.ListDeliveryAttempts(ctx, "missing")
+		if err != nil {
+			t.Fatalf("ListDeliveryAttempts: %v", err)
+		}
+		if len(got) != 0 {
+			t.Fatalf("ListDeliveryAttempts returned %+v", got)
+		}
+	})
+}
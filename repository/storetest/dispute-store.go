@@ -0,0 +1,103 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"go-test/internal/model"
+	"go-test/repository"
+	"testing"
+	"time"
+)
+
+func TestDisputeStore(t *testing.T, newStore func(t *testing.T) repository.DisputeStore) {
+	ctx := context.Background()
+	raisedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	newDispute := func(id string) *model.PackageDispute {
+		return &model.PackageDispute{
+			ID:        id,
+			PackageID: "pkg-1",
+			Category:  model.DisputeDamaged,
+			Reason:    "box was crushed",
+			RaisedBy:  "customer@example.com",
+			RaisedAt:  raisedAt,
+		}
+	}
+
+	t.Run("create and get", func(t *testing.T) {
+		store := newStore(t)
+
+		if err := store.CreateDispute(ctx, newDispute("pkg-1-dispute-1")); err != nil {
+			t.Fatalf("CreateDispute: %v", err)
+		}
+
+		got, err := store.GetDispute(ctx, "pkg-1-dispute-1")
+		if err != nil {
+			t.Fatalf("GetDispute: %v", err)
+		}
+		if got.PackageID != "pkg-1" || got.Category != model.DisputeDamaged || !got.RaisedAt.Equal(raisedAt) || got.ResolvedAt != nil {
+			t.Fatalf("GetDispute returned %+v", got)
+		}
+	})
+
+	t.Run("create duplicate dispute", func(t *testing.T) {
+		store := newStore(t)
+
+		if err := store.CreateDispute(ctx, newDispute("pkg-1-dispute-dup")); err != nil {
+			t.Fatalf("CreateDispute: %v", err)
+		}
+
+		err := store.CreateDispute(ctx, newDispute("pkg-1-dispute-dup"))
+		if !errors.Is(err, repository.ErrDisputeAlreadyExists) {
+			t.Fatalf("CreateDispute error = %v, want ErrDisputeAlreadyExists", err)
+		}
+	})
+
+	t.Run("get missing dispute", func(t *testing.T) {
+		store := newStore(t)
+
+		_, err := store.GetDispute(ctx, "missing")
+		if !errors.Is(err, repository.ErrDisputeNotFound) {
+			t.Fatalf("GetDispute error = %v, want ErrDisputeNotFound", err)
+		}
+	})
+
+	t.Run("resolve once", func(t *testing.T) {
+		store := newStore(t)
+		if err := store.CreateDispute(ctx, newDispute("pkg-1-dispute-resolve")); err != nil {
+			t.Fatalf("CreateDispute: %v", err)
+		}
+
+		resolution := model.DisputeResolution{Action: model.DisputeRefund, ResolvedBy: "ops", Note: "refunded", ResolvedAt: raisedAt.Add(time.Hour)}
+		resolved, err := store.ResolveDispute(ctx, "pkg-1-dispute-resolve", resolution)
+		if err != nil {
+			t.Fatalf("ResolveDispute: %v", err)
+		}
+		if resolved.Action != model.DisputeRefund || resolved.ResolvedBy != "ops" || resolved.ResolvedAt == nil || !resolved.ResolvedAt.Equal(resolution.ResolvedAt) {
+			t.Fatalf("ResolveDispute returned %+v", resolved)
+		}
+
+		resolution.Action = model.DisputeClose
+		_, err = store.ResolveDispute(ctx, "pkg-1-dispute-resolve", resolution)
+		if !errors.Is(err, repository.ErrDisputeResolved) {
+			t.Fatalf("second ResolveDispute error = %v, want ErrDisputeResolved", err)
+		}
+
+		got, err := store.GetDispute(ctx, "pkg-1-dispute-resolve")
+		if err != nil {
+			t.Fatalf("GetDispute: %v", err)
+		}
+		if got.Action != model.DisputeRefund {
+			t.Fatalf("dispute resolved twice: %+v", got)
+		}
+	})
+
+	t.Run("resolve missing dispute", func(t *testing.T) {
+		store := newStore(t)
+
+		_, err := store.ResolveDispute(ctx, "missing", model.DisputeResolution{Action: model.DisputeClose, ResolvedBy: "ops", ResolvedAt: raisedAt})
+		if !errors.Is(err, repository.ErrDisputeNotFound) {
+			t.Fatalf("ResolveDispute error = %v, want ErrDisputeNotFound", err)
+		}
+	})
+}
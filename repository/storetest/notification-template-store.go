@@ -0,0 +1,119 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"go-test/internal/model"
+	"go-test/repository"
+	"testing"
+	"time"
+)
+
+func TestNotificationTemplateStore(t *testing.T, newStore func(t *testing.T) repository.NotificationTemplateStore) {
+	ctx := context.Background()
+	updatedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	newTemplate := func(event model.NotificationKind, channel model.NotificationChannel, locale string) *model.NotificationTemplate {
+		return &model.NotificationTemplate{
+			Event:     event,
+			Channel:   channel,
+			Locale:    locale,
+			Subject:   "Package {{.Package.ID}}",
+			Body:      "Your package {{.Package.ID}} was delivered.",
+			UpdatedAt: updatedAt,
+		}
+	}
+
+	t.Run("save and get", func(t *testing.T) {
+		store := newStore(t)
+
+		if err := store.SaveNotificationTemplate(ctx, newTemplate(model.NotificationDelivered, model.NotificationChannelEmail, "de")); err != nil {
+			t.Fatalf("SaveNotificationTemplate: %v", err)
+		}
+
+		got, err := store.GetNotificationTemplate(ctx, model.NotificationDelivered, model.NotificationChannelEmail, "de")
+		if err != nil {
+			t.Fatalf("GetNotificationTemplate: %v", err)
+		}
+		if got.Subject != "Package {{.Package.ID}}" || got.Body != "Your package {{.Package.ID}} was delivered." {
+			t.Fatalf("GetNotificationTemplate returned %+v", got)
+		}
+
+		_, err = store.GetNotificationTemplate(ctx, model.NotificationDelivered, model.NotificationChannelSMS, "de")
+		if !errors.Is(err, repository.ErrTemplateNotFound) {
+			t.Fatalf("GetNotificationTemplate error = %v, want ErrTemplateNotFound", err)
+		}
+	})
+
+	t.Run("save overwrites", func(t *testing.T) {
+		store := newStore(t)
+
+		template := newTemplate(model.NotificationArrival, model.NotificationChannelSMS, "en")
+		if err := store.SaveNotificationTemplate(ctx, template); err != nil {
+			t.Fatalf("SaveNotificationTemplate: %v", err)
+		}
+
+		template.Body = "Arriving soon"
+		if err := store.SaveNotificationTemplate(ctx, template); err != nil {
+			t.Fatalf("SaveNotificationTemplate: %v", err)
+		}
+
+		got, err := store.GetNotificationTemplate(ctx, model.NotificationArrival, model.NotificationChannelSMS, "en")
+		if err != nil {
+			t.Fatalf("GetNotificationTemplate: %v", err)
+		}
+		if got.Body != "Arriving soon" {
+			t.Fatalf("GetNotificationTemplate returned %+v", got)
+		}
+	})
+
+	t.Run("list in order", func(t *testing.T) {
+		store := newStore(t)
+
+		for _, template := range []*model.NotificationTemplate{
+			newTemplate(model.NotificationDelivered, model.NotificationChannelSMS, "en"),
+			newTemplate(model.NotificationArrival, model.NotificationChannelSMS, "en"),
+			newTemplate(model.NotificationDelivered, model.NotificationChannelEmail, "en"),
+			newTemplate(model.NotificationDelivered, model.NotificationChannelEmail, "de"),
+		} {
+			if err := store.SaveNotificationTemplate(ctx, template); err != nil {
+				t.Fatalf("SaveNotificationTemplate: %v", err)
+			}
+		}
+
+		templates, err := store.ListNotificationTemplates(ctx)
+		if err != nil {
+			t.Fatalf("ListNotificationTemplates: %v", err)
+		}
+
+		var got []string
+		for _, template := range templates {
+			got = append(got, string(template.Event)+"/"+string(template.Channel)+"/"+template.Locale)
+		}
+		want := []string{"arrival/sms/en", "delivered/email/de", "delivered/email/en", "delivered/sms/en"}
+		if len(got) != len(want) {
+			t.Fatalf("ListNotificationTemplates returned %v, want %v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("ListNotificationTemplates returned %v, want %v", got, want)
+			}
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		store := newStore(t)
+
+		if err := store.SaveNotificationTemplate(ctx, newTemplate(model.NotificationDelivered, model.NotificationChannelPush, "en")); err != nil {
+			t.Fatalf("SaveNotificationTemplate: %v", err)
+		}
+		if err := store.DeleteNotificationTemplate(ctx, model.NotificationDelivered, model.NotificationChannelPush, "en"); err != nil {
+			t.Fatalf("DeleteNotificationTemplate: %v", err)
+		}
+
+		err := store.DeleteNotificationTemplate(ctx, model.NotificationDelivered, model.NotificationChannelPush, "en")
+		if !errors.Is(err, repository.ErrTemplateNotFound) {
+			t.Fatalf("DeleteNotificationTemplate error = %v, want ErrTemplateNotFound", err)
+		}
+	})
+}
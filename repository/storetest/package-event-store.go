@@ -0,0 +1,106 @@
+package storetest
+
+import (
+	"context"
+	"go-test/internal/model"
+	"go-test/repository"
+	"testing"
+	"time"
+)
+
+// PackageEventStore is what the delivery summary reads from: the package
+// events and the confirmation links.
+type PackageEventStore interface {
+	repository.PackageEventStore
+	repository.ConfirmationTokenStore
+}
+
+func TestPackageEventStore(t *testing.T, newStore func(t *testing.T) PackageEventStore) {
+	ctx := context.Background()
+	from := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	record := func(t *testing.T, store PackageEventStore, packageID string, event model.PackageEventType, at time.Time) {
+		t.Helper()
+
+		if err := store.RecordPackageEvent(ctx, &model.PackageEvent{PackageID: packageID, Event: event, OccurredAt: at}); err != nil {
+			t.Fatalf("RecordPackageEvent: %v", err)
+		}
+	}
+
+	link := func(t *testing.T, store PackageEventStore, id, packageID string, expiresAt time.Time, consumed bool) {
+		t.Helper()
+
+		token := &model.ConfirmationToken{
+			ID:            id,
+			PackageID:     packageID,
+			CustomerEmail: "customer@example.com",
+			ExpiresAt:     expiresAt,
+			CreatedAt:     expiresAt.Add(-72 * time.Hour),
+		}
+		if err := store.CreateConfirmationToken(ctx, token); err != nil {
+			t.Fatalf("CreateConfirmationToken: %v", err)
+		}
+		if consumed {
+			if _, err := store.ConsumeConfirmationToken(ctx, id, expiresAt.Add(-time.Hour)); err != nil {
+				t.Fatalf("ConsumeConfirmationToken: %v", err)
+			}
+		}
+	}
+
+	t.Run("summarize period", func(t *testing.T) {
+		store := newStore(t)
+
+		// Created the day before, confirmed within the period.
+		record(t, store, "pkg-1", model.PackageEventCreated, from.Add(-3*time.Hour))
+		record(t, store, "pkg-1", model.PackageEventConfirmed, from.Add(3*time.Hour))
+		// Created and confirmed within the period.
+		record(t, store, "pkg-2", model.PackageEventCreated, from.Add(time.Hour))
+		record(t, store, "pkg-2", model.PackageEventConfirmed, from.Add(3*time.Hour))
+		// Created within the period, errored after it.
+		record(t, store, "pkg-3", model.PackageEventCreated, from.Add(2*time.Hour))
+		record(t, store, "pkg-3", model.PackageEventErrored, to.Add(time.Hour))
+		// Errored within the period.
+		record(t, store, "pkg-4", model.PackageEventErrored, from.Add(4*time.Hour))
+
+		// Only the first occurrence of an event counts.
+		record(t, store, "pkg-2", model.PackageEventConfirmed, from.Add(10*time.Hour))
+
+		link(t, store, "tok-1", "pkg-3", from.Add(5*time.Hour), false)
+		link(t, store, "tok-2", "pkg-3", from.Add(6*time.Hour), false)
+		link(t, store, "tok-3", "pkg-5", from.Add(5*time.Hour), true)
+		link(t, store, "tok-4", "pkg-6", to, false)
+		// Confirmed by an operator before the link expired.
+		link(t, store, "tok-5", "pkg-2", from.Add(5*time.Hour), false)
+
+		summary, err := store.SummarizeDeliveries(ctx, from, to)
+		if err != nil {
+			t.Fatalf("SummarizeDeliveries: %v", err)
+		}
+
+		if !summary.From.Equal(from) || !summary.To.Equal(to) {
+			t.Fatalf("SummarizeDeliveries period = %v - %v", summary.From, summary.To)
+		}
+		if summary.Created != 2 || summary.Confirmed != 2 || summary.Errored != 1 || summary.Expired != 1 {
+			t.Fatalf("SummarizeDeliveries returned %+v", summary)
+		}
+		if summary.MedianTimeToConfirm == nil || *summary.MedianTimeToConfirm != 4*time.Hour {
+			t.Fatalf("SummarizeDeliveries median time to confirm = %v, want 4h", summary.MedianTimeToConfirm)
+		}
+	})
+
+	t.Run("summarize empty period", func(t *testing.T) {
+		store := newStore(t)
+
+		summary, err := store.SummarizeDeliveries(ctx, from, to)
+		if err != nil {
+			t.Fatalf("SummarizeDeliveries: %v", err)
+		}
+		if summary.Created != 0 || summary.Confirmed != 0 || summary.Errored != 0 || summary.Expired != 0 {
+			t.Fatalf("SummarizeDeliveries returned %+v", summary)
+		}
+		if summary.MedianTimeToConfirm != nil {
+			t.Fatalf("SummarizeDeliveries median time to confirm = %v, want none", *summary.MedianTimeToConfirm)
+		}
+	})
+}
@@ -0,0 +1,230 @@
+// Package storetest holds the conformance suites every repository store
+// implementation has to pass, regardless of the backend behind it.
+package storetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-test/internal/model"
+	"go-test/repository"
+	"sync"
+	"testing"
+)
+
+func TestPackageStore(t *testing.T, newStore func(t *testing.T) repository.PackageStore) {
+	ctx := context.Background()
+
+	t.Run("create and get", func(t *testing.T) {
+		store := newStore(t)
+		payload := &model.DeliveryPackage{ID: "pkg-1", CustomerEmail: "customer@example.com", DeliveryAddress: "123 Main Street", Region: "eu-central"}
+
+		created, err := store.CreatePackageDelivery(ctx, payload)
+		if err != nil {
+			t.Fatalf("CreatePackageDelivery: %v", err)
+		}
+		if created.ID != payload.ID || created.CustomerEmail != payload.CustomerEmail || created.DeliveryAddress != payload.DeliveryAddress {
+			t.Fatalf("CreatePackageDelivery returned %+v, want %+v", created, payload)
+		}
+
+		got, err := store.GetPackageDelivery(ctx, payload.ID)
+		if err != nil {
+			t.Fatalf("GetPackageDelivery: %v", err)
+		}
+		if got.CustomerEmail != payload.CustomerEmail || got.DeliveryAddress != payload.DeliveryAddress || got.Region != payload.Region {
+			t.Fatalf("GetPackageDelivery returned %+v, want %+v", got, payload)
+		}
+	})
+
+	t.Run("get missing package", func(t *testing.T) {
+		store := newStore(t)
+
+		_, err := store.GetPackageDelivery(ctx, "missing")
+		if !errors.Is(err, repository.ErrPackageNotFound) {
+			t.Fatalf("GetPackageDelivery error = %v, want ErrPackageNotFound", err)
+		}
+	})
+
+	t.Run("create duplicate package", func(t *testing.T) {
+		store := newStore(t)
+		payload := &model.DeliveryPackage{ID: "pkg-dup", CustomerEmail: "customer@example.com", DeliveryAddress: "123 Main Street"}
+
+		if _, err := store.CreatePackageDelivery(ctx, payload); err != nil {
+			t.Fatalf("CreatePackageDelivery: %v", err)
+		}
+
+		_, err := store.CreatePackageDelivery(ctx, payload)
+		if !errors.Is(err, repository.ErrPackageAlreadyExists) {
+			t.Fatalf("CreatePackageDelivery error = %v, want ErrPackageAlreadyExists", err)
+		}
+	})
+
+	t.Run("returned packages are copies", func(t *testing.T) {
+		store := newStore(t)
+		payload := &model.DeliveryPackage{ID: "pkg-copy", CustomerEmail: "customer@example.com", DeliveryAddress: "123 Main Street"}
+
+		created, err := store.CreatePackageDelivery(ctx, payload)
+		if err != nil {
+			t.Fatalf("CreatePackageDelivery: %v", err)
+		}
+		created.DeliveryAddress = "changed"
+		payload.DeliveryAddress = "changed"
+
+		got, err := store.GetPackageDelivery(ctx, "pkg-copy")
+		if err != nil {
+			t.Fatalf("GetPackageDelivery: %v", err)
+		}
+		if got.DeliveryAddress != "123 Main Street" {
+			t.Fatalf("stored package was mutated through a returned value: %+v", got)
+		}
+	})
+
+	t.Run("update bumps version", func(t *testing.T) {
+		store := newStore(t)
+		payload := &model.DeliveryPackage{ID: "pkg-update", CustomerEmail: "customer@example.com", DeliveryAddress: "123 Main Street"}
+
+		created, err := store.CreatePackageDelivery(ctx, payload)
+		if err != nil {
+			t.Fatalf("CreatePackageDelivery: %v", err)
+		}
+		if created.Version != 1 || created.Status != model.PackageDeliverySaved {
+			t.Fatalf("created package = %+v, want version 1 and status %s", created, model.PackageDeliverySaved)
+		}
+
+		changed := *created
+		changed.DeliveryAddress = "456 Side Street"
+		changed.Status = model.PackageDeliveryNotificationFailed
+		updated, err := store.UpdatePackageDelivery(ctx, &changed, created.Version)
+		if err != nil {
+			t.Fatalf("UpdatePackageDelivery: %v", err)
+		}
+		if updated.Version != 2 || updated.DeliveryAddress != "456 Side Street" {
+			t.Fatalf("UpdatePackageDelivery returned %+v", updated)
+		}
+
+		got, err := store.GetPackageDelivery(ctx, payload.ID)
+		if err != nil {
+			t.Fatalf("GetPackageDelivery: %v", err)
+		}
+		if got.Version != 2 || got.DeliveryAddress != "456 Side Street" || got.Status != model.PackageDeliveryNotificationFailed {
+			t.Fatalf("GetPackageDelivery returned %+v after update", got)
+		}
+	})
+
+	t.Run("proof of delivery round trip", func(t *testing.T) {
+		store := newStore(t)
+		payload := &model.DeliveryPackage{
+			ID:              "pkg-proof",
+			CustomerEmail:   "customer@example.com",
+			DeliveryAddress: "123 Main Street",
+			Proof: &model.ProofOfDelivery{
+				RecipientName: "Jane Doe",
+				Signature:     &model.ObjectReference{Key: "packages/pkg-proof/proof/signature", ContentType: "image/png", Size: 42},
+				Location:      &model.GeoLocation{Latitude: 52.52, Longitude: 13.405},
+			},
+		}
+
+		if _, err := store.CreatePackageDelivery(ctx, payload); err != nil {
+			t.Fatalf("CreatePackageDelivery: %v", err)
+		}
+		payload.Proof.Location.Latitude = 0
+
+		got, err := store.GetPackageDelivery(ctx, payload.ID)
+		if err != nil {
+			t.Fatalf("GetPackageDelivery: %v", err)
+		}
+		if got.Proof == nil || got.Proof.RecipientName != "Jane Doe" || got.Proof.Signature == nil || got.Proof.Signature.Size != 42 {
+			t.Fatalf("GetPackageDelivery returned proof %+v", got.Proof)
+		}
+		if got.Proof.Photo != nil || got.Proof.Location == nil || got.Proof.Location.Latitude != 52.52 {
+			t.Fatalf("GetPackageDelivery returned proof %+v", got.Proof)
+		}
+	})
+
+	t.Run("update with stale version", func(t *testing.T) {
+		store := newStore(t)
+		payload := &model.DeliveryPackage{ID: "pkg-stale", CustomerEmail: "customer@example.com", DeliveryAddress: "123 Main Street"}
+
+		created, err := store.CreatePackageDelivery(ctx, payload)
+		if err != nil {
+			t.Fatalf("CreatePackageDelivery: %v", err)
+		}
+		if _, err := store.UpdatePackageDelivery(ctx, created, created.Version); err != nil {
+			t.Fatalf("UpdatePackageDelivery: %v", err)
+		}
+
+		_, err = store.UpdatePackageDelivery(ctx, created, created.Version)
+		var conflict *repository.VersionConflictError
+		if !errors.As(err, &conflict) || !errors.Is(err, repository.ErrVersionConflict) {
+			t.Fatalf("UpdatePackageDelivery error = %v, want VersionConflictError", err)
+		}
+		if conflict.ExpectedVersion != 1 || conflict.ActualVersion != 2 {
+			t.Fatalf("conflict = %+v, want expected 1 and actual 2", conflict)
+		}
+	})
+
+	t.Run("update missing package", func(t *testing.T) {
+		store := newStore(t)
+
+		_, err := store.UpdatePackageDelivery(ctx, &model.DeliveryPackage{ID: "missing"}, 1)
+		if !errors.Is(err, repository.ErrPackageNotFound) {
+			t.Fatalf("UpdatePackageDelivery error = %v, want ErrPackageNotFound", err)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		store := newStore(t)
+		payload := &model.DeliveryPackage{ID: "pkg-delete", CustomerEmail: "customer@example.com", DeliveryAddress: "123 Main Street"}
+
+		created, err := store.CreatePackageDelivery(ctx, payload)
+		if err != nil {
+			t.Fatalf("CreatePackageDelivery: %v", err)
+		}
+
+		err = store.DeletePackageDelivery(ctx, payload.ID, created.Version+1)
+		if !errors.Is(err, repository.ErrVersionConflict) {
+			t.Fatalf("DeletePackageDelivery error = %v, want ErrVersionConflict", err)
+		}
+
+		if err := store.DeletePackageDelivery(ctx, payload.ID, created.Version); err != nil {
+			t.Fatalf("DeletePackageDelivery: %v", err)
+		}
+
+		_, err = store.GetPackageDelivery(ctx, payload.ID)
+		if !errors.Is(err, repository.ErrPackageNotFound) {
+			t.Fatalf("GetPackageDelivery error = %v after delete, want ErrPackageNotFound", err)
+		}
+
+		err = store.DeletePackageDelivery(ctx, payload.ID, created.Version)
+		if !errors.Is(err, repository.ErrPackageNotFound) {
+			t.Fatalf("DeletePackageDelivery error = %v for missing package, want ErrPackageNotFound", err)
+		}
+	})
+
+	t.Run("concurrent creates", func(t *testing.T) {
+		store := newStore(t)
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 20)
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, err := store.CreatePackageDelivery(ctx, &model.DeliveryPackage{
+					ID:              fmt.Sprintf("pkg-concurrent-%d", i),
+					CustomerEmail:   "customer@example.com",
+					DeliveryAddress: "123 Main Street",
+				})
+				errs <- err
+			}(i)
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			if err != nil {
+				t.Fatalf("CreatePackageDelivery: %v", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"go-test/internal/model"
+	"go-test/repository"
+	"testing"
+	"time"
+)
+
+func TestShipmentStore(t *testing.T, newStore func(t *testing.T) repository.ShipmentStore) {
+	ctx := context.Background()
+
+	newShipment := func(id string) *model.Shipment {
+		return &model.Shipment{
+			ID:              id,
+			CustomerEmail:   "customer@example.com",
+			DeliveryAddress: "123 Main Street",
+			Status:          model.ShipmentInProgress,
+			Parcels: model.ShipmentParcels{
+				{PackageID: id + "-1", Status: model.PackageDeliveryInProgress},
+				{PackageID: id + "-2", Status: model.PackageDeliveryInProgress},
+			},
+		}
+	}
+
+	t.Run("save and get", func(t *testing.T) {
+		store := newStore(t)
+
+		if err := store.SaveShipment(ctx, newShipment("shp-1")); err != nil {
+			t.Fatalf("SaveShipment: %v", err)
+		}
+
+		got, err := store.GetShipment(ctx, "shp-1")
+		if err != nil {
+			t.Fatalf("GetShipment: %v", err)
+		}
+		if got.CustomerEmail != "customer@example.com" || len(got.Parcels) != 2 || got.Parcels[1].PackageID != "shp-1-2" {
+			t.Fatalf("GetShipment returned %+v", got)
+		}
+	})
+
+	t.Run("save overwrites", func(t *testing.T) {
+		store := newStore(t)
+
+		shipment := newShipment("shp-2")
+		if err := store.SaveShipment(ctx, shipment); err != nil {
+			t.Fatalf("SaveShipment: %v", err)
+		}
+
+		confirmedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		shipment.Status = model.ShipmentCompleted
+		shipment.Parcels[0] = model.ShipmentParcel{
+			PackageID:    "shp-2-1",
+			Status:       model.PackageDeliveryNotified,
+			Confirmation: &model.DeliveryConfirmation{ConfirmedBy: "customer@example.com", ConfirmedAt: confirmedAt, Channel: model.ConfirmationChannelLink},
+			Completed:    true,
+		}
+		if err := store.SaveShipment(ctx, shipment); err != nil {
+			t.Fatalf("SaveShipment: %v", err)
+		}
+
+		got, err := store.GetShipment(ctx, "shp-2")
+		if err != nil {
+			t.Fatalf("GetShipment: %v", err)
+		}
+		if got.Status != model.ShipmentCompleted || !got.Parcels[0].Delivered() || !got.Parcels[0].Confirmation.ConfirmedAt.Equal(confirmedAt) {
+			t.Fatalf("GetShipment returned %+v", got)
+		}
+	})
+
+	t.Run("get missing shipment", func(t *testing.T) {
+		store := newStore(t)
+
+		_, err := store.GetShipment(ctx, "missing")
+		if !errors.Is(err, repository.ErrShipmentNotFound) {
+			t.Fatalf("GetShipment error = %v, want ErrShipmentNotFound", err)
+		}
+	})
+}
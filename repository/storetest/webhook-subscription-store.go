@@ -0,0 +1,120 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"go-test/internal/model"
+	"go-test/repository"
+	"testing"
+	"time"
+)
+
+func TestWebhookSubscriptionStore(t *testing.T, newStore func(t *testing.T) repository.WebhookSubscriptionStore) {
+	ctx := context.Background()
+	disabledAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	hookURL := func(destination string) string {
+		return "https://partner.example.com/hooks/" + destination
+	}
+	newSubscription := func(destination string) *model.DisabledWebhookSubscription {
+		return &model.DisabledWebhookSubscription{
+			Destination:     destination,
+			URL:             hookURL(destination),
+			StatusCode:      410,
+			ResponseExcerpt: `{"error":"subscription removed"}`,
+			DisabledAt:      disabledAt,
+		}
+	}
+
+	t.Run("disable and get", func(t *testing.T) {
+		store := newStore(t)
+
+		_, err := store.GetDisabledWebhookSubscription(ctx, hookURL("notifications"))
+		if !errors.Is(err, repository.ErrSubscriptionNotDisabled) {
+			t.Fatalf("GetDisabledWebhookSubscription error = %v, want ErrSubscriptionNotDisabled", err)
+		}
+
+		if err := store.DisableWebhookSubscription(ctx, newSubscription("notifications")); err != nil {
+			t.Fatalf("DisableWebhookSubscription: %v", err)
+		}
+
+		got, err := store.GetDisabledWebhookSubscription(ctx, hookURL("notifications"))
+		if err != nil {
+			t.Fatalf("GetDisabledWebhookSubscription: %v", err)
+		}
+		if got.StatusCode != 410 || got.ResponseExcerpt != `{"error":"subscription removed"}` || !got.DisabledAt.Equal(disabledAt) {
+			t.Fatalf("GetDisabledWebhookSubscription returned %+v", got)
+		}
+
+		// Another URL of the same destination stays enabled.
+		_, err = store.GetDisabledWebhookSubscription(ctx, hookURL("notifications")+"/v2")
+		if !errors.Is(err, repository.ErrSubscriptionNotDisabled) {
+			t.Fatalf("GetDisabledWebhookSubscription of another URL = %v, want ErrSubscriptionNotDisabled", err)
+		}
+	})
+
+	t.Run("disable overwrites", func(t *testing.T) {
+		store := newStore(t)
+
+		subscription := newSubscription("support")
+		if err := store.DisableWebhookSubscription(ctx, subscription); err != nil {
+			t.Fatalf("DisableWebhookSubscription: %v", err)
+		}
+
+		subscription.ResponseExcerpt = "gone"
+		if err := store.DisableWebhookSubscription(ctx, subscription); err != nil {
+			t.Fatalf("DisableWebhookSubscription: %v", err)
+		}
+
+		got, err := store.GetDisabledWebhookSubscription(ctx, hookURL("support"))
+		if err != nil {
+			t.Fatalf("GetDisabledWebhookSubscription: %v", err)
+		}
+		if got.ResponseExcerpt != "gone" {
+			t.Fatalf("ResponseExcerpt = %q, want the latest", got.ResponseExcerpt)
+		}
+	})
+
+	t.Run("list by url", func(t *testing.T) {
+		store := newStore(t)
+
+		for _, destination := range []string{"support", "default", "operations"} {
+			if err := store.DisableWebhookSubscription(ctx, newSubscription(destination)); err != nil {
+				t.Fatalf("DisableWebhookSubscription: %v", err)
+			}
+		}
+
+		subscriptions, err := store.ListDisabledWebhookSubscriptions(ctx)
+		if err != nil {
+			t.Fatalf("ListDisabledWebhookSubscriptions: %v", err)
+		}
+
+		var got []string
+		for _, subscription := range subscriptions {
+			got = append(got, subscription.Destination)
+		}
+		if len(got) != 3 || got[0] != "default" || got[1] != "operations" || got[2] != "support" {
+			t.Fatalf("ListDisabledWebhookSubscriptions = %v, want default, operations, support", got)
+		}
+	})
+
+	t.Run("enable", func(t *testing.T) {
+		store := newStore(t)
+
+		if err := store.DisableWebhookSubscription(ctx, newSubscription("operations")); err != nil {
+			t.Fatalf("DisableWebhookSubscription: %v", err)
+		}
+		if err := store.EnableWebhookSubscription(ctx, hookURL("operations")); err != nil {
+			t.Fatalf("EnableWebhookSubscription: %v", err)
+		}
+
+		_, err := store.GetDisabledWebhookSubscription(ctx, hookURL("operations"))
+		if !errors.Is(err, repository.ErrSubscriptionNotDisabled) {
+			t.Fatalf("GetDisabledWebhookSubscription after enable = %v, want ErrSubscriptionNotDisabled", err)
+		}
+
+		if err := store.EnableWebhookSubscription(ctx, hookURL("operations")); !errors.Is(err, repository.ErrSubscriptionNotDisabled) {
+			t.Fatalf("EnableWebhookSubscription twice = %v, want ErrSubscriptionNotDisabled", err)
+		}
+	})
+}
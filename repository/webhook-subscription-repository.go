@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-test/internal/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *Repository) DisableWebhookSubscription(ctx context.Context, subscription *model.DisabledWebhookSubscription) error {
+	err := r.Connection.WithContext(ctx).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(subscription).Error
+	if err != nil {
+		r.Logger.Error("Failed to disable webhook subscription", zap.String("url", subscription.URL), zap.Error(err))
+		return fmt.Errorf("failed to disable webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetDisabledWebhookSubscription(ctx context.Context, url string) (*model.DisabledWebhookSubscription, error) {
+	var subscription model.DisabledWebhookSubscription
+
+	err := r.Connection.WithContext(ctx).Where("url = ?", url).Take(&subscription).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to get disabled webhook subscription %s: %w", url, ErrSubscriptionNotDisabled)
+		}
+
+		r.Logger.Error("Failed to get disabled webhook subscription", zap.String("url", url), zap.Error(err))
+		return nil, fmt.Errorf("failed to get disabled webhook subscription: %w", err)
+	}
+
+	return &subscription, nil
+}
+
+func (r *Repository) ListDisabledWebhookSubscriptions(ctx context.Context) ([]model.DisabledWebhookSubscription, error) {
+	var subscriptions []model.DisabledWebhookSubscription
+
+	if err := r.Connection.WithContext(ctx).Order("url").Find(&subscriptions).Error; err != nil {
+		r.Logger.Error("Failed to list disabled webhook subscriptions", zap.Error(err))
+		return nil, fmt.Errorf("failed to list disabled webhook subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+func (r *Repository) EnableWebhookSubscription(ctx context.Context, url string) error {
+	result := r.Connection.WithContext(ctx).
+		Where("url = ?", url).
+		Delete(&model.DisabledWebhookSubscription{})
+	if result.Error != nil {
+		r.Logger.Error("Failed to enable webhook subscription", zap.String("url", url), zap.Error(result.Error))
+		return fmt.Errorf("failed to enable webhook subscription: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("failed to enable webhook subscription %s: %w", url, ErrSubscriptionNotDisabled)
+	}
+
+	return nil
+}